@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain/model"
+	"github.com/kawabatas/m-bank/domain/repository"
+)
+
+// 1回のポーリングで処理する未完了のジョブの数
+const bulkCreditJobsPerPoll = 10
+
+// bulkCreditWorker periodically processes unfinished bulk credit jobs and requested reversals chunk by chunk.
+type bulkCreditWorker struct {
+	JobRepo   repository.BulkCreditJobRepository
+	Interval  time.Duration
+	ChunkSize int
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newBulkCreditWorker(jobRepo repository.BulkCreditJobRepository, interval time.Duration, chunkSize int) *bulkCreditWorker {
+	return &bulkCreditWorker{
+		JobRepo:   jobRepo,
+		Interval:  interval,
+		ChunkSize: chunkSize,
+	}
+}
+
+// Start runs the worker in a background goroutine until Stop is called.
+func (w *bulkCreditWorker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(w.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.poll(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops the worker and waits for the running chunk to finish.
+func (w *bulkCreditWorker) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+}
+
+func (w *bulkCreditWorker) poll(ctx context.Context) {
+	jobs, err := w.JobRepo.ListUnfinished(ctx, bulkCreditJobsPerPoll)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("list unfinished bulk credit jobs error: %v", err)
+		}
+		return
+	}
+	for _, job := range jobs {
+		w.process(ctx, job.ID)
+	}
+}
+
+func (w *bulkCreditWorker) process(ctx context.Context, id uint64) {
+	// ジョブが完了するまでチャンク単位で処理する。失敗したチャンクは次のポーリングでリトライする
+	for ctx.Err() == nil {
+		job, err := w.JobRepo.ProcessChunk(ctx, id, w.ChunkSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("process bulk credit job %d error: %v", id, err)
+			}
+			return
+		}
+		if job.IsFinished() {
+			if job.ReversalStatus == model.BulkCreditStatusCompleted {
+				log.Printf("reversed bulk credit job %d: reversed %d (amount %d), skipped %d", job.ID, job.ReversedCount, job.ReversedAmount, job.ReversalSkippedCount)
+				return
+			}
+			log.Printf("completed bulk credit job %d: credited %d, failed %d", job.ID, job.CreditedCount, job.FailedCount)
+			return
+		}
+	}
+}
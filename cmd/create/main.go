@@ -7,7 +7,9 @@ import (
 	"os"
 
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v4/stdlib"
 	"github.com/kawabatas/m-bank/infra/database"
+	"github.com/kawabatas/m-bank/infra/postgres"
 	migrate "github.com/rubenv/sql-migrate"
 )
 
@@ -16,24 +18,31 @@ func main() {
 	dbname := os.Getenv("DB_NAME")
 	user := os.Getenv("DB_USER")
 	password := os.Getenv("DB_PASSWORD")
+	driver := os.Getenv("DB_DRIVER")
+	if driver == "" {
+		driver = "mysql"
+	}
+	if driver != "mysql" && driver != "postgres" {
+		log.Fatalf("unknown DB_DRIVER: %s", driver)
+	}
 
 	databases := []string{dbname, database.TestDBName()}
 	for _, dbname := range databases {
-		if err := createDB(host, user, password, dbname); err != nil {
-			log.Fatal(err)
-		}
-
-		dsn := database.DSN(host, user, password, dbname)
-		db, err := sql.Open("mysql", dsn)
+		db, err := createDB(driver, host, user, password, dbname)
 		if err != nil {
 			log.Fatal(err)
 		}
 
+		// マイグレーションはDBごとに別のディレクトリにある
+		dir := "db/migrate"
+		if driver == "postgres" {
+			dir = "db/migrate_postgres"
+		}
 		migrations := &migrate.FileMigrationSource{
-			Dir: "db/migrate",
+			Dir: dir,
 		}
 		migrate.SetTable("migrations")
-		n, err := migrate.Exec(db, "mysql", migrations, migrate.Up)
+		n, err := migrate.Exec(db, driver, migrations, migrate.Up)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -42,20 +51,41 @@ func main() {
 	os.Exit(0)
 }
 
-func createDB(host, user, password, dbname string) error {
+// createDB recreates the database and returns the connection to it.
+func createDB(driver, host, user, password, dbname string) (*sql.DB, error) {
+	if driver == "postgres" {
+		return createPostgresDB(host, user, password, dbname)
+	}
 	dsn := database.DSN(host, user, password, "mysql")
 	db, err := sql.Open("mysql", dsn)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer db.Close()
 
 	if _, err := db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname)); err != nil {
-		return err
+		return nil, err
 	}
 
 	if _, err := db.Exec(fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;", dbname)); err != nil {
-		return err
+		return nil, err
+	}
+	return sql.Open("mysql", database.DSN(host, user, password, dbname))
+}
+
+func createPostgresDB(host, user, password, dbname string) (*sql.DB, error) {
+	db, err := sql.Open("pgx", postgres.DSN(host, user, password, "postgres"))
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbname)); err != nil {
+		return nil, err
+	}
+	// PostgreSQLにはCREATE DATABASE IF NOT EXISTSがない
+	if _, err := db.Exec(fmt.Sprintf("CREATE DATABASE %s ENCODING 'UTF8'", dbname)); err != nil {
+		return nil, err
 	}
-	return nil
+	return sql.Open("pgx", postgres.DSN(host, user, password, dbname))
 }
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE `balances` ADD COLUMN `reserved_amount` INT(11) UNSIGNED NOT NULL DEFAULT '0' AFTER `amount`;
+
+-- +migrate Down
+ALTER TABLE `balances` DROP COLUMN `reserved_amount`;
@@ -0,0 +1,14 @@
+-- +migrate Up
+ALTER TABLE `payment_transactions`
+  ADD COLUMN `expire_time` DATETIME AFTER `try_time`,
+  ADD COLUMN `expired_time` DATETIME AFTER `cancel_time`;
+UPDATE `payment_transactions` SET `expire_time` = DATE_ADD(`try_time`, INTERVAL 10 MINUTE);
+ALTER TABLE `payment_transactions`
+  MODIFY COLUMN `expire_time` DATETIME NOT NULL,
+  ADD INDEX `idx_expire_time` (`expire_time`);
+
+-- +migrate Down
+ALTER TABLE `payment_transactions`
+  DROP INDEX `idx_expire_time`,
+  DROP COLUMN `expired_time`,
+  DROP COLUMN `expire_time`;
@@ -0,0 +1,6 @@
+-- +migrate Up
+ALTER TABLE `payment_transactions` ADD COLUMN `request_fingerprint` CHAR(64) NOT NULL DEFAULT '' AFTER `amount`;
+UPDATE `payment_transactions` SET `request_fingerprint` = SHA2(CONCAT(`user_id`, ':', `amount`), 256);
+
+-- +migrate Down
+ALTER TABLE `payment_transactions` DROP COLUMN `request_fingerprint`;
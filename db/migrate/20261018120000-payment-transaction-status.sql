@@ -0,0 +1,17 @@
+-- +migrate Up
+ALTER TABLE `payment_transactions` ADD COLUMN `status` VARCHAR(16) NOT NULL DEFAULT 'tried' AFTER `request_fingerprint`;
+UPDATE `payment_transactions` SET `status` = CASE
+  WHEN `confirm_time` IS NOT NULL THEN 'confirmed'
+  WHEN `cancel_time` IS NOT NULL THEN 'cancelled'
+  WHEN `expired_time` IS NOT NULL THEN 'expired'
+  ELSE 'tried'
+END;
+ALTER TABLE `payment_transactions`
+  DROP INDEX `idx_expire_time`,
+  ADD INDEX `idx_status_expire_time` (`status`, `expire_time`);
+
+-- +migrate Down
+ALTER TABLE `payment_transactions`
+  DROP INDEX `idx_status_expire_time`,
+  ADD INDEX `idx_expire_time` (`expire_time`),
+  DROP COLUMN `status`;
@@ -0,0 +1,22 @@
+-- +migrate Up
+CREATE TABLE `transfers` (
+  `uuid` VARCHAR(255) NOT NULL,
+  `from_user_id` INT(11) UNSIGNED NOT NULL,
+  `to_user_id` INT(11) UNSIGNED NOT NULL,
+  `amount` INT(11) UNSIGNED NOT NULL,
+  `request_fingerprint` CHAR(64) NOT NULL,
+  `status` VARCHAR(16) NOT NULL,
+  `create_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `try_time` DATETIME NOT NULL,
+  `expire_time` DATETIME NOT NULL,
+  `confirm_time` DATETIME,
+  `cancel_time` DATETIME,
+  `expired_time` DATETIME,
+  PRIMARY KEY (`uuid`),
+  INDEX `idx_status_expire_time` (`status`, `expire_time`),
+  FOREIGN KEY (`from_user_id`) REFERENCES `users` (`id`),
+  FOREIGN KEY (`to_user_id`) REFERENCES `users` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +migrate Down
+DROP TABLE IF EXISTS `transfers`;
@@ -0,0 +1,34 @@
+-- +migrate Up
+CREATE TABLE `journal_entries` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `source_type` VARCHAR(32) NOT NULL,
+  `source_id` VARCHAR(255) NOT NULL,
+  `create_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  INDEX `idx_source` (`source_type`, `source_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `postings` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `journal_entry_id` BIGINT UNSIGNED NOT NULL,
+  `account` VARCHAR(64) NOT NULL,
+  `amount` BIGINT NOT NULL,
+  `create_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  INDEX `idx_account` (`account`),
+  FOREIGN KEY (`journal_entry_id`) REFERENCES `journal_entries` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- 既存の残高を開始残高として仕訳する
+INSERT INTO `journal_entries` (`source_type`, `source_id`)
+  SELECT 'opening_balance', `user_id` FROM `balances` WHERE `amount` > 0;
+INSERT INTO `postings` (`journal_entry_id`, `account`, `amount`)
+  SELECT `je`.`id`, CONCAT('user:', `b`.`user_id`), `b`.`amount`
+  FROM `balances` `b` JOIN `journal_entries` `je` ON `je`.`source_type` = 'opening_balance' AND `je`.`source_id` = `b`.`user_id`;
+INSERT INTO `postings` (`journal_entry_id`, `account`, `amount`)
+  SELECT `je`.`id`, 'system:opening_balance', -CAST(`b`.`amount` AS SIGNED)
+  FROM `balances` `b` JOIN `journal_entries` `je` ON `je`.`source_type` = 'opening_balance' AND `je`.`source_id` = `b`.`user_id`;
+
+-- +migrate Down
+DROP TABLE IF EXISTS `postings`;
+DROP TABLE IF EXISTS `journal_entries`;
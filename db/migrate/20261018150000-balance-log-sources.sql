@@ -0,0 +1,18 @@
+-- +migrate Up
+ALTER TABLE `journal_entries` ADD COLUMN `reason` VARCHAR(255) NOT NULL DEFAULT '' AFTER `source_id`;
+ALTER TABLE `balance_logs`
+  ADD COLUMN `delta` BIGINT NOT NULL DEFAULT '0' AFTER `after_amount`,
+  ADD COLUMN `source_type` VARCHAR(32) NOT NULL DEFAULT '' AFTER `delta`,
+  ADD COLUMN `source_id` VARCHAR(255) NOT NULL DEFAULT '' AFTER `source_type`,
+  ADD COLUMN `reason` VARCHAR(255) NOT NULL DEFAULT '' AFTER `source_id`,
+  ADD INDEX `idx_user_id_id` (`user_id`, `id`);
+UPDATE `balance_logs` SET `delta` = CAST(`after_amount` AS SIGNED) - CAST(`before_amount` AS SIGNED);
+
+-- +migrate Down
+ALTER TABLE `balance_logs`
+  DROP INDEX `idx_user_id_id`,
+  DROP COLUMN `reason`,
+  DROP COLUMN `source_id`,
+  DROP COLUMN `source_type`,
+  DROP COLUMN `delta`;
+ALTER TABLE `journal_entries` DROP COLUMN `reason`;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE `payment_transactions`
+  ADD INDEX `idx_user_id_try_time_uuid` (`user_id`, `try_time`, `uuid`);
+
+-- +migrate Down
+ALTER TABLE `payment_transactions`
+  DROP INDEX `idx_user_id_try_time_uuid`;
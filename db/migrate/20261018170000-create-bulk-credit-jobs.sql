@@ -0,0 +1,33 @@
+-- +migrate Up
+CREATE TABLE `bulk_credit_jobs` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `idempotency_key` VARCHAR(255) NOT NULL,
+  `amount` INT(11) UNSIGNED NOT NULL,
+  `status` VARCHAR(16) NOT NULL,
+  `cursor_user_id` INT(11) UNSIGNED NOT NULL DEFAULT '0',
+  `total_count` INT(11) NOT NULL DEFAULT '0',
+  `credited_count` INT(11) NOT NULL DEFAULT '0',
+  `failed_count` INT(11) NOT NULL DEFAULT '0',
+  `last_error` VARCHAR(255) NOT NULL DEFAULT '',
+  `create_time` DATETIME NOT NULL,
+  `update_time` DATETIME NOT NULL,
+  `finish_time` DATETIME,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_idempotency_key` (`idempotency_key`),
+  INDEX `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `bulk_credit_items` (
+  `job_id` BIGINT UNSIGNED NOT NULL,
+  `user_id` INT(11) UNSIGNED NOT NULL,
+  `status` VARCHAR(16) NOT NULL,
+  `error` VARCHAR(255) NOT NULL DEFAULT '',
+  `create_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`job_id`, `user_id`),
+  FOREIGN KEY (`job_id`) REFERENCES `bulk_credit_jobs` (`id`),
+  FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +migrate Down
+DROP TABLE IF EXISTS `bulk_credit_items`;
+DROP TABLE IF EXISTS `bulk_credit_jobs`;
@@ -0,0 +1,22 @@
+-- +migrate Up
+ALTER TABLE `users` ADD COLUMN `create_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP AFTER `name`,
+  ADD INDEX `idx_create_time` (`create_time`);
+ALTER TABLE `bulk_credit_jobs`
+  ADD COLUMN `request_fingerprint` VARCHAR(64) NOT NULL DEFAULT '' AFTER `idempotency_key`,
+  ADD COLUMN `target_type` VARCHAR(32) NOT NULL DEFAULT 'all' AFTER `amount`,
+  ADD COLUMN `target_user_ids` MEDIUMTEXT AFTER `target_type`,
+  ADD COLUMN `target_created_from` DATETIME AFTER `target_user_ids`,
+  ADD COLUMN `target_created_to` DATETIME AFTER `target_created_from`,
+  ADD COLUMN `target_balance_below` INT(11) UNSIGNED NOT NULL DEFAULT '0' AFTER `target_created_to`;
+-- 既存のジョブはすべてのユーザが対象（model.BulkCreditRequestFingerprint と同じ形式）
+UPDATE `bulk_credit_jobs` SET `request_fingerprint` = SHA2(CONCAT(`amount`, ':all'), 256);
+
+-- +migrate Down
+ALTER TABLE `bulk_credit_jobs`
+  DROP COLUMN `target_balance_below`,
+  DROP COLUMN `target_created_to`,
+  DROP COLUMN `target_created_from`,
+  DROP COLUMN `target_user_ids`,
+  DROP COLUMN `target_type`,
+  DROP COLUMN `request_fingerprint`;
+ALTER TABLE `users` DROP INDEX `idx_create_time`, DROP COLUMN `create_time`;
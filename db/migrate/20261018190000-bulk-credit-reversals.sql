@@ -0,0 +1,38 @@
+-- +migrate Up
+-- キャンペーンの取り消しで残高が負になることがあるので符号付きにする
+ALTER TABLE `balances` MODIFY `amount` INT(11) NOT NULL DEFAULT '0';
+ALTER TABLE `balance_logs`
+  MODIFY `before_amount` INT(11) NOT NULL,
+  MODIFY `after_amount` INT(11) NOT NULL;
+ALTER TABLE `bulk_credit_jobs`
+  ADD COLUMN `reversal_status` VARCHAR(16) NOT NULL DEFAULT '' AFTER `finish_time`,
+  ADD COLUMN `reversal_mode` VARCHAR(16) NOT NULL DEFAULT '' AFTER `reversal_status`,
+  ADD COLUMN `reversal_cursor_user_id` INT(11) UNSIGNED NOT NULL DEFAULT '0' AFTER `reversal_mode`,
+  ADD COLUMN `reversed_count` INT(11) NOT NULL DEFAULT '0' AFTER `reversal_cursor_user_id`,
+  ADD COLUMN `reversal_skipped_count` INT(11) NOT NULL DEFAULT '0' AFTER `reversed_count`,
+  ADD COLUMN `reversed_amount` BIGINT NOT NULL DEFAULT '0' AFTER `reversal_skipped_count`,
+  ADD COLUMN `reversal_request_time` DATETIME AFTER `reversed_amount`,
+  ADD COLUMN `reversal_finish_time` DATETIME AFTER `reversal_request_time`,
+  ADD INDEX `idx_reversal_status` (`reversal_status`);
+ALTER TABLE `bulk_credit_items`
+  ADD COLUMN `reversal_status` VARCHAR(16) NOT NULL DEFAULT '' AFTER `error`,
+  ADD COLUMN `reversed_amount` INT(11) UNSIGNED NOT NULL DEFAULT '0' AFTER `reversal_status`;
+
+-- +migrate Down
+ALTER TABLE `bulk_credit_items`
+  DROP COLUMN `reversed_amount`,
+  DROP COLUMN `reversal_status`;
+ALTER TABLE `bulk_credit_jobs`
+  DROP INDEX `idx_reversal_status`,
+  DROP COLUMN `reversal_finish_time`,
+  DROP COLUMN `reversal_request_time`,
+  DROP COLUMN `reversed_amount`,
+  DROP COLUMN `reversal_skipped_count`,
+  DROP COLUMN `reversed_count`,
+  DROP COLUMN `reversal_cursor_user_id`,
+  DROP COLUMN `reversal_mode`,
+  DROP COLUMN `reversal_status`;
+ALTER TABLE `balance_logs`
+  MODIFY `before_amount` INT(11) UNSIGNED NOT NULL,
+  MODIFY `after_amount` INT(11) UNSIGNED NOT NULL;
+ALTER TABLE `balances` MODIFY `amount` INT(11) UNSIGNED NOT NULL DEFAULT '0';
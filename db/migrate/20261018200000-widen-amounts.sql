@@ -0,0 +1,30 @@
+-- +migrate Up
+-- 金額はすべて通貨の補助単位の64bit整数にする
+ALTER TABLE `balances`
+  ADD COLUMN `currency` CHAR(3) NOT NULL DEFAULT 'JPY' AFTER `user_id`,
+  MODIFY `amount` BIGINT NOT NULL DEFAULT '0',
+  MODIFY `reserved_amount` BIGINT NOT NULL DEFAULT '0';
+ALTER TABLE `balance_logs`
+  MODIFY `before_amount` BIGINT NOT NULL,
+  MODIFY `after_amount` BIGINT NOT NULL;
+ALTER TABLE `payment_transactions` MODIFY `amount` BIGINT NOT NULL;
+ALTER TABLE `transfers` MODIFY `amount` BIGINT NOT NULL;
+ALTER TABLE `bulk_credit_jobs`
+  MODIFY `amount` BIGINT NOT NULL,
+  MODIFY `target_balance_below` BIGINT NOT NULL DEFAULT '0';
+ALTER TABLE `bulk_credit_items` MODIFY `reversed_amount` BIGINT NOT NULL DEFAULT '0';
+
+-- +migrate Down
+ALTER TABLE `bulk_credit_items` MODIFY `reversed_amount` INT(11) UNSIGNED NOT NULL DEFAULT '0';
+ALTER TABLE `bulk_credit_jobs`
+  MODIFY `target_balance_below` INT(11) UNSIGNED NOT NULL DEFAULT '0',
+  MODIFY `amount` INT(11) UNSIGNED NOT NULL;
+ALTER TABLE `transfers` MODIFY `amount` INT(11) UNSIGNED NOT NULL;
+ALTER TABLE `payment_transactions` MODIFY `amount` INT(11) NOT NULL;
+ALTER TABLE `balance_logs`
+  MODIFY `before_amount` INT(11) NOT NULL,
+  MODIFY `after_amount` INT(11) NOT NULL;
+ALTER TABLE `balances`
+  MODIFY `reserved_amount` INT(11) UNSIGNED NOT NULL DEFAULT '0',
+  MODIFY `amount` INT(11) NOT NULL DEFAULT '0',
+  DROP COLUMN `currency`;
@@ -0,0 +1,29 @@
+-- +migrate Up
+-- ユーザは通貨ごとにウォレット（残高）を持つ
+ALTER TABLE `balances`
+  DROP PRIMARY KEY,
+  ADD PRIMARY KEY (`user_id`, `currency`);
+ALTER TABLE `balance_logs`
+  ADD COLUMN `currency` CHAR(3) NOT NULL DEFAULT 'JPY' AFTER `user_id`;
+ALTER TABLE `postings`
+  ADD COLUMN `currency` CHAR(3) NOT NULL DEFAULT 'JPY' AFTER `account`,
+  ADD INDEX `idx_account_currency` (`account`, `currency`);
+ALTER TABLE `payment_transactions`
+  ADD COLUMN `currency` CHAR(3) NOT NULL DEFAULT 'JPY' AFTER `user_id`;
+ALTER TABLE `transfers`
+  ADD COLUMN `currency` CHAR(3) NOT NULL DEFAULT 'JPY' AFTER `to_user_id`;
+ALTER TABLE `bulk_credit_jobs`
+  ADD COLUMN `currency` CHAR(3) NOT NULL DEFAULT 'JPY' AFTER `request_fingerprint`;
+
+-- +migrate Down
+-- JPY以外のウォレットは戻せないので、ロールバックする前に残っていないことを確認すること
+ALTER TABLE `bulk_credit_jobs` DROP COLUMN `currency`;
+ALTER TABLE `transfers` DROP COLUMN `currency`;
+ALTER TABLE `payment_transactions` DROP COLUMN `currency`;
+ALTER TABLE `postings`
+  DROP INDEX `idx_account_currency`,
+  DROP COLUMN `currency`;
+ALTER TABLE `balance_logs` DROP COLUMN `currency`;
+ALTER TABLE `balances`
+  DROP PRIMARY KEY,
+  ADD PRIMARY KEY (`user_id`);
@@ -0,0 +1,34 @@
+-- +migrate Up
+CREATE TABLE `exchange_rates` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `from_currency` CHAR(3) NOT NULL,
+  `to_currency` CHAR(3) NOT NULL,
+  `rate` DECIMAL(24,12) NOT NULL,
+  `rounding` VARCHAR(16) NOT NULL,
+  `valid_from` DATETIME NOT NULL,
+  `valid_to` DATETIME NOT NULL,
+  `create_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  INDEX `idx_currencies_valid_to` (`from_currency`, `to_currency`, `valid_to`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `exchanges` (
+  `uuid` VARCHAR(255) NOT NULL,
+  `user_id` INT(11) UNSIGNED NOT NULL,
+  `rate_id` BIGINT UNSIGNED NOT NULL,
+  `from_currency` CHAR(3) NOT NULL,
+  `from_amount` BIGINT NOT NULL,
+  `to_currency` CHAR(3) NOT NULL,
+  `to_amount` BIGINT NOT NULL,
+  `rate` DECIMAL(24,12) NOT NULL,
+  `rounding` VARCHAR(16) NOT NULL,
+  `create_time` DATETIME NOT NULL,
+  PRIMARY KEY (`uuid`),
+  INDEX `idx_user_id` (`user_id`),
+  FOREIGN KEY (`user_id`) REFERENCES `users` (`id`),
+  FOREIGN KEY (`rate_id`) REFERENCES `exchange_rates` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +migrate Down
+DROP TABLE IF EXISTS `exchanges`;
+DROP TABLE IF EXISTS `exchange_rates`;
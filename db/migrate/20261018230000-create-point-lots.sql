@@ -0,0 +1,24 @@
+-- +migrate Up
+-- ポイントの加算ごとの有効期限と残り。減算は有効期限の近いロットから消費する
+CREATE TABLE `point_lots` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `user_id` INT(11) UNSIGNED NOT NULL,
+  `amount` BIGINT NOT NULL,
+  `remaining_amount` BIGINT NOT NULL,
+  `expire_time` DATETIME,
+  `source_type` VARCHAR(32) NOT NULL,
+  `source_id` VARCHAR(255) NOT NULL,
+  `create_time` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_user_id_expire_time` (`user_id`, `expire_time`),
+  INDEX `idx_expire_time` (`expire_time`),
+  FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- 既存のポイントは失効しないロットとして引き継ぐ
+INSERT INTO `point_lots` (`user_id`, `amount`, `remaining_amount`, `expire_time`, `source_type`, `source_id`, `create_time`)
+SELECT `user_id`, `amount`, `amount`, NULL, 'opening_balance', 'migration', NOW()
+FROM `balances` WHERE `currency` = 'PTS' AND `amount` > 0;
+
+-- +migrate Down
+DROP TABLE IF EXISTS `point_lots`;
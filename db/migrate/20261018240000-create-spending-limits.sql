@@ -0,0 +1,20 @@
+-- +migrate Up
+ALTER TABLE `users`
+  ADD COLUMN `tier` VARCHAR(32) NOT NULL DEFAULT 'standard' AFTER `name`;
+
+-- 支払いの減算の上限。ユーザの上限はそのユーザの区分（tier）の上限より優先する。0は上限なし
+CREATE TABLE `spending_limits` (
+  `scope` VARCHAR(16) NOT NULL,
+  `scope_id` VARCHAR(255) NOT NULL,
+  `currency` CHAR(3) NOT NULL,
+  `max_single_debit` BIGINT NOT NULL DEFAULT '0',
+  `daily_debit_limit` BIGINT NOT NULL DEFAULT '0',
+  `monthly_debit_limit` BIGINT NOT NULL DEFAULT '0',
+  `hourly_debit_count` INT(11) NOT NULL DEFAULT '0',
+  `update_time` DATETIME NOT NULL,
+  PRIMARY KEY (`scope`, `scope_id`, `currency`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +migrate Down
+DROP TABLE IF EXISTS `spending_limits`;
+ALTER TABLE `users` DROP COLUMN `tier`;
@@ -0,0 +1,6 @@
+-- +migrate Up
+-- 残高はすでに符号付きなので、承認された与信枠の分だけ負になることを許す
+ALTER TABLE `balances` ADD COLUMN `overdraft_limit` BIGINT NOT NULL DEFAULT '0' AFTER `reserved_amount`;
+
+-- +migrate Down
+ALTER TABLE `balances` DROP COLUMN `overdraft_limit`;
@@ -0,0 +1,19 @@
+-- +migrate Up
+ALTER TABLE `payment_transactions` ADD COLUMN `refunded_amount` BIGINT NOT NULL DEFAULT '0' AFTER `expired_time`;
+
+CREATE TABLE `refunds` (
+  `uuid` VARCHAR(255) NOT NULL,
+  `payment_uuid` VARCHAR(255) NOT NULL,
+  `user_id` INT(11) UNSIGNED NOT NULL,
+  `currency` CHAR(3) NOT NULL,
+  `amount` BIGINT NOT NULL,
+  `create_time` DATETIME NOT NULL,
+  PRIMARY KEY (`uuid`),
+  INDEX `idx_payment_uuid` (`payment_uuid`),
+  FOREIGN KEY (`payment_uuid`) REFERENCES `payment_transactions` (`uuid`),
+  FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +migrate Down
+DROP TABLE IF EXISTS `refunds`;
+ALTER TABLE `payment_transactions` DROP COLUMN `refunded_amount`;
@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE `outbox_events` (
+  `sequence` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `event_type` VARCHAR(64) NOT NULL,
+  `payload` JSON NOT NULL,
+  `status` VARCHAR(16) NOT NULL,
+  `attempts` INT(11) NOT NULL DEFAULT '0',
+  `next_attempt_time` DATETIME NOT NULL,
+  `last_error` VARCHAR(255) NOT NULL DEFAULT '',
+  `create_time` DATETIME NOT NULL,
+  `publish_time` DATETIME,
+  PRIMARY KEY (`sequence`),
+  INDEX `idx_status_next_attempt_time` (`status`, `next_attempt_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +migrate Down
+DROP TABLE IF EXISTS `outbox_events`;
@@ -0,0 +1,42 @@
+-- +migrate Up
+CREATE TABLE `webhook_subscriptions` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `url` VARCHAR(2048) NOT NULL,
+  `event_types` VARCHAR(255) NOT NULL,
+  `secret` VARCHAR(255) NOT NULL,
+  `create_time` DATETIME NOT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Webhookの配信の対象のパートナーのユーザ。このユーザのイベントだけを配信する
+CREATE TABLE `webhook_subscription_users` (
+  `subscription_id` BIGINT UNSIGNED NOT NULL,
+  `user_id` INT(11) UNSIGNED NOT NULL,
+  PRIMARY KEY (`subscription_id`, `user_id`),
+  INDEX `idx_user_id` (`user_id`),
+  FOREIGN KEY (`subscription_id`) REFERENCES `webhook_subscriptions` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `webhook_deliveries` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `subscription_id` BIGINT UNSIGNED NOT NULL,
+  `event_sequence` BIGINT UNSIGNED NOT NULL,
+  `event_type` VARCHAR(64) NOT NULL,
+  `status` VARCHAR(16) NOT NULL,
+  `attempts` INT(11) NOT NULL DEFAULT '0',
+  `next_attempt_time` DATETIME NOT NULL,
+  `last_status_code` INT(11) NOT NULL DEFAULT '0',
+  `last_error` VARCHAR(255) NOT NULL DEFAULT '',
+  `create_time` DATETIME NOT NULL,
+  `deliver_time` DATETIME,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_subscription_id_event_sequence` (`subscription_id`, `event_sequence`),
+  INDEX `idx_status_next_attempt_time` (`status`, `next_attempt_time`),
+  FOREIGN KEY (`subscription_id`) REFERENCES `webhook_subscriptions` (`id`),
+  FOREIGN KEY (`event_sequence`) REFERENCES `outbox_events` (`sequence`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +migrate Down
+DROP TABLE IF EXISTS `webhook_deliveries`;
+DROP TABLE IF EXISTS `webhook_subscription_users`;
+DROP TABLE IF EXISTS `webhook_subscriptions`;
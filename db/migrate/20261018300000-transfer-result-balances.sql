@@ -0,0 +1,18 @@
+-- +migrate Up
+-- Try/Confirm/Cancelの直後の送金元と送金先のウォレット。同じ冪等キーでの再試行に元の応答と同じ残高を返す
+ALTER TABLE `transfers`
+  ADD COLUMN `from_balance_amount` BIGINT DEFAULT NULL AFTER `expired_time`,
+  ADD COLUMN `from_balance_reserved_amount` BIGINT DEFAULT NULL AFTER `from_balance_amount`,
+  ADD COLUMN `from_balance_overdraft_limit` BIGINT DEFAULT NULL AFTER `from_balance_reserved_amount`,
+  ADD COLUMN `to_balance_amount` BIGINT DEFAULT NULL AFTER `from_balance_overdraft_limit`,
+  ADD COLUMN `to_balance_reserved_amount` BIGINT DEFAULT NULL AFTER `to_balance_amount`,
+  ADD COLUMN `to_balance_overdraft_limit` BIGINT DEFAULT NULL AFTER `to_balance_reserved_amount`;
+
+-- +migrate Down
+ALTER TABLE `transfers`
+  DROP COLUMN `from_balance_amount`,
+  DROP COLUMN `from_balance_reserved_amount`,
+  DROP COLUMN `from_balance_overdraft_limit`,
+  DROP COLUMN `to_balance_amount`,
+  DROP COLUMN `to_balance_reserved_amount`,
+  DROP COLUMN `to_balance_overdraft_limit`;
@@ -0,0 +1,121 @@
+-- +migrate Up
+-- PostgreSQLのスキーマ。残高と支払いのリポジトリ（infra/postgres）が使うテーブルだけを、db/migrate を適用し終えた状態で作る
+CREATE TABLE users (
+  id SERIAL PRIMARY KEY,
+  name VARCHAR(255) NOT NULL,
+  tier VARCHAR(32) NOT NULL DEFAULT 'standard',
+  create_time TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_users_create_time ON users (create_time);
+
+CREATE TABLE balances (
+  user_id INTEGER NOT NULL REFERENCES users (id),
+  currency CHAR(3) NOT NULL DEFAULT 'JPY',
+  amount BIGINT NOT NULL DEFAULT 0,
+  reserved_amount BIGINT NOT NULL DEFAULT 0,
+  overdraft_limit BIGINT NOT NULL DEFAULT 0,
+  update_time TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (user_id, currency)
+);
+
+CREATE TABLE payment_transactions (
+  uuid VARCHAR(255) PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users (id),
+  currency CHAR(3) NOT NULL DEFAULT 'JPY',
+  amount BIGINT NOT NULL,
+  request_fingerprint CHAR(64) NOT NULL DEFAULT '',
+  status VARCHAR(16) NOT NULL DEFAULT 'tried',
+  create_time TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  try_time TIMESTAMPTZ NOT NULL,
+  expire_time TIMESTAMPTZ NOT NULL,
+  confirm_time TIMESTAMPTZ,
+  cancel_time TIMESTAMPTZ,
+  expired_time TIMESTAMPTZ,
+  refunded_amount BIGINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_payment_transactions_status_expire_time ON payment_transactions (status, expire_time);
+CREATE INDEX idx_payment_transactions_user_id_try_time_uuid ON payment_transactions (user_id, try_time, uuid);
+
+CREATE TABLE balance_logs (
+  id BIGSERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users (id),
+  currency CHAR(3) NOT NULL DEFAULT 'JPY',
+  before_amount BIGINT NOT NULL,
+  after_amount BIGINT NOT NULL,
+  delta BIGINT NOT NULL DEFAULT 0,
+  source_type VARCHAR(32) NOT NULL DEFAULT '',
+  source_id VARCHAR(255) NOT NULL DEFAULT '',
+  reason VARCHAR(255) NOT NULL DEFAULT '',
+  create_time TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_balance_logs_user_id_id ON balance_logs (user_id, id);
+
+CREATE TABLE journal_entries (
+  id BIGSERIAL PRIMARY KEY,
+  source_type VARCHAR(32) NOT NULL,
+  source_id VARCHAR(255) NOT NULL,
+  reason VARCHAR(255) NOT NULL DEFAULT '',
+  create_time TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_journal_entries_source ON journal_entries (source_type, source_id);
+
+CREATE TABLE postings (
+  id BIGSERIAL PRIMARY KEY,
+  journal_entry_id BIGINT NOT NULL REFERENCES journal_entries (id),
+  account VARCHAR(64) NOT NULL,
+  currency CHAR(3) NOT NULL DEFAULT 'JPY',
+  amount BIGINT NOT NULL,
+  create_time TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_postings_account_currency ON postings (account, currency);
+
+-- ポイントの加算ごとの有効期限と残り。減算は有効期限の近いロットから消費する
+CREATE TABLE point_lots (
+  id BIGSERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users (id),
+  amount BIGINT NOT NULL,
+  remaining_amount BIGINT NOT NULL,
+  expire_time TIMESTAMPTZ,
+  source_type VARCHAR(32) NOT NULL,
+  source_id VARCHAR(255) NOT NULL,
+  create_time TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX idx_point_lots_user_id_expire_time ON point_lots (user_id, expire_time);
+CREATE INDEX idx_point_lots_expire_time ON point_lots (expire_time);
+
+-- 支払いの減算の上限。ユーザの上限はそのユーザの区分（tier）の上限より優先する。0は上限なし
+CREATE TABLE spending_limits (
+  scope VARCHAR(16) NOT NULL,
+  scope_id VARCHAR(255) NOT NULL,
+  currency CHAR(3) NOT NULL,
+  max_single_debit BIGINT NOT NULL DEFAULT 0,
+  daily_debit_limit BIGINT NOT NULL DEFAULT 0,
+  monthly_debit_limit BIGINT NOT NULL DEFAULT 0,
+  hourly_debit_count INTEGER NOT NULL DEFAULT 0,
+  update_time TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (scope, scope_id, currency)
+);
+
+INSERT INTO users (id, name) VALUES (1, 'user1'), (2, 'user2');
+SELECT setval('users_id_seq', (SELECT MAX(id) FROM users));
+INSERT INTO balances (user_id, amount) VALUES (1, 100), (2, 200);
+
+-- 初期残高を開始残高として仕訳する
+INSERT INTO journal_entries (source_type, source_id)
+  SELECT 'opening_balance', user_id::text FROM balances WHERE amount > 0;
+INSERT INTO postings (journal_entry_id, account, amount)
+  SELECT je.id, 'user:' || b.user_id, b.amount
+  FROM balances b JOIN journal_entries je ON je.source_type = 'opening_balance' AND je.source_id = b.user_id::text;
+INSERT INTO postings (journal_entry_id, account, amount)
+  SELECT je.id, 'system:opening_balance', -b.amount
+  FROM balances b JOIN journal_entries je ON je.source_type = 'opening_balance' AND je.source_id = b.user_id::text;
+
+-- +migrate Down
+DROP TABLE IF EXISTS spending_limits;
+DROP TABLE IF EXISTS point_lots;
+DROP TABLE IF EXISTS postings;
+DROP TABLE IF EXISTS journal_entries;
+DROP TABLE IF EXISTS balance_logs;
+DROP TABLE IF EXISTS payment_transactions;
+DROP TABLE IF EXISTS balances;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,12 @@
+-- +migrate Up
+-- Try/Confirm/Cancelの直後のウォレット。同じ冪等キーでの再試行に元の応答と同じ残高を返す
+ALTER TABLE payment_transactions
+  ADD COLUMN balance_amount BIGINT,
+  ADD COLUMN balance_reserved_amount BIGINT,
+  ADD COLUMN balance_overdraft_limit BIGINT;
+
+-- +migrate Down
+ALTER TABLE payment_transactions
+  DROP COLUMN balance_amount,
+  DROP COLUMN balance_reserved_amount,
+  DROP COLUMN balance_overdraft_limit;
@@ -4,9 +4,19 @@ import "errors"
 
 // predefined errors.
 var (
-	ErrNoSuchEntity  = errors.New("no such entity")
-	ErrDuplicateUUID = errors.New("duplicate uuid")
-	ErrInvalidUUID   = errors.New("invalid uuid")
-	ErrShortBalance  = errors.New("short balance")
-	ErrInvalidParam  = errors.New("invalid param")
+	ErrNoSuchEntity           = errors.New("no such entity")
+	ErrDuplicateUUID          = errors.New("duplicate uuid")
+	ErrInvalidUUID            = errors.New("invalid uuid")
+	ErrShortBalance           = errors.New("short balance")
+	ErrInvalidParam           = errors.New("invalid param")
+	ErrExpiredTransaction     = errors.New("expired transaction")
+	ErrIdempotencyKeyMismatch = errors.New("idempotency key is already used with a different request")
+	ErrTransactionMismatch    = errors.New("request does not match the transaction")
+	ErrIllegalTransition      = errors.New("illegal status transition")
+	ErrUnbalancedEntry        = errors.New("unbalanced journal entry")
+	ErrLedgerInconsistent     = errors.New("ledger is inconsistent")
+	ErrAmountOverflow         = errors.New("amount overflows")
+	ErrCurrencyMismatch       = errors.New("currencies do not match")
+	ErrExpiredRate            = errors.New("exchange rate has expired")
+	ErrLimitExceeded          = errors.New("spending limit exceeded")
 )
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kawabatas/m-bank/domain/repository (interfaces: BalanceLogRepository)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/kawabatas/m-bank/domain/model"
+)
+
+// MockBalanceLogRepository is a mock of BalanceLogRepository interface.
+type MockBalanceLogRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBalanceLogRepositoryMockRecorder
+}
+
+// MockBalanceLogRepositoryMockRecorder is the mock recorder for MockBalanceLogRepository.
+type MockBalanceLogRepositoryMockRecorder struct {
+	mock *MockBalanceLogRepository
+}
+
+// NewMockBalanceLogRepository creates a new mock instance.
+func NewMockBalanceLogRepository(ctrl *gomock.Controller) *MockBalanceLogRepository {
+	mock := &MockBalanceLogRepository{ctrl: ctrl}
+	mock.recorder = &MockBalanceLogRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBalanceLogRepository) EXPECT() *MockBalanceLogRepositoryMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockBalanceLogRepository) List(arg0 context.Context, arg1 uint, arg2 model.BalanceLogFilter) ([]*model.BalanceLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*model.BalanceLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockBalanceLogRepositoryMockRecorder) List(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockBalanceLogRepository)(nil).List), arg0, arg1, arg2)
+}
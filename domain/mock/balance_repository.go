@@ -7,8 +7,10 @@ package mock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
+	domain "github.com/kawabatas/m-bank/domain"
 	model "github.com/kawabatas/m-bank/domain/model"
 )
 
@@ -36,30 +38,90 @@ func (m *MockBalanceRepository) EXPECT() *MockBalanceRepositoryMockRecorder {
 }
 
 // AddToUsers mocks base method.
-func (m *MockBalanceRepository) AddToUsers(arg0 context.Context, arg1, arg2, arg3 int) error {
+func (m *MockBalanceRepository) AddToUsers(arg0 context.Context, arg1 domain.Currency, arg2 int64, arg3 time.Time, arg4, arg5 int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddToUsers", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "AddToUsers", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddToUsers indicates an expected call of AddToUsers.
-func (mr *MockBalanceRepositoryMockRecorder) AddToUsers(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockBalanceRepositoryMockRecorder) AddToUsers(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToUsers", reflect.TypeOf((*MockBalanceRepository)(nil).AddToUsers), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToUsers", reflect.TypeOf((*MockBalanceRepository)(nil).AddToUsers), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// CountTargets mocks base method.
+func (m *MockBalanceRepository) CountTargets(arg0 context.Context, arg1 domain.Currency, arg2 model.BulkCreditTarget) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTargets", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTargets indicates an expected call of CountTargets.
+func (mr *MockBalanceRepositoryMockRecorder) CountTargets(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTargets", reflect.TypeOf((*MockBalanceRepository)(nil).CountTargets), arg0, arg1, arg2)
 }
 
 // Get mocks base method.
-func (m *MockBalanceRepository) Get(arg0 context.Context, arg1 uint) (*model.Balance, error) {
+func (m *MockBalanceRepository) Get(arg0 context.Context, arg1 uint, arg2 domain.Currency) (*model.Balance, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret := m.ctrl.Call(m, "Get", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*model.Balance)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockBalanceRepositoryMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockBalanceRepositoryMockRecorder) Get(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBalanceRepository)(nil).Get), arg0, arg1, arg2)
+}
+
+// List mocks base method.
+func (m *MockBalanceRepository) List(arg0 context.Context, arg1 uint) ([]*model.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*model.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockBalanceRepositoryMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockBalanceRepository)(nil).List), arg0, arg1)
+}
+
+// ListTargets mocks base method.
+func (m *MockBalanceRepository) ListTargets(arg0 context.Context, arg1 domain.Currency, arg2 model.BulkCreditTarget, arg3 uint, arg4 int) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTargets", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTargets indicates an expected call of ListTargets.
+func (mr *MockBalanceRepositoryMockRecorder) ListTargets(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTargets", reflect.TypeOf((*MockBalanceRepository)(nil).ListTargets), arg0, arg1, arg2, arg3, arg4)
+}
+
+// SetOverdraftLimit mocks base method.
+func (m *MockBalanceRepository) SetOverdraftLimit(arg0 context.Context, arg1 uint, arg2 domain.Currency, arg3 int64) (*model.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOverdraftLimit", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetOverdraftLimit indicates an expected call of SetOverdraftLimit.
+func (mr *MockBalanceRepositoryMockRecorder) SetOverdraftLimit(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBalanceRepository)(nil).Get), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOverdraftLimit", reflect.TypeOf((*MockBalanceRepository)(nil).SetOverdraftLimit), arg0, arg1, arg2, arg3)
 }
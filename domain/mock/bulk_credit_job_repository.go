@@ -0,0 +1,157 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kawabatas/m-bank/domain/repository (interfaces: BulkCreditJobRepository)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/kawabatas/m-bank/domain"
+	model "github.com/kawabatas/m-bank/domain/model"
+)
+
+// MockBulkCreditJobRepository is a mock of BulkCreditJobRepository interface.
+type MockBulkCreditJobRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBulkCreditJobRepositoryMockRecorder
+}
+
+// MockBulkCreditJobRepositoryMockRecorder is the mock recorder for MockBulkCreditJobRepository.
+type MockBulkCreditJobRepositoryMockRecorder struct {
+	mock *MockBulkCreditJobRepository
+}
+
+// NewMockBulkCreditJobRepository creates a new mock instance.
+func NewMockBulkCreditJobRepository(ctrl *gomock.Controller) *MockBulkCreditJobRepository {
+	mock := &MockBulkCreditJobRepository{ctrl: ctrl}
+	mock.recorder = &MockBulkCreditJobRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBulkCreditJobRepository) EXPECT() *MockBulkCreditJobRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockBulkCreditJobRepository) Create(arg0 context.Context, arg1 string, arg2 domain.Currency, arg3 int64, arg4 model.BulkCreditTarget) (*model.BulkCreditJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*model.BulkCreditJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockBulkCreditJobRepositoryMockRecorder) Create(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBulkCreditJobRepository)(nil).Create), arg0, arg1, arg2, arg3, arg4)
+}
+
+// Get mocks base method.
+func (m *MockBulkCreditJobRepository) Get(arg0 context.Context, arg1 uint64) (*model.BulkCreditJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*model.BulkCreditJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockBulkCreditJobRepositoryMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBulkCreditJobRepository)(nil).Get), arg0, arg1)
+}
+
+// GetByIdempotencyKey mocks base method.
+func (m *MockBulkCreditJobRepository) GetByIdempotencyKey(arg0 context.Context, arg1 string) (*model.BulkCreditJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(*model.BulkCreditJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIdempotencyKey indicates an expected call of GetByIdempotencyKey.
+func (mr *MockBulkCreditJobRepositoryMockRecorder) GetByIdempotencyKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIdempotencyKey", reflect.TypeOf((*MockBulkCreditJobRepository)(nil).GetByIdempotencyKey), arg0, arg1)
+}
+
+// ListFailedItems mocks base method.
+func (m *MockBulkCreditJobRepository) ListFailedItems(arg0 context.Context, arg1 uint64, arg2 int) ([]*model.BulkCreditItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFailedItems", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*model.BulkCreditItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFailedItems indicates an expected call of ListFailedItems.
+func (mr *MockBulkCreditJobRepositoryMockRecorder) ListFailedItems(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFailedItems", reflect.TypeOf((*MockBulkCreditJobRepository)(nil).ListFailedItems), arg0, arg1, arg2)
+}
+
+// ListSkippedReversalItems mocks base method.
+func (m *MockBulkCreditJobRepository) ListSkippedReversalItems(arg0 context.Context, arg1 uint64, arg2 int) ([]*model.BulkCreditItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSkippedReversalItems", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*model.BulkCreditItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSkippedReversalItems indicates an expected call of ListSkippedReversalItems.
+func (mr *MockBulkCreditJobRepositoryMockRecorder) ListSkippedReversalItems(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSkippedReversalItems", reflect.TypeOf((*MockBulkCreditJobRepository)(nil).ListSkippedReversalItems), arg0, arg1, arg2)
+}
+
+// ListUnfinished mocks base method.
+func (m *MockBulkCreditJobRepository) ListUnfinished(arg0 context.Context, arg1 int) ([]*model.BulkCreditJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnfinished", arg0, arg1)
+	ret0, _ := ret[0].([]*model.BulkCreditJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnfinished indicates an expected call of ListUnfinished.
+func (mr *MockBulkCreditJobRepositoryMockRecorder) ListUnfinished(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnfinished", reflect.TypeOf((*MockBulkCreditJobRepository)(nil).ListUnfinished), arg0, arg1)
+}
+
+// ProcessChunk mocks base method.
+func (m *MockBulkCreditJobRepository) ProcessChunk(arg0 context.Context, arg1 uint64, arg2 int) (*model.BulkCreditJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessChunk", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.BulkCreditJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessChunk indicates an expected call of ProcessChunk.
+func (mr *MockBulkCreditJobRepositoryMockRecorder) ProcessChunk(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessChunk", reflect.TypeOf((*MockBulkCreditJobRepository)(nil).ProcessChunk), arg0, arg1, arg2)
+}
+
+// RequestReversal mocks base method.
+func (m *MockBulkCreditJobRepository) RequestReversal(arg0 context.Context, arg1 uint64, arg2 model.BulkCreditReversalMode) (*model.BulkCreditJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestReversal", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.BulkCreditJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestReversal indicates an expected call of RequestReversal.
+func (mr *MockBulkCreditJobRepositoryMockRecorder) RequestReversal(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestReversal", reflect.TypeOf((*MockBulkCreditJobRepository)(nil).RequestReversal), arg0, arg1, arg2)
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kawabatas/m-bank/domain/repository (interfaces: ExchangeRepository)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/kawabatas/m-bank/domain/model"
+)
+
+// MockExchangeRepository is a mock of ExchangeRepository interface.
+type MockExchangeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeRepositoryMockRecorder
+}
+
+// MockExchangeRepositoryMockRecorder is the mock recorder for MockExchangeRepository.
+type MockExchangeRepositoryMockRecorder struct {
+	mock *MockExchangeRepository
+}
+
+// NewMockExchangeRepository creates a new mock instance.
+func NewMockExchangeRepository(ctrl *gomock.Controller) *MockExchangeRepository {
+	mock := &MockExchangeRepository{ctrl: ctrl}
+	mock.recorder = &MockExchangeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeRepository) EXPECT() *MockExchangeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Exchange mocks base method.
+func (m *MockExchangeRepository) Exchange(arg0 context.Context, arg1 string, arg2 uint, arg3 uint64, arg4 int64, arg5 time.Time) (*model.Exchange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exchange", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(*model.Exchange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exchange indicates an expected call of Exchange.
+func (mr *MockExchangeRepositoryMockRecorder) Exchange(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockExchangeRepository)(nil).Exchange), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// Get mocks base method.
+func (m *MockExchangeRepository) Get(arg0 context.Context, arg1 string) (*model.Exchange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*model.Exchange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockExchangeRepositoryMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockExchangeRepository)(nil).Get), arg0, arg1)
+}
@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kawabatas/m-bank/domain/repository (interfaces: LedgerRepository)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/kawabatas/m-bank/domain/model"
+)
+
+// MockLedgerRepository is a mock of LedgerRepository interface.
+type MockLedgerRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLedgerRepositoryMockRecorder
+}
+
+// MockLedgerRepositoryMockRecorder is the mock recorder for MockLedgerRepository.
+type MockLedgerRepositoryMockRecorder struct {
+	mock *MockLedgerRepository
+}
+
+// NewMockLedgerRepository creates a new mock instance.
+func NewMockLedgerRepository(ctrl *gomock.Controller) *MockLedgerRepository {
+	mock := &MockLedgerRepository{ctrl: ctrl}
+	mock.recorder = &MockLedgerRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLedgerRepository) EXPECT() *MockLedgerRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CheckInvariants mocks base method.
+func (m *MockLedgerRepository) CheckInvariants(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckInvariants", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckInvariants indicates an expected call of CheckInvariants.
+func (mr *MockLedgerRepositoryMockRecorder) CheckInvariants(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckInvariants", reflect.TypeOf((*MockLedgerRepository)(nil).CheckInvariants), arg0)
+}
+
+// ListBySource mocks base method.
+func (m *MockLedgerRepository) ListBySource(arg0 context.Context, arg1, arg2 string) ([]*model.JournalEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBySource", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*model.JournalEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBySource indicates an expected call of ListBySource.
+func (mr *MockLedgerRepositoryMockRecorder) ListBySource(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBySource", reflect.TypeOf((*MockLedgerRepository)(nil).ListBySource), arg0, arg1, arg2)
+}
+
+// Post mocks base method.
+func (m *MockLedgerRepository) Post(arg0 context.Context, arg1 *model.JournalEntry) (*model.JournalEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", arg0, arg1)
+	ret0, _ := ret[0].(*model.JournalEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockLedgerRepositoryMockRecorder) Post(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockLedgerRepository)(nil).Post), arg0, arg1)
+}
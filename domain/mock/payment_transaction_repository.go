@@ -7,8 +7,10 @@ package mock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
+	domain "github.com/kawabatas/m-bank/domain"
 	model "github.com/kawabatas/m-bank/domain/model"
 )
 
@@ -65,6 +67,21 @@ func (mr *MockPaymentTransactionRepositoryMockRecorder) Confirm(arg0, arg1 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockPaymentTransactionRepository)(nil).Confirm), arg0, arg1)
 }
 
+// ExpireTries mocks base method.
+func (m *MockPaymentTransactionRepository) ExpireTries(arg0 context.Context, arg1 time.Time, arg2 int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireTries", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireTries indicates an expected call of ExpireTries.
+func (mr *MockPaymentTransactionRepositoryMockRecorder) ExpireTries(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireTries", reflect.TypeOf((*MockPaymentTransactionRepository)(nil).ExpireTries), arg0, arg1, arg2)
+}
+
 // Get mocks base method.
 func (m *MockPaymentTransactionRepository) Get(arg0 context.Context, arg1 string) (*model.PaymentTransaction, error) {
 	m.ctrl.T.Helper()
@@ -80,17 +97,32 @@ func (mr *MockPaymentTransactionRepositoryMockRecorder) Get(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPaymentTransactionRepository)(nil).Get), arg0, arg1)
 }
 
+// List mocks base method.
+func (m *MockPaymentTransactionRepository) List(arg0 context.Context, arg1 uint, arg2 model.PaymentTransactionFilter) ([]*model.PaymentTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*model.PaymentTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockPaymentTransactionRepositoryMockRecorder) List(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPaymentTransactionRepository)(nil).List), arg0, arg1, arg2)
+}
+
 // Try mocks base method.
-func (m *MockPaymentTransactionRepository) Try(arg0 context.Context, arg1 string, arg2 uint, arg3 int) (*model.PaymentTransaction, error) {
+func (m *MockPaymentTransactionRepository) Try(arg0 context.Context, arg1 string, arg2 uint, arg3 domain.Currency, arg4 int64, arg5 time.Duration) (*model.PaymentTransaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Try", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "Try", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(*model.PaymentTransaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Try indicates an expected call of Try.
-func (mr *MockPaymentTransactionRepositoryMockRecorder) Try(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockPaymentTransactionRepositoryMockRecorder) Try(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Try", reflect.TypeOf((*MockPaymentTransactionRepository)(nil).Try), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Try", reflect.TypeOf((*MockPaymentTransactionRepository)(nil).Try), arg0, arg1, arg2, arg3, arg4, arg5)
 }
@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kawabatas/m-bank/domain/repository (interfaces: PointLotRepository)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/kawabatas/m-bank/domain/model"
+)
+
+// MockPointLotRepository is a mock of PointLotRepository interface.
+type MockPointLotRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPointLotRepositoryMockRecorder
+}
+
+// MockPointLotRepositoryMockRecorder is the mock recorder for MockPointLotRepository.
+type MockPointLotRepositoryMockRecorder struct {
+	mock *MockPointLotRepository
+}
+
+// NewMockPointLotRepository creates a new mock instance.
+func NewMockPointLotRepository(ctrl *gomock.Controller) *MockPointLotRepository {
+	mock := &MockPointLotRepository{ctrl: ctrl}
+	mock.recorder = &MockPointLotRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPointLotRepository) EXPECT() *MockPointLotRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ExpireLots mocks base method.
+func (m *MockPointLotRepository) ExpireLots(arg0 context.Context, arg1 time.Time, arg2 int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireLots", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireLots indicates an expected call of ExpireLots.
+func (mr *MockPointLotRepositoryMockRecorder) ExpireLots(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireLots", reflect.TypeOf((*MockPointLotRepository)(nil).ExpireLots), arg0, arg1, arg2)
+}
+
+// ListByUser mocks base method.
+func (m *MockPointLotRepository) ListByUser(arg0 context.Context, arg1 uint) ([]*model.PointLot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", arg0, arg1)
+	ret0, _ := ret[0].([]*model.PointLot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockPointLotRepositoryMockRecorder) ListByUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockPointLotRepository)(nil).ListByUser), arg0, arg1)
+}
+
+// ListExpirations mocks base method.
+func (m *MockPointLotRepository) ListExpirations(arg0 context.Context, arg1 uint, arg2 time.Time, arg3 int) ([]*model.PointExpiration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpirations", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*model.PointExpiration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpirations indicates an expected call of ListExpirations.
+func (mr *MockPointLotRepositoryMockRecorder) ListExpirations(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpirations", reflect.TypeOf((*MockPointLotRepository)(nil).ListExpirations), arg0, arg1, arg2, arg3)
+}
@@ -0,0 +1,83 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kawabatas/m-bank/domain/repository (interfaces: RefundRepository)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/kawabatas/m-bank/domain/model"
+)
+
+// MockRefundRepository is a mock of RefundRepository interface.
+type MockRefundRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefundRepositoryMockRecorder
+}
+
+// MockRefundRepositoryMockRecorder is the mock recorder for MockRefundRepository.
+type MockRefundRepositoryMockRecorder struct {
+	mock *MockRefundRepository
+}
+
+// NewMockRefundRepository creates a new mock instance.
+func NewMockRefundRepository(ctrl *gomock.Controller) *MockRefundRepository {
+	mock := &MockRefundRepository{ctrl: ctrl}
+	mock.recorder = &MockRefundRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefundRepository) EXPECT() *MockRefundRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockRefundRepository) Get(arg0 context.Context, arg1 string) (*model.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*model.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRefundRepositoryMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRefundRepository)(nil).Get), arg0, arg1)
+}
+
+// ListByPayment mocks base method.
+func (m *MockRefundRepository) ListByPayment(arg0 context.Context, arg1 string) ([]*model.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByPayment", arg0, arg1)
+	ret0, _ := ret[0].([]*model.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByPayment indicates an expected call of ListByPayment.
+func (mr *MockRefundRepositoryMockRecorder) ListByPayment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByPayment", reflect.TypeOf((*MockRefundRepository)(nil).ListByPayment), arg0, arg1)
+}
+
+// Refund mocks base method.
+func (m *MockRefundRepository) Refund(arg0 context.Context, arg1, arg2 string, arg3 int64, arg4 time.Time) (*model.Refund, *model.PaymentTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*model.Refund)
+	ret1, _ := ret[1].(*model.PaymentTransaction)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockRefundRepositoryMockRecorder) Refund(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockRefundRepository)(nil).Refund), arg0, arg1, arg2, arg3, arg4)
+}
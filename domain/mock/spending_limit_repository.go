@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kawabatas/m-bank/domain/repository (interfaces: SpendingLimitRepository)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/kawabatas/m-bank/domain/model"
+)
+
+// MockSpendingLimitRepository is a mock of SpendingLimitRepository interface.
+type MockSpendingLimitRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpendingLimitRepositoryMockRecorder
+}
+
+// MockSpendingLimitRepositoryMockRecorder is the mock recorder for MockSpendingLimitRepository.
+type MockSpendingLimitRepositoryMockRecorder struct {
+	mock *MockSpendingLimitRepository
+}
+
+// NewMockSpendingLimitRepository creates a new mock instance.
+func NewMockSpendingLimitRepository(ctrl *gomock.Controller) *MockSpendingLimitRepository {
+	mock := &MockSpendingLimitRepository{ctrl: ctrl}
+	mock.recorder = &MockSpendingLimitRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSpendingLimitRepository) EXPECT() *MockSpendingLimitRepositoryMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockSpendingLimitRepository) List(arg0 context.Context, arg1 model.SpendingLimitScope, arg2 string) ([]*model.SpendingLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*model.SpendingLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockSpendingLimitRepositoryMockRecorder) List(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSpendingLimitRepository)(nil).List), arg0, arg1, arg2)
+}
+
+// Set mocks base method.
+func (m *MockSpendingLimitRepository) Set(arg0 context.Context, arg1 *model.SpendingLimit) (*model.SpendingLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", arg0, arg1)
+	ret0, _ := ret[0].(*model.SpendingLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockSpendingLimitRepositoryMockRecorder) Set(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockSpendingLimitRepository)(nil).Set), arg0, arg1)
+}
+
+// SetUserTier mocks base method.
+func (m *MockSpendingLimitRepository) SetUserTier(arg0 context.Context, arg1 uint, arg2 string) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserTier", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserTier indicates an expected call of SetUserTier.
+func (mr *MockSpendingLimitRepositoryMockRecorder) SetUserTier(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserTier", reflect.TypeOf((*MockSpendingLimitRepository)(nil).SetUserTier), arg0, arg1, arg2)
+}
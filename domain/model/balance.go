@@ -1,6 +1,64 @@
 package model
 
+import (
+	"math"
+
+	"github.com/kawabatas/m-bank/domain"
+)
+
+// Balance is the wallet of a user in one currency. A user holds a wallet for each currency.
 type Balance struct {
-	UserID uint
-	Amount uint
+	UserID         uint
+	Currency       domain.Currency
+	Amount         int64 // 補助単位での残高。キャンペーンの取り消しなどで負になることがある
+	ReservedAmount int64 // Try済みで未確定の減算額（仮押さえ）
+	OverdraftLimit int64 // 承認された与信枠。残高はこの額まで負になれる
+	// Expirations are the upcoming expirations of the points from the nearest one.
+	// They are only loaded for the point wallets returned by the balance API.
+	Expirations []*PointExpiration
+}
+
+// AvailableAmount returns the amount which can be debited, i.e. the balance and the overdraft limit
+// less the amount reserved by tried payments. It is zero when the overdraft limit is used up or fully reserved.
+// The sum is capped at math.MaxInt64 instead of wrapping around when the limit is huge.
+func (b *Balance) AvailableAmount() int64 {
+	total, err := b.Money().Add(domain.NewMoney(b.OverdraftLimit, b.Currency))
+	if err != nil {
+		// 与信枠は負にならないので、溢れるのは上限を超えた場合だけ
+		return available(math.MaxInt64, b.ReservedAmount)
+	}
+	return available(total.Amount, b.ReservedAmount)
+}
+
+// OwnAvailableAmount returns the available amount without the overdraft limit.
+func (b *Balance) OwnAvailableAmount() int64 {
+	return available(b.Amount, b.ReservedAmount)
+}
+
+// IsOverdrawn reports whether the amount of the balance is beyond the overdraft limit of the wallet.
+func (b *Balance) IsOverdrawn(amount int64) bool {
+	return amount < -b.OverdraftLimit
+}
+
+func available(amount, reserved int64) int64 {
+	if amount <= reserved {
+		return 0
+	}
+	return amount - reserved
+}
+
+// Money returns the balance with its currency.
+func (b *Balance) Money() domain.Money {
+	return domain.NewMoney(b.Amount, b.Currency)
+}
+
+// Key returns the key of the wallet.
+func (b *Balance) Key() WalletKey {
+	return WalletKey{UserID: b.UserID, Currency: b.Currency}
+}
+
+// WalletKey identifies the wallet of a user in a currency.
+type WalletKey struct {
+	UserID   uint
+	Currency domain.Currency
 }
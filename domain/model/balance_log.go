@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+)
+
+// BalanceLog is a change of a user's wallet and the operation which caused it.
+type BalanceLog struct {
+	ID           uint64
+	UserID       uint
+	Currency     domain.Currency
+	BeforeAmount int64
+	AfterAmount  int64
+	Delta        int64
+	SourceType   string // 残高を変更した操作の種類（JournalSource*）
+	SourceID     string // 残高を変更した操作のID（取引のUUIDなど）
+	Reason       string
+	CreateTime   time.Time
+}
+
+// BalanceLogFilter narrows down balance logs. Logs are listed from the newest one.
+type BalanceLogFilter struct {
+	Currency domain.Currency // ゼロ値なら全通貨
+	From     time.Time       // この時刻以降（ゼロ値なら指定なし）
+	To       time.Time       // この時刻より前（ゼロ値なら指定なし）
+	BeforeID uint64          // このIDより前のログ（ゼロ値なら最新から）
+	Limit    int
+}
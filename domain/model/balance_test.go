@@ -0,0 +1,29 @@
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kawabatas/m-bank/domain"
+)
+
+func TestBalance_AvailableAmount(t *testing.T) {
+	tests := []struct {
+		name    string
+		balance Balance
+		want    int64
+	}{
+		{"仮押さえ分を除く", Balance{Currency: domain.JPY, Amount: 1000, ReservedAmount: 300}, 700},
+		{"与信枠を含む", Balance{Currency: domain.JPY, Amount: -500, OverdraftLimit: 1000, ReservedAmount: 200}, 300},
+		{"与信枠を使い切っている", Balance{Currency: domain.JPY, Amount: -1000, OverdraftLimit: 1000}, 0},
+		{"仮押さえが上回る", Balance{Currency: domain.JPY, Amount: 100, ReservedAmount: 300}, 0},
+		{"与信枠を足すと溢れる場合は上限で打ち止める", Balance{Currency: domain.JPY, Amount: 1, OverdraftLimit: math.MaxInt64, ReservedAmount: 10}, math.MaxInt64 - 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.balance.AvailableAmount(); got != tt.want {
+				t.Errorf("Balance.AvailableAmount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
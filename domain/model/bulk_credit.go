@@ -0,0 +1,212 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+)
+
+// BulkCreditStatus is the state of a bulk credit job.
+type BulkCreditStatus string
+
+const (
+	BulkCreditStatusPending   BulkCreditStatus = "pending"
+	BulkCreditStatusRunning   BulkCreditStatus = "running"
+	BulkCreditStatusCompleted BulkCreditStatus = "completed"
+)
+
+// BulkCreditJob credits the same amount to the target users, processing them in chunks ordered by user id.
+type BulkCreditJob struct {
+	ID                 uint64
+	IdempotencyKey     string
+	RequestFingerprint string          // 同じ冪等性キーのリクエストが同じ内容かどうかの判定に使う
+	Currency           domain.Currency // 加算するウォレットの通貨
+	Amount             int64           // 補助単位での各ユーザへの加算額
+	Target             BulkCreditTarget
+	Status             BulkCreditStatus
+	CursorUserID       uint // 処理済みの最後のuser_id。次のチャンクはこれより大きいuser_idから
+	TotalCount         int  // ジョブ作成時点の対象ユーザ数
+	CreditedCount      int
+	FailedCount        int
+	LastError          string // 直近のチャンクの処理に失敗した理由。リトライで成功すると空になる
+	CreateTime         time.Time
+	UpdateTime         time.Time
+	FinishTime         time.Time
+
+	ReversalStatus       BulkCreditStatus // 取り消しの状態（取り消されていなければ空）
+	ReversalMode         BulkCreditReversalMode
+	ReversalCursorUserID uint // 取り消し済みの最後のuser_id
+	ReversedCount        int  // 減算したユーザ数（clampで一部だけ減算したユーザを含む）
+	ReversalSkippedCount int  // skipで減算しなかったユーザ数
+	ReversedAmount       int64
+	ReversalRequestTime  time.Time
+	ReversalFinishTime   time.Time
+}
+
+func NewBulkCreditJob(idempotencyKey string, currency domain.Currency, amount int64, target BulkCreditTarget, totalCount int) *BulkCreditJob {
+	now := time.Now()
+	return &BulkCreditJob{
+		IdempotencyKey:     idempotencyKey,
+		RequestFingerprint: BulkCreditRequestFingerprint(amount, target),
+		Currency:           currency,
+		Amount:             amount,
+		Target:             target,
+		Status:             BulkCreditStatusPending,
+		TotalCount:         totalCount,
+		CreateTime:         now,
+		UpdateTime:         now,
+	}
+}
+
+// BulkCreditRequestFingerprint returns the digest of the bulk credit request which is bound to an idempotency key.
+func BulkCreditRequestFingerprint(amount int64, target BulkCreditTarget) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%s", amount, target)))
+	return hex.EncodeToString(sum[:])
+}
+
+// MatchesRequest reports whether the request is identical to the one which created the job.
+// The currency is compared with the stored one because it is not a part of the fingerprint.
+func (j *BulkCreditJob) MatchesRequest(currency domain.Currency, amount int64, target BulkCreditTarget) bool {
+	return j.Currency == currency && j.RequestFingerprint == BulkCreditRequestFingerprint(amount, target)
+}
+
+// TotalAmount returns the amount which will be credited when all the target users are credited.
+// It returns domain.ErrAmountOverflow when the total does not fit in 64 bits.
+func (j *BulkCreditJob) TotalAmount() (int64, error) {
+	total, err := domain.NewMoney(j.Amount, j.Currency).Mul(int64(j.TotalCount))
+	if err != nil {
+		return 0, err
+	}
+	return total.Amount, nil
+}
+
+// ProcessedCount returns the number of users which have been credited or have failed.
+func (j *BulkCreditJob) ProcessedCount() int {
+	return j.CreditedCount + j.FailedCount
+}
+
+// IsFinished reports whether all the users have been processed, including the reversal when it is requested.
+func (j *BulkCreditJob) IsFinished() bool {
+	return j.Status == BulkCreditStatusCompleted && !j.IsReversing()
+}
+
+// IsReversing reports whether the reversal is requested and not completed yet.
+func (j *BulkCreditJob) IsReversing() bool {
+	return j.ReversalStatus == BulkCreditStatusPending || j.ReversalStatus == BulkCreditStatusRunning
+}
+
+// Advance records the result of a processed chunk whose last user is lastUserID.
+func (j *BulkCreditJob) Advance(lastUserID uint, credited, failed int, now time.Time) {
+	j.Status = BulkCreditStatusRunning
+	j.CursorUserID = lastUserID
+	j.CreditedCount += credited
+	j.FailedCount += failed
+	j.LastError = ""
+	j.UpdateTime = now
+}
+
+// Complete marks the job as completed when no users are left.
+func (j *BulkCreditJob) Complete(now time.Time) {
+	j.Status = BulkCreditStatusCompleted
+	j.LastError = ""
+	j.UpdateTime = now
+	j.FinishTime = now
+}
+
+// RequestReversal requests to debit what the completed job credited. Requesting it again with the same mode does nothing.
+func (j *BulkCreditJob) RequestReversal(mode BulkCreditReversalMode, now time.Time) error {
+	if j.ReversalStatus != "" {
+		if j.ReversalMode != mode {
+			return fmt.Errorf("%w: bulk credit job is already reversed with mode %s", domain.ErrIllegalTransition, j.ReversalMode)
+		}
+		return nil
+	}
+	if j.Status != BulkCreditStatusCompleted {
+		return fmt.Errorf("%w: bulk credit job is %s", domain.ErrIllegalTransition, j.Status)
+	}
+	j.ReversalStatus = BulkCreditStatusPending
+	j.ReversalMode = mode
+	j.ReversalRequestTime = now
+	j.UpdateTime = now
+	return nil
+}
+
+// AdvanceReversal records the result of a reversed chunk whose last user is lastUserID.
+func (j *BulkCreditJob) AdvanceReversal(lastUserID uint, reversed, skipped int, amount int64, now time.Time) {
+	j.ReversalStatus = BulkCreditStatusRunning
+	j.ReversalCursorUserID = lastUserID
+	j.ReversedCount += reversed
+	j.ReversalSkippedCount += skipped
+	j.ReversedAmount += amount
+	j.LastError = ""
+	j.UpdateTime = now
+}
+
+// CompleteReversal marks the reversal as completed when no credited users are left.
+func (j *BulkCreditJob) CompleteReversal(now time.Time) {
+	j.ReversalStatus = BulkCreditStatusCompleted
+	j.LastError = ""
+	j.UpdateTime = now
+	j.ReversalFinishTime = now
+}
+
+// BulkCreditReversalMode is how a reversal treats a user whose available amount no longer covers the credited amount.
+type BulkCreditReversalMode string
+
+const (
+	BulkCreditReversalClamp         BulkCreditReversalMode = "clamp"          // 利用可能額までだけ減算する
+	BulkCreditReversalAllowNegative BulkCreditReversalMode = "allow_negative" // 全額を減算し、残高が負になることを許す
+	BulkCreditReversalSkip          BulkCreditReversalMode = "skip"           // 減算せずにスキップとして報告する
+)
+
+func (m BulkCreditReversalMode) IsValid() bool {
+	switch m {
+	case BulkCreditReversalClamp, BulkCreditReversalAllowNegative, BulkCreditReversalSkip:
+		return true
+	}
+	return false
+}
+
+// Reverse returns how much of the credited amount is debited from a user who has the available amount.
+func (m BulkCreditReversalMode) Reverse(credited, available int64) (BulkCreditItemReversalStatus, int64) {
+	if available >= credited || m == BulkCreditReversalAllowNegative {
+		return BulkCreditItemReversed, credited
+	}
+	if m == BulkCreditReversalSkip {
+		return BulkCreditItemReversalSkipped, 0
+	}
+	return BulkCreditItemReversalClamped, available
+}
+
+// BulkCreditItemStatus is the result of crediting one user in a bulk credit job.
+type BulkCreditItemStatus string
+
+const (
+	BulkCreditItemStatusCredited BulkCreditItemStatus = "credited"
+	BulkCreditItemStatusFailed   BulkCreditItemStatus = "failed"
+)
+
+// BulkCreditItem is the marker of a user processed by a bulk credit job. A user has at most one item per job,
+// so that a retried chunk never credits the user twice.
+type BulkCreditItem struct {
+	JobID      uint64
+	UserID     uint
+	Status     BulkCreditItemStatus
+	Error      string // 失敗した理由
+	CreateTime time.Time
+
+	ReversalStatus BulkCreditItemReversalStatus // 取り消しの結果（取り消していなければ空）
+	ReversedAmount int64
+}
+
+// BulkCreditItemReversalStatus is the result of reversing the credit of one user.
+type BulkCreditItemReversalStatus string
+
+const (
+	BulkCreditItemReversed        BulkCreditItemReversalStatus = "reversed" // 加算した全額を減算した
+	BulkCreditItemReversalClamped BulkCreditItemReversalStatus = "clamped"  // 利用可能額までだけ減算した
+	BulkCreditItemReversalSkipped BulkCreditItemReversalStatus = "skipped"  // 利用可能額が足りず減算しなかった
+)
@@ -0,0 +1,160 @@
+package model
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+)
+
+// BulkCreditTargetType is how the users credited by a bulk credit job are selected.
+type BulkCreditTargetType string
+
+const (
+	BulkCreditTargetAll            BulkCreditTargetType = "all"
+	BulkCreditTargetUserIDs        BulkCreditTargetType = "user_ids"
+	BulkCreditTargetCreatedBetween BulkCreditTargetType = "created_between"
+	BulkCreditTargetBalanceBelow   BulkCreditTargetType = "balance_below"
+)
+
+// MaxBulkCreditTargetUserIDs is the maximum number of users which can be listed explicitly.
+const MaxBulkCreditTargetUserIDs = 10000
+
+// BulkCreditTarget selects the users credited by a bulk credit job.
+// Users are always processed in ascending user id order regardless of the type.
+type BulkCreditTarget struct {
+	Type         BulkCreditTargetType
+	UserIDs      []uint    // user_ids: 対象のユーザ（Normalize後は昇順で重複なし）
+	CreatedFrom  time.Time // created_between: この時刻以降に作成されたユーザ（ゼロ値なら指定なし）
+	CreatedTo    time.Time // created_between: この時刻より前に作成されたユーザ（ゼロ値なら指定なし）
+	BalanceBelow int64     // balance_below: 処理する時点の残高がこの値未満のユーザ
+}
+
+// Normalize validates the target, defaulting the type to all and sorting the user ids.
+func (t *BulkCreditTarget) Normalize() error {
+	if t.Type == "" {
+		t.Type = BulkCreditTargetAll
+	}
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: target %s: %s", domain.ErrInvalidParam, t.Type, fmt.Sprintf(format, args...))
+	}
+	// 種類ごとに使わない条件が指定されていたら、指定の誤りとしてエラーにする
+	if t.Type != BulkCreditTargetUserIDs && len(t.UserIDs) > 0 {
+		return invalid("user_ids cannot be specified")
+	}
+	if t.Type != BulkCreditTargetCreatedBetween && (!t.CreatedFrom.IsZero() || !t.CreatedTo.IsZero()) {
+		return invalid("created_from and created_to cannot be specified")
+	}
+	if t.Type != BulkCreditTargetBalanceBelow && t.BalanceBelow != 0 {
+		return invalid("balance_below cannot be specified")
+	}
+
+	switch t.Type {
+	case BulkCreditTargetAll:
+	case BulkCreditTargetUserIDs:
+		if len(t.UserIDs) == 0 {
+			return invalid("user_ids is required")
+		}
+		seen := make(map[uint]bool, len(t.UserIDs))
+		userIDs := make([]uint, 0, len(t.UserIDs))
+		for _, userID := range t.UserIDs {
+			if userID == 0 {
+				return invalid("user id must be positive")
+			}
+			if !seen[userID] {
+				seen[userID] = true
+				userIDs = append(userIDs, userID)
+			}
+		}
+		if len(userIDs) > MaxBulkCreditTargetUserIDs {
+			return invalid("at most %d users can be specified", MaxBulkCreditTargetUserIDs)
+		}
+		sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+		t.UserIDs = userIDs
+	case BulkCreditTargetCreatedBetween:
+		if t.CreatedFrom.IsZero() && t.CreatedTo.IsZero() {
+			return invalid("created_from or created_to is required")
+		}
+		if !t.CreatedFrom.IsZero() && !t.CreatedTo.IsZero() && !t.CreatedFrom.Before(t.CreatedTo) {
+			return invalid("created_from must be before created_to")
+		}
+	case BulkCreditTargetBalanceBelow:
+		if t.BalanceBelow <= 0 {
+			return invalid("balance_below must be positive")
+		}
+	default:
+		return fmt.Errorf("%w: unknown target type %q", domain.ErrInvalidParam, t.Type)
+	}
+	return nil
+}
+
+// UserIDsAfter returns at most limit user ids of a user_ids target which are greater than userID.
+func (t BulkCreditTarget) UserIDsAfter(userID uint, limit int) []uint {
+	i := sort.Search(len(t.UserIDs), func(i int) bool { return t.UserIDs[i] > userID })
+	j := i + limit
+	if j > len(t.UserIDs) {
+		j = len(t.UserIDs)
+	}
+	return t.UserIDs[i:j]
+}
+
+func (t BulkCreditTarget) String() string {
+	switch t.Type {
+	case BulkCreditTargetUserIDs:
+		ids := make([]string, 0, len(t.UserIDs))
+		for _, userID := range t.UserIDs {
+			ids = append(ids, strconv.FormatUint(uint64(userID), 10))
+		}
+		return fmt.Sprintf("%s:%s", t.Type, strings.Join(ids, ","))
+	case BulkCreditTargetCreatedBetween:
+		return fmt.Sprintf("%s:%d,%d", t.Type, unixOrZero(t.CreatedFrom), unixOrZero(t.CreatedTo))
+	case BulkCreditTargetBalanceBelow:
+		return fmt.Sprintf("%s:%d", t.Type, t.BalanceBelow)
+	}
+	return string(t.Type)
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// ParseUserIDsCSV reads user ids from the first column of a CSV. The first row is skipped when it is a header.
+func ParseUserIDsCSV(r io.Reader) ([]uint, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var userIDs []uint
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: csv: %v", domain.ErrInvalidParam, err)
+		}
+		value := strings.TrimSpace(record[0])
+		if value == "" {
+			continue
+		}
+		userID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			// 1行目は「user_id」などの見出しとみなす
+			if line == 1 {
+				continue
+			}
+			return nil, fmt.Errorf("%w: csv: line %d: invalid user id %q", domain.ErrInvalidParam, line, value)
+		}
+		userIDs = append(userIDs, uint(userID))
+	}
+	return userIDs, nil
+}
@@ -0,0 +1,121 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+)
+
+// ExchangeRate is a rate to exchange one currency for another, which is valid in a time window.
+type ExchangeRate struct {
+	ID           uint64
+	FromCurrency domain.Currency
+	ToCurrency   domain.Currency
+	Rate         string              // 両替元の通貨1単位あたりの両替先の通貨の額（10進数の文字列）
+	Rounding     domain.RoundingMode // 両替先の通貨の補助単位への丸め方
+	ValidFrom    time.Time
+	ValidTo      time.Time // この時刻以降は両替に使えない
+	CreateTime   time.Time
+}
+
+// Validate checks that the rate is between two supported currencies and has a valid window.
+func (r *ExchangeRate) Validate() error {
+	if _, err := domain.LookupCurrency(string(r.FromCurrency)); err != nil {
+		return err
+	}
+	if _, err := domain.LookupCurrency(string(r.ToCurrency)); err != nil {
+		return err
+	}
+	if r.FromCurrency == r.ToCurrency {
+		return fmt.Errorf("%w: rate from %s to itself", domain.ErrInvalidParam, r.FromCurrency)
+	}
+	if _, err := domain.ParseRate(r.Rate); err != nil {
+		return err
+	}
+	if _, err := domain.LookupRoundingMode(string(r.Rounding)); err != nil {
+		return err
+	}
+	if !r.ValidFrom.Before(r.ValidTo) {
+		return fmt.Errorf("%w: valid_from must be before valid_to", domain.ErrInvalidParam)
+	}
+	return nil
+}
+
+// IsValidAt reports whether the rate can be used at now.
+func (r *ExchangeRate) IsValidAt(now time.Time) bool {
+	return !now.Before(r.ValidFrom) && now.Before(r.ValidTo)
+}
+
+// Convert returns the amount of the to currency for the amount of the from currency, both in the minor unit.
+func (r *ExchangeRate) Convert(amount int64) (int64, error) {
+	rate, err := domain.ParseRate(r.Rate)
+	if err != nil {
+		return 0, err
+	}
+	converted, err := domain.NewMoney(amount, r.FromCurrency).Convert(r.ToCurrency, rate, r.Rounding)
+	if err != nil {
+		return 0, err
+	}
+	return converted.Amount, nil
+}
+
+// Exchange is an exchange of a user's balance from one currency wallet to another at a quoted rate.
+type Exchange struct {
+	UUID         string
+	UserID       uint
+	RateID       uint64
+	FromCurrency domain.Currency
+	FromAmount   int64 // 補助単位での減算額（正の数）
+	ToCurrency   domain.Currency
+	ToAmount     int64 // 補助単位での加算額（正の数）
+	Rate         string
+	Rounding     domain.RoundingMode
+	CreateTime   time.Time
+}
+
+// NewExchange creates an exchange of the amount at the rate, which must be valid at now.
+func NewExchange(uuid string, userID uint, rate *ExchangeRate, amount int64, now time.Time) (*Exchange, error) {
+	if !rate.IsValidAt(now) {
+		return nil, fmt.Errorf("%w: rate %d is valid from %s to %s", domain.ErrExpiredRate, rate.ID, rate.ValidFrom.Format(time.RFC3339), rate.ValidTo.Format(time.RFC3339))
+	}
+	if amount <= 0 {
+		return nil, domain.ErrInvalidParam
+	}
+	toAmount, err := rate.Convert(amount)
+	if err != nil {
+		return nil, err
+	}
+	// 丸めた結果が0になる少額の両替は、減算だけが残るので受け付けない
+	if toAmount <= 0 {
+		return nil, fmt.Errorf("%w: %s is too small to exchange into %s", domain.ErrInvalidParam, domain.NewMoney(amount, rate.FromCurrency), rate.ToCurrency)
+	}
+	return &Exchange{
+		UUID:         uuid,
+		UserID:       userID,
+		RateID:       rate.ID,
+		FromCurrency: rate.FromCurrency,
+		FromAmount:   amount,
+		ToCurrency:   rate.ToCurrency,
+		ToAmount:     toAmount,
+		Rate:         rate.Rate,
+		Rounding:     rate.Rounding,
+		CreateTime:   now,
+	}, nil
+}
+
+// MatchesRequest reports whether the request payload is identical to the one which created the exchange.
+func (e *Exchange) MatchesRequest(userID uint, rateID uint64, fromCurrency, toCurrency domain.Currency, amount int64) bool {
+	return e.UserID == userID && e.RateID == rateID && e.FromCurrency == fromCurrency && e.ToCurrency == toCurrency && e.FromAmount == amount
+}
+
+// JournalEntry returns the entry which records both legs of the exchange against the foreign exchange account.
+func (e *Exchange) JournalEntry() *JournalEntry {
+	user := UserAccount(e.UserID)
+	return NewJournalEntry(JournalSourceExchange, e.UUID,
+		&Posting{Account: user, Currency: e.FromCurrency, Amount: -e.FromAmount},
+		&Posting{Account: AccountForeignExchange, Currency: e.FromCurrency, Amount: e.FromAmount},
+		&Posting{Account: AccountForeignExchange, Currency: e.ToCurrency, Amount: -e.ToAmount},
+		&Posting{Account: user, Currency: e.ToCurrency, Amount: e.ToAmount},
+	)
+}
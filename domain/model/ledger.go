@@ -0,0 +1,146 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+)
+
+// LedgerAccount identifies an account which postings are recorded against.
+type LedgerAccount string
+
+// system accounts which are the counterparts of user accounts.
+const (
+	AccountOpeningBalance     LedgerAccount = "system:opening_balance"
+	AccountCampaignFunding    LedgerAccount = "system:campaign_funding"
+	AccountExternalSettlement LedgerAccount = "system:external_settlement"
+	// 両替で受け取った通貨と支払った通貨を計上する勘定。通貨ごとの合計が両替による持ち高になる
+	AccountForeignExchange LedgerAccount = "system:foreign_exchange"
+	// 失効したポイントを計上する勘定
+	AccountPointExpiration LedgerAccount = "system:point_expiration"
+)
+
+const userAccountPrefix = "user:"
+
+// UserAccount returns the account of the user's balance.
+func UserAccount(userID uint) LedgerAccount {
+	return LedgerAccount(fmt.Sprintf("%s%d", userAccountPrefix, userID))
+}
+
+// UserID returns the user id when the account is a user account.
+func (a LedgerAccount) UserID() (uint, bool) {
+	if !strings.HasPrefix(string(a), userAccountPrefix) {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(strings.TrimPrefix(string(a), userAccountPrefix), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// 仕訳の発生源
+const (
+	JournalSourceOpeningBalance = "opening_balance"
+	JournalSourcePayment        = "payment"
+	JournalSourceTransfer       = "transfer"
+	JournalSourceAddToUsers     = "add_to_users"
+	JournalSourceBulkCredit     = "bulk_credit"
+	// 一括加算の取り消し。source_idは取り消した一括加算のジョブID
+	JournalSourceBulkCreditReversal = "bulk_credit_reversal"
+	JournalSourceExchange           = "exchange"
+	// ポイントの失効。source_idは失効したロットのID
+	JournalSourcePointExpiration = "point_expiration"
+	// 支払いの返金。source_idは返金の冪等性キー
+	JournalSourceRefund = "refund"
+)
+
+// 発生源ごとの残高の増減理由
+var journalReasons = map[string]string{
+	JournalSourceOpeningBalance:     "opening balance",
+	JournalSourcePayment:            "payment confirmed",
+	JournalSourceTransfer:           "transfer confirmed",
+	JournalSourceAddToUsers:         "credited to users",
+	JournalSourceBulkCredit:         "bulk credit",
+	JournalSourceBulkCreditReversal: "bulk credit reversed",
+	JournalSourceExchange:           "currency exchanged",
+	JournalSourcePointExpiration:    "points expired",
+	JournalSourceRefund:             "payment refunded",
+}
+
+// JournalEntry is a set of postings which records one operation on the ledger.
+type JournalEntry struct {
+	ID         uint64
+	SourceType string // 仕訳の発生源の種類
+	SourceID   string // 発生源のID（取引のUUIDなど）
+	Reason     string // 残高の増減理由
+	Postings   []*Posting
+	CreateTime time.Time
+
+	// AllowNegativeBalance lets the entry decrease user balances below zero.
+	// It is not recorded and only affects how the entry is posted.
+	AllowNegativeBalance bool
+	// PointExpireTime is when the points credited by the entry expire. Zero means they never expire.
+	// It is not recorded on the entry but on the point lots which the entry creates.
+	PointExpireTime time.Time
+}
+
+// Posting is a change of an account in a currency. A positive amount increases the account and a negative one decreases it.
+type Posting struct {
+	Account  LedgerAccount
+	Currency domain.Currency
+	Amount   int64 // 補助単位
+}
+
+func NewJournalEntry(sourceType, sourceID string, postings ...*Posting) *JournalEntry {
+	return &JournalEntry{
+		SourceType: sourceType,
+		SourceID:   sourceID,
+		Reason:     journalReasons[sourceType],
+		Postings:   postings,
+		CreateTime: time.Now(),
+	}
+}
+
+// Validate checks that the entry has postings and they sum up to zero in each currency without overflow.
+func (e *JournalEntry) Validate() error {
+	if len(e.Postings) == 0 {
+		return domain.ErrUnbalancedEntry
+	}
+	sums := map[domain.Currency]domain.Money{}
+	for _, p := range e.Postings {
+		if _, err := domain.LookupCurrency(string(p.Currency)); err != nil {
+			return err
+		}
+		sum, err := domain.NewMoney(sums[p.Currency].Amount, p.Currency).Add(domain.NewMoney(p.Amount, p.Currency))
+		if err != nil {
+			return err
+		}
+		sums[p.Currency] = sum
+	}
+	for _, sum := range sums {
+		if !sum.IsZero() {
+			return domain.ErrUnbalancedEntry
+		}
+	}
+	return nil
+}
+
+// UserDeltas returns the sum of the posted amounts for each wallet of the user accounts.
+func (e *JournalEntry) UserDeltas() (map[WalletKey]int64, error) {
+	deltas := map[WalletKey]int64{}
+	for _, p := range e.Postings {
+		if userID, ok := p.Account.UserID(); ok {
+			key := WalletKey{UserID: userID, Currency: p.Currency}
+			delta, err := domain.SumAmounts(deltas[key], p.Amount)
+			if err != nil {
+				return nil, err
+			}
+			deltas[key] = delta
+		}
+	}
+	return deltas, nil
+}
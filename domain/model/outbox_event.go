@@ -0,0 +1,336 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+)
+
+// OutboxEventType is the kind of a change of balances which is published to the downstream services.
+type OutboxEventType string
+
+const (
+	EventPaymentConfirmed     OutboxEventType = "payment.confirmed"
+	EventPaymentCancelled     OutboxEventType = "payment.cancelled"
+	EventPaymentExpired       OutboxEventType = "payment.expired"
+	EventRefundCreated        OutboxEventType = "refund.created"
+	EventTransferConfirmed    OutboxEventType = "transfer.confirmed"
+	EventTransferCancelled    OutboxEventType = "transfer.cancelled"
+	EventTransferExpired      OutboxEventType = "transfer.expired"
+	EventExchangeCompleted    OutboxEventType = "exchange.completed"
+	EventPointsExpired        OutboxEventType = "points.expired"
+	EventBalancesAddedToUsers OutboxEventType = "balances.added_to_users"
+	EventBulkCreditCompleted  OutboxEventType = "bulk_credit.completed"
+	EventBulkCreditReversed   OutboxEventType = "bulk_credit.reversed"
+)
+
+// IsValid reports whether the type is one of the defined types.
+func (t OutboxEventType) IsValid() bool {
+	switch t {
+	case EventBalancesAddedToUsers, EventBulkCreditCompleted, EventBulkCreditReversed:
+		return true
+	}
+	return t.IsUserEvent()
+}
+
+// IsUserEvent reports whether the events of the type are about the balances of a user, and can be subscribed by webhooks.
+// The events about many users at once are only published to OUTBOX_PUBLISHER.
+func (t OutboxEventType) IsUserEvent() bool {
+	switch t {
+	case EventPaymentConfirmed, EventPaymentCancelled, EventPaymentExpired, EventRefundCreated,
+		EventTransferConfirmed, EventTransferCancelled, EventTransferExpired, EventExchangeCompleted, EventPointsExpired:
+		return true
+	}
+	return false
+}
+
+// OutboxEventStatus is the delivery state of an outbox event.
+type OutboxEventStatus string
+
+const (
+	OutboxEventStatusPending   OutboxEventStatus = "pending"
+	OutboxEventStatusPublished OutboxEventStatus = "published"
+	// 最大試行回数まで配信に失敗し、配信をあきらめた状態
+	OutboxEventStatusDead OutboxEventStatus = "dead"
+)
+
+// 配信に失敗したイベントやWebhookの再試行の間隔。失敗するたびに倍にし、上限で止める
+const (
+	retryBaseDelay = time.Second
+	retryMaxDelay  = 5 * time.Minute
+)
+
+// OutboxEvent is an event recorded in the same DB transaction as the change of balances, and delivered afterwards
+// at least once. Consumers use the sequence, which increases in the order of recording, to drop duplicates.
+type OutboxEvent struct {
+	Sequence        uint64
+	Type            OutboxEventType
+	Payload         []byte // イベントの種類ごとのJSON
+	Status          OutboxEventStatus
+	Attempts        int       // 配信を試みた回数
+	NextAttemptTime time.Time // 次に配信を試みる時刻
+	LastError       string    // 直近の配信に失敗した理由
+	CreateTime      time.Time
+	PublishTime     time.Time
+	// UserIDs are the users whose balances the event is about, to enqueue the deliveries to their webhooks.
+	// It is only set when the event is recorded, and is not saved.
+	UserIDs []uint
+}
+
+// NewOutboxEvent creates a pending event with the payload encoded in JSON.
+func NewOutboxEvent(eventType OutboxEventType, payload interface{}, now time.Time) (*OutboxEvent, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &OutboxEvent{
+		Type:            eventType,
+		Payload:         b,
+		Status:          OutboxEventStatusPending,
+		NextAttemptTime: now,
+		CreateTime:      now,
+	}, nil
+}
+
+// Published records the successful delivery.
+func (e *OutboxEvent) Published(now time.Time) {
+	e.Attempts++
+	e.Status = OutboxEventStatusPublished
+	e.LastError = ""
+	e.PublishTime = now
+}
+
+// Failed records the failed delivery and schedules the next attempt with an exponential backoff.
+// The event becomes dead when it has been attempted maxAttempts times.
+func (e *OutboxEvent) Failed(err error, maxAttempts int, now time.Time) {
+	e.Attempts++
+	e.LastError = err.Error()
+	if e.Attempts >= maxAttempts {
+		e.Status = OutboxEventStatusDead
+		return
+	}
+	e.NextAttemptTime = now.Add(RetryDelay(e.Attempts))
+}
+
+// RetryDelay returns how long to wait before the next attempt after the attempts failed.
+func RetryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
+
+// Envelope returns the event in the JSON which is delivered to the consumers.
+func (e *OutboxEvent) Envelope() ([]byte, error) {
+	return json.Marshal(struct {
+		Sequence   uint64          `json:"sequence"`
+		Type       OutboxEventType `json:"type"`
+		CreateTime time.Time       `json:"create_time"`
+		Payload    json.RawMessage `json:"payload"`
+	}{e.Sequence, e.Type, e.CreateTime, e.Payload})
+}
+
+// PaymentEventPayload is the payload of payment.confirmed, payment.cancelled and payment.expired.
+type PaymentEventPayload struct {
+	UUID     string          `json:"uuid"`
+	UserID   uint            `json:"user_id"`
+	Currency domain.Currency `json:"currency"`
+	Amount   int64           `json:"amount"`
+	Status   PaymentStatus   `json:"status"`
+}
+
+// NewPaymentEvent creates the event of the payment which has just been confirmed, cancelled or expired.
+func NewPaymentEvent(pt *PaymentTransaction, now time.Time) (*OutboxEvent, error) {
+	eventType := EventPaymentConfirmed
+	switch pt.Status {
+	case PaymentStatusCancelled:
+		eventType = EventPaymentCancelled
+	case PaymentStatusExpired:
+		eventType = EventPaymentExpired
+	}
+	event, err := NewOutboxEvent(eventType, &PaymentEventPayload{
+		UUID:     pt.UUID,
+		UserID:   pt.UserID,
+		Currency: pt.Currency,
+		Amount:   pt.Amount,
+		Status:   pt.Status,
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+	event.UserIDs = []uint{pt.UserID}
+	return event, nil
+}
+
+// RefundEventPayload is the payload of refund.created.
+type RefundEventPayload struct {
+	UUID        string          `json:"uuid"`
+	PaymentUUID string          `json:"payment_uuid"`
+	UserID      uint            `json:"user_id"`
+	Currency    domain.Currency `json:"currency"`
+	Amount      int64           `json:"amount"` // 返金額（正の数）
+}
+
+// NewRefundEvent creates the event of the refund which has just been credited.
+func NewRefundEvent(refund *Refund, now time.Time) (*OutboxEvent, error) {
+	event, err := NewOutboxEvent(EventRefundCreated, &RefundEventPayload{
+		UUID:        refund.UUID,
+		PaymentUUID: refund.PaymentUUID,
+		UserID:      refund.UserID,
+		Currency:    refund.Currency,
+		Amount:      refund.Amount,
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+	event.UserIDs = []uint{refund.UserID}
+	return event, nil
+}
+
+// TransferEventPayload is the payload of transfer.confirmed, transfer.cancelled and transfer.expired.
+type TransferEventPayload struct {
+	UUID       string          `json:"uuid"`
+	FromUserID uint            `json:"from_user_id"`
+	ToUserID   uint            `json:"to_user_id"`
+	Currency   domain.Currency `json:"currency"`
+	Amount     int64           `json:"amount"` // 送金額（正の数）
+	Status     PaymentStatus   `json:"status"`
+}
+
+// NewTransferEvent creates the event of the transfer which has just been confirmed, cancelled or expired.
+// It is about both of the sender and the recipient.
+func NewTransferEvent(t *Transfer, now time.Time) (*OutboxEvent, error) {
+	eventType := EventTransferConfirmed
+	switch t.Status {
+	case PaymentStatusCancelled:
+		eventType = EventTransferCancelled
+	case PaymentStatusExpired:
+		eventType = EventTransferExpired
+	}
+	event, err := NewOutboxEvent(eventType, &TransferEventPayload{
+		UUID:       t.UUID,
+		FromUserID: t.FromUserID,
+		ToUserID:   t.ToUserID,
+		Currency:   t.Currency,
+		Amount:     t.Amount,
+		Status:     t.Status,
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+	event.UserIDs = []uint{t.FromUserID, t.ToUserID}
+	return event, nil
+}
+
+// ExchangeEventPayload is the payload of exchange.completed.
+type ExchangeEventPayload struct {
+	UUID         string          `json:"uuid"`
+	UserID       uint            `json:"user_id"`
+	FromCurrency domain.Currency `json:"from_currency"`
+	FromAmount   int64           `json:"from_amount"`
+	ToCurrency   domain.Currency `json:"to_currency"`
+	ToAmount     int64           `json:"to_amount"`
+	Rate         string          `json:"rate"`
+}
+
+// NewExchangeEvent creates the event of the exchange which has just been made.
+func NewExchangeEvent(e *Exchange, now time.Time) (*OutboxEvent, error) {
+	event, err := NewOutboxEvent(EventExchangeCompleted, &ExchangeEventPayload{
+		UUID:         e.UUID,
+		UserID:       e.UserID,
+		FromCurrency: e.FromCurrency,
+		FromAmount:   e.FromAmount,
+		ToCurrency:   e.ToCurrency,
+		ToAmount:     e.ToAmount,
+		Rate:         e.Rate,
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+	event.UserIDs = []uint{e.UserID}
+	return event, nil
+}
+
+// PointsExpiredEventPayload is the payload of points.expired.
+type PointsExpiredEventPayload struct {
+	UserID uint  `json:"user_id"`
+	Amount int64 `json:"amount"` // 失効したポイント（正の数）
+}
+
+// NewPointsExpiredEvent creates the event of the points of the user which have just expired.
+func NewPointsExpiredEvent(userID uint, amount int64, now time.Time) (*OutboxEvent, error) {
+	event, err := NewOutboxEvent(EventPointsExpired, &PointsExpiredEventPayload{UserID: userID, Amount: amount}, now)
+	if err != nil {
+		return nil, err
+	}
+	event.UserIDs = []uint{userID}
+	return event, nil
+}
+
+// AddToUsersEventPayload is the payload of balances.added_to_users.
+type AddToUsersEventPayload struct {
+	Currency        domain.Currency `json:"currency"`
+	Amount          int64           `json:"amount"` // 各ユーザへの加算額
+	UserIDs         []uint          `json:"user_ids"`
+	PointExpireTime *time.Time      `json:"point_expire_time,omitempty"`
+}
+
+// NewAddToUsersEvent creates the event of the amount added to each of the users.
+func NewAddToUsersEvent(currency domain.Currency, amount int64, userIDs []uint, pointExpireTime time.Time, now time.Time) (*OutboxEvent, error) {
+	payload := &AddToUsersEventPayload{
+		Currency: currency,
+		Amount:   amount,
+		UserIDs:  userIDs,
+	}
+	if !pointExpireTime.IsZero() {
+		payload.PointExpireTime = &pointExpireTime
+	}
+	return NewOutboxEvent(EventBalancesAddedToUsers, payload, now)
+}
+
+// BulkCreditCompletedEventPayload is the payload of bulk_credit.completed.
+type BulkCreditCompletedEventPayload struct {
+	JobID         uint64          `json:"job_id"`
+	Currency      domain.Currency `json:"currency"`
+	Amount        int64           `json:"amount"` // 各ユーザへの加算額
+	CreditedCount int             `json:"credited_count"`
+	FailedCount   int             `json:"failed_count"`
+}
+
+// NewBulkCreditCompletedEvent creates the event of the job which has just been completed.
+func NewBulkCreditCompletedEvent(job *BulkCreditJob, now time.Time) (*OutboxEvent, error) {
+	return NewOutboxEvent(EventBulkCreditCompleted, &BulkCreditCompletedEventPayload{
+		JobID:         job.ID,
+		Currency:      job.Currency,
+		Amount:        job.Amount,
+		CreditedCount: job.CreditedCount,
+		FailedCount:   job.FailedCount,
+	}, now)
+}
+
+// BulkCreditReversedEventPayload is the payload of bulk_credit.reversed.
+type BulkCreditReversedEventPayload struct {
+	JobID          uint64                 `json:"job_id"`
+	Currency       domain.Currency        `json:"currency"`
+	Mode           BulkCreditReversalMode `json:"mode"`
+	ReversedCount  int                    `json:"reversed_count"`
+	SkippedCount   int                    `json:"skipped_count"`
+	ReversedAmount int64                  `json:"reversed_amount"` // 減算した合計額
+}
+
+// NewBulkCreditReversedEvent creates the event of the job whose reversal has just been completed.
+func NewBulkCreditReversedEvent(job *BulkCreditJob, now time.Time) (*OutboxEvent, error) {
+	return NewOutboxEvent(EventBulkCreditReversed, &BulkCreditReversedEventPayload{
+		JobID:          job.ID,
+		Currency:       job.Currency,
+		Mode:           job.ReversalMode,
+		ReversedCount:  job.ReversedCount,
+		SkippedCount:   job.ReversalSkippedCount,
+		ReversedAmount: job.ReversedAmount,
+	}, now)
+}
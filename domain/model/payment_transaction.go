@@ -1,30 +1,247 @@
 package model
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+)
+
+// DefaultTryTTL is how long a tried payment stays confirmable when the caller does not specify it.
+const DefaultTryTTL = 10 * time.Minute
+
+// PaymentStatus is the state of a payment transaction.
+type PaymentStatus string
+
+const (
+	PaymentStatusTried     PaymentStatus = "tried"
+	PaymentStatusConfirmed PaymentStatus = "confirmed"
+	PaymentStatusCancelled PaymentStatus = "cancelled"
+	PaymentStatusExpired   PaymentStatus = "expired"
+	// 残高不足や利用上限の超過などでConfirmできなかった状態
+	PaymentStatusFailed PaymentStatus = "failed"
+	// 確定した加減算を取り消した状態
+	PaymentStatusReversed PaymentStatus = "reversed"
+	// 確定した減算の一部を返金した状態
+	PaymentStatusPartiallyRefunded PaymentStatus = "partially_refunded"
+	// 確定した減算の全額を返金した状態
+	PaymentStatusRefunded PaymentStatus = "refunded"
 )
 
+// 遷移可能なステータス。ここにないステータスからは遷移できない
+var paymentStatusTransitions = map[PaymentStatus][]PaymentStatus{
+	PaymentStatusTried:             {PaymentStatusConfirmed, PaymentStatusCancelled, PaymentStatusExpired, PaymentStatusFailed},
+	PaymentStatusConfirmed:         {PaymentStatusReversed, PaymentStatusPartiallyRefunded, PaymentStatusRefunded},
+	PaymentStatusPartiallyRefunded: {PaymentStatusPartiallyRefunded, PaymentStatusRefunded},
+}
+
+// CanTransitionTo reports whether the status can be changed to next.
+func (s PaymentStatus) CanTransitionTo(next PaymentStatus) bool {
+	for _, to := range paymentStatusTransitions[s] {
+		if to == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValid reports whether the status is one of the defined statuses.
+func (s PaymentStatus) IsValid() bool {
+	switch s {
+	case PaymentStatusTried, PaymentStatusConfirmed, PaymentStatusCancelled, PaymentStatusExpired, PaymentStatusFailed, PaymentStatusReversed,
+		PaymentStatusPartiallyRefunded, PaymentStatusRefunded:
+		return true
+	}
+	return false
+}
+
+func (s *PaymentStatus) transitTo(next PaymentStatus) error {
+	if !s.CanTransitionTo(next) {
+		return domain.ErrIllegalTransition
+	}
+	*s = next
+	return nil
+}
+
 type PaymentTransaction struct {
-	UUID        string
-	UserID      uint
-	Amount      int // 負の数もとりうる
-	TryTime     time.Time
-	ConfirmTime time.Time
-	CancelTime  time.Time
+	UUID               string
+	UserID             uint
+	Currency           domain.Currency // 加減算するウォレットの通貨
+	Amount             int64           // 補助単位での加減算額。負の数もとりうる
+	RequestFingerprint string          // 同じ冪等性キーのリクエストが同じ内容かどうかの判定に使う
+	Status             PaymentStatus
+	TryTime            time.Time
+	ExpireTime         time.Time // この時刻を過ぎたTryはConfirmできない
+	ConfirmTime        time.Time
+	CancelTime         time.Time
+	ExpiredTime        time.Time // 期限切れとして処理された時刻
+	RefundedAmount     int64     // 返金済みの額の合計（正の数）
+	// 直近のTry/Confirm/Cancelの直後のウォレット。再試行に元の応答と同じ残高を返せるよう、同じトランザクションで保存する。
+	// 保存されていない取引ではnil
+	ResultBalance *Balance
+}
+
+// AmountSign narrows down payment transactions by the sign of the amount.
+type AmountSign string
+
+const (
+	AmountSignPositive AmountSign = "positive" // 加算
+	AmountSignNegative AmountSign = "negative" // 減算
+)
+
+// IsValid reports whether the sign is one of the defined signs.
+func (s AmountSign) IsValid() bool {
+	return s == AmountSignPositive || s == AmountSignNegative
+}
+
+// PaymentTransactionFilter narrows down payment transactions of a user. Transactions are listed from the newest try.
+type PaymentTransactionFilter struct {
+	Status        PaymentStatus // ゼロ値なら指定なし
+	Sign          AmountSign    // ゼロ値なら指定なし
+	From          time.Time     // この時刻以降のTry（ゼロ値なら指定なし）
+	To            time.Time     // この時刻より前のTry（ゼロ値なら指定なし）
+	BeforeTryTime time.Time     // (BeforeTryTime, BeforeUUID) より前の取引（ゼロ値なら最新から）
+	BeforeUUID    string
+	Limit         int
 }
 
-func NewPaymentTransaction(uuid string, userID uint, amount int) *PaymentTransaction {
+func NewPaymentTransaction(uuid string, userID uint, currency domain.Currency, amount int64, ttl time.Duration) *PaymentTransaction {
+	if ttl <= 0 {
+		ttl = DefaultTryTTL
+	}
+	now := time.Now()
 	return &PaymentTransaction{
-		UUID:    uuid,
-		UserID:  userID,
-		Amount:  amount,
-		TryTime: time.Now(),
+		UUID:               uuid,
+		UserID:             userID,
+		Currency:           currency,
+		Amount:             amount,
+		RequestFingerprint: RequestFingerprint(userID, amount),
+		Status:             PaymentStatusTried,
+		TryTime:            now,
+		ExpireTime:         now.Add(ttl),
 	}
 }
 
+// RequestFingerprint returns the digest of the request payload which is bound to an idempotency key.
+func RequestFingerprint(userID uint, amount int64) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%d", userID, amount)))
+	return hex.EncodeToString(sum[:])
+}
+
+// MatchesRequest reports whether the request payload is identical to the one which created the transaction.
+// The currency is compared with the stored one because it is not a part of the fingerprint.
+func (pt *PaymentTransaction) MatchesRequest(userID uint, currency domain.Currency, amount int64) bool {
+	return pt.Currency == currency && pt.RequestFingerprint == RequestFingerprint(userID, amount)
+}
+
 func (pt *PaymentTransaction) IsTryStatus() bool {
-	if pt.ConfirmTime.IsZero() && pt.CancelTime.IsZero() {
+	return pt.Status == PaymentStatusTried
+}
+
+// IsConfirmStatus reports whether the transaction has been confirmed, including the ones refunded afterwards.
+func (pt *PaymentTransaction) IsConfirmStatus() bool {
+	switch pt.Status {
+	case PaymentStatusConfirmed, PaymentStatusPartiallyRefunded, PaymentStatusRefunded:
 		return true
 	}
 	return false
 }
+
+func (pt *PaymentTransaction) IsCancelStatus() bool {
+	return pt.Status == PaymentStatusCancelled
+}
+
+// IsExpired reports whether the transaction has expired or is a tried one past its expire time at now.
+func (pt *PaymentTransaction) IsExpired(now time.Time) bool {
+	if pt.Status == PaymentStatusExpired {
+		return true
+	}
+	return pt.IsTryStatus() && !pt.ExpireTime.IsZero() && !now.Before(pt.ExpireTime)
+}
+
+// Confirm moves a tried transaction to confirmed.
+func (pt *PaymentTransaction) Confirm(now time.Time) error {
+	// 期限切れのTryは、スイーパーが処理する前でもConfirmできない
+	if pt.IsExpired(now) {
+		return domain.ErrExpiredTransaction
+	}
+	if err := pt.Status.transitTo(PaymentStatusConfirmed); err != nil {
+		return err
+	}
+	pt.ConfirmTime = now
+	return nil
+}
+
+// Cancel moves a tried transaction to cancelled.
+func (pt *PaymentTransaction) Cancel(now time.Time) error {
+	if err := pt.Status.transitTo(PaymentStatusCancelled); err != nil {
+		return err
+	}
+	pt.CancelTime = now
+	return nil
+}
+
+// Expire moves a tried transaction to expired.
+func (pt *PaymentTransaction) Expire(now time.Time) error {
+	if err := pt.Status.transitTo(PaymentStatusExpired); err != nil {
+		return err
+	}
+	pt.ExpiredTime = now
+	return nil
+}
+
+// Fail moves a tried transaction to failed when its Confirm hits a business error.
+func (pt *PaymentTransaction) Fail() error {
+	return pt.Status.transitTo(PaymentStatusFailed)
+}
+
+// Reverse moves a confirmed transaction to reversed. A refunded one cannot be reversed.
+func (pt *PaymentTransaction) Reverse() error {
+	return pt.Status.transitTo(PaymentStatusReversed)
+}
+
+// IsConfirmFailure reports whether the error of a Confirm is a business error which fails the transaction:
+// the debit is short of the balance or over the spending limit, or the credit overflows the balance.
+func IsConfirmFailure(err error) bool {
+	return errors.Is(err, domain.ErrShortBalance) || errors.Is(err, domain.ErrLimitExceeded) || errors.Is(err, domain.ErrAmountOverflow)
+}
+
+// RefundableAmount returns the amount of a confirmed debit which has not been refunded yet.
+func (pt *PaymentTransaction) RefundableAmount() int64 {
+	if pt.Amount >= 0 || !pt.IsConfirmStatus() {
+		return 0
+	}
+	return -pt.Amount - pt.RefundedAmount
+}
+
+// Refund records the refund of the amount against a confirmed debit. The transaction becomes refunded
+// when the whole debit has been refunded, or partially refunded otherwise.
+func (pt *PaymentTransaction) Refund(amount int64) error {
+	if pt.Amount >= 0 {
+		return fmt.Errorf("%w: only debits can be refunded", domain.ErrInvalidParam)
+	}
+	if amount <= 0 {
+		return fmt.Errorf("%w: refund amount must be positive", domain.ErrInvalidParam)
+	}
+	// 返金済みや未確定の取引は、金額に関わらず遷移できない
+	next := PaymentStatusPartiallyRefunded
+	if !pt.Status.CanTransitionTo(next) {
+		return domain.ErrIllegalTransition
+	}
+	refundable := pt.RefundableAmount()
+	if amount > refundable {
+		return fmt.Errorf("%w: refund of %s exceeds the refundable %s", domain.ErrInvalidParam,
+			domain.NewMoney(amount, pt.Currency), domain.NewMoney(refundable, pt.Currency))
+	}
+	if amount == refundable {
+		next = PaymentStatusRefunded
+	}
+	if err := pt.Status.transitTo(next); err != nil {
+		return err
+	}
+	pt.RefundedAmount += amount
+	return nil
+}
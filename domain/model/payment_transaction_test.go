@@ -0,0 +1,58 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kawabatas/m-bank/domain"
+)
+
+func TestPaymentTransaction_Fail(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  PaymentStatus
+		want    PaymentStatus
+		wantErr error
+	}{
+		{"Try済みは失敗にできる", PaymentStatusTried, PaymentStatusFailed, nil},
+		{"Confirm済みは失敗にできない", PaymentStatusConfirmed, PaymentStatusConfirmed, domain.ErrIllegalTransition},
+		{"Cancel済みは失敗にできない", PaymentStatusCancelled, PaymentStatusCancelled, domain.ErrIllegalTransition},
+		{"失敗済みは失敗にできない", PaymentStatusFailed, PaymentStatusFailed, domain.ErrIllegalTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := &PaymentTransaction{Status: tt.status}
+			if err := pt.Fail(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("PaymentTransaction.Fail() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if pt.Status != tt.want {
+				t.Errorf("PaymentTransaction.Fail() status = %v, want %v", pt.Status, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaymentTransaction_Reverse(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  PaymentStatus
+		want    PaymentStatus
+		wantErr error
+	}{
+		{"Confirm済みは取り消せる", PaymentStatusConfirmed, PaymentStatusReversed, nil},
+		{"Try済みは取り消せない", PaymentStatusTried, PaymentStatusTried, domain.ErrIllegalTransition},
+		{"返金済みは取り消せない", PaymentStatusRefunded, PaymentStatusRefunded, domain.ErrIllegalTransition},
+		{"取り消し済みは取り消せない", PaymentStatusReversed, PaymentStatusReversed, domain.ErrIllegalTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := &PaymentTransaction{Status: tt.status}
+			if err := pt.Reverse(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("PaymentTransaction.Reverse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if pt.Status != tt.want {
+				t.Errorf("PaymentTransaction.Reverse() status = %v, want %v", pt.Status, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// PointLot is a credit of points which expires on its own. Debits of points consume the lots in order of expiry,
+// so the remaining amounts of the lots of a user sum up to the positive part of the point balance.
+type PointLot struct {
+	ID              uint64
+	UserID          uint
+	Amount          int64     // 加算したポイント
+	RemainingAmount int64     // まだ使われておらず、失効していないポイント
+	ExpireTime      time.Time // ゼロ値の場合は失効しない
+	SourceType      string    // 加算した仕訳の発生源の種類
+	SourceID        string
+	CreateTime      time.Time
+}
+
+// PointExpiration is the amount of points which expire at the time.
+type PointExpiration struct {
+	Amount     int64
+	ExpireTime time.Time
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+)
+
+// Refund is a credit which gives back a part or the whole of a confirmed debit to the user.
+type Refund struct {
+	UUID        string
+	PaymentUUID string // 返金の対象の支払いの冪等性キー
+	UserID      uint
+	Currency    domain.Currency
+	Amount      int64 // 補助単位での返金額（正の数）
+	CreateTime  time.Time
+}
+
+// NewRefund creates a refund of the amount against the payment.
+func NewRefund(uuid string, pt *PaymentTransaction, amount int64, now time.Time) *Refund {
+	return &Refund{
+		UUID:        uuid,
+		PaymentUUID: pt.UUID,
+		UserID:      pt.UserID,
+		Currency:    pt.Currency,
+		Amount:      amount,
+		CreateTime:  now,
+	}
+}
+
+// MatchesRequest reports whether the request payload is identical to the one which created the refund.
+func (r *Refund) MatchesRequest(paymentUUID string, amount int64) bool {
+	return r.PaymentUUID == paymentUUID && r.Amount == amount
+}
+
+// JournalEntry returns the entry which credits the user against the external settlement account,
+// in the opposite direction to the confirmed payment.
+func (r *Refund) JournalEntry() *JournalEntry {
+	return NewJournalEntry(JournalSourceRefund, r.UUID,
+		&Posting{Account: UserAccount(r.UserID), Currency: r.Currency, Amount: r.Amount},
+		&Posting{Account: AccountExternalSettlement, Currency: r.Currency, Amount: -r.Amount},
+	)
+}
@@ -0,0 +1,104 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+)
+
+// SpendingLimitScope is what a spending limit applies to.
+type SpendingLimitScope string
+
+const (
+	// SpendingLimitScopeUser applies to one user. The scope id is the user id.
+	SpendingLimitScopeUser SpendingLimitScope = "user"
+	// SpendingLimitScopeTier applies to the users of a tier. The scope id is the tier.
+	SpendingLimitScopeTier SpendingLimitScope = "tier"
+)
+
+// SpendingLimit restricts the debits of payments in a currency. A limit of a user overrides the one of the user's tier.
+// Zero means no limit.
+type SpendingLimit struct {
+	Scope             SpendingLimitScope
+	ScopeID           string
+	Currency          domain.Currency
+	MaxSingleDebit    int64 // 1回の減算額の上限
+	DailyDebitLimit   int64 // 1日（Tryの日時の暦日）の減算額の合計の上限
+	MonthlyDebitLimit int64 // 1ヶ月（Tryの日時の暦月）の減算額の合計の上限
+	HourlyDebitCount  int   // 直近1時間の減算の回数の上限
+	UpdateTime        time.Time
+}
+
+// Validate checks the scope and that the limits are not negative.
+func (l *SpendingLimit) Validate() error {
+	switch l.Scope {
+	case SpendingLimitScopeUser:
+		if id, err := strconv.ParseUint(l.ScopeID, 10, 32); err != nil || id == 0 {
+			return fmt.Errorf("%w: scope_id of a user limit must be a user id: %q", domain.ErrInvalidParam, l.ScopeID)
+		}
+	case SpendingLimitScopeTier:
+		if l.ScopeID == "" {
+			return fmt.Errorf("%w: scope_id of a tier limit must be a tier", domain.ErrInvalidParam)
+		}
+	default:
+		return fmt.Errorf("%w: unknown scope %q", domain.ErrInvalidParam, l.Scope)
+	}
+	if _, err := domain.LookupCurrency(string(l.Currency)); err != nil {
+		return err
+	}
+	if l.MaxSingleDebit < 0 || l.DailyDebitLimit < 0 || l.MonthlyDebitLimit < 0 || l.HourlyDebitCount < 0 {
+		return fmt.Errorf("%w: limits must not be negative", domain.ErrInvalidParam)
+	}
+	return nil
+}
+
+// SpendingWindows are the periods which the debits are summed up in for a debit tried at a time.
+type SpendingWindows struct {
+	DayStart   time.Time
+	MonthStart time.Time
+	HourStart  time.Time // 直近1時間の始まり（この時刻は含まない）
+}
+
+// NewSpendingWindows returns the windows of the debit tried at t. Days and months are the calendar ones in the local time.
+func NewSpendingWindows(t time.Time) SpendingWindows {
+	t = t.In(time.Local)
+	y, m, d := t.Date()
+	return SpendingWindows{
+		DayStart:   time.Date(y, m, d, 0, 0, 0, 0, time.Local),
+		MonthStart: time.Date(y, m, 1, 0, 0, 0, 0, time.Local),
+		HourStart:  t.Add(-time.Hour),
+	}
+}
+
+// Start returns the earliest start of the windows.
+func (w SpendingWindows) Start() time.Time {
+	if w.HourStart.Before(w.MonthStart) {
+		return w.HourStart
+	}
+	return w.MonthStart
+}
+
+// SpendingUsage is the debits in the windows, including the one being checked.
+// The debits tried after the one being checked are also included when it is confirmed.
+type SpendingUsage struct {
+	DailyDebit       int64
+	MonthlyDebit     int64
+	HourlyDebitCount int
+}
+
+// Check returns domain.ErrLimitExceeded when the debit of the amount or the usage including it exceeds the limits.
+func (l *SpendingLimit) Check(amount int64, usage SpendingUsage) error {
+	switch {
+	case l.MaxSingleDebit > 0 && amount > l.MaxSingleDebit:
+		return fmt.Errorf("%w: debit of %s is over the maximum of %s", domain.ErrLimitExceeded, domain.NewMoney(amount, l.Currency), domain.NewMoney(l.MaxSingleDebit, l.Currency))
+	case l.DailyDebitLimit > 0 && usage.DailyDebit > l.DailyDebitLimit:
+		return fmt.Errorf("%w: debits of the day would be %s, over the limit of %s", domain.ErrLimitExceeded, domain.NewMoney(usage.DailyDebit, l.Currency), domain.NewMoney(l.DailyDebitLimit, l.Currency))
+	case l.MonthlyDebitLimit > 0 && usage.MonthlyDebit > l.MonthlyDebitLimit:
+		return fmt.Errorf("%w: debits of the month would be %s, over the limit of %s", domain.ErrLimitExceeded, domain.NewMoney(usage.MonthlyDebit, l.Currency), domain.NewMoney(l.MonthlyDebitLimit, l.Currency))
+	case l.HourlyDebitCount > 0 && usage.HourlyDebitCount > l.HourlyDebitCount:
+		return fmt.Errorf("%w: %d debits in the last hour are over the limit of %d", domain.ErrLimitExceeded, usage.HourlyDebitCount, l.HourlyDebitCount)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+)
+
+// Transfer is a transfer of balance from one user to another.
+type Transfer struct {
+	UUID               string
+	FromUserID         uint
+	ToUserID           uint
+	Currency           domain.Currency // 送金元と送金先のウォレットの通貨
+	Amount             int64           // 補助単位での送金額（正の数）
+	RequestFingerprint string          // 同じ冪等性キーのリクエストが同じ内容かどうかの判定に使う
+	Status             PaymentStatus
+	TryTime            time.Time
+	ExpireTime         time.Time // この時刻を過ぎたTryはConfirmできない
+	ConfirmTime        time.Time
+	CancelTime         time.Time
+	ExpiredTime        time.Time // 期限切れとして処理された時刻
+	// 直近のTry/Confirm/Cancelの直後の送金元と送金先のウォレット。再試行に元の応答と同じ残高を返せるよう、同じトランザクションで保存する。
+	// 保存されていない送金ではnil
+	FromResultBalance *Balance
+	ToResultBalance   *Balance
+}
+
+func NewTransfer(uuid string, fromUserID, toUserID uint, currency domain.Currency, amount int64, ttl time.Duration) *Transfer {
+	if ttl <= 0 {
+		ttl = DefaultTryTTL
+	}
+	now := time.Now()
+	return &Transfer{
+		UUID:               uuid,
+		FromUserID:         fromUserID,
+		ToUserID:           toUserID,
+		Currency:           currency,
+		Amount:             amount,
+		RequestFingerprint: TransferRequestFingerprint(fromUserID, toUserID, amount),
+		Status:             PaymentStatusTried,
+		TryTime:            now,
+		ExpireTime:         now.Add(ttl),
+	}
+}
+
+// TransferRequestFingerprint returns the digest of the transfer request payload which is bound to an idempotency key.
+func TransferRequestFingerprint(fromUserID, toUserID uint, amount int64) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%d:%d", fromUserID, toUserID, amount)))
+	return hex.EncodeToString(sum[:])
+}
+
+// MatchesRequest reports whether the request payload is identical to the one which created the transfer.
+// The currency is compared with the stored one because it is not a part of the fingerprint.
+func (t *Transfer) MatchesRequest(fromUserID, toUserID uint, currency domain.Currency, amount int64) bool {
+	return t.Currency == currency && t.RequestFingerprint == TransferRequestFingerprint(fromUserID, toUserID, amount)
+}
+
+func (t *Transfer) IsTryStatus() bool {
+	return t.Status == PaymentStatusTried
+}
+
+func (t *Transfer) IsConfirmStatus() bool {
+	return t.Status == PaymentStatusConfirmed
+}
+
+func (t *Transfer) IsCancelStatus() bool {
+	return t.Status == PaymentStatusCancelled
+}
+
+// IsExpired reports whether the transfer has expired or is a tried one past its expire time at now.
+func (t *Transfer) IsExpired(now time.Time) bool {
+	if t.Status == PaymentStatusExpired {
+		return true
+	}
+	return t.IsTryStatus() && !t.ExpireTime.IsZero() && !now.Before(t.ExpireTime)
+}
+
+// Confirm moves a tried transfer to confirmed.
+func (t *Transfer) Confirm(now time.Time) error {
+	if t.IsExpired(now) {
+		return domain.ErrExpiredTransaction
+	}
+	if err := t.Status.transitTo(PaymentStatusConfirmed); err != nil {
+		return err
+	}
+	t.ConfirmTime = now
+	return nil
+}
+
+// Cancel moves a tried transfer to cancelled.
+func (t *Transfer) Cancel(now time.Time) error {
+	if err := t.Status.transitTo(PaymentStatusCancelled); err != nil {
+		return err
+	}
+	t.CancelTime = now
+	return nil
+}
+
+// Expire moves a tried transfer to expired.
+func (t *Transfer) Expire(now time.Time) error {
+	if err := t.Status.transitTo(PaymentStatusExpired); err != nil {
+		return err
+	}
+	t.ExpiredTime = now
+	return nil
+}
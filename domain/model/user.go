@@ -3,4 +3,8 @@ package model
 type User struct {
 	ID   uint
 	Name string
+	Tier string // 利用上限を共有するユーザの区分
 }
+
+// DefaultUserTier is the tier of the users which no tier is assigned to.
+const DefaultUserTier = "standard"
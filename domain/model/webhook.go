@@ -0,0 +1,124 @@
+package model
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+)
+
+// WebhookSubscription is a URL registered by a partner to receive the events of the types about its users.
+type WebhookSubscription struct {
+	ID         uint64
+	URL        string
+	EventTypes []OutboxEventType // 昇順で重複なし
+	UserIDs    []uint            // 配信の対象のパートナーのユーザ。昇順で重複なし
+	Secret     string            // 配信の署名の鍵
+	CreateTime time.Time
+}
+
+// MaxWebhookUsers is the maximum number of the users of a subscription.
+const MaxWebhookUsers = 1000
+
+// NewWebhookSubscription creates a subscription of the http or https URL to the events of the types about the users.
+// Only the events about a user can be subscribed, so that a partner does not receive the other users' changes.
+func NewWebhookSubscription(rawURL string, eventTypes []OutboxEventType, userIDs []uint, secret string, now time.Time) (*WebhookSubscription, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: webhook url must be an absolute http or https url: %q", domain.ErrInvalidParam, rawURL)
+	}
+	if len(eventTypes) == 0 {
+		return nil, fmt.Errorf("%w: no event types", domain.ErrInvalidParam)
+	}
+	seen := map[OutboxEventType]bool{}
+	types := make([]OutboxEventType, 0, len(eventTypes))
+	for _, t := range eventTypes {
+		if !t.IsValid() {
+			return nil, fmt.Errorf("%w: unknown event type %q", domain.ErrInvalidParam, t)
+		}
+		if !t.IsUserEvent() {
+			return nil, fmt.Errorf("%w: event type %q is not about a user and cannot be subscribed", domain.ErrInvalidParam, t)
+		}
+		if !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	if len(userIDs) == 0 || len(userIDs) > MaxWebhookUsers {
+		return nil, fmt.Errorf("%w: user ids must be 1 to %d users", domain.ErrInvalidParam, MaxWebhookUsers)
+	}
+	seenUsers := map[uint]bool{}
+	users := make([]uint, 0, len(userIDs))
+	for _, id := range userIDs {
+		if id == 0 {
+			return nil, fmt.Errorf("%w: user id must be positive", domain.ErrInvalidParam)
+		}
+		if !seenUsers[id] {
+			seenUsers[id] = true
+			users = append(users, id)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i] < users[j] })
+	return &WebhookSubscription{
+		URL:        rawURL,
+		EventTypes: types,
+		UserIDs:    users,
+		Secret:     secret,
+		CreateTime: now,
+	}, nil
+}
+
+// WebhookDeliveryStatus is the state of a delivery of an event to a webhook.
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+	// 最大試行回数まで配信に失敗し、配信をあきらめた状態（デッドレター）
+	WebhookDeliveryStatusDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery is a delivery of an outbox event to a subscription, retried until it succeeds or becomes dead.
+type WebhookDelivery struct {
+	ID              uint64
+	SubscriptionID  uint64
+	EventSequence   uint64
+	EventType       OutboxEventType
+	Status          WebhookDeliveryStatus
+	Attempts        int       // 配信を試みた回数
+	NextAttemptTime time.Time // 次に配信を試みる時刻
+	LastStatusCode  int       // 直近の配信の応答のHTTPステータス。応答がなければ0
+	LastError       string    // 直近の配信に失敗した理由
+	CreateTime      time.Time
+	DeliverTime     time.Time
+
+	// 配信するために取得したときだけ設定される
+	Subscription *WebhookSubscription
+	Event        *OutboxEvent
+}
+
+// Succeeded records the delivery which the webhook accepted with the status code.
+func (d *WebhookDelivery) Succeeded(statusCode int, now time.Time) {
+	d.Attempts++
+	d.Status = WebhookDeliveryStatusSucceeded
+	d.LastStatusCode = statusCode
+	d.LastError = ""
+	d.DeliverTime = now
+}
+
+// Failed records the failed delivery and schedules the next attempt with an exponential backoff.
+// The delivery becomes dead when it has been attempted maxAttempts times.
+// The status code is 0 when the webhook did not respond.
+func (d *WebhookDelivery) Failed(statusCode int, err error, maxAttempts int, now time.Time) {
+	d.Attempts++
+	d.LastStatusCode = statusCode
+	d.LastError = err.Error()
+	if d.Attempts >= maxAttempts {
+		d.Status = WebhookDeliveryStatusDead
+		return
+	}
+	d.NextAttemptTime = now.Add(RetryDelay(d.Attempts))
+}
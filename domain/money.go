@@ -0,0 +1,218 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Currency is the code of a currency which balances are held in, e.g. "JPY".
+type Currency string
+
+// supported currencies.
+const (
+	JPY   Currency = "JPY"
+	USD   Currency = "USD"
+	EUR   Currency = "EUR"
+	Point Currency = "PTS" // サービス内のポイント（ISO 4217の通貨ではない）
+)
+
+// DefaultCurrency is the currency of the requests which do not specify one.
+const DefaultCurrency = JPY
+
+// 補助単位の桁数（JPYは0、USDは2）
+var currencyPrecisions = map[Currency]int{
+	JPY:   0,
+	USD:   2,
+	EUR:   2,
+	Point: 0,
+}
+
+// LookupCurrency returns the supported currency of the code.
+func LookupCurrency(code string) (Currency, error) {
+	c := Currency(code)
+	if _, ok := currencyPrecisions[c]; !ok {
+		return "", fmt.Errorf("%w: unsupported currency %q", ErrInvalidParam, code)
+	}
+	return c, nil
+}
+
+// Precision returns the number of the digits of the minor unit.
+func (c Currency) Precision() int {
+	return currencyPrecisions[c]
+}
+
+// Money is an amount in the minor unit of the currency (e.g. cents for USD).
+// The arithmetic returns ErrAmountOverflow instead of wrapping around.
+type Money struct {
+	Amount   int64
+	Currency Currency
+}
+
+func NewMoney(amount int64, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Add returns m + o. Both must be in the same currency.
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	if (o.Amount > 0 && m.Amount > math.MaxInt64-o.Amount) || (o.Amount < 0 && m.Amount < math.MinInt64-o.Amount) {
+		return Money{}, fmt.Errorf("%w: %d + %d", ErrAmountOverflow, m.Amount, o.Amount)
+	}
+	return Money{Amount: m.Amount + o.Amount, Currency: m.Currency}, nil
+}
+
+// Sub returns m - o. Both must be in the same currency.
+func (m Money) Sub(o Money) (Money, error) {
+	neg, err := o.Neg()
+	if err != nil {
+		return Money{}, err
+	}
+	return m.Add(neg)
+}
+
+// Neg returns -m.
+func (m Money) Neg() (Money, error) {
+	if m.Amount == math.MinInt64 {
+		return Money{}, fmt.Errorf("%w: -(%d)", ErrAmountOverflow, m.Amount)
+	}
+	return Money{Amount: -m.Amount, Currency: m.Currency}, nil
+}
+
+// Mul returns m * n.
+func (m Money) Mul(n int64) (Money, error) {
+	if m.Amount == 0 || n == 0 {
+		return Money{Amount: 0, Currency: m.Currency}, nil
+	}
+	product := m.Amount * n
+	if product/n != m.Amount || (m.Amount == -1 && n == math.MinInt64) || (n == -1 && m.Amount == math.MinInt64) {
+		return Money{}, fmt.Errorf("%w: %d * %d", ErrAmountOverflow, m.Amount, n)
+	}
+	return Money{Amount: product, Currency: m.Currency}, nil
+}
+
+// Convert converts m into the currency at the rate, which is the amount of to in the major unit per one major unit of m.
+// The result is rounded to the minor unit of to with the rounding mode.
+func (m Money) Convert(to Currency, rate *big.Rat, rounding RoundingMode) (Money, error) {
+	v := new(big.Rat).Mul(new(big.Rat).SetInt64(m.Amount), rate)
+	// 補助単位の桁数の違いを調整する（JPYの1円はUSDの100セント分）
+	diff := to.Precision() - m.Currency.Precision()
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(diff))), nil))
+	if diff >= 0 {
+		v.Mul(v, scale)
+	} else {
+		v.Quo(v, scale)
+	}
+	n := rounding.round(v)
+	if !n.IsInt64() {
+		return Money{}, fmt.Errorf("%w: %s at the rate %s", ErrAmountOverflow, m, rate.FloatString(rateMaxScale))
+	}
+	return Money{Amount: n.Int64(), Currency: to}, nil
+}
+
+// String formats the amount in the major unit, e.g. "12.34 USD".
+func (m Money) String() string {
+	digits := strconv.FormatUint(absUint64(m.Amount), 10)
+	if p := m.Currency.Precision(); p > 0 {
+		if len(digits) <= p {
+			digits = strings.Repeat("0", p-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-p] + "." + digits[len(digits)-p:]
+	}
+	if m.Amount < 0 {
+		digits = "-" + digits
+	}
+	return digits + " " + string(m.Currency)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func absUint64(n int64) uint64 {
+	if n < 0 {
+		return uint64(-(n + 1)) + 1
+	}
+	return uint64(n)
+}
+
+// SumAmounts adds up the amounts in the minor unit of the same currency, checking overflow.
+func SumAmounts(amounts ...int64) (int64, error) {
+	sum := Money{}
+	for _, amount := range amounts {
+		var err error
+		if sum, err = sum.Add(Money{Amount: amount}); err != nil {
+			return 0, err
+		}
+	}
+	return sum.Amount, nil
+}
+
+// RoundingMode is how a converted amount is rounded to the minor unit.
+// The modes are symmetric about zero.
+type RoundingMode string
+
+// rounding modes.
+const (
+	RoundDown     RoundingMode = "down"      // 切り捨て（0に近づける）
+	RoundHalfUp   RoundingMode = "half_up"   // 四捨五入
+	RoundHalfEven RoundingMode = "half_even" // 偶数丸め（銀行家の丸め）
+)
+
+// LookupRoundingMode returns the rounding mode of the name.
+func LookupRoundingMode(name string) (RoundingMode, error) {
+	switch r := RoundingMode(name); r {
+	case RoundDown, RoundHalfUp, RoundHalfEven:
+		return r, nil
+	}
+	return "", fmt.Errorf("%w: unknown rounding mode %q", ErrInvalidParam, name)
+}
+
+func (r RoundingMode) round(v *big.Rat) *big.Int {
+	den := v.Denom()
+	q, rem := new(big.Int).QuoRem(new(big.Int).Abs(v.Num()), den, new(big.Int))
+	if r != RoundDown {
+		// 端数の2倍と分母を比べて、0.5より大きいか、ちょうど0.5かを判定する
+		half := new(big.Int).Lsh(rem, 1).Cmp(den)
+		if half > 0 || (half == 0 && (r == RoundHalfUp || q.Bit(0) == 1)) {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	if v.Sign() < 0 {
+		q.Neg(q)
+	}
+	return q
+}
+
+// 為替レートの小数部の最大桁数
+const rateMaxScale = 12
+
+var ratePattern = regexp.MustCompile(`^[0-9]+(\.[0-9]{1,12})?$`)
+
+// ParseRate parses a positive exchange rate in decimal notation, e.g. "0.0067".
+func ParseRate(s string) (*big.Rat, error) {
+	if !ratePattern.MatchString(s) {
+		return nil, fmt.Errorf("%w: rate must be a decimal with at most %d fractional digits: %q", ErrInvalidParam, rateMaxScale, s)
+	}
+	rate, ok := new(big.Rat).SetString(s)
+	if !ok || rate.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: rate must be positive: %q", ErrInvalidParam, s)
+	}
+	return rate, nil
+}
@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestMoney_Add(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       Money
+		o       Money
+		want    Money
+		wantErr error
+	}{
+		{"加算できる", NewMoney(100, JPY), NewMoney(-30, JPY), NewMoney(70, JPY), nil},
+		{"上限を超えるとエラー", NewMoney(math.MaxInt64, JPY), NewMoney(1, JPY), Money{}, ErrAmountOverflow},
+		{"下限を超えるとエラー", NewMoney(math.MinInt64, JPY), NewMoney(-1, JPY), Money{}, ErrAmountOverflow},
+		{"通貨が異なるとエラー", NewMoney(100, JPY), NewMoney(100, USD), Money{}, ErrCurrencyMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.Add(tt.o)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Money.Add() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Money.Add() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_Mul(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       Money
+		n       int64
+		want    Money
+		wantErr error
+	}{
+		{"乗算できる", NewMoney(-100, JPY), 3, NewMoney(-300, JPY), nil},
+		{"0倍は0", NewMoney(math.MaxInt64, JPY), 0, NewMoney(0, JPY), nil},
+		{"上限を超えるとエラー", NewMoney(math.MaxInt64/2+1, JPY), 2, Money{}, ErrAmountOverflow},
+		{"符号を反転できない値はエラー", NewMoney(math.MinInt64, JPY), -1, Money{}, ErrAmountOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.Mul(tt.n)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Money.Mul() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Money.Mul() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Money
+		want string
+	}{
+		{"補助単位のない通貨", NewMoney(1234, JPY), "1234 JPY"},
+		{"補助単位のある通貨", NewMoney(1234, USD), "12.34 USD"},
+		{"1未満の負の数", NewMoney(-5, USD), "-0.05 USD"},
+		{"最小値", NewMoney(math.MinInt64, USD), "-92233720368547758.08 USD"},
+		{"ポイント", NewMoney(300, Point), "300 PTS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.String(); got != tt.want {
+				t.Errorf("Money.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupCurrency(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		want    Currency
+		wantErr error
+	}{
+		{"JPY", "JPY", JPY, nil},
+		{"ポイント", "PTS", Point, nil},
+		{"小文字は受け付けない", "usd", "", ErrInvalidParam},
+		{"対応していない通貨", "GBP", "", ErrInvalidParam},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LookupCurrency(tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("LookupCurrency() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("LookupCurrency() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_Convert(t *testing.T) {
+	tests := []struct {
+		name     string
+		m        Money
+		to       Currency
+		rate     string
+		rounding RoundingMode
+		want     Money
+		wantErr  error
+	}{
+		{"補助単位の桁数を調整する", NewMoney(1000, JPY), USD, "0.0067", RoundDown, NewMoney(670, USD), nil},
+		{"端数を切り捨てる", NewMoney(150, JPY), USD, "0.0067", RoundDown, NewMoney(100, USD), nil},
+		{"ちょうど0.5は四捨五入で切り上げる", NewMoney(150, JPY), USD, "0.0067", RoundHalfUp, NewMoney(101, USD), nil},
+		{"ちょうど0.5は偶数丸めで偶数にする", NewMoney(150, JPY), USD, "0.0067", RoundHalfEven, NewMoney(100, USD), nil},
+		{"0.5より大きい端数は偶数丸めでも切り上げる", NewMoney(151, JPY), USD, "0.0067", RoundHalfEven, NewMoney(101, USD), nil},
+		{"補助単位の桁数が少ない通貨への両替", NewMoney(1234, USD), JPY, "149.5", RoundHalfUp, NewMoney(1845, JPY), nil},
+		{"負の数は0を中心に対称に丸める", NewMoney(-1234, USD), JPY, "149.5", RoundDown, NewMoney(-1844, JPY), nil},
+		{"上限を超えるとエラー", NewMoney(math.MaxInt64, JPY), Point, "2", RoundDown, Money{}, ErrAmountOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := ParseRate(tt.rate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := tt.m.Convert(tt.to, rate, tt.rounding)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Money.Convert() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Money.Convert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		wantErr error
+	}{
+		{"小数", "0.0067", nil},
+		{"整数", "150", nil},
+		{"0はエラー", "0.000", ErrInvalidParam},
+		{"負の数はエラー", "-1.5", ErrInvalidParam},
+		{"分数はエラー", "1/3", ErrInvalidParam},
+		{"指数表記はエラー", "1e-3", ErrInvalidParam},
+		{"小数部が長すぎるとエラー", "0.1234567890123", ErrInvalidParam},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseRate(tt.s); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseRate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
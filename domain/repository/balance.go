@@ -2,11 +2,28 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	"github.com/kawabatas/m-bank/domain"
 	"github.com/kawabatas/m-bank/domain/model"
 )
 
 type BalanceRepository interface {
-	Get(ctx context.Context, userID uint) (*model.Balance, error)
-	AddToUsers(ctx context.Context, amount, limit, offset int) error
+	// Get returns the wallet of the user in the currency.
+	// It returns an empty wallet when the user exists but has never held the currency.
+	Get(ctx context.Context, userID uint, currency domain.Currency) (*model.Balance, error)
+	// List returns all the wallets of the user ordered by currency.
+	// It returns domain.ErrNoSuchEntity when the user does not exist.
+	List(ctx context.Context, userID uint) ([]*model.Balance, error)
+	// SetOverdraftLimit sets the overdraft limit of the user's wallet in the currency, creating the wallet if needed.
+	// It returns domain.ErrNoSuchEntity when the user does not exist.
+	SetOverdraftLimit(ctx context.Context, userID uint, currency domain.Currency, limit int64) (*model.Balance, error)
+	// AddToUsers credits the users in the range of limit and offset. The points credited expire at pointExpireTime
+	// unless it is zero.
+	AddToUsers(ctx context.Context, currency domain.Currency, amount int64, pointExpireTime time.Time, limit, offset int) error
+	// CountTargets returns the number of users selected by the target. balance_below compares the wallets in the currency.
+	CountTargets(ctx context.Context, currency domain.Currency, target model.BulkCreditTarget) (int, error)
+	// ListTargets returns at most limit ids of the users selected by the target whose id is greater than afterUserID,
+	// in ascending order.
+	ListTargets(ctx context.Context, currency domain.Currency, target model.BulkCreditTarget, afterUserID uint, limit int) ([]uint, error)
 }
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type BalanceLogRepository interface {
+	List(ctx context.Context, userID uint, filter model.BalanceLogFilter) ([]*model.BalanceLog, error)
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type BulkCreditJobRepository interface {
+	// Create creates a pending job for the normalized target.
+	// It returns domain.ErrDuplicateUUID when the idempotency key is already used.
+	Create(ctx context.Context, idempotencyKey string, currency domain.Currency, amount int64, target model.BulkCreditTarget) (*model.BulkCreditJob, error)
+	Get(ctx context.Context, id uint64) (*model.BulkCreditJob, error)
+	GetByIdempotencyKey(ctx context.Context, idempotencyKey string) (*model.BulkCreditJob, error)
+	// ListUnfinished returns at most limit jobs which are not completed or are being reversed, from the oldest one.
+	ListUnfinished(ctx context.Context, limit int) ([]*model.BulkCreditJob, error)
+	// ProcessChunk credits at most chunkSize target users after the cursor of the job and advances the cursor
+	// in the same DB transaction. A user who cannot be credited is recorded as a failed item and skipped.
+	// When the reversal of the completed job is requested, it reverses at most chunkSize credited users instead.
+	ProcessChunk(ctx context.Context, id uint64, chunkSize int) (*model.BulkCreditJob, error)
+	// RequestReversal requests to reverse the completed job with the mode.
+	// It returns domain.ErrIllegalTransition when the job is not completed or is reversed with another mode.
+	RequestReversal(ctx context.Context, id uint64, mode model.BulkCreditReversalMode) (*model.BulkCreditJob, error)
+	// ListFailedItems returns at most limit failed items of the job ordered by user id.
+	ListFailedItems(ctx context.Context, id uint64, limit int) ([]*model.BulkCreditItem, error)
+	// ListSkippedReversalItems returns at most limit items whose reversal is skipped, ordered by user id.
+	ListSkippedReversalItems(ctx context.Context, id uint64, limit int) ([]*model.BulkCreditItem, error)
+}
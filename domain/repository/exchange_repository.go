@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type ExchangeRateRepository interface {
+	// Create stores the validated rates in one DB transaction and returns them with their ids.
+	Create(ctx context.Context, rates []*model.ExchangeRate) ([]*model.ExchangeRate, error)
+	Get(ctx context.Context, id uint64) (*model.ExchangeRate, error)
+	// ListValid returns the rates from the currency to the other one which are valid at now, from the newest one.
+	ListValid(ctx context.Context, fromCurrency, toCurrency domain.Currency, now time.Time) ([]*model.ExchangeRate, error)
+}
+
+type ExchangeRepository interface {
+	Get(ctx context.Context, uuid string) (*model.Exchange, error)
+	// Exchange debits the amount from the user's wallet of the from currency of the rate and credits
+	// the converted amount to the wallet of the to currency as a single journal entry.
+	// It returns domain.ErrExpiredRate when the rate is not valid at now,
+	// and domain.ErrDuplicateUUID when the uuid is already used.
+	Exchange(ctx context.Context, uuid string, userID uint, rateID uint64, amount int64, now time.Time) (*model.Exchange, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type LedgerRepository interface {
+	// Post records a balanced journal entry and applies its user postings to the balances.
+	Post(ctx context.Context, entry *model.JournalEntry) (*model.JournalEntry, error)
+	ListBySource(ctx context.Context, sourceType, sourceID string) ([]*model.JournalEntry, error)
+	// CheckInvariants verifies that all postings sum up to zero and
+	// each balance equals the sum of the postings of its user account.
+	CheckInvariants(ctx context.Context) error
+}
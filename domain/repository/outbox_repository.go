@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+// OutboxRepository reads the events which the other repositories record in their DB transactions, to deliver them.
+type OutboxRepository interface {
+	// ClaimPending returns at most limit pending events whose next attempt time has come, from the smallest sequence,
+	// and postpones their next attempt by lease so that another relay does not deliver them at the same time.
+	// An event which is not updated within the lease is claimed again.
+	ClaimPending(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.OutboxEvent, error)
+	// Update saves the delivery state of the event.
+	Update(ctx context.Context, event *model.OutboxEvent) error
+}
@@ -2,13 +2,20 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	"github.com/kawabatas/m-bank/domain"
 	"github.com/kawabatas/m-bank/domain/model"
 )
 
 type PaymentTransactionRepository interface {
 	Get(ctx context.Context, uuid string) (*model.PaymentTransaction, error)
-	Try(ctx context.Context, uuid string, userID uint, amount int) (*model.PaymentTransaction, error)
+	// List returns the transactions of the user which match the filter, ordered by try time and uuid descending.
+	List(ctx context.Context, userID uint, filter model.PaymentTransactionFilter) ([]*model.PaymentTransaction, error)
+	Try(ctx context.Context, uuid string, userID uint, currency domain.Currency, amount int64, ttl time.Duration) (*model.PaymentTransaction, error)
 	Confirm(ctx context.Context, uuid string) (*model.PaymentTransaction, error)
 	Cancel(ctx context.Context, uuid string) (*model.PaymentTransaction, error)
+	// ExpireTries expires at most limit tried transactions whose expire time has passed at now,
+	// releasing their reserved balances. It returns the number of expired transactions.
+	ExpireTries(ctx context.Context, now time.Time, limit int) (int, error)
 }
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type PointLotRepository interface {
+	// ListByUser returns all the point lots of the user ordered by id.
+	ListByUser(ctx context.Context, userID uint) ([]*model.PointLot, error)
+	// ListExpirations returns at most limit amounts of the user's points which expire after now, from the nearest one.
+	ListExpirations(ctx context.Context, userID uint, now time.Time, limit int) ([]*model.PointExpiration, error)
+	// ExpireLots expires at most limit point lots whose expire time has passed at now,
+	// debiting their remaining points except those reserved by tried payments. It returns the number of expired lots.
+	ExpireLots(ctx context.Context, now time.Time, limit int) (int, error)
+}
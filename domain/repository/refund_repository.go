@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type RefundRepository interface {
+	// Get returns the refund of the idempotency key. It returns domain.ErrInvalidUUID when there is no such refund.
+	Get(ctx context.Context, uuid string) (*model.Refund, error)
+	// ListByPayment returns the refunds of the payment from the oldest one.
+	ListByPayment(ctx context.Context, paymentUUID string) ([]*model.Refund, error)
+	// Refund credits the amount back to the user of the confirmed debit and updates the refunded amount of the payment
+	// in one transaction. It returns domain.ErrNoSuchEntity when the payment does not exist.
+	Refund(ctx context.Context, uuid, paymentUUID string, amount int64, now time.Time) (*model.Refund, *model.PaymentTransaction, error)
+}
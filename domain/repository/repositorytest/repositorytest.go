@@ -0,0 +1,487 @@
+// Package repositorytest provides the conformance tests which every implementation of the repositories must pass,
+// so that the services behave the same on any storage.
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+	"github.com/kawabatas/m-bank/domain/repository"
+)
+
+// InitialBalance is the amount in JPY which each user created by Fixture.CreateUsers holds.
+const InitialBalance = 1000
+
+// Fixture is the repositories under the test on an empty storage.
+type Fixture struct {
+	BalanceRepo repository.BalanceRepository
+	PaymentRepo repository.PaymentTransactionRepository
+	// CreateUsers creates the users with the ids from 1 to count, each holding InitialBalance in JPY.
+	CreateUsers func(t *testing.T, count int) []*model.User
+}
+
+// Run runs the conformance tests. newFixture is called for each test to start from an empty storage.
+func Run(t *testing.T, newFixture func(t *testing.T) *Fixture) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, f *Fixture)
+	}{
+		{"BalanceRepository_Get", testBalanceGet},
+		{"BalanceRepository_List", testBalanceList},
+		{"BalanceRepository_SetOverdraftLimit", testBalanceSetOverdraftLimit},
+		{"BalanceRepository_AddToUsers", testBalanceAddToUsers},
+		{"PaymentTransactionRepository_Try", testPaymentTry},
+		{"PaymentTransactionRepository_Confirm", testPaymentConfirm},
+		{"PaymentTransactionRepository_ConfirmFailure", testPaymentConfirmFailure},
+		{"PaymentTransactionRepository_Cancel", testPaymentCancel},
+		{"PaymentTransactionRepository_ExpireTries", testPaymentExpireTries},
+		{"PaymentTransactionRepository_List", testPaymentList},
+		{"PaymentTransactionRepository_ConcurrentTry", testPaymentConcurrentTry},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newFixture(t))
+		})
+	}
+}
+
+func testBalanceGet(t *testing.T, f *Fixture) {
+	users := f.CreateUsers(t, 1)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		userID   uint
+		currency domain.Currency
+		want     *model.Balance
+		wantErr  error
+	}{
+		{"取得できる", users[0].ID, domain.JPY, &model.Balance{UserID: users[0].ID, Currency: domain.JPY, Amount: InitialBalance}, nil},
+		{"持っていない通貨は空のウォレット", users[0].ID, domain.USD, &model.Balance{UserID: users[0].ID, Currency: domain.USD}, nil},
+		{"存在しないユーザ", 100, domain.JPY, nil, domain.ErrNoSuchEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.BalanceRepo.Get(ctx, tt.userID, tt.currency)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("BalanceRepository.Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("BalanceRepository.Get() mismatch (-want +got): \n %s", diff)
+			}
+		})
+	}
+}
+
+func testBalanceList(t *testing.T, f *Fixture) {
+	users := f.CreateUsers(t, 1)
+	ctx := context.Background()
+	if _, err := f.BalanceRepo.SetOverdraftLimit(ctx, users[0].ID, domain.USD, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		userID  uint
+		want    []*model.Balance
+		wantErr error
+	}{
+		{
+			"通貨順に取得できる",
+			users[0].ID,
+			[]*model.Balance{
+				{UserID: users[0].ID, Currency: domain.JPY, Amount: InitialBalance},
+				{UserID: users[0].ID, Currency: domain.USD, OverdraftLimit: 100},
+			},
+			nil,
+		},
+		{"存在しないユーザ", 100, nil, domain.ErrNoSuchEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.BalanceRepo.List(ctx, tt.userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("BalanceRepository.List() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("BalanceRepository.List() mismatch (-want +got): \n %s", diff)
+			}
+		})
+	}
+}
+
+func testBalanceSetOverdraftLimit(t *testing.T, f *Fixture) {
+	users := f.CreateUsers(t, 1)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		userID   uint
+		currency domain.Currency
+		limit    int64
+		want     *model.Balance
+		wantErr  error
+	}{
+		{"設定できる", users[0].ID, domain.JPY, 500, &model.Balance{UserID: users[0].ID, Currency: domain.JPY, Amount: InitialBalance, OverdraftLimit: 500}, nil},
+		{"ウォレットがなければ作られる", users[0].ID, domain.USD, 100, &model.Balance{UserID: users[0].ID, Currency: domain.USD, OverdraftLimit: 100}, nil},
+		{"存在しないユーザ", 100, domain.JPY, 500, nil, domain.ErrNoSuchEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.BalanceRepo.SetOverdraftLimit(ctx, tt.userID, tt.currency, tt.limit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("BalanceRepository.SetOverdraftLimit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("BalanceRepository.SetOverdraftLimit() mismatch (-want +got): \n %s", diff)
+			}
+		})
+	}
+}
+
+func testBalanceAddToUsers(t *testing.T, f *Fixture) {
+	users := f.CreateUsers(t, 3)
+	ctx := context.Background()
+
+	// 2人目から2人に加算する
+	if err := f.BalanceRepo.AddToUsers(ctx, domain.JPY, 100, time.Time{}, 2, 1); err != nil {
+		t.Fatalf("BalanceRepository.AddToUsers() error = %v", err)
+	}
+	want := []int64{InitialBalance, InitialBalance + 100, InitialBalance + 100}
+	for i, u := range users {
+		assertBalance(t, f, u.ID, want[i], 0)
+	}
+}
+
+func testPaymentTry(t *testing.T, f *Fixture) {
+	users := f.CreateUsers(t, 1)
+	ctx := context.Background()
+	userID := users[0].ID
+
+	tests := []struct {
+		name         string
+		uuid         string
+		userID       uint
+		amount       int64
+		want         *model.PaymentTransaction
+		wantErr      error
+		wantReserved int64
+	}{
+		{
+			"加算は仮押さえしない",
+			"credit", userID, 100,
+			&model.PaymentTransaction{UUID: "credit", UserID: userID, Currency: domain.JPY, Amount: 100, Status: model.PaymentStatusTried,
+				ResultBalance: &model.Balance{UserID: userID, Currency: domain.JPY, Amount: InitialBalance}},
+			nil, 0,
+		},
+		{
+			"減算は仮押さえする",
+			"debit", userID, -300,
+			&model.PaymentTransaction{UUID: "debit", UserID: userID, Currency: domain.JPY, Amount: -300, Status: model.PaymentStatusTried,
+				ResultBalance: &model.Balance{UserID: userID, Currency: domain.JPY, Amount: InitialBalance, ReservedAmount: 300}},
+			nil, 300,
+		},
+		{"同じuuid", "debit", userID, -300, nil, domain.ErrDuplicateUUID, 300},
+		{"仮押さえを除いた残高が不足", "short", userID, -(InitialBalance - 300 + 1), nil, domain.ErrShortBalance, 300},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.PaymentRepo.Try(ctx, tt.uuid, tt.userID, domain.JPY, tt.amount, time.Minute)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PaymentTransactionRepository.Try() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got, ignoreTimes); diff != "" {
+				t.Errorf("PaymentTransactionRepository.Try() mismatch (-want +got): \n %s", diff)
+			}
+			assertBalance(t, f, userID, InitialBalance, tt.wantReserved)
+		})
+	}
+
+	// 残高不足のTryは残らない
+	if _, err := f.PaymentRepo.Get(ctx, "short"); !errors.Is(err, domain.ErrInvalidUUID) {
+		t.Errorf("PaymentTransactionRepository.Get() error = %v, wantErr %v", err, domain.ErrInvalidUUID)
+	}
+	if _, err := f.PaymentRepo.Try(ctx, "unknown user", 100, domain.JPY, -100, time.Minute); err == nil {
+		t.Errorf("PaymentTransactionRepository.Try() to an unknown user succeeded")
+	}
+}
+
+func testPaymentConfirm(t *testing.T, f *Fixture) {
+	users := f.CreateUsers(t, 1)
+	ctx := context.Background()
+	userID := users[0].ID
+	tryPayment(t, f, "credit", userID, 100)
+	tryPayment(t, f, "debit", userID, -300)
+
+	tests := []struct {
+		name        string
+		uuid        string
+		want        *model.PaymentTransaction
+		wantErr     error
+		wantAmount  int64
+		wantReserve int64
+	}{
+		{
+			"加算を確定できる",
+			"credit",
+			&model.PaymentTransaction{UUID: "credit", UserID: userID, Currency: domain.JPY, Amount: 100, Status: model.PaymentStatusConfirmed,
+				ResultBalance: &model.Balance{UserID: userID, Currency: domain.JPY, Amount: InitialBalance + 100, ReservedAmount: 300}},
+			nil, InitialBalance + 100, 300,
+		},
+		{
+			"減算を確定すると仮押さえが解放される",
+			"debit",
+			&model.PaymentTransaction{UUID: "debit", UserID: userID, Currency: domain.JPY, Amount: -300, Status: model.PaymentStatusConfirmed,
+				ResultBalance: &model.Balance{UserID: userID, Currency: domain.JPY, Amount: InitialBalance + 100 - 300}},
+			nil, InitialBalance + 100 - 300, 0,
+		},
+		{"確定済み", "debit", nil, domain.ErrIllegalTransition, InitialBalance + 100 - 300, 0},
+		{"存在しないuuid", "unknown", nil, domain.ErrInvalidUUID, InitialBalance + 100 - 300, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.PaymentRepo.Confirm(ctx, tt.uuid)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PaymentTransactionRepository.Confirm() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got, ignoreTimes); diff != "" {
+				t.Errorf("PaymentTransactionRepository.Confirm() mismatch (-want +got): \n %s", diff)
+			}
+			assertBalance(t, f, userID, tt.wantAmount, tt.wantReserve)
+		})
+	}
+
+	// 後の取引で残高が変わっても、確定した直後の残高は変わらない
+	pt, err := f.PaymentRepo.Get(ctx, "credit")
+	if err != nil {
+		t.Fatalf("PaymentTransactionRepository.Get() error = %v", err)
+	}
+	want := &model.Balance{UserID: userID, Currency: domain.JPY, Amount: InitialBalance + 100, ReservedAmount: 300}
+	if diff := cmp.Diff(want, pt.ResultBalance); diff != "" {
+		t.Errorf("PaymentTransactionRepository.Get() result balance mismatch (-want +got): \n %s", diff)
+	}
+}
+
+func testPaymentConfirmFailure(t *testing.T, f *Fixture) {
+	users := f.CreateUsers(t, 1)
+	ctx := context.Background()
+	userID := users[0].ID
+	if _, err := f.BalanceRepo.SetOverdraftLimit(ctx, userID, domain.JPY, 500); err != nil {
+		t.Fatal(err)
+	}
+	tryPayment(t, f, "debit", userID, -1500)
+
+	// Tryの後に与信枠が下げられると残高不足でConfirmできず、取引は失敗になって仮押さえが解放される
+	if _, err := f.BalanceRepo.SetOverdraftLimit(ctx, userID, domain.JPY, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.PaymentRepo.Confirm(ctx, "debit"); !errors.Is(err, domain.ErrShortBalance) {
+		t.Fatalf("PaymentTransactionRepository.Confirm() error = %v, wantErr %v", err, domain.ErrShortBalance)
+	}
+	pt, err := f.PaymentRepo.Get(ctx, "debit")
+	if err != nil {
+		t.Fatalf("PaymentTransactionRepository.Get() error = %v", err)
+	}
+	if pt.Status != model.PaymentStatusFailed {
+		t.Errorf("PaymentTransactionRepository.Get() status = %v, want %v", pt.Status, model.PaymentStatusFailed)
+	}
+	assertBalance(t, f, userID, InitialBalance, 0)
+
+	// 失敗した取引はConfirmもCancelもできない
+	if _, err := f.PaymentRepo.Confirm(ctx, "debit"); !errors.Is(err, domain.ErrIllegalTransition) {
+		t.Errorf("PaymentTransactionRepository.Confirm() error = %v, wantErr %v", err, domain.ErrIllegalTransition)
+	}
+	if _, err := f.PaymentRepo.Cancel(ctx, "debit"); !errors.Is(err, domain.ErrIllegalTransition) {
+		t.Errorf("PaymentTransactionRepository.Cancel() error = %v, wantErr %v", err, domain.ErrIllegalTransition)
+	}
+	assertBalance(t, f, userID, InitialBalance, 0)
+}
+
+func testPaymentCancel(t *testing.T, f *Fixture) {
+	users := f.CreateUsers(t, 1)
+	ctx := context.Background()
+	userID := users[0].ID
+	tryPayment(t, f, "debit", userID, -300)
+
+	tests := []struct {
+		name    string
+		uuid    string
+		want    *model.PaymentTransaction
+		wantErr error
+	}{
+		{
+			"取り消すと仮押さえが解放される",
+			"debit",
+			&model.PaymentTransaction{UUID: "debit", UserID: userID, Currency: domain.JPY, Amount: -300, Status: model.PaymentStatusCancelled,
+				ResultBalance: &model.Balance{UserID: userID, Currency: domain.JPY, Amount: InitialBalance}},
+			nil,
+		},
+		{"取り消し済み", "debit", nil, domain.ErrIllegalTransition},
+		{"存在しないuuid", "unknown", nil, domain.ErrInvalidUUID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.PaymentRepo.Cancel(ctx, tt.uuid)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PaymentTransactionRepository.Cancel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got, ignoreTimes); diff != "" {
+				t.Errorf("PaymentTransactionRepository.Cancel() mismatch (-want +got): \n %s", diff)
+			}
+			assertBalance(t, f, userID, InitialBalance, 0)
+		})
+	}
+
+	// 取り消したTryは確定できない
+	if _, err := f.PaymentRepo.Confirm(ctx, "debit"); !errors.Is(err, domain.ErrIllegalTransition) {
+		t.Errorf("PaymentTransactionRepository.Confirm() error = %v, wantErr %v", err, domain.ErrIllegalTransition)
+	}
+}
+
+func testPaymentExpireTries(t *testing.T, f *Fixture) {
+	users := f.CreateUsers(t, 1)
+	ctx := context.Background()
+	userID := users[0].ID
+	tryPayment(t, f, "debit1", userID, -100)
+	tryPayment(t, f, "debit2", userID, -200)
+	tryPayment(t, f, "confirmed", userID, -300)
+	if _, err := f.PaymentRepo.Confirm(ctx, "confirmed"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 期限前は何も処理しない
+	if got, err := f.PaymentRepo.ExpireTries(ctx, time.Now(), 10); err != nil || got != 0 {
+		t.Errorf("PaymentTransactionRepository.ExpireTries() = %v, %v, want 0", got, err)
+	}
+	// 件数の上限ずつ処理する
+	expireTime := time.Now().Add(2 * time.Minute)
+	for i, want := range []int{1, 1, 0} {
+		got, err := f.PaymentRepo.ExpireTries(ctx, expireTime, 1)
+		if err != nil {
+			t.Fatalf("PaymentTransactionRepository.ExpireTries() error = %v", err)
+		}
+		if got != want {
+			t.Errorf("PaymentTransactionRepository.ExpireTries() #%d = %v, want %v", i, got, want)
+		}
+	}
+	assertBalance(t, f, userID, InitialBalance-300, 0)
+
+	for _, uuid := range []string{"debit1", "debit2"} {
+		pt, err := f.PaymentRepo.Get(ctx, uuid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pt.Status != model.PaymentStatusExpired {
+			t.Errorf("status of %s = %v, want %v", uuid, pt.Status, model.PaymentStatusExpired)
+		}
+		// 期限切れのTryは確定できない
+		if _, err := f.PaymentRepo.Confirm(ctx, uuid); !errors.Is(err, domain.ErrExpiredTransaction) {
+			t.Errorf("PaymentTransactionRepository.Confirm() error = %v, wantErr %v", err, domain.ErrExpiredTransaction)
+		}
+	}
+}
+
+func testPaymentList(t *testing.T, f *Fixture) {
+	users := f.CreateUsers(t, 2)
+	ctx := context.Background()
+	for i := 1; i <= 5; i++ {
+		tryPayment(t, f, fmt.Sprintf("pt%d", i), users[0].ID, int64(i*10))
+	}
+	tryPayment(t, f, "debit", users[0].ID, -10)
+	tryPayment(t, f, "other", users[1].ID, 10)
+
+	// 同じ時刻のTryもあるので、順序ではなくページングで漏れや重複がないことを確かめる
+	seen := map[string]bool{}
+	filter := model.PaymentTransactionFilter{Sign: model.AmountSignPositive, Limit: 2}
+	for page := 0; ; page++ {
+		got, err := f.PaymentRepo.List(ctx, users[0].ID, filter)
+		if err != nil {
+			t.Fatalf("PaymentTransactionRepository.List() error = %v", err)
+		}
+		if len(got) == 0 {
+			break
+		}
+		if page > 5 || len(got) > filter.Limit {
+			t.Fatalf("PaymentTransactionRepository.List() returned %d transactions on page %d", len(got), page)
+		}
+		for i, pt := range got {
+			if seen[pt.UUID] {
+				t.Errorf("PaymentTransactionRepository.List() returned %s twice", pt.UUID)
+			}
+			seen[pt.UUID] = true
+			if i > 0 && pt.TryTime.After(got[i-1].TryTime) {
+				t.Errorf("PaymentTransactionRepository.List() is not in the descending order of try time")
+			}
+		}
+		last := got[len(got)-1]
+		filter.BeforeTryTime = last.TryTime
+		filter.BeforeUUID = last.UUID
+	}
+	want := map[string]bool{"pt1": true, "pt2": true, "pt3": true, "pt4": true, "pt5": true}
+	if diff := cmp.Diff(want, seen); diff != "" {
+		t.Errorf("PaymentTransactionRepository.List() mismatch (-want +got): \n %s", diff)
+	}
+}
+
+func testPaymentConcurrentTry(t *testing.T, f *Fixture) {
+	users := f.CreateUsers(t, 1)
+	ctx := context.Background()
+	userID := users[0].ID
+
+	// 同時に減算しても、残高を超えて仮押さえしない
+	const count = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, count)
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := f.PaymentRepo.Try(ctx, fmt.Sprintf("debit%d", i), userID, domain.JPY, -100, time.Minute)
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, domain.ErrShortBalance):
+			t.Errorf("PaymentTransactionRepository.Try() error = %v", err)
+		}
+	}
+	if succeeded != InitialBalance/100 {
+		t.Errorf("PaymentTransactionRepository.Try() succeeded %d times, want %d", succeeded, InitialBalance/100)
+	}
+	assertBalance(t, f, userID, InitialBalance, InitialBalance)
+}
+
+var ignoreTimes = cmpopts.IgnoreFields(model.PaymentTransaction{}, "RequestFingerprint", "TryTime", "ExpireTime", "ConfirmTime", "CancelTime", "ExpiredTime")
+
+func tryPayment(t *testing.T, f *Fixture, uuid string, userID uint, amount int64) {
+	t.Helper()
+	if _, err := f.PaymentRepo.Try(context.Background(), uuid, userID, domain.JPY, amount, time.Minute); err != nil {
+		t.Fatalf("try %s error: %v", uuid, err)
+	}
+}
+
+func assertBalance(t *testing.T, f *Fixture, userID uint, amount, reserved int64) {
+	t.Helper()
+	b, err := f.BalanceRepo.Get(context.Background(), userID, domain.JPY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Amount != amount || b.ReservedAmount != reserved {
+		t.Errorf("balance of user %d = %d (reserved %d), want %d (reserved %d)", userID, b.Amount, b.ReservedAmount, amount, reserved)
+	}
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type SpendingLimitRepository interface {
+	// Set creates or replaces the limit of the scope in the currency.
+	Set(ctx context.Context, limit *model.SpendingLimit) (*model.SpendingLimit, error)
+	// List returns the limits of the scope ordered by currency.
+	List(ctx context.Context, scope model.SpendingLimitScope, scopeID string) ([]*model.SpendingLimit, error)
+	// SetUserTier assigns the tier to the user. It returns domain.ErrNoSuchEntity when the user does not exist.
+	SetUserTier(ctx context.Context, userID uint, tier string) (*model.User, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type TransferRepository interface {
+	Get(ctx context.Context, uuid string) (*model.Transfer, error)
+	Try(ctx context.Context, uuid string, fromUserID, toUserID uint, currency domain.Currency, amount int64, ttl time.Duration) (*model.Transfer, error)
+	Confirm(ctx context.Context, uuid string) (*model.Transfer, error)
+	Cancel(ctx context.Context, uuid string) (*model.Transfer, error)
+	// ExpireTries expires at most limit tried transfers whose expire time has passed at now,
+	// releasing their reserved balances. It returns the number of expired transfers.
+	ExpireTries(ctx context.Context, now time.Time, limit int) (int, error)
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type WebhookRepository interface {
+	Create(ctx context.Context, subscription *model.WebhookSubscription) (*model.WebhookSubscription, error)
+	// Get returns the subscription. It returns domain.ErrNoSuchEntity when there is no such subscription.
+	Get(ctx context.Context, id uint64) (*model.WebhookSubscription, error)
+	// ClaimPendingDeliveries returns at most limit pending deliveries whose next attempt time has come, with their
+	// subscriptions and events, and postpones their next attempt by lease so that another dispatcher does not send them
+	// at the same time. A delivery which is not updated within the lease is claimed again.
+	ClaimPendingDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.WebhookDelivery, error)
+	// UpdateDelivery saves the state of the delivery.
+	UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+	// ListDeliveries returns at most limit deliveries of the subscription whose id is less than beforeID
+	// (all of them when beforeID is 0), from the newest one.
+	ListDeliveries(ctx context.Context, subscriptionID, beforeID uint64, limit int) ([]*model.WebhookDelivery, error)
+}
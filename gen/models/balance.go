@@ -8,7 +8,9 @@ package models
 import (
 	"bytes"
 	"encoding/json"
+	"strconv"
 
+	"github.com/go-openapi/errors"
 	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
 )
@@ -18,8 +20,20 @@ import (
 // swagger:model balance
 type Balance struct {
 
-	// amount
-	Amount int32 `json:"amount,omitempty"`
+	// 残高（仮押さえ分を含む）。与信枠の分や一斉加算の取り消しで負になることがある
+	Amount string `json:"amount,omitempty"`
+
+	// 利用可能残高（残高と与信枠の合計から、Try済みの減算を仮押さえした残り）
+	Available string `json:"available,omitempty"`
+
+	// 通貨コード（JPY, USD, EUR, PTS）。金額はすべてこの通貨の補助単位の整数を10進数の文字列で表す
+	Currency string `json:"currency,omitempty"`
+
+	// ポイント（PTS）のウォレットの失効予定（失効日時の近い順に最大10件）
+	Expirations []*PointExpiration `json:"expirations"`
+
+	// 与信枠。残高はこの額まで負になれる
+	OverdraftLimit string `json:"overdraft_limit,omitempty"`
 
 	// user id
 	UserID int32 `json:"user_id,omitempty"`
@@ -29,8 +43,20 @@ type Balance struct {
 func (m *Balance) UnmarshalJSON(data []byte) error {
 	var props struct {
 
-		// amount
-		Amount int32 `json:"amount,omitempty"`
+		// 残高（仮押さえ分を含む）。与信枠の分や一斉加算の取り消しで負になることがある
+		Amount string `json:"amount,omitempty"`
+
+		// 利用可能残高（残高と与信枠の合計から、Try済みの減算を仮押さえした残り）
+		Available string `json:"available,omitempty"`
+
+		// 通貨コード（JPY, USD, EUR, PTS）。金額はすべてこの通貨の補助単位の整数を10進数の文字列で表す
+		Currency string `json:"currency,omitempty"`
+
+		// ポイント（PTS）のウォレットの失効予定（失効日時の近い順に最大10件）
+		Expirations []*PointExpiration `json:"expirations"`
+
+		// 与信枠。残高はこの額まで負になれる
+		OverdraftLimit string `json:"overdraft_limit,omitempty"`
 
 		// user id
 		UserID int32 `json:"user_id,omitempty"`
@@ -43,12 +69,50 @@ func (m *Balance) UnmarshalJSON(data []byte) error {
 	}
 
 	m.Amount = props.Amount
+	m.Available = props.Available
+	m.Currency = props.Currency
+	m.Expirations = props.Expirations
+	m.OverdraftLimit = props.OverdraftLimit
 	m.UserID = props.UserID
 	return nil
 }
 
 // Validate validates this balance
 func (m *Balance) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateExpirations(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *Balance) validateExpirations(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Expirations) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Expirations); i++ {
+		if swag.IsZero(m.Expirations[i]) { // not required
+			continue
+		}
+
+		if m.Expirations[i] != nil {
+			if err := m.Expirations[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("expirations" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
 	return nil
 }
 
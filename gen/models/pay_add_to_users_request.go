@@ -22,7 +22,14 @@ type PayAddToUsersRequest struct {
 
 	// amount
 	// Required: true
-	Amount *int32 `json:"amount"`
+	Amount *string `json:"amount"`
+
+	// 通貨コード（JPY, USD, EUR, PTS）。省略時はJPY
+	Currency string `json:"currency,omitempty"`
+
+	// 加算したポイントが失効するまでの日数。PTSのみ指定でき、省略時は失効しない
+	// Minimum: 1
+	ExpiresInDays int32 `json:"expires_in_days,omitempty"`
 
 	// limit
 	Limit int32 `json:"limit,omitempty"`
@@ -37,7 +44,14 @@ func (m *PayAddToUsersRequest) UnmarshalJSON(data []byte) error {
 
 		// amount
 		// Required: true
-		Amount *int32 `json:"amount"`
+		Amount *string `json:"amount"`
+
+		// 通貨コード（JPY, USD, EUR, PTS）。省略時はJPY
+		Currency string `json:"currency,omitempty"`
+
+		// 加算したポイントが失効するまでの日数。PTSのみ指定でき、省略時は失効しない
+		// Minimum: 1
+		ExpiresInDays int32 `json:"expires_in_days,omitempty"`
 
 		// limit
 		Limit int32 `json:"limit,omitempty"`
@@ -53,6 +67,8 @@ func (m *PayAddToUsersRequest) UnmarshalJSON(data []byte) error {
 	}
 
 	m.Amount = props.Amount
+	m.Currency = props.Currency
+	m.ExpiresInDays = props.ExpiresInDays
 	m.Limit = props.Limit
 	m.Offset = props.Offset
 	return nil
@@ -66,6 +82,10 @@ func (m *PayAddToUsersRequest) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateExpiresInDays(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -81,6 +101,19 @@ func (m *PayAddToUsersRequest) validateAmount(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *PayAddToUsersRequest) validateExpiresInDays(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.ExpiresInDays) { // not required
+		return nil
+	}
+
+	if err := validate.MinimumInt("expires_in_days", "body", int64(m.ExpiresInDays), 1, false); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *PayAddToUsersRequest) MarshalBinary() ([]byte, error) {
 	if m == nil {
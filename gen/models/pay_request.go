@@ -20,8 +20,15 @@ import (
 // swagger:model payRequest
 type PayRequest struct {
 
-	// amount
-	Amount int32 `json:"amount,omitempty"`
+	// 加減算額（補助単位の整数の10進数文字列。減算は負の数）
+	Amount string `json:"amount,omitempty"`
+
+	// 通貨コード（JPY, USD, EUR, PTS）。省略時はJPY
+	Currency string `json:"currency,omitempty"`
+
+	// Tryの有効期限（秒）。省略時はサーバーのデフォルト値
+	// Minimum: 1
+	ExpiresIn int32 `json:"expires_in,omitempty"`
 
 	// 冪等性キー
 	// Required: true
@@ -36,8 +43,15 @@ type PayRequest struct {
 func (m *PayRequest) UnmarshalJSON(data []byte) error {
 	var props struct {
 
-		// amount
-		Amount int32 `json:"amount,omitempty"`
+		// 加減算額（補助単位の整数の10進数文字列。減算は負の数）
+		Amount string `json:"amount,omitempty"`
+
+		// 通貨コード（JPY, USD, EUR, PTS）。省略時はJPY
+		Currency string `json:"currency,omitempty"`
+
+		// Tryの有効期限（秒）。省略時はサーバーのデフォルト値
+		// Minimum: 1
+		ExpiresIn int32 `json:"expires_in,omitempty"`
 
 		// 冪等性キー
 		// Required: true
@@ -55,6 +69,8 @@ func (m *PayRequest) UnmarshalJSON(data []byte) error {
 	}
 
 	m.Amount = props.Amount
+	m.Currency = props.Currency
+	m.ExpiresIn = props.ExpiresIn
 	m.IdempotencyKey = props.IdempotencyKey
 	m.UserID = props.UserID
 	return nil
@@ -64,6 +80,10 @@ func (m *PayRequest) UnmarshalJSON(data []byte) error {
 func (m *PayRequest) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateExpiresIn(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateIdempotencyKey(formats); err != nil {
 		res = append(res, err)
 	}
@@ -78,6 +98,19 @@ func (m *PayRequest) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *PayRequest) validateExpiresIn(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.ExpiresIn) { // not required
+		return nil
+	}
+
+	if err := validate.MinimumInt("expires_in", "body", int64(m.ExpiresIn), 1, false); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (m *PayRequest) validateIdempotencyKey(formats strfmt.Registry) error {
 
 	if err := validate.Required("idempotency_key", "body", m.IdempotencyKey); err != nil {
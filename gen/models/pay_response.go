@@ -31,9 +31,23 @@ type PayResponse struct {
 	// Format: date-time
 	ConfirmTime strfmt.DateTime `json:"confirm_time,omitempty"`
 
+	// この時刻を過ぎるとConfirmできない
+	// Format: date-time
+	ExpireTime strfmt.DateTime `json:"expire_time,omitempty"`
+
+	// 期限切れとして処理された時刻
+	// Format: date-time
+	ExpiredTime strfmt.DateTime `json:"expired_time,omitempty"`
+
 	// 冪等性キー
 	IdempotencyKey string `json:"idempotency_key,omitempty"`
 
+	// 返金済みの額の合計
+	RefundedAmount string `json:"refunded_amount,omitempty"`
+
+	// ステータス（tried, confirmed, cancelled, expired, failed, reversed, partially_refunded, refunded）
+	Status string `json:"status,omitempty"`
+
 	// try time
 	// Format: date-time
 	TryTime strfmt.DateTime `json:"try_time,omitempty"`
@@ -54,9 +68,23 @@ func (m *PayResponse) UnmarshalJSON(data []byte) error {
 		// Format: date-time
 		ConfirmTime strfmt.DateTime `json:"confirm_time,omitempty"`
 
+		// この時刻を過ぎるとConfirmできない
+		// Format: date-time
+		ExpireTime strfmt.DateTime `json:"expire_time,omitempty"`
+
+		// 期限切れとして処理された時刻
+		// Format: date-time
+		ExpiredTime strfmt.DateTime `json:"expired_time,omitempty"`
+
 		// 冪等性キー
 		IdempotencyKey string `json:"idempotency_key,omitempty"`
 
+		// 返金済みの額の合計
+		RefundedAmount string `json:"refunded_amount,omitempty"`
+
+		// ステータス（tried, confirmed, cancelled, expired, failed, reversed, partially_refunded, refunded）
+		Status string `json:"status,omitempty"`
+
 		// try time
 		// Format: date-time
 		TryTime strfmt.DateTime `json:"try_time,omitempty"`
@@ -71,7 +99,11 @@ func (m *PayResponse) UnmarshalJSON(data []byte) error {
 	m.Balance = props.Balance
 	m.CancelTime = props.CancelTime
 	m.ConfirmTime = props.ConfirmTime
+	m.ExpireTime = props.ExpireTime
+	m.ExpiredTime = props.ExpiredTime
 	m.IdempotencyKey = props.IdempotencyKey
+	m.RefundedAmount = props.RefundedAmount
+	m.Status = props.Status
 	m.TryTime = props.TryTime
 	return nil
 }
@@ -92,6 +124,14 @@ func (m *PayResponse) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateExpireTime(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateExpiredTime(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateTryTime(formats); err != nil {
 		res = append(res, err)
 	}
@@ -146,6 +186,32 @@ func (m *PayResponse) validateConfirmTime(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *PayResponse) validateExpireTime(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.ExpireTime) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("expire_time", "body", "date-time", m.ExpireTime.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *PayResponse) validateExpiredTime(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.ExpiredTime) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("expired_time", "body", "date-time", m.ExpiredTime.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (m *PayResponse) validateTryTime(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.TryTime) { // not required
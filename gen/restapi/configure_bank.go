@@ -38,11 +38,61 @@ func configureAPI(api *operations.BankAPI) http.Handler {
 
 	api.JSONProducer = runtime.JSONProducer()
 
+	if api.BankCreateBulkCreditHandler == nil {
+		api.BankCreateBulkCreditHandler = bank.CreateBulkCreditHandlerFunc(func(params bank.CreateBulkCreditParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.CreateBulkCredit has not yet been implemented")
+		})
+	}
+	if api.BankCreateWebhookHandler == nil {
+		api.BankCreateWebhookHandler = bank.CreateWebhookHandlerFunc(func(params bank.CreateWebhookParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.CreateWebhook has not yet been implemented")
+		})
+	}
+	if api.BankExchangeHandler == nil {
+		api.BankExchangeHandler = bank.ExchangeHandlerFunc(func(params bank.ExchangeParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.Exchange has not yet been implemented")
+		})
+	}
 	if api.BankGetBalanceHandler == nil {
 		api.BankGetBalanceHandler = bank.GetBalanceHandlerFunc(func(params bank.GetBalanceParams) middleware.Responder {
 			return middleware.NotImplemented("operation bank.GetBalance has not yet been implemented")
 		})
 	}
+	if api.BankGetBulkCreditHandler == nil {
+		api.BankGetBulkCreditHandler = bank.GetBulkCreditHandlerFunc(func(params bank.GetBulkCreditParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.GetBulkCredit has not yet been implemented")
+		})
+	}
+	if api.BankListBalanceLogsHandler == nil {
+		api.BankListBalanceLogsHandler = bank.ListBalanceLogsHandlerFunc(func(params bank.ListBalanceLogsParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.ListBalanceLogs has not yet been implemented")
+		})
+	}
+	if api.BankListExchangeRatesHandler == nil {
+		api.BankListExchangeRatesHandler = bank.ListExchangeRatesHandlerFunc(func(params bank.ListExchangeRatesParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.ListExchangeRates has not yet been implemented")
+		})
+	}
+	if api.BankListPaymentsHandler == nil {
+		api.BankListPaymentsHandler = bank.ListPaymentsHandlerFunc(func(params bank.ListPaymentsParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.ListPayments has not yet been implemented")
+		})
+	}
+	if api.BankListRefundsHandler == nil {
+		api.BankListRefundsHandler = bank.ListRefundsHandlerFunc(func(params bank.ListRefundsParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.ListRefunds has not yet been implemented")
+		})
+	}
+	if api.BankListSpendingLimitsHandler == nil {
+		api.BankListSpendingLimitsHandler = bank.ListSpendingLimitsHandlerFunc(func(params bank.ListSpendingLimitsParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.ListSpendingLimits has not yet been implemented")
+		})
+	}
+	if api.BankListWebhookDeliveriesHandler == nil {
+		api.BankListWebhookDeliveriesHandler = bank.ListWebhookDeliveriesHandlerFunc(func(params bank.ListWebhookDeliveriesParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.ListWebhookDeliveries has not yet been implemented")
+		})
+	}
 	if api.BankPaymentAddToUsersHandler == nil {
 		api.BankPaymentAddToUsersHandler = bank.PaymentAddToUsersHandlerFunc(func(params bank.PaymentAddToUsersParams) middleware.Responder {
 			return middleware.NotImplemented("operation bank.PaymentAddToUsers has not yet been implemented")
@@ -58,11 +108,56 @@ func configureAPI(api *operations.BankAPI) http.Handler {
 			return middleware.NotImplemented("operation bank.PaymentConfirm has not yet been implemented")
 		})
 	}
+	if api.BankPaymentRefundHandler == nil {
+		api.BankPaymentRefundHandler = bank.PaymentRefundHandlerFunc(func(params bank.PaymentRefundParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.PaymentRefund has not yet been implemented")
+		})
+	}
 	if api.BankPaymentTryHandler == nil {
 		api.BankPaymentTryHandler = bank.PaymentTryHandlerFunc(func(params bank.PaymentTryParams) middleware.Responder {
 			return middleware.NotImplemented("operation bank.PaymentTry has not yet been implemented")
 		})
 	}
+	if api.BankReverseBulkCreditHandler == nil {
+		api.BankReverseBulkCreditHandler = bank.ReverseBulkCreditHandlerFunc(func(params bank.ReverseBulkCreditParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.ReverseBulkCredit has not yet been implemented")
+		})
+	}
+	if api.BankSetOverdraftLimitHandler == nil {
+		api.BankSetOverdraftLimitHandler = bank.SetOverdraftLimitHandlerFunc(func(params bank.SetOverdraftLimitParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.SetOverdraftLimit has not yet been implemented")
+		})
+	}
+	if api.BankSetSpendingLimitHandler == nil {
+		api.BankSetSpendingLimitHandler = bank.SetSpendingLimitHandlerFunc(func(params bank.SetSpendingLimitParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.SetSpendingLimit has not yet been implemented")
+		})
+	}
+	if api.BankSetUserTierHandler == nil {
+		api.BankSetUserTierHandler = bank.SetUserTierHandlerFunc(func(params bank.SetUserTierParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.SetUserTier has not yet been implemented")
+		})
+	}
+	if api.BankTransferCancelHandler == nil {
+		api.BankTransferCancelHandler = bank.TransferCancelHandlerFunc(func(params bank.TransferCancelParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.TransferCancel has not yet been implemented")
+		})
+	}
+	if api.BankTransferConfirmHandler == nil {
+		api.BankTransferConfirmHandler = bank.TransferConfirmHandlerFunc(func(params bank.TransferConfirmParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.TransferConfirm has not yet been implemented")
+		})
+	}
+	if api.BankTransferTryHandler == nil {
+		api.BankTransferTryHandler = bank.TransferTryHandlerFunc(func(params bank.TransferTryParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.TransferTry has not yet been implemented")
+		})
+	}
+	if api.BankUploadExchangeRatesHandler == nil {
+		api.BankUploadExchangeRatesHandler = bank.UploadExchangeRatesHandlerFunc(func(params bank.UploadExchangeRatesParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.UploadExchangeRates has not yet been implemented")
+		})
+	}
 
 	api.PreServerShutdown = func() {}
 
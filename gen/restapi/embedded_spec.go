@@ -31,14 +31,14 @@ func init() {
     "version": "version not set"
   },
   "paths": {
-    "/balances/{userId}": {
-      "get": {
-        "description": "ユーザの残高を取得",
+    "/admin/balances/{userId}/overdraft": {
+      "put": {
+        "description": "管理者がユーザの通貨ごとのウォレットの与信枠を設定する。ポイントには設定できない",
         "tags": [
           "Bank"
         ],
-        "summary": "GetBalance",
-        "operationId": "GetBalance",
+        "summary": "SetOverdraftLimit",
+        "operationId": "SetOverdraftLimit",
         "parameters": [
           {
             "type": "integer",
@@ -46,6 +46,14 @@ func init() {
             "name": "userId",
             "in": "path",
             "required": true
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/overdraftLimitRequest"
+            }
           }
         ],
         "responses": {
@@ -64,27 +72,30 @@ func init() {
         }
       }
     },
-    "/payments/add_to_users": {
+    "/admin/exchange_rates": {
       "post": {
-        "description": "（limit,offsetを指定して）ユーザの残高に一斉に加算する",
+        "description": "管理者が為替レートを登録する。すべてのレートを1つのDBトランザクションで登録し、1つでも不正なレートがあれば何も登録しない",
         "tags": [
           "Bank"
         ],
-        "summary": "PaymentAddToUsers",
-        "operationId": "PaymentAddToUsers",
+        "summary": "UploadExchangeRates",
+        "operationId": "UploadExchangeRates",
         "parameters": [
           {
             "name": "body",
             "in": "body",
             "required": true,
             "schema": {
-              "$ref": "#/definitions/payAddToUsersRequest"
+              "$ref": "#/definitions/exchangeRateUploadRequest"
             }
           }
         ],
         "responses": {
           "200": {
-            "description": "A successful response."
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/exchangeRateList"
+            }
           },
           "default": {
             "description": "An unexpected error response",
@@ -95,29 +106,35 @@ func init() {
         }
       }
     },
-    "/payments/cancel": {
-      "post": {
-        "description": "支払い（ユーザの残高の加減算）をCancelする",
+    "/admin/spending_limits": {
+      "get": {
+        "description": "管理者がユーザまたはユーザ区分の利用上限を通貨順に取得する",
         "tags": [
           "Bank"
         ],
-        "summary": "PaymentCancel",
-        "operationId": "PaymentCancel",
+        "summary": "ListSpendingLimits",
+        "operationId": "ListSpendingLimits",
         "parameters": [
           {
-            "name": "body",
-            "in": "body",
-            "required": true,
-            "schema": {
-              "$ref": "#/definitions/payRequest"
-            }
+            "type": "string",
+            "description": "上限の対象（user, tier）",
+            "name": "scope",
+            "in": "query",
+            "required": true
+          },
+          {
+            "type": "string",
+            "description": "userの場合はユーザID、tierの場合はユーザ区分",
+            "name": "scope_id",
+            "in": "query",
+            "required": true
           }
         ],
         "responses": {
           "200": {
             "description": "A successful response.",
             "schema": {
-              "$ref": "#/definitions/payResponse"
+              "$ref": "#/definitions/spendingLimitList"
             }
           },
           "default": {
@@ -127,23 +144,21 @@ func init() {
             }
           }
         }
-      }
-    },
-    "/payments/confirm": {
-      "post": {
-        "description": "支払い（ユーザの残高の加減算）をConfirmする",
+      },
+      "put": {
+        "description": "管理者がユーザまたはユーザ区分の通貨ごとの利用上限を設定する。同じ対象と通貨の上限は置き換える",
         "tags": [
           "Bank"
         ],
-        "summary": "PaymentConfirm",
-        "operationId": "PaymentConfirm",
+        "summary": "SetSpendingLimit",
+        "operationId": "SetSpendingLimit",
         "parameters": [
           {
             "name": "body",
             "in": "body",
             "required": true,
             "schema": {
-              "$ref": "#/definitions/payRequest"
+              "$ref": "#/definitions/spendingLimit"
             }
           }
         ],
@@ -151,7 +166,7 @@ func init() {
           "200": {
             "description": "A successful response.",
             "schema": {
-              "$ref": "#/definitions/payResponse"
+              "$ref": "#/definitions/spendingLimit"
             }
           },
           "default": {
@@ -163,21 +178,28 @@ func init() {
         }
       }
     },
-    "/payments/try": {
-      "post": {
-        "description": "支払い（ユーザの残高の加減算）をTryする",
+    "/admin/users/{userId}/tier": {
+      "put": {
+        "description": "管理者がユーザの区分を変更する。ユーザ個別の上限がない通貨は、区分の上限が適用される",
         "tags": [
           "Bank"
         ],
-        "summary": "PaymentTry",
-        "operationId": "PaymentTry",
+        "summary": "SetUserTier",
+        "operationId": "SetUserTier",
         "parameters": [
+          {
+            "type": "integer",
+            "format": "int32",
+            "name": "userId",
+            "in": "path",
+            "required": true
+          },
           {
             "name": "body",
             "in": "body",
             "required": true,
             "schema": {
-              "$ref": "#/definitions/payRequest"
+              "$ref": "#/definitions/userTierRequest"
             }
           }
         ],
@@ -185,7 +207,7 @@ func init() {
           "200": {
             "description": "A successful response.",
             "schema": {
-              "$ref": "#/definitions/payResponse"
+              "$ref": "#/definitions/user"
             }
           },
           "default": {
@@ -196,125 +218,10 @@ func init() {
           }
         }
       }
-    }
-  },
-  "definitions": {
-    "balance": {
-      "type": "object",
-      "properties": {
-        "amount": {
-          "type": "integer",
-          "format": "int32"
-        },
-        "user_id": {
-          "type": "integer",
-          "format": "int32"
-        }
-      }
-    },
-    "errorResponse": {
-      "type": "object",
-      "properties": {
-        "code": {
-          "type": "integer",
-          "format": "int32",
-          "maximum": 600,
-          "minimum": 100
-        },
-        "message": {
-          "type": "string"
-        }
-      }
-    },
-    "payAddToUsersRequest": {
-      "type": "object",
-      "required": [
-        "amount"
-      ],
-      "properties": {
-        "amount": {
-          "type": "integer",
-          "format": "int32"
-        },
-        "limit": {
-          "type": "integer",
-          "format": "int32"
-        },
-        "offset": {
-          "type": "integer",
-          "format": "int32"
-        }
-      }
-    },
-    "payRequest": {
-      "type": "object",
-      "required": [
-        "idempotency_key",
-        "user_id"
-      ],
-      "properties": {
-        "amount": {
-          "type": "integer",
-          "format": "int32"
-        },
-        "idempotency_key": {
-          "type": "string",
-          "title": "冪等性キー"
-        },
-        "user_id": {
-          "type": "integer",
-          "format": "int32"
-        }
-      }
     },
-    "payResponse": {
-      "type": "object",
-      "properties": {
-        "balance": {
-          "$ref": "#/definitions/balance"
-        },
-        "cancel_time": {
-          "type": "string",
-          "format": "date-time"
-        },
-        "confirm_time": {
-          "type": "string",
-          "format": "date-time"
-        },
-        "idempotency_key": {
-          "type": "string",
-          "title": "冪等性キー"
-        },
-        "try_time": {
-          "type": "string",
-          "format": "date-time"
-        }
-      }
-    }
-  },
-  "tags": [
-    {
-      "name": "Bank"
-    }
-  ]
-}`))
-	FlatSwaggerJSON = json.RawMessage([]byte(`{
-  "consumes": [
-    "application/json"
-  ],
-  "produces": [
-    "application/json"
-  ],
-  "swagger": "2.0",
-  "info": {
-    "description": "This is the API for m-bank.",
-    "title": "m-bank",
-    "version": "version not set"
-  },
-  "paths": {
     "/balances/{userId}": {
       "get": {
-        "description": "ユーザの残高を取得",
+        "description": "ユーザの通貨ごとのウォレット（残高）をすべて取得",
         "tags": [
           "Bank"
         ],
@@ -333,7 +240,7 @@ func init() {
           "200": {
             "description": "A successful response.",
             "schema": {
-              "$ref": "#/definitions/balance"
+              "$ref": "#/definitions/balanceList"
             }
           },
           "default": {
@@ -345,27 +252,62 @@ func init() {
         }
       }
     },
-    "/payments/add_to_users": {
-      "post": {
-        "description": "（limit,offsetを指定して）ユーザの残高に一斉に加算する",
+    "/balances/{userId}/logs": {
+      "get": {
+        "description": "ユーザの残高の変更履歴を新しい順に取得",
         "tags": [
           "Bank"
         ],
-        "summary": "PaymentAddToUsers",
-        "operationId": "PaymentAddToUsers",
+        "summary": "ListBalanceLogs",
+        "operationId": "ListBalanceLogs",
         "parameters": [
           {
-            "name": "body",
-            "in": "body",
-            "required": true,
-            "schema": {
-              "$ref": "#/definitions/payAddToUsersRequest"
-            }
+            "type": "integer",
+            "format": "int32",
+            "name": "userId",
+            "in": "path",
+            "required": true
+          },
+          {
+            "type": "string",
+            "description": "この通貨のウォレットの履歴（省略時はすべての通貨）",
+            "name": "currency",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "この時刻以降の履歴",
+            "name": "from",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "この時刻より前の履歴",
+            "name": "to",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "前のレスポンスのnext_cursor",
+            "name": "cursor",
+            "in": "query"
+          },
+          {
+            "type": "integer",
+            "format": "int32",
+            "description": "取得件数（デフォルト20、最大100）",
+            "name": "limit",
+            "in": "query"
           }
         ],
         "responses": {
           "200": {
-            "description": "A successful response."
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/balanceLogList"
+            }
           },
           "default": {
             "description": "An unexpected error response",
@@ -376,21 +318,21 @@ func init() {
         }
       }
     },
-    "/payments/cancel": {
+    "/bulk_credits": {
       "post": {
-        "description": "支払い（ユーザの残高の加減算）をCancelする",
+        "description": "対象のユーザの残高に一斉に加算するジョブを作成する。加算はワーカーがチャンク単位で行う。dry_runの場合はジョブを作成せず、対象数と合計金額を返す",
         "tags": [
           "Bank"
         ],
-        "summary": "PaymentCancel",
-        "operationId": "PaymentCancel",
+        "summary": "CreateBulkCredit",
+        "operationId": "CreateBulkCredit",
         "parameters": [
           {
             "name": "body",
             "in": "body",
             "required": true,
             "schema": {
-              "$ref": "#/definitions/payRequest"
+              "$ref": "#/definitions/bulkCreditRequest"
             }
           }
         ],
@@ -398,7 +340,7 @@ func init() {
           "200": {
             "description": "A successful response.",
             "schema": {
-              "$ref": "#/definitions/payResponse"
+              "$ref": "#/definitions/bulkCreditJob"
             }
           },
           "default": {
@@ -410,29 +352,28 @@ func init() {
         }
       }
     },
-    "/payments/confirm": {
-      "post": {
-        "description": "支払い（ユーザの残高の加減算）をConfirmする",
+    "/bulk_credits/{id}": {
+      "get": {
+        "description": "一斉加算のジョブの進捗を取得する",
         "tags": [
           "Bank"
         ],
-        "summary": "PaymentConfirm",
-        "operationId": "PaymentConfirm",
+        "summary": "GetBulkCredit",
+        "operationId": "GetBulkCredit",
         "parameters": [
           {
-            "name": "body",
-            "in": "body",
-            "required": true,
-            "schema": {
-              "$ref": "#/definitions/payRequest"
-            }
+            "type": "integer",
+            "format": "int64",
+            "name": "id",
+            "in": "path",
+            "required": true
           }
         ],
         "responses": {
           "200": {
             "description": "A successful response.",
             "schema": {
-              "$ref": "#/definitions/payResponse"
+              "$ref": "#/definitions/bulkCreditJob"
             }
           },
           "default": {
@@ -444,21 +385,28 @@ func init() {
         }
       }
     },
-    "/payments/try": {
+    "/bulk_credits/{id}/reverse": {
       "post": {
-        "description": "支払い（ユーザの残高の加減算）をTryする",
+        "description": "完了した一斉加算で各ユーザに加算した金額を減算する。減算はワーカーがチャンク単位で行う。同じmodeでのリトライは現在のジョブを返す",
         "tags": [
           "Bank"
         ],
-        "summary": "PaymentTry",
-        "operationId": "PaymentTry",
+        "summary": "ReverseBulkCredit",
+        "operationId": "ReverseBulkCredit",
         "parameters": [
+          {
+            "type": "integer",
+            "format": "int64",
+            "name": "id",
+            "in": "path",
+            "required": true
+          },
           {
             "name": "body",
             "in": "body",
             "required": true,
             "schema": {
-              "$ref": "#/definitions/payRequest"
+              "$ref": "#/definitions/bulkCreditReverseRequest"
             }
           }
         ],
@@ -466,7 +414,7 @@ func init() {
           "200": {
             "description": "A successful response.",
             "schema": {
-              "$ref": "#/definitions/payResponse"
+              "$ref": "#/definitions/bulkCreditJob"
             }
           },
           "default": {
@@ -477,82 +425,3406 @@ func init() {
           }
         }
       }
-    }
-  },
-  "definitions": {
-    "balance": {
-      "type": "object",
-      "properties": {
-        "amount": {
-          "type": "integer",
-          "format": "int32"
-        },
-        "user_id": {
-          "type": "integer",
-          "format": "int32"
-        }
-      }
     },
-    "errorResponse": {
+    "/exchange_rates": {
+      "get": {
+        "description": "現在有効な為替レートを新しい順に取得する。両替のリクエストにはここで取得したレートのidを指定する",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "ListExchangeRates",
+        "operationId": "ListExchangeRates",
+        "parameters": [
+          {
+            "type": "string",
+            "description": "両替元の通貨コード（必須）",
+            "name": "from_currency",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "両替先の通貨コード（必須）",
+            "name": "to_currency",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/exchangeRateList"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/exchanges": {
+      "post": {
+        "description": "ユーザのウォレット間で、見積もった為替レートで両替する。両替元の減算と両替先の加算は1つの仕訳として記録する。レートの有効期限が切れている場合はエラー",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "Exchange",
+        "operationId": "Exchange",
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/exchangeRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/exchangeResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/payments/add_to_users": {
+      "post": {
+        "description": "（limit,offsetを指定して）ユーザの残高に一斉に加算する。非推奨。冪等で再開可能な /bulk_credits を使う",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "PaymentAddToUsers",
+        "operationId": "PaymentAddToUsers",
+        "deprecated": true,
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/payAddToUsersRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response."
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/payments/cancel": {
+      "post": {
+        "description": "支払い（ユーザの残高の加減算）をCancelする",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "PaymentCancel",
+        "operationId": "PaymentCancel",
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/payRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/payResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/payments/confirm": {
+      "post": {
+        "description": "支払い（ユーザの残高の加減算）をConfirmする",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "PaymentConfirm",
+        "operationId": "PaymentConfirm",
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/payRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/payResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/payments/try": {
+      "post": {
+        "description": "支払い（ユーザの残高の加減算）をTryする",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "PaymentTry",
+        "operationId": "PaymentTry",
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/payRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/payResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/payments/{idempotency_key}/refunds": {
+      "get": {
+        "description": "支払いの返金を古い順に取得",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "ListRefunds",
+        "operationId": "ListRefunds",
+        "parameters": [
+          {
+            "type": "string",
+            "description": "返金の対象の支払いの冪等性キー",
+            "name": "idempotency_key",
+            "in": "path",
+            "required": true
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/refundList"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      },
+      "post": {
+        "description": "Confirm済みの減算の全額または一部を返金する。返金額の合計が元の減算額を超えない範囲で、複数回に分けて返金できる",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "PaymentRefund",
+        "operationId": "PaymentRefund",
+        "parameters": [
+          {
+            "type": "string",
+            "description": "返金の対象の支払いの冪等性キー",
+            "name": "idempotency_key",
+            "in": "path",
+            "required": true
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/refundRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/refundResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/transfers/cancel": {
+      "post": {
+        "description": "ユーザ間の送金をCancelする",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "TransferCancel",
+        "operationId": "TransferCancel",
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/transferRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/transferResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/transfers/confirm": {
+      "post": {
+        "description": "ユーザ間の送金をConfirmする",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "TransferConfirm",
+        "operationId": "TransferConfirm",
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/transferRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/transferResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/transfers/try": {
+      "post": {
+        "description": "ユーザ間の送金をTryする",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "TransferTry",
+        "operationId": "TransferTry",
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/transferRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/transferResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/users/{userId}/payments": {
+      "get": {
+        "description": "ユーザの支払いを新しい順に取得",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "ListPayments",
+        "operationId": "ListPayments",
+        "parameters": [
+          {
+            "type": "integer",
+            "format": "int32",
+            "name": "userId",
+            "in": "path",
+            "required": true
+          },
+          {
+            "type": "string",
+            "description": "ステータス（tried, confirmed, cancelled, expired, failed, reversed, partially_refunded, refunded）",
+            "name": "status",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "金額の符号（positive は加算、negative は減算）",
+            "name": "sign",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "この時刻以降にTryされた支払い",
+            "name": "from",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "この時刻より前にTryされた支払い",
+            "name": "to",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "前のレスポンスのnext_cursor",
+            "name": "cursor",
+            "in": "query"
+          },
+          {
+            "type": "integer",
+            "format": "int32",
+            "description": "取得件数（デフォルト20、最大100）",
+            "name": "limit",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/paymentList"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/webhooks": {
+      "post": {
+        "description": "指定した種類の残高の変更のイベントを受け取るWebhookを登録する。配信はsecretを鍵にしたHMAC-SHA256で署名する。secretは登録時のレスポンスでのみ返す",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "CreateWebhook",
+        "operationId": "CreateWebhook",
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/webhookRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/webhook"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/webhooks/{id}/deliveries": {
+      "get": {
+        "description": "Webhookへの配信を新しい順に取得する",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "ListWebhookDeliveries",
+        "operationId": "ListWebhookDeliveries",
+        "parameters": [
+          {
+            "type": "integer",
+            "format": "int64",
+            "name": "id",
+            "in": "path",
+            "required": true
+          },
+          {
+            "type": "string",
+            "description": "前のレスポンスのnext_cursor",
+            "name": "cursor",
+            "in": "query"
+          },
+          {
+            "type": "integer",
+            "format": "int32",
+            "description": "取得件数（デフォルト20、最大100）",
+            "name": "limit",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/webhookDeliveryList"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    }
+  },
+  "definitions": {
+    "balance": {
+      "type": "object",
+      "properties": {
+        "amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "残高（仮押さえ分を含む）。与信枠の分や一斉加算の取り消しで負になることがある"
+        },
+        "available": {
+          "type": "string",
+          "format": "int64",
+          "title": "利用可能残高（残高と与信枠の合計から、Try済みの減算を仮押さえした残り）"
+        },
+        "currency": {
+          "type": "string",
+          "title": "通貨コード（JPY, USD, EUR, PTS）。金額はすべてこの通貨の補助単位の整数を10進数の文字列で表す"
+        },
+        "expirations": {
+          "type": "array",
+          "title": "ポイント（PTS）のウォレットの失効予定（失効日時の近い順に最大10件）",
+          "items": {
+            "$ref": "#/definitions/pointExpiration"
+          }
+        },
+        "overdraft_limit": {
+          "type": "string",
+          "format": "int64",
+          "title": "与信枠。残高はこの額まで負になれる"
+        },
+        "user_id": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "balanceList": {
+      "type": "object",
+      "properties": {
+        "balances": {
+          "type": "array",
+          "title": "通貨ごとのウォレット（通貨コード順）。一度も使っていない通貨のウォレットは含まない",
+          "items": {
+            "$ref": "#/definitions/balance"
+          }
+        },
+        "user_id": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "balanceLog": {
+      "type": "object",
+      "properties": {
+        "after_amount": {
+          "type": "string",
+          "format": "int64"
+        },
+        "before_amount": {
+          "type": "string",
+          "format": "int64"
+        },
+        "create_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "currency": {
+          "type": "string"
+        },
+        "delta": {
+          "type": "string",
+          "format": "int64",
+          "title": "増減額"
+        },
+        "id": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "reason": {
+          "type": "string"
+        },
+        "source_id": {
+          "type": "string",
+          "title": "残高を変更した操作のID（支払いの冪等性キーなど）"
+        },
+        "source_type": {
+          "type": "string",
+          "title": "残高を変更した操作の種類（payment, transfer, add_to_usersなど）"
+        },
+        "user_id": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "balanceLogList": {
+      "type": "object",
+      "properties": {
+        "logs": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/balanceLog"
+          }
+        },
+        "next_cursor": {
+          "type": "string",
+          "title": "次のページのカーソル。次のページがなければ空"
+        }
+      }
+    },
+    "bulkCreditFailure": {
+      "type": "object",
+      "properties": {
+        "error": {
+          "type": "string"
+        },
+        "user_id": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "bulkCreditJob": {
+      "type": "object",
+      "properties": {
+        "amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "各ユーザに加算する金額"
+        },
+        "create_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "credited_count": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "currency": {
+          "type": "string"
+        },
+        "dry_run": {
+          "type": "boolean",
+          "title": "trueの場合は作成されていないジョブのプレビュー"
+        },
+        "failed_count": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "failures": {
+          "type": "array",
+          "title": "加算に失敗したユーザ（user_id順に最大100件）",
+          "items": {
+            "$ref": "#/definitions/bulkCreditFailure"
+          }
+        },
+        "finish_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "id": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "idempotency_key": {
+          "type": "string",
+          "title": "冪等性キー"
+        },
+        "last_error": {
+          "type": "string",
+          "title": "直近のチャンクの処理に失敗した理由。リトライで成功すると空になる"
+        },
+        "processed_count": {
+          "type": "integer",
+          "format": "int32",
+          "title": "処理済みのユーザ数（credited_count + failed_count）"
+        },
+        "reversal": {
+          "$ref": "#/definitions/bulkCreditReversal"
+        },
+        "status": {
+          "type": "string",
+          "title": "ステータス（pending, running, completed）。dry_runの場合は空"
+        },
+        "target": {
+          "$ref": "#/definitions/bulkCreditTarget"
+        },
+        "total_amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "対象ユーザ全員に加算した場合の合計金額"
+        },
+        "total_count": {
+          "type": "integer",
+          "format": "int32",
+          "title": "ジョブ作成時点の対象ユーザ数"
+        },
+        "update_time": {
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "bulkCreditRequest": {
+      "type": "object",
+      "required": [
+        "idempotency_key",
+        "amount"
+      ],
+      "properties": {
+        "amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "各ユーザに加算する金額"
+        },
+        "currency": {
+          "type": "string",
+          "title": "通貨コード（JPY, USD, EUR, PTS）。省略時はJPY。balance_belowもこの通貨のウォレットで判定する"
+        },
+        "dry_run": {
+          "type": "boolean",
+          "title": "trueの場合はジョブを作成せず、対象数と合計金額だけを返す"
+        },
+        "idempotency_key": {
+          "type": "string",
+          "title": "冪等性キー"
+        },
+        "target": {
+          "$ref": "#/definitions/bulkCreditTarget"
+        }
+      }
+    },
+    "bulkCreditReversal": {
+      "type": "object",
+      "properties": {
+        "finish_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "mode": {
+          "type": "string",
+          "title": "利用可能額が加算した金額に足りないユーザの扱い（clamp, allow_negative, skip）"
+        },
+        "request_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "reversed_amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "減算した合計金額"
+        },
+        "reversed_count": {
+          "type": "integer",
+          "format": "int32",
+          "title": "減算したユーザ数（clampで一部だけ減算したユーザを含む）"
+        },
+        "skipped": {
+          "type": "array",
+          "title": "減算しなかったユーザ（user_id順に最大100件）",
+          "items": {
+            "$ref": "#/definitions/bulkCreditFailure"
+          }
+        },
+        "skipped_count": {
+          "type": "integer",
+          "format": "int32",
+          "title": "skipで減算しなかったユーザ数"
+        },
+        "status": {
+          "type": "string",
+          "title": "取り消しのステータス（pending, running, completed）"
+        }
+      }
+    },
+    "bulkCreditReverseRequest": {
+      "type": "object",
+      "required": [
+        "mode"
+      ],
+      "properties": {
+        "mode": {
+          "type": "string",
+          "title": "利用可能額が加算した金額に足りないユーザの扱い（clamp, allow_negative, skip）"
+        }
+      }
+    },
+    "bulkCreditTarget": {
+      "type": "object",
+      "properties": {
+        "balance_below": {
+          "type": "string",
+          "format": "int64",
+          "title": "balance_below: 処理する時点の残高がこの値未満のユーザ"
+        },
+        "created_from": {
+          "type": "string",
+          "format": "date-time",
+          "title": "created_between: この時刻以降に作成されたユーザ"
+        },
+        "created_to": {
+          "type": "string",
+          "format": "date-time",
+          "title": "created_between: この時刻より前に作成されたユーザ"
+        },
+        "csv": {
+          "type": "string",
+          "title": "csv: 1列目がuser_idのCSV（1行目は見出しでもよい。最大10000人）"
+        },
+        "type": {
+          "type": "string",
+          "title": "対象の種類（all, user_ids, csv, created_between, balance_below）。省略時はall"
+        },
+        "user_ids": {
+          "type": "array",
+          "title": "user_ids: 対象のユーザ（最大10000人）",
+          "items": {
+            "type": "integer",
+            "format": "int32"
+          }
+        }
+      }
+    },
+    "errorResponse": {
+      "type": "object",
+      "properties": {
+        "code": {
+          "type": "integer",
+          "format": "int32",
+          "maximum": 600,
+          "minimum": 100
+        },
+        "message": {
+          "type": "string"
+        }
+      }
+    },
+    "exchangeRate": {
+      "type": "object",
+      "properties": {
+        "create_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "from_currency": {
+          "type": "string"
+        },
+        "id": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "rate": {
+          "type": "string",
+          "title": "両替元の通貨1単位あたりの両替先の通貨の額（10進数の文字列、小数部は最大12桁）"
+        },
+        "rounding": {
+          "type": "string",
+          "title": "両替先の通貨の補助単位への丸め方（down, half_up, half_even）。省略時はdown"
+        },
+        "to_currency": {
+          "type": "string"
+        },
+        "valid_from": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "valid_to": {
+          "type": "string",
+          "format": "date-time",
+          "title": "この時刻以降は両替に使えない"
+        }
+      }
+    },
+    "exchangeRateList": {
+      "type": "object",
+      "properties": {
+        "rates": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/exchangeRate"
+          }
+        }
+      }
+    },
+    "exchangeRateUploadRequest": {
+      "type": "object",
+      "required": [
+        "rates"
+      ],
+      "properties": {
+        "rates": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/exchangeRate"
+          }
+        }
+      }
+    },
+    "exchangeRequest": {
+      "type": "object",
+      "required": [
+        "idempotency_key",
+        "user_id",
+        "rate_id",
+        "from_currency",
+        "to_currency",
+        "amount"
+      ],
+      "properties": {
+        "amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "両替元の通貨の補助単位での減算額"
+        },
+        "from_currency": {
+          "type": "string",
+          "title": "両替元の通貨コード。レートの両替元の通貨と一致する必要がある"
+        },
+        "idempotency_key": {
+          "type": "string",
+          "title": "冪等性キー"
+        },
+        "rate_id": {
+          "type": "integer",
+          "format": "int64",
+          "title": "見積もりに使った為替レートのid"
+        },
+        "to_currency": {
+          "type": "string",
+          "title": "両替先の通貨コード。レートの両替先の通貨と一致する必要がある"
+        },
+        "user_id": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "exchangeResponse": {
+      "type": "object",
+      "properties": {
+        "create_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "from_amount": {
+          "type": "string",
+          "format": "int64"
+        },
+        "from_balance": {
+          "$ref": "#/definitions/balance"
+        },
+        "from_currency": {
+          "type": "string"
+        },
+        "idempotency_key": {
+          "type": "string",
+          "title": "冪等性キー"
+        },
+        "rate": {
+          "type": "string"
+        },
+        "rate_id": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "rounding": {
+          "type": "string",
+          "title": "丸め方（down, half_up, half_even）"
+        },
+        "to_amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "レートで換算して丸めた加算額"
+        },
+        "to_balance": {
+          "$ref": "#/definitions/balance"
+        },
+        "to_currency": {
+          "type": "string"
+        },
+        "user_id": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "overdraftLimitRequest": {
+      "type": "object",
+      "required": [
+        "currency",
+        "overdraft_limit"
+      ],
+      "properties": {
+        "currency": {
+          "type": "string"
+        },
+        "overdraft_limit": {
+          "type": "string",
+          "format": "int64",
+          "title": "与信枠（0は与信枠なし）"
+        }
+      }
+    },
+    "payAddToUsersRequest": {
+      "type": "object",
+      "required": [
+        "amount"
+      ],
+      "properties": {
+        "amount": {
+          "type": "string",
+          "format": "int64"
+        },
+        "currency": {
+          "type": "string",
+          "title": "通貨コード（JPY, USD, EUR, PTS）。省略時はJPY"
+        },
+        "expires_in_days": {
+          "type": "integer",
+          "format": "int32",
+          "title": "加算したポイントが失効するまでの日数。PTSのみ指定でき、省略時は失効しない",
+          "minimum": 1
+        },
+        "limit": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "offset": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "payRequest": {
+      "type": "object",
+      "required": [
+        "idempotency_key",
+        "user_id"
+      ],
+      "properties": {
+        "amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "加減算額（補助単位の整数の10進数文字列。減算は負の数）"
+        },
+        "currency": {
+          "type": "string",
+          "title": "通貨コード（JPY, USD, EUR, PTS）。省略時はJPY"
+        },
+        "expires_in": {
+          "type": "integer",
+          "format": "int32",
+          "title": "Tryの有効期限（秒）。省略時はサーバーのデフォルト値",
+          "minimum": 1
+        },
+        "idempotency_key": {
+          "type": "string",
+          "title": "冪等性キー"
+        },
+        "user_id": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "payResponse": {
+      "type": "object",
+      "properties": {
+        "balance": {
+          "$ref": "#/definitions/balance"
+        },
+        "cancel_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "confirm_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "expire_time": {
+          "type": "string",
+          "format": "date-time",
+          "title": "この時刻を過ぎるとConfirmできない"
+        },
+        "expired_time": {
+          "type": "string",
+          "format": "date-time",
+          "title": "期限切れとして処理された時刻"
+        },
+        "idempotency_key": {
+          "type": "string",
+          "title": "冪等性キー"
+        },
+        "refunded_amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "返金済みの額の合計"
+        },
+        "status": {
+          "type": "string",
+          "title": "ステータス（tried, confirmed, cancelled, expired, failed, reversed, partially_refunded, refunded）"
+        },
+        "try_time": {
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "payment": {
+      "type": "object",
+      "properties": {
+        "amount": {
+          "type": "string",
+          "format": "int64"
+        },
+        "cancel_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "confirm_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "currency": {
+          "type": "string"
+        },
+        "expire_time": {
+          "type": "string",
+          "format": "date-time",
+          "title": "この時刻を過ぎるとConfirmできない"
+        },
+        "expired_time": {
+          "type": "string",
+          "format": "date-time",
+          "title": "期限切れとして処理された時刻"
+        },
+        "idempotency_key": {
+          "type": "string",
+          "title": "冪等性キー"
+        },
+        "refunded_amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "返金済みの額の合計"
+        },
+        "status": {
+          "type": "string",
+          "title": "ステータス（tried, confirmed, cancelled, expired, failed, reversed, partially_refunded, refunded）"
+        },
+        "try_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "user_id": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "paymentList": {
+      "type": "object",
+      "properties": {
+        "next_cursor": {
+          "type": "string",
+          "title": "次のページのカーソル。次のページがなければ空"
+        },
+        "payments": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/payment"
+          }
+        }
+      }
+    },
+    "pointExpiration": {
+      "type": "object",
+      "properties": {
+        "amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "失効するポイント"
+        },
+        "expire_time": {
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "refund": {
+      "type": "object",
+      "properties": {
+        "amount": {
+          "type": "string",
+          "format": "int64"
+        },
+        "create_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "currency": {
+          "type": "string"
+        },
+        "idempotency_key": {
+          "type": "string",
+          "title": "返金の冪等性キー"
+        },
+        "payment_idempotency_key": {
+          "type": "string",
+          "title": "返金の対象の支払いの冪等性キー"
+        },
+        "user_id": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "refundList": {
+      "type": "object",
+      "properties": {
+        "refunds": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/refund"
+          }
+        }
+      }
+    },
+    "refundRequest": {
+      "type": "object",
+      "required": [
+        "idempotency_key",
+        "amount"
+      ],
+      "properties": {
+        "amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "返金額（正の数）"
+        },
+        "idempotency_key": {
+          "type": "string",
+          "title": "返金の冪等性キー（支払いの冪等性キーとは別）"
+        }
+      }
+    },
+    "refundResponse": {
+      "type": "object",
+      "properties": {
+        "balance": {
+          "$ref": "#/definitions/balance"
+        },
+        "payment": {
+          "$ref": "#/definitions/payment"
+        },
+        "refund": {
+          "$ref": "#/definitions/refund"
+        }
+      }
+    },
+    "spendingLimit": {
+      "type": "object",
+      "required": [
+        "scope",
+        "scope_id",
+        "currency"
+      ],
+      "properties": {
+        "currency": {
+          "type": "string"
+        },
+        "daily_debit_limit": {
+          "type": "string",
+          "format": "int64",
+          "title": "1日の減算額の合計の上限。0は上限なし"
+        },
+        "hourly_debit_count": {
+          "type": "integer",
+          "format": "int32",
+          "title": "直近1時間の減算の回数の上限。0は上限なし"
+        },
+        "max_single_debit": {
+          "type": "string",
+          "format": "int64",
+          "title": "1回の減算額の上限。0は上限なし"
+        },
+        "monthly_debit_limit": {
+          "type": "string",
+          "format": "int64",
+          "title": "1ヶ月の減算額の合計の上限。0は上限なし"
+        },
+        "scope": {
+          "type": "string",
+          "title": "上限の対象（user, tier）。ユーザ個別の上限はユーザ区分の上限より優先する"
+        },
+        "scope_id": {
+          "type": "string",
+          "title": "userの場合はユーザID、tierの場合はユーザ区分"
+        },
+        "update_time": {
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "spendingLimitList": {
+      "type": "object",
+      "properties": {
+        "limits": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/spendingLimit"
+          }
+        }
+      }
+    },
+    "transferRequest": {
+      "type": "object",
+      "required": [
+        "idempotency_key",
+        "from_user_id",
+        "to_user_id",
+        "amount"
+      ],
+      "properties": {
+        "amount": {
+          "type": "string",
+          "format": "int64"
+        },
+        "currency": {
+          "type": "string",
+          "title": "通貨コード（JPY, USD, EUR, PTS）。送金元と送金先のこの通貨のウォレット間で送金する。省略時はJPY"
+        },
+        "expires_in": {
+          "type": "integer",
+          "format": "int32",
+          "title": "Tryの有効期限（秒）。省略時はサーバーのデフォルト値",
+          "minimum": 1
+        },
+        "from_user_id": {
+          "type": "integer",
+          "format": "int32",
+          "title": "送金元のユーザ"
+        },
+        "idempotency_key": {
+          "type": "string",
+          "title": "冪等性キー"
+        },
+        "to_user_id": {
+          "type": "integer",
+          "format": "int32",
+          "title": "送金先のユーザ"
+        }
+      }
+    },
+    "transferResponse": {
+      "type": "object",
+      "properties": {
+        "amount": {
+          "type": "string",
+          "format": "int64"
+        },
+        "cancel_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "confirm_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "currency": {
+          "type": "string"
+        },
+        "expire_time": {
+          "type": "string",
+          "format": "date-time",
+          "title": "この時刻を過ぎるとConfirmできない"
+        },
+        "expired_time": {
+          "type": "string",
+          "format": "date-time",
+          "title": "期限切れとして処理された時刻"
+        },
+        "from_balance": {
+          "$ref": "#/definitions/balance"
+        },
+        "idempotency_key": {
+          "type": "string",
+          "title": "冪等性キー"
+        },
+        "status": {
+          "type": "string",
+          "title": "ステータス（tried, confirmed, cancelled, expired）"
+        },
+        "to_balance": {
+          "$ref": "#/definitions/balance"
+        },
+        "try_time": {
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "user": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "name": {
+          "type": "string"
+        },
+        "tier": {
+          "type": "string"
+        }
+      }
+    },
+    "userTierRequest": {
+      "type": "object",
+      "required": [
+        "tier"
+      ],
+      "properties": {
+        "tier": {
+          "type": "string",
+          "title": "ユーザ区分（例えばstandard）"
+        }
+      }
+    },
+    "webhook": {
+      "type": "object",
+      "properties": {
+        "create_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "event_types": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "id": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "secret": {
+          "type": "string",
+          "title": "配信の署名の鍵（登録時のみ）"
+        },
+        "url": {
+          "type": "string"
+        },
+        "user_ids": {
+          "type": "array",
+          "items": {
+            "type": "integer",
+            "format": "int32"
+          }
+        }
+      }
+    },
+    "webhookDelivery": {
+      "type": "object",
+      "properties": {
+        "attempts": {
+          "type": "integer",
+          "format": "int32",
+          "title": "配信を試みた回数"
+        },
+        "create_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "deliver_time": {
+          "type": "string",
+          "format": "date-time",
+          "title": "配信に成功した時刻"
+        },
+        "event_sequence": {
+          "type": "integer",
+          "format": "int64",
+          "title": "イベントの通し番号"
+        },
+        "event_type": {
+          "type": "string"
+        },
+        "id": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "last_error": {
+          "type": "string",
+          "title": "直近の配信に失敗した理由"
+        },
+        "last_status_code": {
+          "type": "integer",
+          "format": "int32",
+          "title": "直近の配信の応答のHTTPステータス（応答がなければ0）"
+        },
+        "next_attempt_time": {
+          "type": "string",
+          "format": "date-time",
+          "title": "次に配信を試みる時刻（pendingのみ）"
+        },
+        "status": {
+          "type": "string",
+          "title": "ステータス（pending, succeeded, dead）"
+        }
+      }
+    },
+    "webhookDeliveryList": {
+      "type": "object",
+      "properties": {
+        "deliveries": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/webhookDelivery"
+          }
+        },
+        "next_cursor": {
+          "type": "string",
+          "title": "次のページのカーソル。次のページがなければ空"
+        }
+      }
+    },
+    "webhookRequest": {
+      "type": "object",
+      "required": [
+        "url",
+        "event_types",
+        "user_ids"
+      ],
+      "properties": {
+        "event_types": {
+          "type": "array",
+          "title": "受け取るイベントの種類（payment.confirmed, payment.cancelled, payment.expired, refund.created, transfer.confirmed, transfer.cancelled, transfer.expired, exchange.completed, points.expired）。複数のユーザの一斉加算のイベントは購読できない",
+          "items": {
+            "type": "string"
+          }
+        },
+        "url": {
+          "type": "string",
+          "title": "配信先のURL（httpまたはhttps）。ホストは公開されたアドレスに解決できること"
+        },
+        "user_ids": {
+          "type": "array",
+          "title": "配信の対象のパートナーのユーザ（最大1000人）。このユーザの残高の変更のイベントだけを配信する",
+          "items": {
+            "type": "integer",
+            "format": "int32"
+          }
+        }
+      }
+    }
+  },
+  "tags": [
+    {
+      "name": "Bank"
+    }
+  ]
+}`))
+	FlatSwaggerJSON = json.RawMessage([]byte(`{
+  "consumes": [
+    "application/json"
+  ],
+  "produces": [
+    "application/json"
+  ],
+  "swagger": "2.0",
+  "info": {
+    "description": "This is the API for m-bank.",
+    "title": "m-bank",
+    "version": "version not set"
+  },
+  "paths": {
+    "/admin/balances/{userId}/overdraft": {
+      "put": {
+        "description": "管理者がユーザの通貨ごとのウォレットの与信枠を設定する。ポイントには設定できない",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "SetOverdraftLimit",
+        "operationId": "SetOverdraftLimit",
+        "parameters": [
+          {
+            "type": "integer",
+            "format": "int32",
+            "name": "userId",
+            "in": "path",
+            "required": true
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/overdraftLimitRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/balance"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/admin/exchange_rates": {
+      "post": {
+        "description": "管理者が為替レートを登録する。すべてのレートを1つのDBトランザクションで登録し、1つでも不正なレートがあれば何も登録しない",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "UploadExchangeRates",
+        "operationId": "UploadExchangeRates",
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/exchangeRateUploadRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/exchangeRateList"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/admin/spending_limits": {
+      "get": {
+        "description": "管理者がユーザまたはユーザ区分の利用上限を通貨順に取得する",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "ListSpendingLimits",
+        "operationId": "ListSpendingLimits",
+        "parameters": [
+          {
+            "type": "string",
+            "description": "上限の対象（user, tier）",
+            "name": "scope",
+            "in": "query",
+            "required": true
+          },
+          {
+            "type": "string",
+            "description": "userの場合はユーザID、tierの場合はユーザ区分",
+            "name": "scope_id",
+            "in": "query",
+            "required": true
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/spendingLimitList"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      },
+      "put": {
+        "description": "管理者がユーザまたはユーザ区分の通貨ごとの利用上限を設定する。同じ対象と通貨の上限は置き換える",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "SetSpendingLimit",
+        "operationId": "SetSpendingLimit",
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/spendingLimit"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/spendingLimit"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/admin/users/{userId}/tier": {
+      "put": {
+        "description": "管理者がユーザの区分を変更する。ユーザ個別の上限がない通貨は、区分の上限が適用される",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "SetUserTier",
+        "operationId": "SetUserTier",
+        "parameters": [
+          {
+            "type": "integer",
+            "format": "int32",
+            "name": "userId",
+            "in": "path",
+            "required": true
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/userTierRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/user"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/balances/{userId}": {
+      "get": {
+        "description": "ユーザの通貨ごとのウォレット（残高）をすべて取得",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "GetBalance",
+        "operationId": "GetBalance",
+        "parameters": [
+          {
+            "type": "integer",
+            "format": "int32",
+            "name": "userId",
+            "in": "path",
+            "required": true
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/balanceList"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/balances/{userId}/logs": {
+      "get": {
+        "description": "ユーザの残高の変更履歴を新しい順に取得",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "ListBalanceLogs",
+        "operationId": "ListBalanceLogs",
+        "parameters": [
+          {
+            "type": "integer",
+            "format": "int32",
+            "name": "userId",
+            "in": "path",
+            "required": true
+          },
+          {
+            "type": "string",
+            "description": "この通貨のウォレットの履歴（省略時はすべての通貨）",
+            "name": "currency",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "この時刻以降の履歴",
+            "name": "from",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "この時刻より前の履歴",
+            "name": "to",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "前のレスポンスのnext_cursor",
+            "name": "cursor",
+            "in": "query"
+          },
+          {
+            "type": "integer",
+            "format": "int32",
+            "description": "取得件数（デフォルト20、最大100）",
+            "name": "limit",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/balanceLogList"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/bulk_credits": {
+      "post": {
+        "description": "対象のユーザの残高に一斉に加算するジョブを作成する。加算はワーカーがチャンク単位で行う。dry_runの場合はジョブを作成せず、対象数と合計金額を返す",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "CreateBulkCredit",
+        "operationId": "CreateBulkCredit",
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/bulkCreditRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/bulkCreditJob"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/bulk_credits/{id}": {
+      "get": {
+        "description": "一斉加算のジョブの進捗を取得する",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "GetBulkCredit",
+        "operationId": "GetBulkCredit",
+        "parameters": [
+          {
+            "type": "integer",
+            "format": "int64",
+            "name": "id",
+            "in": "path",
+            "required": true
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/bulkCreditJob"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/bulk_credits/{id}/reverse": {
+      "post": {
+        "description": "完了した一斉加算で各ユーザに加算した金額を減算する。減算はワーカーがチャンク単位で行う。同じmodeでのリトライは現在のジョブを返す",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "ReverseBulkCredit",
+        "operationId": "ReverseBulkCredit",
+        "parameters": [
+          {
+            "type": "integer",
+            "format": "int64",
+            "name": "id",
+            "in": "path",
+            "required": true
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/bulkCreditReverseRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/bulkCreditJob"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/exchange_rates": {
+      "get": {
+        "description": "現在有効な為替レートを新しい順に取得する。両替のリクエストにはここで取得したレートのidを指定する",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "ListExchangeRates",
+        "operationId": "ListExchangeRates",
+        "parameters": [
+          {
+            "type": "string",
+            "description": "両替元の通貨コード（必須）",
+            "name": "from_currency",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "両替先の通貨コード（必須）",
+            "name": "to_currency",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/exchangeRateList"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/exchanges": {
+      "post": {
+        "description": "ユーザのウォレット間で、見積もった為替レートで両替する。両替元の減算と両替先の加算は1つの仕訳として記録する。レートの有効期限が切れている場合はエラー",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "Exchange",
+        "operationId": "Exchange",
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/exchangeRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/exchangeResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/payments/add_to_users": {
+      "post": {
+        "description": "（limit,offsetを指定して）ユーザの残高に一斉に加算する。非推奨。冪等で再開可能な /bulk_credits を使う",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "PaymentAddToUsers",
+        "operationId": "PaymentAddToUsers",
+        "deprecated": true,
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/payAddToUsersRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response."
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/payments/cancel": {
+      "post": {
+        "description": "支払い（ユーザの残高の加減算）をCancelする",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "PaymentCancel",
+        "operationId": "PaymentCancel",
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/payRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/payResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/payments/confirm": {
+      "post": {
+        "description": "支払い（ユーザの残高の加減算）をConfirmする",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "PaymentConfirm",
+        "operationId": "PaymentConfirm",
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/payRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/payResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/payments/try": {
+      "post": {
+        "description": "支払い（ユーザの残高の加減算）をTryする",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "PaymentTry",
+        "operationId": "PaymentTry",
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/payRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/payResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/payments/{idempotency_key}/refunds": {
+      "get": {
+        "description": "支払いの返金を古い順に取得",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "ListRefunds",
+        "operationId": "ListRefunds",
+        "parameters": [
+          {
+            "type": "string",
+            "description": "返金の対象の支払いの冪等性キー",
+            "name": "idempotency_key",
+            "in": "path",
+            "required": true
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/refundList"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      },
+      "post": {
+        "description": "Confirm済みの減算の全額または一部を返金する。返金額の合計が元の減算額を超えない範囲で、複数回に分けて返金できる",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "PaymentRefund",
+        "operationId": "PaymentRefund",
+        "parameters": [
+          {
+            "type": "string",
+            "description": "返金の対象の支払いの冪等性キー",
+            "name": "idempotency_key",
+            "in": "path",
+            "required": true
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/refundRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/refundResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/transfers/cancel": {
+      "post": {
+        "description": "ユーザ間の送金をCancelする",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "TransferCancel",
+        "operationId": "TransferCancel",
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/transferRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/transferResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/transfers/confirm": {
+      "post": {
+        "description": "ユーザ間の送金をConfirmする",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "TransferConfirm",
+        "operationId": "TransferConfirm",
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/transferRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/transferResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/transfers/try": {
+      "post": {
+        "description": "ユーザ間の送金をTryする",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "TransferTry",
+        "operationId": "TransferTry",
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/transferRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/transferResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/users/{userId}/payments": {
+      "get": {
+        "description": "ユーザの支払いを新しい順に取得",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "ListPayments",
+        "operationId": "ListPayments",
+        "parameters": [
+          {
+            "type": "integer",
+            "format": "int32",
+            "name": "userId",
+            "in": "path",
+            "required": true
+          },
+          {
+            "type": "string",
+            "description": "ステータス（tried, confirmed, cancelled, expired, failed, reversed, partially_refunded, refunded）",
+            "name": "status",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "金額の符号（positive は加算、negative は減算）",
+            "name": "sign",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "この時刻以降にTryされた支払い",
+            "name": "from",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "この時刻より前にTryされた支払い",
+            "name": "to",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "前のレスポンスのnext_cursor",
+            "name": "cursor",
+            "in": "query"
+          },
+          {
+            "type": "integer",
+            "format": "int32",
+            "description": "取得件数（デフォルト20、最大100）",
+            "name": "limit",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/paymentList"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/webhooks": {
+      "post": {
+        "description": "指定した種類の残高の変更のイベントを受け取るWebhookを登録する。配信はsecretを鍵にしたHMAC-SHA256で署名する。secretは登録時のレスポンスでのみ返す",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "CreateWebhook",
+        "operationId": "CreateWebhook",
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/webhookRequest"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/webhook"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    },
+    "/webhooks/{id}/deliveries": {
+      "get": {
+        "description": "Webhookへの配信を新しい順に取得する",
+        "tags": [
+          "Bank"
+        ],
+        "summary": "ListWebhookDeliveries",
+        "operationId": "ListWebhookDeliveries",
+        "parameters": [
+          {
+            "type": "integer",
+            "format": "int64",
+            "name": "id",
+            "in": "path",
+            "required": true
+          },
+          {
+            "type": "string",
+            "description": "前のレスポンスのnext_cursor",
+            "name": "cursor",
+            "in": "query"
+          },
+          {
+            "type": "integer",
+            "format": "int32",
+            "description": "取得件数（デフォルト20、最大100）",
+            "name": "limit",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/webhookDeliveryList"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/errorResponse"
+            }
+          }
+        }
+      }
+    }
+  },
+  "definitions": {
+    "balance": {
+      "type": "object",
+      "properties": {
+        "amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "残高（仮押さえ分を含む）。与信枠の分や一斉加算の取り消しで負になることがある"
+        },
+        "available": {
+          "type": "string",
+          "format": "int64",
+          "title": "利用可能残高（残高と与信枠の合計から、Try済みの減算を仮押さえした残り）"
+        },
+        "currency": {
+          "type": "string",
+          "title": "通貨コード（JPY, USD, EUR, PTS）。金額はすべてこの通貨の補助単位の整数を10進数の文字列で表す"
+        },
+        "expirations": {
+          "type": "array",
+          "title": "ポイント（PTS）のウォレットの失効予定（失効日時の近い順に最大10件）",
+          "items": {
+            "$ref": "#/definitions/pointExpiration"
+          }
+        },
+        "overdraft_limit": {
+          "type": "string",
+          "format": "int64",
+          "title": "与信枠。残高はこの額まで負になれる"
+        },
+        "user_id": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "balanceList": {
+      "type": "object",
+      "properties": {
+        "balances": {
+          "type": "array",
+          "title": "通貨ごとのウォレット（通貨コード順）。一度も使っていない通貨のウォレットは含まない",
+          "items": {
+            "$ref": "#/definitions/balance"
+          }
+        },
+        "user_id": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "balanceLog": {
+      "type": "object",
+      "properties": {
+        "after_amount": {
+          "type": "string",
+          "format": "int64"
+        },
+        "before_amount": {
+          "type": "string",
+          "format": "int64"
+        },
+        "create_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "currency": {
+          "type": "string"
+        },
+        "delta": {
+          "type": "string",
+          "format": "int64",
+          "title": "増減額"
+        },
+        "id": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "reason": {
+          "type": "string"
+        },
+        "source_id": {
+          "type": "string",
+          "title": "残高を変更した操作のID（支払いの冪等性キーなど）"
+        },
+        "source_type": {
+          "type": "string",
+          "title": "残高を変更した操作の種類（payment, transfer, add_to_usersなど）"
+        },
+        "user_id": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "balanceLogList": {
+      "type": "object",
+      "properties": {
+        "logs": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/balanceLog"
+          }
+        },
+        "next_cursor": {
+          "type": "string",
+          "title": "次のページのカーソル。次のページがなければ空"
+        }
+      }
+    },
+    "bulkCreditFailure": {
+      "type": "object",
+      "properties": {
+        "error": {
+          "type": "string"
+        },
+        "user_id": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "bulkCreditJob": {
+      "type": "object",
+      "properties": {
+        "amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "各ユーザに加算する金額"
+        },
+        "create_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "credited_count": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "currency": {
+          "type": "string"
+        },
+        "dry_run": {
+          "type": "boolean",
+          "title": "trueの場合は作成されていないジョブのプレビュー"
+        },
+        "failed_count": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "failures": {
+          "type": "array",
+          "title": "加算に失敗したユーザ（user_id順に最大100件）",
+          "items": {
+            "$ref": "#/definitions/bulkCreditFailure"
+          }
+        },
+        "finish_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "id": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "idempotency_key": {
+          "type": "string",
+          "title": "冪等性キー"
+        },
+        "last_error": {
+          "type": "string",
+          "title": "直近のチャンクの処理に失敗した理由。リトライで成功すると空になる"
+        },
+        "processed_count": {
+          "type": "integer",
+          "format": "int32",
+          "title": "処理済みのユーザ数（credited_count + failed_count）"
+        },
+        "reversal": {
+          "$ref": "#/definitions/bulkCreditReversal"
+        },
+        "status": {
+          "type": "string",
+          "title": "ステータス（pending, running, completed）。dry_runの場合は空"
+        },
+        "target": {
+          "$ref": "#/definitions/bulkCreditTarget"
+        },
+        "total_amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "対象ユーザ全員に加算した場合の合計金額"
+        },
+        "total_count": {
+          "type": "integer",
+          "format": "int32",
+          "title": "ジョブ作成時点の対象ユーザ数"
+        },
+        "update_time": {
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "bulkCreditRequest": {
+      "type": "object",
+      "required": [
+        "idempotency_key",
+        "amount"
+      ],
+      "properties": {
+        "amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "各ユーザに加算する金額"
+        },
+        "currency": {
+          "type": "string",
+          "title": "通貨コード（JPY, USD, EUR, PTS）。省略時はJPY。balance_belowもこの通貨のウォレットで判定する"
+        },
+        "dry_run": {
+          "type": "boolean",
+          "title": "trueの場合はジョブを作成せず、対象数と合計金額だけを返す"
+        },
+        "idempotency_key": {
+          "type": "string",
+          "title": "冪等性キー"
+        },
+        "target": {
+          "$ref": "#/definitions/bulkCreditTarget"
+        }
+      }
+    },
+    "bulkCreditReversal": {
+      "type": "object",
+      "properties": {
+        "finish_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "mode": {
+          "type": "string",
+          "title": "利用可能額が加算した金額に足りないユーザの扱い（clamp, allow_negative, skip）"
+        },
+        "request_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "reversed_amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "減算した合計金額"
+        },
+        "reversed_count": {
+          "type": "integer",
+          "format": "int32",
+          "title": "減算したユーザ数（clampで一部だけ減算したユーザを含む）"
+        },
+        "skipped": {
+          "type": "array",
+          "title": "減算しなかったユーザ（user_id順に最大100件）",
+          "items": {
+            "$ref": "#/definitions/bulkCreditFailure"
+          }
+        },
+        "skipped_count": {
+          "type": "integer",
+          "format": "int32",
+          "title": "skipで減算しなかったユーザ数"
+        },
+        "status": {
+          "type": "string",
+          "title": "取り消しのステータス（pending, running, completed）"
+        }
+      }
+    },
+    "bulkCreditReverseRequest": {
+      "type": "object",
+      "required": [
+        "mode"
+      ],
+      "properties": {
+        "mode": {
+          "type": "string",
+          "title": "利用可能額が加算した金額に足りないユーザの扱い（clamp, allow_negative, skip）"
+        }
+      }
+    },
+    "bulkCreditTarget": {
+      "type": "object",
+      "properties": {
+        "balance_below": {
+          "type": "string",
+          "format": "int64",
+          "title": "balance_below: 処理する時点の残高がこの値未満のユーザ"
+        },
+        "created_from": {
+          "type": "string",
+          "format": "date-time",
+          "title": "created_between: この時刻以降に作成されたユーザ"
+        },
+        "created_to": {
+          "type": "string",
+          "format": "date-time",
+          "title": "created_between: この時刻より前に作成されたユーザ"
+        },
+        "csv": {
+          "type": "string",
+          "title": "csv: 1列目がuser_idのCSV（1行目は見出しでもよい。最大10000人）"
+        },
+        "type": {
+          "type": "string",
+          "title": "対象の種類（all, user_ids, csv, created_between, balance_below）。省略時はall"
+        },
+        "user_ids": {
+          "type": "array",
+          "title": "user_ids: 対象のユーザ（最大10000人）",
+          "items": {
+            "type": "integer",
+            "format": "int32"
+          }
+        }
+      }
+    },
+    "errorResponse": {
+      "type": "object",
+      "properties": {
+        "code": {
+          "type": "integer",
+          "format": "int32",
+          "maximum": 600,
+          "minimum": 100
+        },
+        "message": {
+          "type": "string"
+        }
+      }
+    },
+    "exchangeRate": {
+      "type": "object",
+      "properties": {
+        "create_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "from_currency": {
+          "type": "string"
+        },
+        "id": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "rate": {
+          "type": "string",
+          "title": "両替元の通貨1単位あたりの両替先の通貨の額（10進数の文字列、小数部は最大12桁）"
+        },
+        "rounding": {
+          "type": "string",
+          "title": "両替先の通貨の補助単位への丸め方（down, half_up, half_even）。省略時はdown"
+        },
+        "to_currency": {
+          "type": "string"
+        },
+        "valid_from": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "valid_to": {
+          "type": "string",
+          "format": "date-time",
+          "title": "この時刻以降は両替に使えない"
+        }
+      }
+    },
+    "exchangeRateList": {
+      "type": "object",
+      "properties": {
+        "rates": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/exchangeRate"
+          }
+        }
+      }
+    },
+    "exchangeRateUploadRequest": {
+      "type": "object",
+      "required": [
+        "rates"
+      ],
+      "properties": {
+        "rates": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/exchangeRate"
+          }
+        }
+      }
+    },
+    "exchangeRequest": {
+      "type": "object",
+      "required": [
+        "idempotency_key",
+        "user_id",
+        "rate_id",
+        "from_currency",
+        "to_currency",
+        "amount"
+      ],
+      "properties": {
+        "amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "両替元の通貨の補助単位での減算額"
+        },
+        "from_currency": {
+          "type": "string",
+          "title": "両替元の通貨コード。レートの両替元の通貨と一致する必要がある"
+        },
+        "idempotency_key": {
+          "type": "string",
+          "title": "冪等性キー"
+        },
+        "rate_id": {
+          "type": "integer",
+          "format": "int64",
+          "title": "見積もりに使った為替レートのid"
+        },
+        "to_currency": {
+          "type": "string",
+          "title": "両替先の通貨コード。レートの両替先の通貨と一致する必要がある"
+        },
+        "user_id": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "exchangeResponse": {
+      "type": "object",
+      "properties": {
+        "create_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "from_amount": {
+          "type": "string",
+          "format": "int64"
+        },
+        "from_balance": {
+          "$ref": "#/definitions/balance"
+        },
+        "from_currency": {
+          "type": "string"
+        },
+        "idempotency_key": {
+          "type": "string",
+          "title": "冪等性キー"
+        },
+        "rate": {
+          "type": "string"
+        },
+        "rate_id": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "rounding": {
+          "type": "string",
+          "title": "丸め方（down, half_up, half_even）"
+        },
+        "to_amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "レートで換算して丸めた加算額"
+        },
+        "to_balance": {
+          "$ref": "#/definitions/balance"
+        },
+        "to_currency": {
+          "type": "string"
+        },
+        "user_id": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "overdraftLimitRequest": {
+      "type": "object",
+      "required": [
+        "currency",
+        "overdraft_limit"
+      ],
+      "properties": {
+        "currency": {
+          "type": "string"
+        },
+        "overdraft_limit": {
+          "type": "string",
+          "format": "int64",
+          "title": "与信枠（0は与信枠なし）"
+        }
+      }
+    },
+    "payAddToUsersRequest": {
+      "type": "object",
+      "required": [
+        "amount"
+      ],
+      "properties": {
+        "amount": {
+          "type": "string",
+          "format": "int64"
+        },
+        "currency": {
+          "type": "string",
+          "title": "通貨コード（JPY, USD, EUR, PTS）。省略時はJPY"
+        },
+        "expires_in_days": {
+          "type": "integer",
+          "format": "int32",
+          "title": "加算したポイントが失効するまでの日数。PTSのみ指定でき、省略時は失効しない",
+          "minimum": 1
+        },
+        "limit": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "offset": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "payRequest": {
+      "type": "object",
+      "required": [
+        "idempotency_key",
+        "user_id"
+      ],
+      "properties": {
+        "amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "加減算額（補助単位の整数の10進数文字列。減算は負の数）"
+        },
+        "currency": {
+          "type": "string",
+          "title": "通貨コード（JPY, USD, EUR, PTS）。省略時はJPY"
+        },
+        "expires_in": {
+          "type": "integer",
+          "format": "int32",
+          "title": "Tryの有効期限（秒）。省略時はサーバーのデフォルト値",
+          "minimum": 1
+        },
+        "idempotency_key": {
+          "type": "string",
+          "title": "冪等性キー"
+        },
+        "user_id": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "payResponse": {
+      "type": "object",
+      "properties": {
+        "balance": {
+          "$ref": "#/definitions/balance"
+        },
+        "cancel_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "confirm_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "expire_time": {
+          "type": "string",
+          "format": "date-time",
+          "title": "この時刻を過ぎるとConfirmできない"
+        },
+        "expired_time": {
+          "type": "string",
+          "format": "date-time",
+          "title": "期限切れとして処理された時刻"
+        },
+        "idempotency_key": {
+          "type": "string",
+          "title": "冪等性キー"
+        },
+        "refunded_amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "返金済みの額の合計"
+        },
+        "status": {
+          "type": "string",
+          "title": "ステータス（tried, confirmed, cancelled, expired, failed, reversed, partially_refunded, refunded）"
+        },
+        "try_time": {
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "payment": {
       "type": "object",
       "properties": {
-        "code": {
+        "amount": {
+          "type": "string",
+          "format": "int64"
+        },
+        "cancel_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "confirm_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "currency": {
+          "type": "string"
+        },
+        "expire_time": {
+          "type": "string",
+          "format": "date-time",
+          "title": "この時刻を過ぎるとConfirmできない"
+        },
+        "expired_time": {
+          "type": "string",
+          "format": "date-time",
+          "title": "期限切れとして処理された時刻"
+        },
+        "idempotency_key": {
+          "type": "string",
+          "title": "冪等性キー"
+        },
+        "refunded_amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "返金済みの額の合計"
+        },
+        "status": {
+          "type": "string",
+          "title": "ステータス（tried, confirmed, cancelled, expired, failed, reversed, partially_refunded, refunded）"
+        },
+        "try_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "user_id": {
           "type": "integer",
-          "format": "int32",
-          "maximum": 600,
-          "minimum": 100
+          "format": "int32"
+        }
+      }
+    },
+    "paymentList": {
+      "type": "object",
+      "properties": {
+        "next_cursor": {
+          "type": "string",
+          "title": "次のページのカーソル。次のページがなければ空"
         },
-        "message": {
+        "payments": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/payment"
+          }
+        }
+      }
+    },
+    "pointExpiration": {
+      "type": "object",
+      "properties": {
+        "amount": {
+          "type": "string",
+          "format": "int64",
+          "title": "失効するポイント"
+        },
+        "expire_time": {
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "refund": {
+      "type": "object",
+      "properties": {
+        "amount": {
+          "type": "string",
+          "format": "int64"
+        },
+        "create_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "currency": {
           "type": "string"
+        },
+        "idempotency_key": {
+          "type": "string",
+          "title": "返金の冪等性キー"
+        },
+        "payment_idempotency_key": {
+          "type": "string",
+          "title": "返金の対象の支払いの冪等性キー"
+        },
+        "user_id": {
+          "type": "integer",
+          "format": "int32"
         }
       }
     },
-    "payAddToUsersRequest": {
+    "refundList": {
+      "type": "object",
+      "properties": {
+        "refunds": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/refund"
+          }
+        }
+      }
+    },
+    "refundRequest": {
       "type": "object",
       "required": [
+        "idempotency_key",
         "amount"
       ],
       "properties": {
         "amount": {
-          "type": "integer",
-          "format": "int32"
+          "type": "string",
+          "format": "int64",
+          "title": "返金額（正の数）"
         },
-        "limit": {
-          "type": "integer",
-          "format": "int32"
+        "idempotency_key": {
+          "type": "string",
+          "title": "返金の冪等性キー（支払いの冪等性キーとは別）"
+        }
+      }
+    },
+    "refundResponse": {
+      "type": "object",
+      "properties": {
+        "balance": {
+          "$ref": "#/definitions/balance"
         },
-        "offset": {
+        "payment": {
+          "$ref": "#/definitions/payment"
+        },
+        "refund": {
+          "$ref": "#/definitions/refund"
+        }
+      }
+    },
+    "spendingLimit": {
+      "type": "object",
+      "required": [
+        "scope",
+        "scope_id",
+        "currency"
+      ],
+      "properties": {
+        "currency": {
+          "type": "string"
+        },
+        "daily_debit_limit": {
+          "type": "string",
+          "format": "int64",
+          "title": "1日の減算額の合計の上限。0は上限なし"
+        },
+        "hourly_debit_count": {
           "type": "integer",
-          "format": "int32"
+          "format": "int32",
+          "title": "直近1時間の減算の回数の上限。0は上限なし"
+        },
+        "max_single_debit": {
+          "type": "string",
+          "format": "int64",
+          "title": "1回の減算額の上限。0は上限なし"
+        },
+        "monthly_debit_limit": {
+          "type": "string",
+          "format": "int64",
+          "title": "1ヶ月の減算額の合計の上限。0は上限なし"
+        },
+        "scope": {
+          "type": "string",
+          "title": "上限の対象（user, tier）。ユーザ個別の上限はユーザ区分の上限より優先する"
+        },
+        "scope_id": {
+          "type": "string",
+          "title": "userの場合はユーザID、tierの場合はユーザ区分"
+        },
+        "update_time": {
+          "type": "string",
+          "format": "date-time"
         }
       }
     },
-    "payRequest": {
+    "spendingLimitList": {
+      "type": "object",
+      "properties": {
+        "limits": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/spendingLimit"
+          }
+        }
+      }
+    },
+    "transferRequest": {
       "type": "object",
       "required": [
         "idempotency_key",
-        "user_id"
+        "from_user_id",
+        "to_user_id",
+        "amount"
       ],
       "properties": {
         "amount": {
+          "type": "string",
+          "format": "int64"
+        },
+        "currency": {
+          "type": "string",
+          "title": "通貨コード（JPY, USD, EUR, PTS）。送金元と送金先のこの通貨のウォレット間で送金する。省略時はJPY"
+        },
+        "expires_in": {
           "type": "integer",
-          "format": "int32"
+          "format": "int32",
+          "title": "Tryの有効期限（秒）。省略時はサーバーのデフォルト値",
+          "minimum": 1
+        },
+        "from_user_id": {
+          "type": "integer",
+          "format": "int32",
+          "title": "送金元のユーザ"
         },
         "idempotency_key": {
           "type": "string",
           "title": "冪等性キー"
         },
-        "user_id": {
+        "to_user_id": {
           "type": "integer",
-          "format": "int32"
+          "format": "int32",
+          "title": "送金先のユーザ"
         }
       }
     },
-    "payResponse": {
+    "transferResponse": {
       "type": "object",
       "properties": {
-        "balance": {
-          "$ref": "#/definitions/balance"
+        "amount": {
+          "type": "string",
+          "format": "int64"
         },
         "cancel_time": {
           "type": "string",
@@ -562,15 +3834,191 @@ func init() {
           "type": "string",
           "format": "date-time"
         },
+        "currency": {
+          "type": "string"
+        },
+        "expire_time": {
+          "type": "string",
+          "format": "date-time",
+          "title": "この時刻を過ぎるとConfirmできない"
+        },
+        "expired_time": {
+          "type": "string",
+          "format": "date-time",
+          "title": "期限切れとして処理された時刻"
+        },
+        "from_balance": {
+          "$ref": "#/definitions/balance"
+        },
         "idempotency_key": {
           "type": "string",
           "title": "冪等性キー"
         },
+        "status": {
+          "type": "string",
+          "title": "ステータス（tried, confirmed, cancelled, expired）"
+        },
+        "to_balance": {
+          "$ref": "#/definitions/balance"
+        },
         "try_time": {
           "type": "string",
           "format": "date-time"
         }
       }
+    },
+    "user": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "name": {
+          "type": "string"
+        },
+        "tier": {
+          "type": "string"
+        }
+      }
+    },
+    "userTierRequest": {
+      "type": "object",
+      "required": [
+        "tier"
+      ],
+      "properties": {
+        "tier": {
+          "type": "string",
+          "title": "ユーザ区分（例えばstandard）"
+        }
+      }
+    },
+    "webhook": {
+      "type": "object",
+      "properties": {
+        "create_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "event_types": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "id": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "secret": {
+          "type": "string",
+          "title": "配信の署名の鍵（登録時のみ）"
+        },
+        "url": {
+          "type": "string"
+        },
+        "user_ids": {
+          "type": "array",
+          "items": {
+            "type": "integer",
+            "format": "int32"
+          }
+        }
+      }
+    },
+    "webhookDelivery": {
+      "type": "object",
+      "properties": {
+        "attempts": {
+          "type": "integer",
+          "format": "int32",
+          "title": "配信を試みた回数"
+        },
+        "create_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "deliver_time": {
+          "type": "string",
+          "format": "date-time",
+          "title": "配信に成功した時刻"
+        },
+        "event_sequence": {
+          "type": "integer",
+          "format": "int64",
+          "title": "イベントの通し番号"
+        },
+        "event_type": {
+          "type": "string"
+        },
+        "id": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "last_error": {
+          "type": "string",
+          "title": "直近の配信に失敗した理由"
+        },
+        "last_status_code": {
+          "type": "integer",
+          "format": "int32",
+          "title": "直近の配信の応答のHTTPステータス（応答がなければ0）"
+        },
+        "next_attempt_time": {
+          "type": "string",
+          "format": "date-time",
+          "title": "次に配信を試みる時刻（pendingのみ）"
+        },
+        "status": {
+          "type": "string",
+          "title": "ステータス（pending, succeeded, dead）"
+        }
+      }
+    },
+    "webhookDeliveryList": {
+      "type": "object",
+      "properties": {
+        "deliveries": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/webhookDelivery"
+          }
+        },
+        "next_cursor": {
+          "type": "string",
+          "title": "次のページのカーソル。次のページがなければ空"
+        }
+      }
+    },
+    "webhookRequest": {
+      "type": "object",
+      "required": [
+        "url",
+        "event_types",
+        "user_ids"
+      ],
+      "properties": {
+        "event_types": {
+          "type": "array",
+          "title": "受け取るイベントの種類（payment.confirmed, payment.cancelled, payment.expired, refund.created, transfer.confirmed, transfer.cancelled, transfer.expired, exchange.completed, points.expired）。複数のユーザの一斉加算のイベントは購読できない",
+          "items": {
+            "type": "string"
+          }
+        },
+        "url": {
+          "type": "string",
+          "title": "配信先のURL（httpまたはhttps）。ホストは公開されたアドレスに解決できること"
+        },
+        "user_ids": {
+          "type": "array",
+          "title": "配信の対象のパートナーのユーザ（最大1000人）。このユーザの残高の変更のイベントだけを配信する",
+          "items": {
+            "type": "integer",
+            "format": "int32"
+          }
+        }
+      }
     }
   },
   "tags": [
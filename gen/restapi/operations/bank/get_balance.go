@@ -33,7 +33,7 @@ func NewGetBalance(ctx *middleware.Context, handler GetBalanceHandler) *GetBalan
 
 GetBalance
 
-ユーザの残高を取得
+ユーザの通貨ごとのウォレット（残高）をすべて取得
 
 */
 type GetBalance struct {
@@ -25,7 +25,7 @@ type GetBalanceOK struct {
 	/*
 	  In: Body
 	*/
-	Payload *models.Balance `json:"body,omitempty"`
+	Payload *models.BalanceList `json:"body,omitempty"`
 }
 
 // NewGetBalanceOK creates GetBalanceOK with default headers values
@@ -35,13 +35,13 @@ func NewGetBalanceOK() *GetBalanceOK {
 }
 
 // WithPayload adds the payload to the get balance o k response
-func (o *GetBalanceOK) WithPayload(payload *models.Balance) *GetBalanceOK {
+func (o *GetBalanceOK) WithPayload(payload *models.BalanceList) *GetBalanceOK {
 	o.Payload = payload
 	return o
 }
 
 // SetPayload sets the payload to the get balance o k response
-func (o *GetBalanceOK) SetPayload(payload *models.Balance) {
+func (o *GetBalanceOK) SetPayload(payload *models.BalanceList) {
 	o.Payload = payload
 }
 
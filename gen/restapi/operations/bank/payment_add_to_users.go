@@ -33,7 +33,7 @@ func NewPaymentAddToUsers(ctx *middleware.Context, handler PaymentAddToUsersHand
 
 PaymentAddToUsers
 
-（limit,offsetを指定して）ユーザの残高に一斉に加算する
+（limit,offsetを指定して）ユーザの残高に一斉に加算する。非推奨。冪等で再開可能な /bulk_credits を使う
 
 */
 type PaymentAddToUsers struct {
@@ -44,9 +44,39 @@ func NewBankAPI(spec *loads.Document) *BankAPI {
 
 		JSONProducer: runtime.JSONProducer(),
 
+		BankCreateBulkCreditHandler: bank.CreateBulkCreditHandlerFunc(func(params bank.CreateBulkCreditParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.CreateBulkCredit has not yet been implemented")
+		}),
+		BankCreateWebhookHandler: bank.CreateWebhookHandlerFunc(func(params bank.CreateWebhookParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.CreateWebhook has not yet been implemented")
+		}),
+		BankExchangeHandler: bank.ExchangeHandlerFunc(func(params bank.ExchangeParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.Exchange has not yet been implemented")
+		}),
 		BankGetBalanceHandler: bank.GetBalanceHandlerFunc(func(params bank.GetBalanceParams) middleware.Responder {
 			return middleware.NotImplemented("operation bank.GetBalance has not yet been implemented")
 		}),
+		BankGetBulkCreditHandler: bank.GetBulkCreditHandlerFunc(func(params bank.GetBulkCreditParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.GetBulkCredit has not yet been implemented")
+		}),
+		BankListBalanceLogsHandler: bank.ListBalanceLogsHandlerFunc(func(params bank.ListBalanceLogsParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.ListBalanceLogs has not yet been implemented")
+		}),
+		BankListExchangeRatesHandler: bank.ListExchangeRatesHandlerFunc(func(params bank.ListExchangeRatesParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.ListExchangeRates has not yet been implemented")
+		}),
+		BankListPaymentsHandler: bank.ListPaymentsHandlerFunc(func(params bank.ListPaymentsParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.ListPayments has not yet been implemented")
+		}),
+		BankListRefundsHandler: bank.ListRefundsHandlerFunc(func(params bank.ListRefundsParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.ListRefunds has not yet been implemented")
+		}),
+		BankListSpendingLimitsHandler: bank.ListSpendingLimitsHandlerFunc(func(params bank.ListSpendingLimitsParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.ListSpendingLimits has not yet been implemented")
+		}),
+		BankListWebhookDeliveriesHandler: bank.ListWebhookDeliveriesHandlerFunc(func(params bank.ListWebhookDeliveriesParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.ListWebhookDeliveries has not yet been implemented")
+		}),
 		BankPaymentAddToUsersHandler: bank.PaymentAddToUsersHandlerFunc(func(params bank.PaymentAddToUsersParams) middleware.Responder {
 			return middleware.NotImplemented("operation bank.PaymentAddToUsers has not yet been implemented")
 		}),
@@ -56,9 +86,36 @@ func NewBankAPI(spec *loads.Document) *BankAPI {
 		BankPaymentConfirmHandler: bank.PaymentConfirmHandlerFunc(func(params bank.PaymentConfirmParams) middleware.Responder {
 			return middleware.NotImplemented("operation bank.PaymentConfirm has not yet been implemented")
 		}),
+		BankPaymentRefundHandler: bank.PaymentRefundHandlerFunc(func(params bank.PaymentRefundParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.PaymentRefund has not yet been implemented")
+		}),
 		BankPaymentTryHandler: bank.PaymentTryHandlerFunc(func(params bank.PaymentTryParams) middleware.Responder {
 			return middleware.NotImplemented("operation bank.PaymentTry has not yet been implemented")
 		}),
+		BankReverseBulkCreditHandler: bank.ReverseBulkCreditHandlerFunc(func(params bank.ReverseBulkCreditParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.ReverseBulkCredit has not yet been implemented")
+		}),
+		BankSetOverdraftLimitHandler: bank.SetOverdraftLimitHandlerFunc(func(params bank.SetOverdraftLimitParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.SetOverdraftLimit has not yet been implemented")
+		}),
+		BankSetSpendingLimitHandler: bank.SetSpendingLimitHandlerFunc(func(params bank.SetSpendingLimitParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.SetSpendingLimit has not yet been implemented")
+		}),
+		BankSetUserTierHandler: bank.SetUserTierHandlerFunc(func(params bank.SetUserTierParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.SetUserTier has not yet been implemented")
+		}),
+		BankTransferCancelHandler: bank.TransferCancelHandlerFunc(func(params bank.TransferCancelParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.TransferCancel has not yet been implemented")
+		}),
+		BankTransferConfirmHandler: bank.TransferConfirmHandlerFunc(func(params bank.TransferConfirmParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.TransferConfirm has not yet been implemented")
+		}),
+		BankTransferTryHandler: bank.TransferTryHandlerFunc(func(params bank.TransferTryParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.TransferTry has not yet been implemented")
+		}),
+		BankUploadExchangeRatesHandler: bank.UploadExchangeRatesHandlerFunc(func(params bank.UploadExchangeRatesParams) middleware.Responder {
+			return middleware.NotImplemented("operation bank.UploadExchangeRates has not yet been implemented")
+		}),
 	}
 }
 
@@ -93,16 +150,54 @@ type BankAPI struct {
 	//   - application/json
 	JSONProducer runtime.Producer
 
+	// BankCreateBulkCreditHandler sets the operation handler for the create bulk credit operation
+	BankCreateBulkCreditHandler bank.CreateBulkCreditHandler
+	// BankCreateWebhookHandler sets the operation handler for the create webhook operation
+	BankCreateWebhookHandler bank.CreateWebhookHandler
+	// BankExchangeHandler sets the operation handler for the exchange operation
+	BankExchangeHandler bank.ExchangeHandler
 	// BankGetBalanceHandler sets the operation handler for the get balance operation
 	BankGetBalanceHandler bank.GetBalanceHandler
+	// BankGetBulkCreditHandler sets the operation handler for the get bulk credit operation
+	BankGetBulkCreditHandler bank.GetBulkCreditHandler
+	// BankListBalanceLogsHandler sets the operation handler for the list balance logs operation
+	BankListBalanceLogsHandler bank.ListBalanceLogsHandler
+	// BankListExchangeRatesHandler sets the operation handler for the list exchange rates operation
+	BankListExchangeRatesHandler bank.ListExchangeRatesHandler
+	// BankListPaymentsHandler sets the operation handler for the list payments operation
+	BankListPaymentsHandler bank.ListPaymentsHandler
+	// BankListRefundsHandler sets the operation handler for the list refunds operation
+	BankListRefundsHandler bank.ListRefundsHandler
+	// BankListSpendingLimitsHandler sets the operation handler for the list spending limits operation
+	BankListSpendingLimitsHandler bank.ListSpendingLimitsHandler
+	// BankListWebhookDeliveriesHandler sets the operation handler for the list webhook deliveries operation
+	BankListWebhookDeliveriesHandler bank.ListWebhookDeliveriesHandler
 	// BankPaymentAddToUsersHandler sets the operation handler for the payment add to users operation
 	BankPaymentAddToUsersHandler bank.PaymentAddToUsersHandler
 	// BankPaymentCancelHandler sets the operation handler for the payment cancel operation
 	BankPaymentCancelHandler bank.PaymentCancelHandler
 	// BankPaymentConfirmHandler sets the operation handler for the payment confirm operation
 	BankPaymentConfirmHandler bank.PaymentConfirmHandler
+	// BankPaymentRefundHandler sets the operation handler for the payment refund operation
+	BankPaymentRefundHandler bank.PaymentRefundHandler
 	// BankPaymentTryHandler sets the operation handler for the payment try operation
 	BankPaymentTryHandler bank.PaymentTryHandler
+	// BankReverseBulkCreditHandler sets the operation handler for the reverse bulk credit operation
+	BankReverseBulkCreditHandler bank.ReverseBulkCreditHandler
+	// BankSetOverdraftLimitHandler sets the operation handler for the set overdraft limit operation
+	BankSetOverdraftLimitHandler bank.SetOverdraftLimitHandler
+	// BankSetSpendingLimitHandler sets the operation handler for the set spending limit operation
+	BankSetSpendingLimitHandler bank.SetSpendingLimitHandler
+	// BankSetUserTierHandler sets the operation handler for the set user tier operation
+	BankSetUserTierHandler bank.SetUserTierHandler
+	// BankTransferCancelHandler sets the operation handler for the transfer cancel operation
+	BankTransferCancelHandler bank.TransferCancelHandler
+	// BankTransferConfirmHandler sets the operation handler for the transfer confirm operation
+	BankTransferConfirmHandler bank.TransferConfirmHandler
+	// BankTransferTryHandler sets the operation handler for the transfer try operation
+	BankTransferTryHandler bank.TransferTryHandler
+	// BankUploadExchangeRatesHandler sets the operation handler for the upload exchange rates operation
+	BankUploadExchangeRatesHandler bank.UploadExchangeRatesHandler
 	// ServeError is called when an error is received, there is a default handler
 	// but you can set your own with this
 	ServeError func(http.ResponseWriter, *http.Request, error)
@@ -179,9 +274,39 @@ func (o *BankAPI) Validate() error {
 		unregistered = append(unregistered, "JSONProducer")
 	}
 
+	if o.BankCreateBulkCreditHandler == nil {
+		unregistered = append(unregistered, "bank.CreateBulkCreditHandler")
+	}
+	if o.BankCreateWebhookHandler == nil {
+		unregistered = append(unregistered, "bank.CreateWebhookHandler")
+	}
+	if o.BankExchangeHandler == nil {
+		unregistered = append(unregistered, "bank.ExchangeHandler")
+	}
 	if o.BankGetBalanceHandler == nil {
 		unregistered = append(unregistered, "bank.GetBalanceHandler")
 	}
+	if o.BankGetBulkCreditHandler == nil {
+		unregistered = append(unregistered, "bank.GetBulkCreditHandler")
+	}
+	if o.BankListBalanceLogsHandler == nil {
+		unregistered = append(unregistered, "bank.ListBalanceLogsHandler")
+	}
+	if o.BankListExchangeRatesHandler == nil {
+		unregistered = append(unregistered, "bank.ListExchangeRatesHandler")
+	}
+	if o.BankListPaymentsHandler == nil {
+		unregistered = append(unregistered, "bank.ListPaymentsHandler")
+	}
+	if o.BankListRefundsHandler == nil {
+		unregistered = append(unregistered, "bank.ListRefundsHandler")
+	}
+	if o.BankListSpendingLimitsHandler == nil {
+		unregistered = append(unregistered, "bank.ListSpendingLimitsHandler")
+	}
+	if o.BankListWebhookDeliveriesHandler == nil {
+		unregistered = append(unregistered, "bank.ListWebhookDeliveriesHandler")
+	}
 	if o.BankPaymentAddToUsersHandler == nil {
 		unregistered = append(unregistered, "bank.PaymentAddToUsersHandler")
 	}
@@ -191,9 +316,36 @@ func (o *BankAPI) Validate() error {
 	if o.BankPaymentConfirmHandler == nil {
 		unregistered = append(unregistered, "bank.PaymentConfirmHandler")
 	}
+	if o.BankPaymentRefundHandler == nil {
+		unregistered = append(unregistered, "bank.PaymentRefundHandler")
+	}
 	if o.BankPaymentTryHandler == nil {
 		unregistered = append(unregistered, "bank.PaymentTryHandler")
 	}
+	if o.BankReverseBulkCreditHandler == nil {
+		unregistered = append(unregistered, "bank.ReverseBulkCreditHandler")
+	}
+	if o.BankSetOverdraftLimitHandler == nil {
+		unregistered = append(unregistered, "bank.SetOverdraftLimitHandler")
+	}
+	if o.BankSetSpendingLimitHandler == nil {
+		unregistered = append(unregistered, "bank.SetSpendingLimitHandler")
+	}
+	if o.BankSetUserTierHandler == nil {
+		unregistered = append(unregistered, "bank.SetUserTierHandler")
+	}
+	if o.BankTransferCancelHandler == nil {
+		unregistered = append(unregistered, "bank.TransferCancelHandler")
+	}
+	if o.BankTransferConfirmHandler == nil {
+		unregistered = append(unregistered, "bank.TransferConfirmHandler")
+	}
+	if o.BankTransferTryHandler == nil {
+		unregistered = append(unregistered, "bank.TransferTryHandler")
+	}
+	if o.BankUploadExchangeRatesHandler == nil {
+		unregistered = append(unregistered, "bank.UploadExchangeRatesHandler")
+	}
 
 	if len(unregistered) > 0 {
 		return fmt.Errorf("missing registration: %s", strings.Join(unregistered, ", "))
@@ -282,10 +434,50 @@ func (o *BankAPI) initHandlerCache() {
 		o.handlers = make(map[string]map[string]http.Handler)
 	}
 
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/bulk_credits"] = bank.NewCreateBulkCredit(o.context, o.BankCreateBulkCreditHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/webhooks"] = bank.NewCreateWebhook(o.context, o.BankCreateWebhookHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/exchanges"] = bank.NewExchange(o.context, o.BankExchangeHandler)
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
 	o.handlers["GET"]["/balances/{userId}"] = bank.NewGetBalance(o.context, o.BankGetBalanceHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/bulk_credits/{id}"] = bank.NewGetBulkCredit(o.context, o.BankGetBulkCreditHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/balances/{userId}/logs"] = bank.NewListBalanceLogs(o.context, o.BankListBalanceLogsHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/exchange_rates"] = bank.NewListExchangeRates(o.context, o.BankListExchangeRatesHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/users/{userId}/payments"] = bank.NewListPayments(o.context, o.BankListPaymentsHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/payments/{idempotency_key}/refunds"] = bank.NewListRefunds(o.context, o.BankListRefundsHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/admin/spending_limits"] = bank.NewListSpendingLimits(o.context, o.BankListSpendingLimitsHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/webhooks/{id}/deliveries"] = bank.NewListWebhookDeliveries(o.context, o.BankListWebhookDeliveriesHandler)
 	if o.handlers["POST"] == nil {
 		o.handlers["POST"] = make(map[string]http.Handler)
 	}
@@ -301,7 +493,43 @@ func (o *BankAPI) initHandlerCache() {
 	if o.handlers["POST"] == nil {
 		o.handlers["POST"] = make(map[string]http.Handler)
 	}
+	o.handlers["POST"]["/payments/{idempotency_key}/refunds"] = bank.NewPaymentRefund(o.context, o.BankPaymentRefundHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
 	o.handlers["POST"]["/payments/try"] = bank.NewPaymentTry(o.context, o.BankPaymentTryHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/bulk_credits/{id}/reverse"] = bank.NewReverseBulkCredit(o.context, o.BankReverseBulkCreditHandler)
+	if o.handlers["PUT"] == nil {
+		o.handlers["PUT"] = make(map[string]http.Handler)
+	}
+	o.handlers["PUT"]["/admin/balances/{userId}/overdraft"] = bank.NewSetOverdraftLimit(o.context, o.BankSetOverdraftLimitHandler)
+	if o.handlers["PUT"] == nil {
+		o.handlers["PUT"] = make(map[string]http.Handler)
+	}
+	o.handlers["PUT"]["/admin/spending_limits"] = bank.NewSetSpendingLimit(o.context, o.BankSetSpendingLimitHandler)
+	if o.handlers["PUT"] == nil {
+		o.handlers["PUT"] = make(map[string]http.Handler)
+	}
+	o.handlers["PUT"]["/admin/users/{userId}/tier"] = bank.NewSetUserTier(o.context, o.BankSetUserTierHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/transfers/cancel"] = bank.NewTransferCancel(o.context, o.BankTransferCancelHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/transfers/confirm"] = bank.NewTransferConfirm(o.context, o.BankTransferConfirmHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/transfers/try"] = bank.NewTransferTry(o.context, o.BankTransferTryHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/admin/exchange_rates"] = bank.NewUploadExchangeRates(o.context, o.BankUploadExchangeRatesHandler)
 }
 
 // Serve creates a http handler to serve the API over HTTP
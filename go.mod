@@ -12,11 +12,16 @@ require (
 	github.com/go-openapi/swag v0.19.14
 	github.com/go-openapi/validate v0.20.2
 	github.com/go-sql-driver/mysql v1.5.0
-	github.com/golang/mock v1.5.0 // indirect
-	github.com/google/go-cmp v0.5.2
+	github.com/golang/mock v1.5.0
+	github.com/google/go-cmp v0.5.5
+	github.com/jackc/pgconn v1.10.1
+	github.com/jackc/pgx/v4 v4.14.1
 	github.com/jessevdk/go-flags v1.4.0
 	github.com/labstack/gommon v0.3.0
 	github.com/rs/cors v1.7.0
 	github.com/rubenv/sql-migrate v0.0.0-20210215143335-f84234893558
-	golang.org/x/net v0.0.0-20210119194325-5f4716e94777
+	golang.org/x/net v0.0.0-20210226172049-e18ecbb05110
+	google.golang.org/grpc v1.43.0
+	google.golang.org/protobuf v1.27.1
+	modernc.org/sqlite v1.14.3
 )
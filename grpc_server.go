@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+	"github.com/kawabatas/m-bank/proto/bankpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// grpcServer serves the gRPC API with the same application services as the REST API.
+type grpcServer struct {
+	bankpb.UnimplementedBankServer
+	app *application
+}
+
+func newGRPCServer(app *application) *grpc.Server {
+	s := grpc.NewServer()
+	bankpb.RegisterBankServer(s, &grpcServer{app: app})
+	return s
+}
+
+func (s *grpcServer) GetBalance(ctx context.Context, req *bankpb.GetBalanceRequest) (*bankpb.BalanceList, error) {
+	balances, err := s.app.BalanceService.List(ctx, uint(req.UserId))
+	if err != nil {
+		return nil, errToStatus(err)
+	}
+	list := &bankpb.BalanceList{
+		UserId:   req.UserId,
+		Balances: make([]*bankpb.Balance, 0, len(balances)),
+	}
+	for _, b := range balances {
+		list.Balances = append(list.Balances, toPBBalance(b))
+	}
+	return list, nil
+}
+
+func (s *grpcServer) Try(ctx context.Context, req *bankpb.PaymentRequest) (*bankpb.PayResponse, error) {
+	expiresIn := time.Duration(req.ExpiresIn) * time.Second
+	pt, balance, err := s.app.PaymentService.Try(ctx, req.IdempotencyKey, uint(req.UserId), req.Currency, req.Amount, expiresIn)
+	if err != nil {
+		return nil, errToStatus(err)
+	}
+	return toPBPayResponse(pt, balance), nil
+}
+
+func (s *grpcServer) Confirm(ctx context.Context, req *bankpb.PaymentRequest) (*bankpb.PayResponse, error) {
+	pt, balance, err := s.app.PaymentService.Confirm(ctx, req.IdempotencyKey, uint(req.UserId), req.Currency, req.Amount)
+	if err != nil {
+		return nil, errToStatus(err)
+	}
+	return toPBPayResponse(pt, balance), nil
+}
+
+func (s *grpcServer) Cancel(ctx context.Context, req *bankpb.PaymentRequest) (*bankpb.PayResponse, error) {
+	pt, balance, err := s.app.PaymentService.Cancel(ctx, req.IdempotencyKey, uint(req.UserId), req.Currency, req.Amount)
+	if err != nil {
+		return nil, errToStatus(err)
+	}
+	return toPBPayResponse(pt, balance), nil
+}
+
+func (s *grpcServer) AddToUsers(ctx context.Context, req *bankpb.AddToUsersRequest) (*bankpb.AddToUsersResponse, error) {
+	if err := s.app.PaymentService.AddToUsers(ctx, req.Currency, req.Amount, int(req.ExpiresInDays), int(req.Limit), int(req.Offset)); err != nil {
+		return nil, errToStatus(err)
+	}
+	return &bankpb.AddToUsersResponse{}, nil
+}
+
+func toPBPayResponse(pt *model.PaymentTransaction, balance *model.Balance) *bankpb.PayResponse {
+	return &bankpb.PayResponse{
+		IdempotencyKey: pt.UUID,
+		Status:         string(pt.Status),
+		TryTime:        toPBTimestamp(pt.TryTime),
+		ExpireTime:     toPBTimestamp(pt.ExpireTime),
+		ConfirmTime:    toPBTimestamp(pt.ConfirmTime),
+		CancelTime:     toPBTimestamp(pt.CancelTime),
+		ExpiredTime:    toPBTimestamp(pt.ExpiredTime),
+		RefundedAmount: pt.RefundedAmount,
+		Balance:        toPBBalance(balance),
+	}
+}
+
+func toPBBalance(balance *model.Balance) *bankpb.Balance {
+	res := &bankpb.Balance{
+		UserId:         uint32(balance.UserID),
+		Currency:       string(balance.Currency),
+		Amount:         balance.Amount,
+		Available:      balance.AvailableAmount(),
+		OverdraftLimit: balance.OverdraftLimit,
+	}
+	for _, e := range balance.Expirations {
+		res.Expirations = append(res.Expirations, &bankpb.PointExpiration{
+			Amount:     e.Amount,
+			ExpireTime: toPBTimestamp(e.ExpireTime),
+		})
+	}
+	return res
+}
+
+// 未設定の時刻はフィールドごと省略する
+func toPBTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// errToStatus maps the domain errors to the gRPC status codes. Unlike the REST API, an unknown idempotency key is NotFound.
+func errToStatus(err error) error {
+	var code codes.Code
+	switch {
+	case errors.Is(err, domain.ErrNoSuchEntity) || errors.Is(err, domain.ErrInvalidUUID):
+		code = codes.NotFound
+	case errors.Is(err, domain.ErrIdempotencyKeyMismatch) || errors.Is(err, domain.ErrDuplicateUUID):
+		code = codes.AlreadyExists
+	case errors.Is(err, domain.ErrLimitExceeded):
+		code = codes.ResourceExhausted
+	case errors.Is(err, domain.ErrShortBalance) || errors.Is(err, domain.ErrExpiredTransaction) || errors.Is(err, domain.ErrIllegalTransition) || errors.Is(err, domain.ErrExpiredRate):
+		code = codes.FailedPrecondition
+	case errors.Is(err, domain.ErrInvalidParam) || errors.Is(err, domain.ErrTransactionMismatch) || errors.Is(err, domain.ErrAmountOverflow) || errors.Is(err, domain.ErrCurrencyMismatch):
+		code = codes.InvalidArgument
+	default:
+		code = codes.Internal
+	}
+	return status.Error(code, err.Error())
+}
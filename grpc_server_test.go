@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/proto/bankpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func Test_errToStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"存在しないユーザ", domain.ErrNoSuchEntity, codes.NotFound},
+		{"存在しない冪等キー", domain.ErrInvalidUUID, codes.NotFound},
+		{"冪等キーが別のリクエストで使われている", domain.ErrIdempotencyKeyMismatch, codes.AlreadyExists},
+		{"減算の上限を超える", domain.ErrLimitExceeded, codes.ResourceExhausted},
+		{"ラップされた残高不足", fmt.Errorf("try: %w", domain.ErrShortBalance), codes.FailedPrecondition},
+		{"パラメータが不正", domain.ErrInvalidParam, codes.InvalidArgument},
+		{"その他のエラー", fmt.Errorf("unknown"), codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(errToStatus(tt.err)); got != tt.want {
+				t.Errorf("errToStatus() code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// user1(100円)とuser2(200円)のいるメモリ上のアプリケーションに、bufconn経由で順にリクエストする
+func Test_grpcServer(t *testing.T) {
+	app, err := newMemoryApp(&config{TryTTL: time.Minute, StrictMode: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis := bufconn.Listen(1024 * 1024)
+	s := newGRPCServer(app)
+	go s.Serve(lis)
+	defer s.Stop()
+
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := bankpb.NewBankClient(conn)
+
+	type payFunc func(context.Context, *bankpb.PaymentRequest, ...grpc.CallOption) (*bankpb.PayResponse, error)
+	pay := func(f payFunc, key string, userID uint32, amount int64) func() (string, *bankpb.Balance, error) {
+		return func() (string, *bankpb.Balance, error) {
+			res, err := f(ctx, &bankpb.PaymentRequest{IdempotencyKey: key, UserId: userID, Amount: amount})
+			if err != nil {
+				return "", nil, err
+			}
+			return res.Status, res.Balance, nil
+		}
+	}
+	getBalance := func(userID uint32) func() (string, *bankpb.Balance, error) {
+		return func() (string, *bankpb.Balance, error) {
+			res, err := client.GetBalance(ctx, &bankpb.GetBalanceRequest{UserId: userID})
+			if err != nil {
+				return "", nil, err
+			}
+			for _, b := range res.Balances {
+				if b.Currency == string(domain.JPY) {
+					return "", b, nil
+				}
+			}
+			return "", nil, fmt.Errorf("no JPY wallet: %v", res.Balances)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		call          func() (string, *bankpb.Balance, error)
+		wantCode      codes.Code
+		wantStatus    string
+		wantAmount    int64
+		wantAvailable int64
+	}{
+		{"Tryで減算を仮押さえできる", pay(client.Try, "key-1", 1, -30), codes.OK, "tried", 100, 70},
+		{"同じ内容での再試行は保存済みの結果を返す", pay(client.Try, "key-1", 1, -30), codes.OK, "tried", 100, 70},
+		{"異なる内容での再試行", pay(client.Try, "key-1", 1, -31), codes.AlreadyExists, "", 0, 0},
+		{"残高が足りない", pay(client.Try, "key-2", 1, -71), codes.FailedPrecondition, "", 0, 0},
+		{"冪等キーが空", pay(client.Try, "", 1, -1), codes.InvalidArgument, "", 0, 0},
+		{"ユーザIDが空", pay(client.Try, "key-3", 0, -1), codes.InvalidArgument, "", 0, 0},
+		{"存在しないユーザ", pay(client.Try, "key-4", 99, -1), codes.NotFound, "", 0, 0},
+		{"Confirmで確定できる", pay(client.Confirm, "key-1", 1, -30), codes.OK, "confirmed", 70, 70},
+		{"確定済みの取引はキャンセルできない", pay(client.Cancel, "key-1", 1, -30), codes.FailedPrecondition, "", 0, 0},
+		{"存在しない冪等キーは確定できない", pay(client.Confirm, "key-5", 1, -30), codes.NotFound, "", 0, 0},
+		{"冪等キーが空のConfirm", pay(client.Confirm, "", 1, -30), codes.InvalidArgument, "", 0, 0},
+		{"別のユーザのTry", pay(client.Try, "key-6", 2, -50), codes.OK, "tried", 200, 150},
+		{"Cancelで仮押さえを取り消せる", pay(client.Cancel, "key-6", 2, -50), codes.OK, "cancelled", 200, 200},
+		{"ユーザIDが空のCancel", pay(client.Cancel, "key-6", 0, -50), codes.InvalidArgument, "", 0, 0},
+		{"残高を取得できる", getBalance(1), codes.OK, "", 70, 70},
+		{"存在しないユーザの残高", getBalance(99), codes.NotFound, "", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStatus, gotBalance, err := tt.call()
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("code = %v, want %v (%v)", got, tt.wantCode, err)
+			}
+			if err != nil {
+				return
+			}
+			if gotStatus != tt.wantStatus {
+				t.Errorf("status = %v, want %v", gotStatus, tt.wantStatus)
+			}
+			if gotBalance.Amount != tt.wantAmount || gotBalance.Available != tt.wantAvailable {
+				t.Errorf("balance = %v/%v, want %v/%v", gotBalance.Amount, gotBalance.Available, tt.wantAmount, tt.wantAvailable)
+			}
+		})
+	}
+
+	t.Run("負のオフセットで一括加算できない", func(t *testing.T) {
+		_, err := client.AddToUsers(ctx, &bankpb.AddToUsersRequest{Amount: 1, Limit: 10, Offset: -1})
+		if got := status.Code(err); got != codes.InvalidArgument {
+			t.Errorf("code = %v, want %v (%v)", got, codes.InvalidArgument, err)
+		}
+	})
+}
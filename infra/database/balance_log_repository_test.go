@@ -0,0 +1,143 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+func newBalanceLogRepo(t *testing.T) *BalanceLogRepository {
+	t.Helper()
+	db := newTestConnection(t)
+	return NewBalanceLogRepository(db)
+}
+
+func TestBalanceLogRepository_List(t *testing.T) {
+	repo := newBalanceLogRepo(t)
+	users := createSampleUsers(t, repo.DB, 2)
+	ctx := context.Background()
+
+	// 支払いのConfirmと一斉加算で、操作と紐付いたログが記録される
+	createSamplePaymentTransaction(t, repo.DB,
+		&model.PaymentTransaction{
+			UUID:     "pay",
+			UserID:   users[0].ID,
+			Currency: domain.JPY,
+			Amount:   -100,
+			TryTime:  time.Now(),
+		},
+	)
+	if _, err := NewPaymentTransactionRepository(repo.DB).Confirm(ctx, "pay"); err != nil {
+		t.Fatalf("PaymentTransactionRepository.Confirm() error = %v", err)
+	}
+	if err := NewBalanceRepository(repo.DB).AddToUsers(ctx, domain.JPY, 10, time.Time{}, 10, 0); err != nil {
+		t.Fatalf("BalanceRepository.AddToUsers() error = %v", err)
+	}
+	payLog := &model.BalanceLog{
+		UserID:       users[0].ID,
+		Currency:     domain.JPY,
+		BeforeAmount: initBalanceAmount,
+		AfterAmount:  initBalanceAmount - 100,
+		Delta:        -100,
+		SourceType:   model.JournalSourcePayment,
+		SourceID:     "pay",
+		Reason:       "payment confirmed",
+	}
+	addLog := &model.BalanceLog{
+		UserID:       users[0].ID,
+		Currency:     domain.JPY,
+		BeforeAmount: initBalanceAmount - 100,
+		AfterAmount:  initBalanceAmount - 90,
+		Delta:        10,
+		SourceType:   model.JournalSourceAddToUsers,
+		SourceID:     "limit=10,offset=0",
+		Reason:       "credited to users",
+	}
+	all, err := repo.List(ctx, users[0].ID, model.BalanceLogFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("BalanceLogRepository.List() error = %v", err)
+	}
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx    context.Context
+		userID uint
+		filter model.BalanceLogFilter
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*model.BalanceLog
+		wantErr bool
+	}{
+		{
+			"新しい順に取得できる",
+			fields{repo.DB},
+			args{ctx, users[0].ID, model.BalanceLogFilter{Limit: 10}},
+			[]*model.BalanceLog{addLog, payLog},
+			false,
+		},
+		{
+			"件数を指定できる",
+			fields{repo.DB},
+			args{ctx, users[0].ID, model.BalanceLogFilter{Limit: 1}},
+			[]*model.BalanceLog{addLog},
+			false,
+		},
+		{
+			"カーソルより前のログを取得できる",
+			fields{repo.DB},
+			args{ctx, users[0].ID, model.BalanceLogFilter{BeforeID: all[0].ID, Limit: 10}},
+			[]*model.BalanceLog{payLog},
+			false,
+		},
+		{
+			"期間外のログは取得しない",
+			fields{repo.DB},
+			args{ctx, users[0].ID, model.BalanceLogFilter{To: time.Now().Add(-time.Hour), Limit: 10}},
+			nil,
+			false,
+		},
+		{
+			"他のユーザのログは取得しない",
+			fields{repo.DB},
+			args{ctx, users[1].ID, model.BalanceLogFilter{From: time.Now().Add(-time.Hour), Limit: 10}},
+			[]*model.BalanceLog{{
+				UserID:       users[1].ID,
+				Currency:     domain.JPY,
+				BeforeAmount: initBalanceAmount,
+				AfterAmount:  initBalanceAmount + 10,
+				Delta:        10,
+				SourceType:   model.JournalSourceAddToUsers,
+				SourceID:     "limit=10,offset=0",
+				Reason:       "credited to users",
+			}},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &BalanceLogRepository{
+				DB: tt.fields.DB,
+			}
+			got, err := r.List(tt.args.ctx, tt.args.userID, tt.args.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BalanceLogRepository.List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			opt := cmpopts.IgnoreFields(model.BalanceLog{}, "ID", "CreateTime")
+			if diff := cmp.Diff(tt.want, got, opt); diff != "" {
+				t.Errorf("BalanceLogRepository.List() mismatch (-want +got): \n %s", diff)
+			}
+		})
+	}
+}
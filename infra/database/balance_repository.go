@@ -4,8 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"strconv"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/kawabatas/m-bank/domain"
 	"github.com/kawabatas/m-bank/domain/model"
@@ -19,11 +20,78 @@ func NewBalanceRepository(db *sql.DB) *BalanceRepository {
 	return &BalanceRepository{DB: db}
 }
 
-func (r *BalanceRepository) Get(ctx context.Context, userID uint) (*model.Balance, error) {
-	return findBalance(ctx, r.DB, userID)
+func (r *BalanceRepository) Get(ctx context.Context, userID uint, currency domain.Currency) (*model.Balance, error) {
+	return findBalance(ctx, r.DB, userID, currency, false)
 }
 
-func (r *BalanceRepository) AddToUsers(ctx context.Context, amount, limit, offset int) error {
+func (r *BalanceRepository) List(ctx context.Context, userID uint) ([]*model.Balance, error) {
+	query := `
+	SELECT u.id, b.currency, b.amount, b.reserved_amount, b.overdraft_limit
+	FROM users u LEFT JOIN balances b ON b.user_id = u.id
+	WHERE u.id = ? ORDER BY b.currency ASC`
+	rows, err := r.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	found := false
+	balances := []*model.Balance{}
+	for rows.Next() {
+		found = true
+		var currency sql.NullString
+		var amount, reservedAmount, overdraftLimit sql.NullInt64
+		balance := &model.Balance{}
+		if err := rows.Scan(&balance.UserID, &currency, &amount, &reservedAmount, &overdraftLimit); err != nil {
+			return nil, err
+		}
+		// ウォレットを1つも持っていないユーザ
+		if !currency.Valid {
+			continue
+		}
+		balance.Currency = domain.Currency(currency.String)
+		balance.Amount = amount.Int64
+		balance.ReservedAmount = reservedAmount.Int64
+		balance.OverdraftLimit = overdraftLimit.Int64
+		balances = append(balances, balance)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, domain.ErrNoSuchEntity
+	}
+	return balances, nil
+}
+
+func (r *BalanceRepository) SetOverdraftLimit(ctx context.Context, userID uint, currency domain.Currency, limit int64) (*model.Balance, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// ユーザの存在を確認し、同じユーザの減算と直列にする
+	if _, err := findBalance(ctx, tx, userID, currency, true); err != nil {
+		return nil, err
+	}
+	// 与信枠を下げて残高が枠を超えて負になっていても、そのままにする（以降の減算が残高不足になる）
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO balances (user_id, currency, overdraft_limit) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE overdraft_limit = VALUES(overdraft_limit)`,
+		userID, currency, limit,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return findBalance(ctx, r.DB, userID, currency, false)
+}
+
+func (r *BalanceRepository) AddToUsers(ctx context.Context, currency domain.Currency, amount int64, pointExpireTime time.Time, limit, offset int) error {
 	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -32,43 +100,36 @@ func (r *BalanceRepository) AddToUsers(ctx context.Context, amount, limit, offse
 		_ = tx.Rollback()
 	}()
 
-	// 対象のユーザの残高取得
-	var balances []model.Balance
-	fetchQuery := `SELECT user_id, amount FROM balances ORDER BY user_id ASC LIMIT ? OFFSET ? FOR UPDATE`
-	rows, err := tx.QueryContext(ctx, fetchQuery, limit, offset)
+	// 対象のユーザ取得（その通貨のウォレットがなければ加算時に作られる）
+	userIDs, err := queryUserIDs(ctx, tx, `SELECT id FROM users ORDER BY id ASC LIMIT ? OFFSET ?`, limit, offset)
 	if err != nil {
 		return err
 	}
-	for rows.Next() {
-		var balance model.Balance
-		if err := rows.Scan(&balance.UserID, &balance.Amount); err != nil {
-			return err
-		}
-		balances = append(balances, balance)
+
+	if len(userIDs) == 0 {
+		return nil
 	}
 
-	// 残高加算
-	ids := make([]interface{}, len(balances))
-	for i, b := range balances {
-		ids[i] = b.UserID
+	// キャンペーンの原資の勘定を相手にした仕訳として残高を加算する
+	postings := make([]*model.Posting, 0, len(userIDs)+1)
+	for _, userID := range userIDs {
+		postings = append(postings, &model.Posting{Account: model.UserAccount(userID), Currency: currency, Amount: amount})
 	}
-	updateQuery := "UPDATE balances SET amount = amount + " + strconv.Itoa(amount) + " WHERE user_id IN (?" + strings.Repeat(",?", len(ids)-1) + ")"
-	if _, err := tx.ExecContext(ctx, updateQuery, ids...); err != nil {
+	total, err := domain.NewMoney(-amount, currency).Mul(int64(len(userIDs)))
+	if err != nil {
 		return err
 	}
-
-	// ログ挿入(bulk insert)
-	valueStrings := []string{}
-	valueArgs := []interface{}{}
-	for _, b := range balances {
-		valueStrings = append(valueStrings, "(?, ?, ?)")
-		valueArgs = append(valueArgs, b.UserID)
-		valueArgs = append(valueArgs, b.Amount)
-		valueArgs = append(valueArgs, b.Amount+uint(amount))
+	postings = append(postings, &model.Posting{Account: model.AccountCampaignFunding, Currency: currency, Amount: total.Amount})
+	entry := model.NewJournalEntry(model.JournalSourceAddToUsers, fmt.Sprintf("currency=%s,limit=%d,offset=%d", currency, limit, offset), postings...)
+	entry.PointExpireTime = pointExpireTime
+	if err := postJournalEntry(ctx, tx, entry); err != nil {
+		return err
+	}
+	event, err := model.NewAddToUsersEvent(currency, amount, userIDs, pointExpireTime, entry.CreateTime)
+	if err != nil {
+		return err
 	}
-	insertQuery := "INSERT INTO balance_logs (user_id, before_amount, after_amount) VALUES %s"
-	insertQuery = fmt.Sprintf(insertQuery, strings.Join(valueStrings, ","))
-	if _, err := tx.ExecContext(ctx, insertQuery, valueArgs...); err != nil {
+	if err := insertOutboxEvent(ctx, tx, event); err != nil {
 		return err
 	}
 
@@ -78,9 +139,122 @@ func (r *BalanceRepository) AddToUsers(ctx context.Context, amount, limit, offse
 	return nil
 }
 
-func findBalance(ctx context.Context, db dbContext, userID uint) (*model.Balance, error) {
-	query := `SELECT user_id, amount FROM balances WHERE user_id = ?`
-	rows, err := db.QueryContext(ctx, query, userID)
+func (r *BalanceRepository) CountTargets(ctx context.Context, currency domain.Currency, target model.BulkCreditTarget) (int, error) {
+	return countTargets(ctx, r.DB, currency, target)
+}
+
+func (r *BalanceRepository) ListTargets(ctx context.Context, currency domain.Currency, target model.BulkCreditTarget, afterUserID uint, limit int) ([]uint, error) {
+	return listTargets(ctx, r.DB, currency, target, afterUserID, limit)
+}
+
+// ウォレットを持っていないユーザも対象にするため、usersを起点にその通貨のウォレットを結合する
+const targetFrom = ` FROM users u LEFT JOIN balances b ON b.user_id = u.id AND b.currency = ?`
+
+func countTargets(ctx context.Context, db dbContext, currency domain.Currency, target model.BulkCreditTarget) (int, error) {
+	if target.Type == model.BulkCreditTargetUserIDs && len(target.UserIDs) == 0 {
+		return 0, nil
+	}
+	condition, args := targetCondition(target, target.UserIDs)
+	rows, err := db.QueryContext(ctx, `SELECT COUNT(u.id)`+targetFrom+` WHERE 1 = 1`+condition, append([]interface{}{currency}, args...)...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return 0, nil
+	}
+	var count int
+	if err := rows.Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func listTargets(ctx context.Context, db dbContext, currency domain.Currency, target model.BulkCreditTarget, afterUserID uint, limit int) ([]uint, error) {
+	for {
+		// ユーザの指定がある場合は、カーソルより後の指定されたユーザのうち、存在するユーザだけを対象にする
+		var candidates []uint
+		if target.Type == model.BulkCreditTargetUserIDs {
+			candidates = target.UserIDsAfter(afterUserID, limit)
+			if len(candidates) == 0 {
+				return nil, nil
+			}
+		}
+		condition, args := targetCondition(target, candidates)
+		query := `SELECT u.id` + targetFrom + ` WHERE u.id > ?` + condition + ` ORDER BY u.id ASC LIMIT ?`
+		args = append(append([]interface{}{currency, afterUserID}, args...), limit)
+		userIDs, err := queryUserIDs(ctx, db, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		if len(userIDs) > 0 || candidates == nil {
+			return userIDs, nil
+		}
+		// 存在しないユーザだけが指定されていた範囲は飛ばす
+		afterUserID = candidates[len(candidates)-1]
+	}
+}
+
+// targetCondition returns the conditions on users u and their wallets b which select the target users.
+func targetCondition(target model.BulkCreditTarget, userIDs []uint) (string, []interface{}) {
+	var condition string
+	var args []interface{}
+	switch target.Type {
+	case model.BulkCreditTargetUserIDs:
+		condition = ` AND u.id IN (?` + strings.Repeat(",?", len(userIDs)-1) + `)`
+		for _, userID := range userIDs {
+			args = append(args, userID)
+		}
+	case model.BulkCreditTargetCreatedBetween:
+		if !target.CreatedFrom.IsZero() {
+			condition += ` AND u.create_time >= ?`
+			args = append(args, target.CreatedFrom)
+		}
+		if !target.CreatedTo.IsZero() {
+			condition += ` AND u.create_time < ?`
+			args = append(args, target.CreatedTo)
+		}
+	case model.BulkCreditTargetBalanceBelow:
+		// ウォレットがなければ残高0として扱う
+		condition = ` AND COALESCE(b.amount, 0) < ?`
+		args = append(args, target.BalanceBelow)
+	}
+	return condition, args
+}
+
+func queryUserIDs(ctx context.Context, db dbContext, query string, args ...interface{}) ([]uint, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []uint
+	for rows.Next() {
+		var userID uint
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+// findBalance returns the wallet of the user in the currency, or an empty one when the user has never held the currency.
+// It returns domain.ErrNoSuchEntity when the user does not exist.
+func findBalance(ctx context.Context, db dbContext, userID uint, currency domain.Currency, withLock bool) (*model.Balance, error) {
+	query := `
+	SELECT u.id, COALESCE(b.amount, 0), COALESCE(b.reserved_amount, 0), COALESCE(b.overdraft_limit, 0)
+	FROM users u LEFT JOIN balances b ON b.user_id = u.id AND b.currency = ?
+	WHERE u.id = ?`
+	if withLock {
+		query = query + ` FOR UPDATE`
+	}
+	rows, err := db.QueryContext(ctx, query, currency, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +263,31 @@ func findBalance(ctx context.Context, db dbContext, userID uint) (*model.Balance
 	if !rows.Next() {
 		return nil, domain.ErrNoSuchEntity
 	}
-	return rowsToBalance(rows)
+	balance := &model.Balance{Currency: currency}
+	if err := rows.Scan(&balance.UserID, &balance.Amount, &balance.ReservedAmount, &balance.OverdraftLimit); err != nil {
+		return nil, err
+	}
+	return balance, nil
+}
+
+// lockBalances locks the wallets of the users in the currency in ascending user_id order to avoid deadlocks.
+func lockBalances(ctx context.Context, db dbContext, currency domain.Currency, userIDs ...uint) (map[uint]*model.Balance, error) {
+	sorted := make([]uint, len(userIDs))
+	copy(sorted, userIDs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	balances := make(map[uint]*model.Balance, len(sorted))
+	for _, userID := range sorted {
+		if _, ok := balances[userID]; ok {
+			continue
+		}
+		balance, err := findBalance(ctx, db, userID, currency, true)
+		if err != nil {
+			return nil, err
+		}
+		balances[userID] = balance
+	}
+	return balances, nil
 }
 
 func countBalanceLog(ctx context.Context, db dbContext, userID uint) (int, error) {
@@ -112,7 +310,7 @@ func countBalanceLog(ctx context.Context, db dbContext, userID uint) (int, error
 
 func rowsToBalance(rows *sql.Rows) (*model.Balance, error) {
 	balance := &model.Balance{}
-	if err := rows.Scan(&balance.UserID, &balance.Amount); err != nil {
+	if err := rows.Scan(&balance.UserID, &balance.Currency, &balance.Amount, &balance.ReservedAmount, &balance.OverdraftLimit); err != nil {
 		return nil, err
 	}
 	return balance, nil
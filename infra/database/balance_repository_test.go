@@ -3,9 +3,12 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/kawabatas/m-bank/domain"
 	"github.com/kawabatas/m-bank/domain/model"
 )
 
@@ -24,8 +27,9 @@ func TestBalanceRepository_Get(t *testing.T) {
 		DB *sql.DB
 	}
 	type args struct {
-		ctx    context.Context
-		userID uint
+		ctx      context.Context
+		userID   uint
+		currency domain.Currency
 	}
 	tests := []struct {
 		name    string
@@ -37,17 +41,28 @@ func TestBalanceRepository_Get(t *testing.T) {
 		{
 			"取得できる",
 			fields{repo.DB},
-			args{ctx, users[0].ID},
+			args{ctx, users[0].ID, domain.JPY},
+			&model.Balance{
+				UserID:   users[0].ID,
+				Currency: domain.JPY,
+				Amount:   initBalanceAmount,
+			},
+			false,
+		},
+		{
+			"持っていない通貨は残高0のウォレットを返す",
+			fields{repo.DB},
+			args{ctx, users[0].ID, domain.USD},
 			&model.Balance{
-				UserID: users[0].ID,
-				Amount: initBalanceAmount,
+				UserID:   users[0].ID,
+				Currency: domain.USD,
 			},
 			false,
 		},
 		{
 			"取得できない",
 			fields{repo.DB},
-			args{ctx, users[0].ID + 100},
+			args{ctx, users[0].ID + 100, domain.JPY},
 			nil,
 			true,
 		},
@@ -57,7 +72,7 @@ func TestBalanceRepository_Get(t *testing.T) {
 			r := &BalanceRepository{
 				DB: tt.fields.DB,
 			}
-			got, err := r.Get(tt.args.ctx, tt.args.userID)
+			got, err := r.Get(tt.args.ctx, tt.args.userID, tt.args.currency)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("BalanceRepository.Get() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -69,6 +84,109 @@ func TestBalanceRepository_Get(t *testing.T) {
 	}
 }
 
+func TestBalanceRepository_List(t *testing.T) {
+	repo := newBalanceRepo(t)
+	users := createSampleUsers(t, repo.DB, 2)
+	ctx := context.Background()
+
+	// 1人目にだけUSDのウォレットを作る
+	entry := model.NewJournalEntry(model.JournalSourcePayment, "usd",
+		&model.Posting{Account: model.UserAccount(users[0].ID), Currency: domain.USD, Amount: 250},
+		&model.Posting{Account: model.AccountExternalSettlement, Currency: domain.USD, Amount: -250},
+	)
+	if _, err := NewLedgerRepository(repo.DB).Post(ctx, entry); err != nil {
+		t.Fatalf("LedgerRepository.Post() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		userID  uint
+		want    []*model.Balance
+		wantErr error
+	}{
+		{
+			"通貨ごとのウォレットを通貨コード順に取得できる",
+			users[0].ID,
+			[]*model.Balance{
+				{UserID: users[0].ID, Currency: domain.JPY, Amount: initBalanceAmount},
+				{UserID: users[0].ID, Currency: domain.USD, Amount: 250},
+			},
+			nil,
+		},
+		{
+			"使っていない通貨のウォレットは含まない",
+			users[1].ID,
+			[]*model.Balance{
+				{UserID: users[1].ID, Currency: domain.JPY, Amount: initBalanceAmount},
+			},
+			nil,
+		},
+		{
+			"存在しないユーザ",
+			users[1].ID + 100,
+			nil,
+			domain.ErrNoSuchEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.List(ctx, tt.userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("BalanceRepository.List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("BalanceRepository.List() mismatch (-want +got): \n %s", diff)
+			}
+		})
+	}
+}
+
+func TestBalanceRepository_SetOverdraftLimit(t *testing.T) {
+	repo := newBalanceRepo(t)
+	users := createSampleUsers(t, repo.DB, 1)
+	ctx := context.Background()
+	paymentRepo := NewPaymentTransactionRepository(repo.DB)
+
+	if _, err := repo.SetOverdraftLimit(ctx, users[0].ID+1, domain.JPY, 500); !errors.Is(err, domain.ErrNoSuchEntity) {
+		t.Fatalf("BalanceRepository.SetOverdraftLimit() error = %v, wantErr %v", err, domain.ErrNoSuchEntity)
+	}
+	got, err := repo.SetOverdraftLimit(ctx, users[0].ID, domain.JPY, 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.AvailableAmount() != initBalanceAmount+500 {
+		t.Errorf("AvailableAmount() = %v, want %v", got.AvailableAmount(), initBalanceAmount+500)
+	}
+
+	// 与信枠の分まで減算でき、残高は負になる
+	if _, err := paymentRepo.Try(ctx, "foo", users[0].ID, domain.JPY, -(initBalanceAmount + 500), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := paymentRepo.Try(ctx, "bar", users[0].ID, domain.JPY, -1, time.Minute); !errors.Is(err, domain.ErrShortBalance) {
+		t.Errorf("PaymentTransactionRepository.Try() error = %v, wantErr %v", err, domain.ErrShortBalance)
+	}
+	if _, err := paymentRepo.Confirm(ctx, "foo"); err != nil {
+		t.Fatal(err)
+	}
+	got, err = repo.Get(ctx, users[0].ID, domain.JPY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&model.Balance{UserID: users[0].ID, Currency: domain.JPY, Amount: -500, OverdraftLimit: 500}, got); diff != "" {
+		t.Errorf("BalanceRepository.Get() mismatch (-want +got):\n%s", diff)
+	}
+
+	// 持っていない通貨でもウォレットを作って与信枠を設定できる
+	got, err = repo.SetOverdraftLimit(ctx, users[0].ID, domain.USD, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&model.Balance{UserID: users[0].ID, Currency: domain.USD, OverdraftLimit: 100}, got); diff != "" {
+		t.Errorf("BalanceRepository.SetOverdraftLimit() mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestBalanceRepository_AddToUsers(t *testing.T) {
 	repo := newBalanceRepo(t)
 	users := createSampleUsers(t, repo.DB, 3)
@@ -79,12 +197,12 @@ func TestBalanceRepository_AddToUsers(t *testing.T) {
 	}
 	type args struct {
 		ctx    context.Context
-		amount int
+		amount int64
 		limit  int
 		offset int
 	}
 	type want struct {
-		Amounts   []int
+		Amounts   []int64
 		LogCounts []int
 	}
 	tests := []struct {
@@ -99,7 +217,7 @@ func TestBalanceRepository_AddToUsers(t *testing.T) {
 			fields{repo.DB},
 			args{ctx, 1, 10, 0},
 			want{
-				[]int{initBalanceAmount + 1, initBalanceAmount + 1, initBalanceAmount + 1},
+				[]int64{initBalanceAmount + 1, initBalanceAmount + 1, initBalanceAmount + 1},
 				[]int{1, 1, 1},
 			},
 			false,
@@ -109,7 +227,7 @@ func TestBalanceRepository_AddToUsers(t *testing.T) {
 			fields{repo.DB},
 			args{ctx, 10, 1, 1},
 			want{
-				[]int{initBalanceAmount + 1, initBalanceAmount + 1 + 10, initBalanceAmount + 1},
+				[]int64{initBalanceAmount + 1, initBalanceAmount + 1 + 10, initBalanceAmount + 1},
 				[]int{1, 2, 1},
 			},
 			false,
@@ -120,17 +238,18 @@ func TestBalanceRepository_AddToUsers(t *testing.T) {
 			r := &BalanceRepository{
 				DB: tt.fields.DB,
 			}
-			if err := r.AddToUsers(tt.args.ctx, tt.args.amount, tt.args.limit, tt.args.offset); (err != nil) != tt.wantErr {
+			if err := r.AddToUsers(tt.args.ctx, domain.JPY, tt.args.amount, time.Time{}, tt.args.limit, tt.args.offset); (err != nil) != tt.wantErr {
 				t.Errorf("BalanceRepository.AddToUsers() error = %v, wantErr %v", err, tt.wantErr)
 			}
 
-			var amounts, logCounts []int
+			var amounts []int64
+			var logCounts []int
 			for _, u := range users {
-				b, err := r.Get(context.Background(), u.ID)
+				b, err := r.Get(context.Background(), u.ID, domain.JPY)
 				if err != nil {
 					t.Errorf("BalanceRepository.AddToUsers() r.Get error %v", err)
 				}
-				amounts = append(amounts, int(b.Amount))
+				amounts = append(amounts, b.Amount)
 				c, err := countBalanceLog(context.Background(), r.DB, u.ID)
 				if err != nil {
 					t.Errorf("BalanceRepository.AddToUsers() countBalanceLog error %v", err)
@@ -145,3 +264,102 @@ func TestBalanceRepository_AddToUsers(t *testing.T) {
 		})
 	}
 }
+
+func TestBalanceRepository_ListTargets(t *testing.T) {
+	repo := newBalanceRepo(t)
+	users := createSampleUsers(t, repo.DB, 4)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	for i, u := range users {
+		if _, err := repo.DB.ExecContext(ctx, `UPDATE users SET create_time = ? WHERE id = ?`, now.Add(time.Duration(i)*time.Hour), u.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := repo.DB.ExecContext(ctx, `UPDATE balances SET amount = 0 WHERE user_id IN (?, ?)`, users[1].ID, users[3].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx         context.Context
+		target      model.BulkCreditTarget
+		afterUserID uint
+		limit       int
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		want      []uint
+		wantCount int
+	}{
+		{
+			"すべてのユーザを取得できる",
+			fields{repo.DB},
+			args{ctx, model.BulkCreditTarget{Type: model.BulkCreditTargetAll}, 0, 10},
+			[]uint{users[0].ID, users[1].ID, users[2].ID, users[3].ID},
+			4,
+		},
+		{
+			"カーソルより後のユーザを件数を指定して取得できる",
+			fields{repo.DB},
+			args{ctx, model.BulkCreditTarget{Type: model.BulkCreditTargetAll}, users[0].ID, 2},
+			[]uint{users[1].ID, users[2].ID},
+			4,
+		},
+		{
+			"指定したユーザのうち存在するユーザを取得できる",
+			fields{repo.DB},
+			args{ctx, model.BulkCreditTarget{Type: model.BulkCreditTargetUserIDs, UserIDs: []uint{users[1].ID, users[3].ID, 100}}, 0, 10},
+			[]uint{users[1].ID, users[3].ID},
+			2,
+		},
+		{
+			"存在しないユーザだけの範囲は飛ばす",
+			fields{repo.DB},
+			args{ctx, model.BulkCreditTarget{Type: model.BulkCreditTargetUserIDs, UserIDs: []uint{users[0].ID, 100, 101, 102}}, users[0].ID, 1},
+			nil,
+			1,
+		},
+		{
+			"作成日時で絞り込める",
+			fields{repo.DB},
+			args{ctx, model.BulkCreditTarget{Type: model.BulkCreditTargetCreatedBetween, CreatedFrom: now.Add(time.Hour), CreatedTo: now.Add(3 * time.Hour)}, 0, 10},
+			[]uint{users[1].ID, users[2].ID},
+			2,
+		},
+		{
+			"残高で絞り込める",
+			fields{repo.DB},
+			args{ctx, model.BulkCreditTarget{Type: model.BulkCreditTargetBalanceBelow, BalanceBelow: 1}, 0, 10},
+			[]uint{users[1].ID, users[3].ID},
+			2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &BalanceRepository{
+				DB: tt.fields.DB,
+			}
+			got, err := r.ListTargets(tt.args.ctx, domain.JPY, tt.args.target, tt.args.afterUserID, tt.args.limit)
+			if err != nil {
+				t.Errorf("BalanceRepository.ListTargets() error = %v", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("BalanceRepository.ListTargets() mismatch (-want +got): \n %s", diff)
+			}
+			count, err := r.CountTargets(tt.args.ctx, domain.JPY, tt.args.target)
+			if err != nil {
+				t.Errorf("BalanceRepository.CountTargets() error = %v", err)
+				return
+			}
+			if count != tt.wantCount {
+				t.Errorf("BalanceRepository.CountTargets() = %v, want %v", count, tt.wantCount)
+			}
+		})
+	}
+}
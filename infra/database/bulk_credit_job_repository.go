@@ -0,0 +1,563 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+// bulk_credit_jobs.last_error, bulk_credit_items.error, outbox_events.last_error の長さ
+const maxErrorLength = 255
+
+type BulkCreditJobRepository struct {
+	DB *sql.DB
+}
+
+func NewBulkCreditJobRepository(db *sql.DB) *BulkCreditJobRepository {
+	return &BulkCreditJobRepository{DB: db}
+}
+
+func (r *BulkCreditJobRepository) Create(ctx context.Context, idempotencyKey string, currency domain.Currency, amount int64, target model.BulkCreditTarget) (*model.BulkCreditJob, error) {
+	total, err := countTargets(ctx, r.DB, currency, target)
+	if err != nil {
+		return nil, err
+	}
+
+	job := model.NewBulkCreditJob(idempotencyKey, currency, amount, target, total)
+	// 合計金額が64bitに収まらないジョブは作成しない
+	if _, err := job.TotalAmount(); err != nil {
+		return nil, err
+	}
+	var targetUserIDs sql.NullString
+	if job.Target.Type == model.BulkCreditTargetUserIDs {
+		targetUserIDs.Valid = true
+		targetUserIDs.String = joinUserIDs(job.Target.UserIDs)
+	}
+	var targetCreatedFrom, targetCreatedTo sql.NullTime
+	if !job.Target.CreatedFrom.IsZero() {
+		targetCreatedFrom.Valid = true
+		targetCreatedFrom.Time = job.Target.CreatedFrom
+	}
+	if !job.Target.CreatedTo.IsZero() {
+		targetCreatedTo.Valid = true
+		targetCreatedTo.Time = job.Target.CreatedTo
+	}
+	res, err := r.DB.ExecContext(ctx,
+		`INSERT INTO bulk_credit_jobs (
+			idempotency_key, request_fingerprint, currency, amount,
+			target_type, target_user_ids, target_created_from, target_created_to, target_balance_below,
+			status, total_count, create_time, update_time
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		job.IdempotencyKey, job.RequestFingerprint, job.Currency, job.Amount,
+		job.Target.Type, targetUserIDs, targetCreatedFrom, targetCreatedTo, job.Target.BalanceBelow,
+		job.Status, job.TotalCount, job.CreateTime, job.UpdateTime,
+	)
+	if err != nil {
+		if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1062 {
+			return nil, domain.ErrDuplicateUUID
+		}
+		return nil, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return findBulkCreditJob(ctx, r.DB, uint64(id), false)
+}
+
+func (r *BulkCreditJobRepository) Get(ctx context.Context, id uint64) (*model.BulkCreditJob, error) {
+	return findBulkCreditJob(ctx, r.DB, id, false)
+}
+
+func (r *BulkCreditJobRepository) GetByIdempotencyKey(ctx context.Context, idempotencyKey string) (*model.BulkCreditJob, error) {
+	rows, err := r.DB.QueryContext(ctx, selectBulkCreditJobQuery+` WHERE idempotency_key = ?`, idempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, domain.ErrNoSuchEntity
+	}
+	return rowsToBulkCreditJob(rows)
+}
+
+func (r *BulkCreditJobRepository) ListUnfinished(ctx context.Context, limit int) ([]*model.BulkCreditJob, error) {
+	rows, err := r.DB.QueryContext(ctx,
+		selectBulkCreditJobQuery+` WHERE status IN (?, ?) OR reversal_status IN (?, ?) ORDER BY id ASC LIMIT ?`,
+		model.BulkCreditStatusPending, model.BulkCreditStatusRunning,
+		model.BulkCreditStatusPending, model.BulkCreditStatusRunning, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*model.BulkCreditJob
+	for rows.Next() {
+		job, err := rowsToBulkCreditJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *BulkCreditJobRepository) ProcessChunk(ctx context.Context, id uint64, chunkSize int) (*model.BulkCreditJob, error) {
+	job, err := r.processChunk(ctx, id, chunkSize)
+	if err != nil {
+		// チャンクはロールバックされ、次回のリトライで同じユーザから再開する。失敗した理由はジョブに残しておく
+		if ctx.Err() == nil {
+			_, _ = r.DB.ExecContext(ctx, `UPDATE bulk_credit_jobs SET last_error = ? WHERE id = ?`, truncateError(err), id)
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *BulkCreditJobRepository) processChunk(ctx context.Context, id uint64, chunkSize int) (*model.BulkCreditJob, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// 同じジョブを複数のワーカーが同時に処理しないようにロックする
+	job, err := findBulkCreditJob(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if job.IsFinished() {
+		return job, nil
+	}
+
+	now := time.Now()
+	if job.IsReversing() {
+		err = reverseNextChunk(ctx, tx, job, chunkSize, now)
+	} else {
+		err = creditNextChunk(ctx, tx, job, chunkSize, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := updateBulkCreditJob(ctx, tx, job); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *BulkCreditJobRepository) RequestReversal(ctx context.Context, id uint64, mode model.BulkCreditReversalMode) (*model.BulkCreditJob, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	job, err := findBulkCreditJob(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := job.RequestReversal(mode, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := updateBulkCreditJob(ctx, tx, job); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *BulkCreditJobRepository) ListFailedItems(ctx context.Context, id uint64, limit int) ([]*model.BulkCreditItem, error) {
+	return queryBulkCreditItems(ctx, r.DB, `WHERE job_id = ? AND status = ? ORDER BY user_id ASC LIMIT ?`, id, model.BulkCreditItemStatusFailed, limit)
+}
+
+func (r *BulkCreditJobRepository) ListSkippedReversalItems(ctx context.Context, id uint64, limit int) ([]*model.BulkCreditItem, error) {
+	return queryBulkCreditItems(ctx, r.DB, `WHERE job_id = ? AND reversal_status = ? ORDER BY user_id ASC LIMIT ?`, id, model.BulkCreditItemReversalSkipped, limit)
+}
+
+func queryBulkCreditItems(ctx context.Context, db dbContext, condition string, args ...interface{}) ([]*model.BulkCreditItem, error) {
+	query := `
+	SELECT job_id, user_id, status, error, create_time, reversal_status, reversed_amount
+	FROM bulk_credit_items ` + condition
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*model.BulkCreditItem
+	for rows.Next() {
+		item := &model.BulkCreditItem{}
+		if err := rows.Scan(&item.JobID, &item.UserID, &item.Status, &item.Error, &item.CreateTime, &item.ReversalStatus, &item.ReversedAmount); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// creditNextChunk credits the next chunk of the target users, completing the job when no users are left.
+func creditNextChunk(ctx context.Context, db dbContext, job *model.BulkCreditJob, chunkSize int, now time.Time) error {
+	userIDs, err := listTargets(ctx, db, job.Currency, job.Target, job.CursorUserID, chunkSize)
+	if err != nil {
+		return err
+	}
+	if len(userIDs) == 0 {
+		job.Complete(now)
+		event, err := model.NewBulkCreditCompletedEvent(job, now)
+		if err != nil {
+			return err
+		}
+		return insertOutboxEvent(ctx, db, event)
+	}
+	credited, failed, err := creditBulkCreditChunk(ctx, db, job, userIDs)
+	if err != nil {
+		return err
+	}
+	job.Advance(userIDs[len(userIDs)-1], credited, failed, now)
+	return nil
+}
+
+// reverseNextChunk reverses the next chunk of the credited users, completing the reversal when no users are left.
+func reverseNextChunk(ctx context.Context, db dbContext, job *model.BulkCreditJob, chunkSize int, now time.Time) error {
+	// 取り消し済みのマーカーがあるユーザは二重に減算しない
+	userIDs, err := queryUserIDs(ctx, db,
+		`SELECT user_id FROM bulk_credit_items WHERE job_id = ? AND status = ? AND reversal_status = '' AND user_id > ? ORDER BY user_id ASC LIMIT ?`,
+		job.ID, model.BulkCreditItemStatusCredited, job.ReversalCursorUserID, chunkSize,
+	)
+	if err != nil {
+		return err
+	}
+	if len(userIDs) == 0 {
+		job.CompleteReversal(now)
+		event, err := model.NewBulkCreditReversedEvent(job, now)
+		if err != nil {
+			return err
+		}
+		return insertOutboxEvent(ctx, db, event)
+	}
+	reversed, skipped, amount, err := reverseBulkCreditUsers(ctx, db, job, userIDs)
+	if err != nil {
+		return err
+	}
+	job.AdvanceReversal(userIDs[len(userIDs)-1], reversed, skipped, amount, now)
+	return nil
+}
+
+// reverseBulkCreditUsers debits what the job credited to the users according to the reversal mode in one journal entry,
+// and records the result on their items.
+func reverseBulkCreditUsers(ctx context.Context, db dbContext, job *model.BulkCreditJob, userIDs []uint) (reversed, skipped int, amount int64, err error) {
+	balances, err := lockBalances(ctx, db, job.Currency, userIDs...)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	type result struct {
+		status model.BulkCreditItemReversalStatus
+		amount int64
+	}
+	// 同じ結果のユーザはまとめて更新する
+	byResult := map[result][]interface{}{}
+	postings := make([]*model.Posting, 0, len(userIDs)+1)
+	for _, userID := range userIDs {
+		// 取り消しで与信枠は使わない
+		status, debit := job.ReversalMode.Reverse(job.Amount, balances[userID].OwnAvailableAmount())
+		byResult[result{status, debit}] = append(byResult[result{status, debit}], userID)
+		if status == model.BulkCreditItemReversalSkipped {
+			skipped++
+			continue
+		}
+		reversed++
+		if debit > 0 {
+			postings = append(postings, &model.Posting{Account: model.UserAccount(userID), Currency: job.Currency, Amount: -debit})
+			if amount, err = domain.SumAmounts(amount, debit); err != nil {
+				return 0, 0, 0, err
+			}
+		}
+	}
+	for res, ids := range byResult {
+		updateQuery := "UPDATE bulk_credit_items SET reversal_status = ?, reversed_amount = ? WHERE job_id = ? AND user_id IN (?" + strings.Repeat(",?", len(ids)-1) + ")"
+		if _, err := db.ExecContext(ctx, updateQuery, append([]interface{}{res.status, res.amount, job.ID}, ids...)...); err != nil {
+			return 0, 0, 0, err
+		}
+	}
+	if len(postings) == 0 {
+		return reversed, skipped, 0, nil
+	}
+
+	// 加算したときと逆に、キャンペーンの原資の勘定へ戻す仕訳として残高を減算する
+	postings = append(postings, &model.Posting{Account: model.AccountCampaignFunding, Currency: job.Currency, Amount: amount})
+	entry := model.NewJournalEntry(model.JournalSourceBulkCreditReversal, strconv.FormatUint(job.ID, 10), postings...)
+	entry.AllowNegativeBalance = job.ReversalMode == model.BulkCreditReversalAllowNegative
+	if err := postJournalEntry(ctx, db, entry); err != nil {
+		return 0, 0, 0, err
+	}
+	return reversed, skipped, amount, nil
+}
+
+// creditBulkCreditChunk credits the users who have no item of the job yet. It first credits them all
+// in one journal entry, and if that fails, credits them one by one so that only the failing users are skipped.
+func creditBulkCreditChunk(ctx context.Context, db dbContext, job *model.BulkCreditJob, userIDs []uint) (credited, failed int, err error) {
+	// 処理済みのマーカーがあるユーザは二重に加算しない
+	pending, err := unprocessedBulkCreditUsers(ctx, db, job.ID, userIDs)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(pending) == 0 {
+		return 0, 0, nil
+	}
+
+	if _, err := db.ExecContext(ctx, `SAVEPOINT bulk_credit_chunk`); err != nil {
+		return 0, 0, err
+	}
+	chunkErr := creditBulkCreditUsers(ctx, db, job, pending...)
+	if chunkErr == nil {
+		return len(pending), 0, nil
+	}
+	// ロック待ちやデッドロックなどのエラーはチャンクごと失敗させ、後でリトライする
+	if !isBulkCreditItemError(chunkErr) {
+		return 0, 0, chunkErr
+	}
+	if _, err := db.ExecContext(ctx, `ROLLBACK TO SAVEPOINT bulk_credit_chunk`); err != nil {
+		return 0, 0, err
+	}
+
+	for _, userID := range pending {
+		if _, err := db.ExecContext(ctx, `SAVEPOINT bulk_credit_user`); err != nil {
+			return 0, 0, err
+		}
+		creditErr := creditBulkCreditUsers(ctx, db, job, userID)
+		if creditErr == nil {
+			credited++
+			continue
+		}
+		if !isBulkCreditItemError(creditErr) {
+			return 0, 0, creditErr
+		}
+		if _, err := db.ExecContext(ctx, `ROLLBACK TO SAVEPOINT bulk_credit_user`); err != nil {
+			return 0, 0, err
+		}
+		if _, err := db.ExecContext(ctx,
+			"INSERT INTO bulk_credit_items (job_id, user_id, status, error) VALUES (?, ?, ?, ?)",
+			job.ID, userID, model.BulkCreditItemStatusFailed, truncateError(creditErr),
+		); err != nil {
+			return 0, 0, err
+		}
+		failed++
+	}
+	return credited, failed, nil
+}
+
+// isBulkCreditItemError reports whether the credit failed because of the user, such as an overflow of the balance,
+// so that the user is recorded as failed. The other errors, such as a lock wait timeout, a deadlock or a cancelled
+// context, are returned to retry the chunk.
+func isBulkCreditItemError(err error) bool {
+	return errors.Is(err, domain.ErrNoSuchEntity) || errors.Is(err, domain.ErrAmountOverflow) ||
+		errors.Is(err, domain.ErrShortBalance) || errors.Is(err, domain.ErrInvalidParam)
+}
+
+// creditBulkCreditUsers records the items of the users and credits them in one journal entry.
+func creditBulkCreditUsers(ctx context.Context, db dbContext, job *model.BulkCreditJob, userIDs ...uint) error {
+	valueStrings := make([]string, 0, len(userIDs))
+	valueArgs := make([]interface{}, 0, len(userIDs)*3)
+	postings := make([]*model.Posting, 0, len(userIDs)+1)
+	for _, userID := range userIDs {
+		valueStrings = append(valueStrings, "(?, ?, ?)")
+		valueArgs = append(valueArgs, job.ID, userID, model.BulkCreditItemStatusCredited)
+		postings = append(postings, &model.Posting{Account: model.UserAccount(userID), Currency: job.Currency, Amount: job.Amount})
+	}
+	insertQuery := "INSERT INTO bulk_credit_items (job_id, user_id, status) VALUES " + strings.Join(valueStrings, ",")
+	if _, err := db.ExecContext(ctx, insertQuery, valueArgs...); err != nil {
+		return err
+	}
+
+	// キャンペーンの原資の勘定を相手にした仕訳として残高を加算する
+	total, err := domain.NewMoney(-job.Amount, job.Currency).Mul(int64(len(userIDs)))
+	if err != nil {
+		return err
+	}
+	postings = append(postings, &model.Posting{Account: model.AccountCampaignFunding, Currency: job.Currency, Amount: total.Amount})
+	entry := model.NewJournalEntry(model.JournalSourceBulkCredit, strconv.FormatUint(job.ID, 10), postings...)
+	return postJournalEntry(ctx, db, entry)
+}
+
+func unprocessedBulkCreditUsers(ctx context.Context, db dbContext, jobID uint64, userIDs []uint) ([]uint, error) {
+	args := make([]interface{}, 0, len(userIDs)+1)
+	args = append(args, jobID)
+	for _, userID := range userIDs {
+		args = append(args, userID)
+	}
+	query := "SELECT user_id FROM bulk_credit_items WHERE job_id = ? AND user_id IN (?" + strings.Repeat(",?", len(userIDs)-1) + ")"
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	processed := map[uint]bool{}
+	for rows.Next() {
+		var userID uint
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		processed[userID] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	pending := make([]uint, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if !processed[userID] {
+			pending = append(pending, userID)
+		}
+	}
+	return pending, nil
+}
+
+const selectBulkCreditJobQuery = `
+	SELECT
+		id, idempotency_key, request_fingerprint, currency, amount,
+		target_type, target_user_ids, target_created_from, target_created_to, target_balance_below,
+		status, cursor_user_id, total_count, credited_count, failed_count,
+		last_error, create_time, update_time, finish_time,
+		reversal_status, reversal_mode, reversal_cursor_user_id, reversed_count, reversal_skipped_count, reversed_amount,
+		reversal_request_time, reversal_finish_time
+	FROM bulk_credit_jobs`
+
+// updateBulkCreditJob saves the progress of the job.
+func updateBulkCreditJob(ctx context.Context, db dbContext, job *model.BulkCreditJob) error {
+	_, err := db.ExecContext(ctx,
+		`UPDATE bulk_credit_jobs SET
+			status = ?, cursor_user_id = ?, credited_count = ?, failed_count = ?, last_error = ?, update_time = ?, finish_time = ?,
+			reversal_status = ?, reversal_mode = ?, reversal_cursor_user_id = ?, reversed_count = ?, reversal_skipped_count = ?, reversed_amount = ?,
+			reversal_request_time = ?, reversal_finish_time = ?
+		WHERE id = ?`,
+		job.Status, job.CursorUserID, job.CreditedCount, job.FailedCount, job.LastError, job.UpdateTime, nullTime(job.FinishTime),
+		job.ReversalStatus, job.ReversalMode, job.ReversalCursorUserID, job.ReversedCount, job.ReversalSkippedCount, job.ReversedAmount,
+		nullTime(job.ReversalRequestTime), nullTime(job.ReversalFinishTime),
+		job.ID,
+	)
+	return err
+}
+
+func findBulkCreditJob(ctx context.Context, db dbContext, id uint64, withLock bool) (*model.BulkCreditJob, error) {
+	query := selectBulkCreditJobQuery + ` WHERE id = ?`
+	if withLock {
+		query = query + ` FOR UPDATE`
+	}
+	rows, err := db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, domain.ErrNoSuchEntity
+	}
+	return rowsToBulkCreditJob(rows)
+}
+
+func rowsToBulkCreditJob(rows *sql.Rows) (*model.BulkCreditJob, error) {
+	job := &model.BulkCreditJob{}
+	var targetUserIDs sql.NullString
+	var targetCreatedFrom, targetCreatedTo, finishTime, reversalRequestTime, reversalFinishTime sql.NullTime
+	if err := rows.Scan(
+		&job.ID, &job.IdempotencyKey, &job.RequestFingerprint, &job.Currency, &job.Amount,
+		&job.Target.Type, &targetUserIDs, &targetCreatedFrom, &targetCreatedTo, &job.Target.BalanceBelow,
+		&job.Status, &job.CursorUserID, &job.TotalCount, &job.CreditedCount, &job.FailedCount,
+		&job.LastError, &job.CreateTime, &job.UpdateTime, &finishTime,
+		&job.ReversalStatus, &job.ReversalMode, &job.ReversalCursorUserID, &job.ReversedCount, &job.ReversalSkippedCount, &job.ReversedAmount,
+		&reversalRequestTime, &reversalFinishTime,
+	); err != nil {
+		return nil, err
+	}
+	if targetUserIDs.Valid {
+		userIDs, err := splitUserIDs(targetUserIDs.String)
+		if err != nil {
+			return nil, err
+		}
+		job.Target.UserIDs = userIDs
+	}
+	if targetCreatedFrom.Valid {
+		job.Target.CreatedFrom = targetCreatedFrom.Time
+	}
+	if targetCreatedTo.Valid {
+		job.Target.CreatedTo = targetCreatedTo.Time
+	}
+	if finishTime.Valid {
+		job.FinishTime = finishTime.Time
+	}
+	if reversalRequestTime.Valid {
+		job.ReversalRequestTime = reversalRequestTime.Time
+	}
+	if reversalFinishTime.Valid {
+		job.ReversalFinishTime = reversalFinishTime.Time
+	}
+	return job, nil
+}
+
+// nullTime stores the zero time as NULL.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// bulk_credit_jobs.target_user_ids は昇順のuser_idのカンマ区切り
+func joinUserIDs(userIDs []uint) string {
+	ids := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		ids = append(ids, strconv.FormatUint(uint64(userID), 10))
+	}
+	return strings.Join(ids, ",")
+}
+
+func splitUserIDs(s string) ([]uint, error) {
+	if s == "" {
+		return nil, nil
+	}
+	ids := strings.Split(s, ",")
+	userIDs := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		userID, err := strconv.ParseUint(id, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, uint(userID))
+	}
+	return userIDs, nil
+}
+
+func truncateError(err error) string {
+	return truncateMessage(err.Error())
+}
+
+func truncateMessage(message string) string {
+	if len(message) > maxErrorLength {
+		return message[:maxErrorLength]
+	}
+	return message
+}
@@ -0,0 +1,352 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+func newBulkCreditJobRepo(t *testing.T) *BulkCreditJobRepository {
+	t.Helper()
+	db := newTestConnection(t)
+	return NewBulkCreditJobRepository(db)
+}
+
+func TestBulkCreditJobRepository_Create(t *testing.T) {
+	repo := newBulkCreditJobRepo(t)
+	users := createSampleUsers(t, repo.DB, 3)
+	ctx := context.Background()
+	all := model.BulkCreditTarget{Type: model.BulkCreditTargetAll}
+	// 存在しないユーザは対象数に含まない
+	userIDs := model.BulkCreditTarget{Type: model.BulkCreditTargetUserIDs, UserIDs: []uint{users[0].ID, users[2].ID, 100}}
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx            context.Context
+		idempotencyKey string
+		amount         int64
+		target         model.BulkCreditTarget
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *model.BulkCreditJob
+		wantErr error
+	}{
+		{
+			"作成できる",
+			fields{repo.DB},
+			args{ctx, "foo", 100, all},
+			&model.BulkCreditJob{
+				IdempotencyKey:     "foo",
+				RequestFingerprint: model.BulkCreditRequestFingerprint(100, all),
+				Currency:           domain.JPY,
+				Amount:             100,
+				Target:             all,
+				Status:             model.BulkCreditStatusPending,
+				TotalCount:         3,
+			},
+			nil,
+		},
+		{
+			"対象のユーザを指定して作成できる",
+			fields{repo.DB},
+			args{ctx, "bar", 100, userIDs},
+			&model.BulkCreditJob{
+				IdempotencyKey:     "bar",
+				RequestFingerprint: model.BulkCreditRequestFingerprint(100, userIDs),
+				Currency:           domain.JPY,
+				Amount:             100,
+				Target:             userIDs,
+				Status:             model.BulkCreditStatusPending,
+				TotalCount:         2,
+			},
+			nil,
+		},
+		{
+			"同じ冪等性キーでは作成できない",
+			fields{repo.DB},
+			args{ctx, "foo", 100, all},
+			nil,
+			domain.ErrDuplicateUUID,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &BulkCreditJobRepository{
+				DB: tt.fields.DB,
+			}
+			got, err := r.Create(tt.args.ctx, tt.args.idempotencyKey, domain.JPY, tt.args.amount, tt.args.target)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("BulkCreditJobRepository.Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			opt := cmpopts.IgnoreFields(model.BulkCreditJob{}, "ID", "CreateTime", "UpdateTime")
+			if diff := cmp.Diff(tt.want, got, opt); diff != "" {
+				t.Errorf("BulkCreditJobRepository.Create() mismatch (-want +got): \n %s", diff)
+			}
+		})
+	}
+}
+
+func TestBulkCreditJobRepository_ProcessChunk(t *testing.T) {
+	repo := newBulkCreditJobRepo(t)
+	users := createSampleUsers(t, repo.DB, 3)
+	ctx := context.Background()
+
+	// 加算すると上限を超えるユーザは失敗として記録され、他のユーザの加算は続ける
+	if _, err := NewLedgerRepository(repo.DB).Post(ctx, model.NewJournalEntry(model.JournalSourceOpeningBalance, "max",
+		&model.Posting{Account: model.UserAccount(users[1].ID), Currency: domain.JPY, Amount: math.MaxInt64 - initBalanceAmount},
+		&model.Posting{Account: model.AccountOpeningBalance, Currency: domain.JPY, Amount: -(math.MaxInt64 - initBalanceAmount)},
+	)); err != nil {
+		t.Fatal(err)
+	}
+	job, err := repo.Create(ctx, "foo", domain.JPY, 100, model.BulkCreditTarget{Type: model.BulkCreditTargetAll})
+	if err != nil {
+		t.Fatalf("BulkCreditJobRepository.Create() error = %v", err)
+	}
+
+	type want struct {
+		Status        model.BulkCreditStatus
+		CursorUserID  uint
+		CreditedCount int
+		FailedCount   int
+	}
+	tests := []struct {
+		name string
+		want want
+	}{
+		{
+			"1チャンク目を処理できる",
+			want{model.BulkCreditStatusRunning, users[1].ID, 1, 1},
+		},
+		{
+			"2チャンク目を処理できる",
+			want{model.BulkCreditStatusRunning, users[2].ID, 2, 1},
+		},
+		{
+			"対象のユーザがいなくなると完了する",
+			want{model.BulkCreditStatusCompleted, users[2].ID, 2, 1},
+		},
+		{
+			"完了したジョブは処理しない",
+			want{model.BulkCreditStatusCompleted, users[2].ID, 2, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.ProcessChunk(ctx, job.ID, 2)
+			if err != nil {
+				t.Errorf("BulkCreditJobRepository.ProcessChunk() error = %v", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, want{got.Status, got.CursorUserID, got.CreditedCount, got.FailedCount}); diff != "" {
+				t.Errorf("BulkCreditJobRepository.ProcessChunk() mismatch (-want +got): \n %s", diff)
+			}
+		})
+	}
+
+	// 各ユーザは1回だけ加算される
+	for i, want := range []int64{initBalanceAmount + 100, math.MaxInt64, initBalanceAmount + 100} {
+		b, err := findBalance(ctx, repo.DB, users[i].ID, domain.JPY, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.Amount != want {
+			t.Errorf("balance of user %d = %d, want %d", users[i].ID, b.Amount, want)
+		}
+	}
+	failures, err := repo.ListFailedItems(ctx, job.ID, 10)
+	if err != nil {
+		t.Fatalf("BulkCreditJobRepository.ListFailedItems() error = %v", err)
+	}
+	if len(failures) != 1 || failures[0].UserID != users[1].ID || failures[0].Error == "" {
+		t.Errorf("BulkCreditJobRepository.ListFailedItems() = %v, want the failure of user %d", failures, users[1].ID)
+	}
+	if err := NewLedgerRepository(repo.DB).CheckInvariants(ctx); err != nil {
+		t.Errorf("LedgerRepository.CheckInvariants() error = %v", err)
+	}
+}
+
+func TestBulkCreditJobRepository_ProcessChunk_retry(t *testing.T) {
+	repo := newBulkCreditJobRepo(t)
+	users := createSampleUsers(t, repo.DB, 2)
+	ctx := context.Background()
+
+	job, err := repo.Create(ctx, "foo", domain.JPY, 100, model.BulkCreditTarget{Type: model.BulkCreditTargetAll})
+	if err != nil {
+		t.Fatalf("BulkCreditJobRepository.Create() error = %v", err)
+	}
+	if _, err := repo.ProcessChunk(ctx, job.ID, 1); err != nil {
+		t.Fatalf("BulkCreditJobRepository.ProcessChunk() error = %v", err)
+	}
+	// カーソルが失われても、処理済みのマーカーがあるユーザには二重に加算しない
+	if _, err := repo.DB.ExecContext(ctx, `UPDATE bulk_credit_jobs SET cursor_user_id = 0 WHERE id = ?`, job.ID); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		got, err := repo.ProcessChunk(ctx, job.ID, 1)
+		if err != nil {
+			t.Fatalf("BulkCreditJobRepository.ProcessChunk() error = %v", err)
+		}
+		if got.IsFinished() {
+			break
+		}
+	}
+	for _, u := range users {
+		b, err := findBalance(ctx, repo.DB, u.ID, domain.JPY, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.Amount != initBalanceAmount+100 {
+			t.Errorf("balance of user %d = %d, want %d", u.ID, b.Amount, initBalanceAmount+100)
+		}
+	}
+}
+
+func Test_isBulkCreditItemError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"残高の上限を超える", fmt.Errorf("add: %w", domain.ErrAmountOverflow), true},
+		{"ユーザがいない", domain.ErrNoSuchEntity, true},
+		{"ロック待ちのタイムアウト", &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"}, false},
+		{"デッドロック", &mysql.MySQLError{Number: 1213, Message: "Deadlock found"}, false},
+		{"キャンセル", context.Canceled, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isBulkCreditItemError(tt.err); got != tt.want {
+				t.Errorf("isBulkCreditItemError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBulkCreditJobRepository_Reverse(t *testing.T) {
+	type want struct {
+		Balances             []int64
+		ReversedCount        int
+		ReversalSkippedCount int
+		ReversedAmount       int64
+	}
+	tests := []struct {
+		name string
+		mode model.BulkCreditReversalMode
+		want want
+	}{
+		{
+			"clampでは利用可能額までだけ減算する",
+			model.BulkCreditReversalClamp,
+			want{[]int64{initBalanceAmount, 0}, 2, 0, 150},
+		},
+		{
+			"allow_negativeでは残高が負になっても全額を減算する",
+			model.BulkCreditReversalAllowNegative,
+			want{[]int64{initBalanceAmount, -50}, 2, 0, 200},
+		},
+		{
+			"skipでは利用可能額が足りないユーザを減算しない",
+			model.BulkCreditReversalSkip,
+			want{[]int64{initBalanceAmount, 50}, 1, 1, 100},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newBulkCreditJobRepo(t)
+			users := createSampleUsers(t, repo.DB, 2)
+			ctx := context.Background()
+
+			job, err := repo.Create(ctx, "foo", domain.JPY, 100, model.BulkCreditTarget{Type: model.BulkCreditTargetAll})
+			if err != nil {
+				t.Fatalf("BulkCreditJobRepository.Create() error = %v", err)
+			}
+			// 完了していないジョブは取り消せない
+			if _, err := repo.RequestReversal(ctx, job.ID, tt.mode); !errors.Is(err, domain.ErrIllegalTransition) {
+				t.Errorf("BulkCreditJobRepository.RequestReversal() error = %v, wantErr %v", err, domain.ErrIllegalTransition)
+			}
+			processBulkCreditJob(t, repo, job.ID)
+			// 加算された後に2人目のユーザが1050円使った
+			if _, err := NewLedgerRepository(repo.DB).Post(ctx, model.NewJournalEntry(model.JournalSourcePayment, "spent",
+				&model.Posting{Account: model.UserAccount(users[1].ID), Currency: domain.JPY, Amount: -1050},
+				&model.Posting{Account: model.AccountExternalSettlement, Currency: domain.JPY, Amount: 1050},
+			)); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := repo.RequestReversal(ctx, job.ID, tt.mode); err != nil {
+				t.Fatalf("BulkCreditJobRepository.RequestReversal() error = %v", err)
+			}
+			got := processBulkCreditJob(t, repo, job.ID)
+			// 取り消した後は同じmodeでしか取り消しを要求できない
+			if _, err := repo.RequestReversal(ctx, job.ID, tt.mode); err != nil {
+				t.Errorf("BulkCreditJobRepository.RequestReversal() error = %v", err)
+			}
+			if _, err := repo.RequestReversal(ctx, job.ID, "other"); !errors.Is(err, domain.ErrIllegalTransition) {
+				t.Errorf("BulkCreditJobRepository.RequestReversal() error = %v, wantErr %v", err, domain.ErrIllegalTransition)
+			}
+
+			gotWant := want{nil, got.ReversedCount, got.ReversalSkippedCount, got.ReversedAmount}
+			for _, u := range users {
+				b, err := findBalance(ctx, repo.DB, u.ID, domain.JPY, false)
+				if err != nil {
+					t.Fatal(err)
+				}
+				gotWant.Balances = append(gotWant.Balances, b.Amount)
+			}
+			if diff := cmp.Diff(tt.want, gotWant); diff != "" {
+				t.Errorf("BulkCreditJobRepository.ProcessChunk() mismatch (-want +got): \n %s", diff)
+			}
+			if got.ReversalStatus != model.BulkCreditStatusCompleted {
+				t.Errorf("BulkCreditJobRepository.ProcessChunk() ReversalStatus = %v, want %v", got.ReversalStatus, model.BulkCreditStatusCompleted)
+			}
+			skips, err := repo.ListSkippedReversalItems(ctx, job.ID, 10)
+			if err != nil {
+				t.Fatalf("BulkCreditJobRepository.ListSkippedReversalItems() error = %v", err)
+			}
+			if len(skips) != tt.want.ReversalSkippedCount {
+				t.Errorf("BulkCreditJobRepository.ListSkippedReversalItems() = %v, want %d items", skips, tt.want.ReversalSkippedCount)
+			}
+			// 取り消しの残高の履歴は元のジョブに紐付く
+			entries, err := NewLedgerRepository(repo.DB).ListBySource(ctx, model.JournalSourceBulkCreditReversal, strconv.FormatUint(job.ID, 10))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 {
+				t.Errorf("LedgerRepository.ListBySource() = %d entries, want 1", len(entries))
+			}
+			if err := NewLedgerRepository(repo.DB).CheckInvariants(ctx); err != nil {
+				t.Errorf("LedgerRepository.CheckInvariants() error = %v", err)
+			}
+		})
+	}
+}
+
+// processBulkCreditJob processes the job chunk by chunk until it is finished.
+func processBulkCreditJob(t *testing.T, repo *BulkCreditJobRepository, id uint64) *model.BulkCreditJob {
+	t.Helper()
+	for {
+		job, err := repo.ProcessChunk(context.Background(), id, 1)
+		if err != nil {
+			t.Fatalf("BulkCreditJobRepository.ProcessChunk() error = %v", err)
+		}
+		if job.IsFinished() {
+			return job
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type ExchangeRateRepository struct {
+	DB *sql.DB
+}
+
+func NewExchangeRateRepository(db *sql.DB) *ExchangeRateRepository {
+	return &ExchangeRateRepository{DB: db}
+}
+
+func (r *ExchangeRateRepository) Create(ctx context.Context, rates []*model.ExchangeRate) ([]*model.ExchangeRate, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	now := time.Now()
+	for _, rate := range rates {
+		if err := rate.Validate(); err != nil {
+			return nil, err
+		}
+		rate.CreateTime = now
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO exchange_rates (from_currency, to_currency, rate, rounding, valid_from, valid_to, create_time) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			rate.FromCurrency, rate.ToCurrency, rate.Rate, rate.Rounding, rate.ValidFrom, rate.ValidTo, rate.CreateTime,
+		)
+		if err != nil {
+			return nil, err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+		rate.ID = uint64(id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+func (r *ExchangeRateRepository) Get(ctx context.Context, id uint64) (*model.ExchangeRate, error) {
+	return findExchangeRate(ctx, r.DB, id)
+}
+
+func (r *ExchangeRateRepository) ListValid(ctx context.Context, fromCurrency, toCurrency domain.Currency, now time.Time) ([]*model.ExchangeRate, error) {
+	query := `
+	SELECT id, from_currency, to_currency, rate, rounding, valid_from, valid_to, create_time
+	FROM exchange_rates
+	WHERE from_currency = ? AND to_currency = ? AND valid_to > ? AND valid_from <= ?
+	ORDER BY id DESC`
+	rows, err := r.DB.QueryContext(ctx, query, fromCurrency, toCurrency, now, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rates []*model.ExchangeRate
+	for rows.Next() {
+		rate, err := rowsToExchangeRate(rows)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+func findExchangeRate(ctx context.Context, db dbContext, id uint64) (*model.ExchangeRate, error) {
+	query := `
+	SELECT id, from_currency, to_currency, rate, rounding, valid_from, valid_to, create_time
+	FROM exchange_rates WHERE id = ?`
+	rows, err := db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, domain.ErrNoSuchEntity
+	}
+	return rowsToExchangeRate(rows)
+}
+
+func rowsToExchangeRate(rows *sql.Rows) (*model.ExchangeRate, error) {
+	rate := &model.ExchangeRate{}
+	if err := rows.Scan(&rate.ID, &rate.FromCurrency, &rate.ToCurrency, &rate.Rate, &rate.Rounding, &rate.ValidFrom, &rate.ValidTo, &rate.CreateTime); err != nil {
+		return nil, err
+	}
+	rate.Rate = trimDecimal(rate.Rate)
+	return rate, nil
+}
+
+// trimDecimal removes the trailing zeros which DECIMAL columns are padded with, e.g. "0.006700000000" to "0.0067".
+func trimDecimal(s string) string {
+	if !strings.Contains(s, ".") {
+		return s
+	}
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
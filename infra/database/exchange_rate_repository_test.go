@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+func newExchangeRateRepo(t *testing.T) *ExchangeRateRepository {
+	t.Helper()
+	db := newTestConnection(t)
+	return NewExchangeRateRepository(db)
+}
+
+func createSampleExchangeRates(t *testing.T, repo *ExchangeRateRepository, rates ...*model.ExchangeRate) []*model.ExchangeRate {
+	t.Helper()
+	created, err := repo.Create(context.Background(), rates)
+	if err != nil {
+		t.Fatalf("create exchange_rates error: %v", err)
+	}
+	return created
+}
+
+func TestExchangeRateRepository_ListValid(t *testing.T) {
+	repo := newExchangeRateRepo(t)
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	rates := createSampleExchangeRates(t, repo,
+		&model.ExchangeRate{FromCurrency: domain.JPY, ToCurrency: domain.USD, Rate: "0.0067", Rounding: domain.RoundDown, ValidFrom: now.Add(-2 * time.Hour), ValidTo: now.Add(-time.Hour)},
+		&model.ExchangeRate{FromCurrency: domain.JPY, ToCurrency: domain.USD, Rate: "0.0068", Rounding: domain.RoundDown, ValidFrom: now.Add(-time.Hour), ValidTo: now.Add(time.Hour)},
+		&model.ExchangeRate{FromCurrency: domain.JPY, ToCurrency: domain.USD, Rate: "0.0069", Rounding: domain.RoundHalfEven, ValidFrom: now.Add(-time.Minute), ValidTo: now.Add(time.Hour)},
+		&model.ExchangeRate{FromCurrency: domain.JPY, ToCurrency: domain.USD, Rate: "0.007", Rounding: domain.RoundDown, ValidFrom: now.Add(time.Hour), ValidTo: now.Add(2 * time.Hour)},
+		&model.ExchangeRate{FromCurrency: domain.USD, ToCurrency: domain.JPY, Rate: "149.5", Rounding: domain.RoundDown, ValidFrom: now.Add(-time.Hour), ValidTo: now.Add(time.Hour)},
+	)
+
+	got, err := repo.ListValid(ctx, domain.JPY, domain.USD, now)
+	if err != nil {
+		t.Fatalf("ExchangeRateRepository.ListValid() error = %v", err)
+	}
+	// 有効期間内のレートだけを新しい順に返し、DECIMALの末尾の0は取り除く
+	want := []*model.ExchangeRate{rates[2], rates[1]}
+	if diff := cmp.Diff(want, got, cmpopts.EquateApproxTime(time.Second)); diff != "" {
+		t.Errorf("ExchangeRateRepository.ListValid() mismatch (-want +got): \n %s", diff)
+	}
+}
+
+func TestExchangeRateRepository_Create(t *testing.T) {
+	repo := newExchangeRateRepo(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	valid := &model.ExchangeRate{FromCurrency: domain.JPY, ToCurrency: domain.USD, Rate: "0.0067", Rounding: domain.RoundDown, ValidFrom: now, ValidTo: now.Add(time.Hour)}
+	invalid := &model.ExchangeRate{FromCurrency: domain.JPY, ToCurrency: domain.JPY, Rate: "1", Rounding: domain.RoundDown, ValidFrom: now, ValidTo: now.Add(time.Hour)}
+	// 1つでも不正なレートがあれば何も登録しない
+	if _, err := repo.Create(ctx, []*model.ExchangeRate{valid, invalid}); !errors.Is(err, domain.ErrInvalidParam) {
+		t.Fatalf("ExchangeRateRepository.Create() error = %v, wantErr %v", err, domain.ErrInvalidParam)
+	}
+	got, err := repo.ListValid(ctx, domain.JPY, domain.USD, now)
+	if err != nil {
+		t.Fatalf("ExchangeRateRepository.ListValid() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("ExchangeRateRepository.Create() created %d rates, want 0", len(got))
+	}
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type ExchangeRepository struct {
+	DB *sql.DB
+}
+
+func NewExchangeRepository(db *sql.DB) *ExchangeRepository {
+	return &ExchangeRepository{DB: db}
+}
+
+func (r *ExchangeRepository) Get(ctx context.Context, uuid string) (*model.Exchange, error) {
+	return findExchange(ctx, r.DB, uuid)
+}
+
+func (r *ExchangeRepository) Exchange(ctx context.Context, uuid string, userID uint, rateID uint64, amount int64, now time.Time) (*model.Exchange, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	rate, err := findExchangeRate(ctx, tx, rateID)
+	if err != nil {
+		return nil, err
+	}
+	e, err := model.NewExchange(uuid, userID, rate, amount, now)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO exchanges (uuid, user_id, rate_id, from_currency, from_amount, to_currency, to_amount, rate, rounding, create_time)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.UUID, e.UserID, e.RateID, e.FromCurrency, e.FromAmount, e.ToCurrency, e.ToAmount, e.Rate, e.Rounding, e.CreateTime,
+	); err != nil {
+		if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1062 {
+			return nil, domain.ErrDuplicateUUID
+		}
+		return nil, err
+	}
+
+	// 両替元のウォレットをロックして、仮押さえ分を除いた利用可能残高と利用上限をチェックする。
+	// usersの行もロックするので、同じユーザの両替や送金とは直列に処理される
+	from, err := findBalance(ctx, tx, e.UserID, e.FromCurrency, true)
+	if err != nil {
+		return nil, err
+	}
+	if from.AvailableAmount() < e.FromAmount {
+		return nil, domain.ErrShortBalance
+	}
+	if err := checkSpendingLimit(ctx, tx, e.UserID, e.FromCurrency, e.FromAmount, e.CreateTime); err != nil {
+		return nil, err
+	}
+	// 両替元の減算と両替先の加算を1つの仕訳として記録する
+	if err := postJournalEntry(ctx, tx, e.JournalEntry()); err != nil {
+		return nil, err
+	}
+	event, err := model.NewExchangeEvent(e, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := insertOutboxEvent(ctx, tx, event); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return findExchange(ctx, r.DB, uuid)
+}
+
+func findExchange(ctx context.Context, db dbContext, uuid string) (*model.Exchange, error) {
+	query := `
+	SELECT uuid, user_id, rate_id, from_currency, from_amount, to_currency, to_amount, rate, rounding, create_time
+	FROM exchanges WHERE uuid = ?`
+	rows, err := db.QueryContext(ctx, query, uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, domain.ErrInvalidUUID
+	}
+	e := &model.Exchange{}
+	if err := rows.Scan(&e.UUID, &e.UserID, &e.RateID, &e.FromCurrency, &e.FromAmount, &e.ToCurrency, &e.ToAmount, &e.Rate, &e.Rounding, &e.CreateTime); err != nil {
+		return nil, err
+	}
+	e.Rate = trimDecimal(e.Rate)
+	return e, nil
+}
@@ -0,0 +1,153 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+func newExchangeRepo(t *testing.T) *ExchangeRepository {
+	t.Helper()
+	db := newTestConnection(t)
+	return NewExchangeRepository(db)
+}
+
+func TestExchangeRepository_Exchange(t *testing.T) {
+	repo := newExchangeRepo(t)
+	users := createSampleUsers(t, repo.DB, 1)
+	ctx := context.Background()
+	now := time.Now()
+
+	rates := createSampleExchangeRates(t, NewExchangeRateRepository(repo.DB),
+		&model.ExchangeRate{FromCurrency: domain.JPY, ToCurrency: domain.USD, Rate: "0.0067", Rounding: domain.RoundHalfUp, ValidFrom: now.Add(-time.Hour), ValidTo: now.Add(time.Hour)},
+		&model.ExchangeRate{FromCurrency: domain.JPY, ToCurrency: domain.USD, Rate: "0.0067", Rounding: domain.RoundHalfUp, ValidFrom: now.Add(-2 * time.Hour), ValidTo: now.Add(-time.Hour)},
+	)
+	// 仮押さえ分は両替に使えない
+	createSamplePaymentTransaction(t, repo.DB, &model.PaymentTransaction{UUID: "reserved", UserID: users[0].ID, Amount: -100, TryTime: now})
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx    context.Context
+		uuid   string
+		userID uint
+		rateID uint64
+		amount int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *model.Exchange
+		want2   []*model.Balance
+		wantErr error
+	}{
+		{
+			"両替元の減算と両替先の加算を記録する",
+			fields{repo.DB},
+			args{ctx, "foo", users[0].ID, rates[0].ID, 150},
+			&model.Exchange{
+				UUID:         "foo",
+				UserID:       users[0].ID,
+				RateID:       rates[0].ID,
+				FromCurrency: domain.JPY,
+				FromAmount:   150,
+				ToCurrency:   domain.USD,
+				ToAmount:     101,
+				Rate:         "0.0067",
+				Rounding:     domain.RoundHalfUp,
+			},
+			[]*model.Balance{
+				{UserID: users[0].ID, Currency: domain.JPY, Amount: initBalanceAmount - 150, ReservedAmount: 100},
+				{UserID: users[0].ID, Currency: domain.USD, Amount: 101},
+			},
+			nil,
+		},
+		{
+			"UUIDの重複",
+			fields{repo.DB},
+			args{ctx, "foo", users[0].ID, rates[0].ID, 150},
+			nil,
+			[]*model.Balance{
+				{UserID: users[0].ID, Currency: domain.JPY, Amount: initBalanceAmount - 150, ReservedAmount: 100},
+				{UserID: users[0].ID, Currency: domain.USD, Amount: 101},
+			},
+			domain.ErrDuplicateUUID,
+		},
+		{
+			"有効期限が切れたレート",
+			fields{repo.DB},
+			args{ctx, "expired", users[0].ID, rates[1].ID, 150},
+			nil,
+			[]*model.Balance{
+				{UserID: users[0].ID, Currency: domain.JPY, Amount: initBalanceAmount - 150, ReservedAmount: 100},
+				{UserID: users[0].ID, Currency: domain.USD, Amount: 101},
+			},
+			domain.ErrExpiredRate,
+		},
+		{
+			"仮押さえ分を除いた残高が足りない",
+			fields{repo.DB},
+			args{ctx, "short", users[0].ID, rates[0].ID, initBalanceAmount - 150 - 100 + 1},
+			nil,
+			[]*model.Balance{
+				{UserID: users[0].ID, Currency: domain.JPY, Amount: initBalanceAmount - 150, ReservedAmount: 100},
+				{UserID: users[0].ID, Currency: domain.USD, Amount: 101},
+			},
+			domain.ErrShortBalance,
+		},
+		{
+			"0円は両替できない",
+			fields{repo.DB},
+			args{ctx, "small", users[0].ID, rates[0].ID, 0},
+			nil,
+			[]*model.Balance{
+				{UserID: users[0].ID, Currency: domain.JPY, Amount: initBalanceAmount - 150, ReservedAmount: 100},
+				{UserID: users[0].ID, Currency: domain.USD, Amount: 101},
+			},
+			domain.ErrInvalidParam,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ExchangeRepository{
+				DB: tt.fields.DB,
+			}
+			got, err := r.Exchange(tt.args.ctx, tt.args.uuid, tt.args.userID, tt.args.rateID, tt.args.amount, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ExchangeRepository.Exchange() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(model.Exchange{}, "CreateTime")); diff != "" {
+				t.Errorf("ExchangeRepository.Exchange() mismatch (-want +got): \n %s", diff)
+			}
+			balances, err := NewBalanceRepository(tt.fields.DB).List(tt.args.ctx, tt.args.userID)
+			if err != nil {
+				t.Fatalf("BalanceRepository.List() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want2, balances); diff != "" {
+				t.Errorf("ExchangeRepository.Exchange() balances mismatch (-want +got): \n %s", diff)
+			}
+		})
+	}
+
+	// 両方の脚が1つの仕訳に記録され、通貨ごとに合計が0になる
+	entries, err := NewLedgerRepository(repo.DB).ListBySource(ctx, model.JournalSourceExchange, "foo")
+	if err != nil {
+		t.Fatalf("LedgerRepository.ListBySource() error = %v", err)
+	}
+	if len(entries) != 1 || len(entries[0].Postings) != 4 {
+		t.Fatalf("LedgerRepository.ListBySource() = %v, want 1 entry with 4 postings", entries)
+	}
+	if err := NewLedgerRepository(repo.DB).CheckInvariants(ctx); err != nil {
+		t.Errorf("LedgerRepository.CheckInvariants() error = %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type LedgerRepository struct {
+	DB *sql.DB
+}
+
+func NewLedgerRepository(db *sql.DB) *LedgerRepository {
+	return &LedgerRepository{DB: db}
+}
+
+func (r *LedgerRepository) Post(ctx context.Context, entry *model.JournalEntry) (*model.JournalEntry, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := postJournalEntry(ctx, tx, entry); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (r *LedgerRepository) ListBySource(ctx context.Context, sourceType, sourceID string) ([]*model.JournalEntry, error) {
+	query := `
+	SELECT je.id, je.source_type, je.source_id, je.reason, je.create_time, p.account, p.currency, p.amount
+	FROM journal_entries je JOIN postings p ON p.journal_entry_id = je.id
+	WHERE je.source_type = ? AND je.source_id = ?
+	ORDER BY je.id ASC, p.id ASC`
+	rows, err := r.DB.QueryContext(ctx, query, sourceType, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*model.JournalEntry
+	for rows.Next() {
+		e := &model.JournalEntry{}
+		p := &model.Posting{}
+		if err := rows.Scan(&e.ID, &e.SourceType, &e.SourceID, &e.Reason, &e.CreateTime, &p.Account, &p.Currency, &p.Amount); err != nil {
+			return nil, err
+		}
+		if n := len(entries); n > 0 && entries[n-1].ID == e.ID {
+			e = entries[n-1]
+		} else {
+			entries = append(entries, e)
+		}
+		e.Postings = append(e.Postings, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *LedgerRepository) CheckInvariants(ctx context.Context) error {
+	// すべての仕訳の合計は通貨ごとに0になる
+	var currency domain.Currency
+	var sum int64
+	err := r.DB.QueryRowContext(ctx, `SELECT currency, SUM(amount) FROM postings GROUP BY currency HAVING SUM(amount) <> 0 ORDER BY currency ASC LIMIT 1`).Scan(&currency, &sum)
+	if err == nil {
+		return fmt.Errorf("%w: sum of postings in %s is %d", domain.ErrLedgerInconsistent, currency, sum)
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	// ウォレットの残高はユーザの勘定のその通貨の合計と一致する
+	query := `
+	SELECT b.user_id, b.currency, b.amount, COALESCE(p.total, 0)
+	FROM balances b LEFT JOIN (
+		SELECT account, currency, SUM(amount) AS total FROM postings WHERE account LIKE 'user:%' GROUP BY account, currency
+	) p ON p.account = CONCAT('user:', b.user_id) AND p.currency = b.currency
+	WHERE b.amount <> COALESCE(p.total, 0)
+	ORDER BY b.user_id ASC, b.currency ASC LIMIT 1`
+	var userID uint
+	var amount, total int64
+	err = r.DB.QueryRowContext(ctx, query).Scan(&userID, &currency, &amount, &total)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %s balance of user %d is %d but postings sum up to %d", domain.ErrLedgerInconsistent, currency, userID, amount, total)
+}
+
+// postJournalEntry records the entry and applies its user postings to the wallets in the same DB transaction.
+// A wallet which the user has never held is created on the first posting to it.
+func postJournalEntry(ctx context.Context, db dbContext, entry *model.JournalEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	if err := insertJournalEntry(ctx, db, entry); err != nil {
+		return err
+	}
+
+	deltas, err := entry.UserDeltas()
+	if err != nil {
+		return err
+	}
+	if len(deltas) == 0 {
+		return nil
+	}
+	// デッドロックを避けるため(user_id, currency)順にロックする
+	keys := make([]model.WalletKey, 0, len(deltas))
+	for key := range deltas {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].UserID != keys[j].UserID {
+			return keys[i].UserID < keys[j].UserID
+		}
+		return keys[i].Currency < keys[j].Currency
+	})
+	keyStrings := make([]string, 0, len(keys))
+	keyArgs := make([]interface{}, 0, len(keys)*2)
+	for _, key := range keys {
+		keyStrings = append(keyStrings, "(?, ?)")
+		keyArgs = append(keyArgs, key.UserID, key.Currency)
+	}
+	// まだないウォレットを作る。存在しないユーザは外部キー制約で無視され、下の件数チェックでエラーになる
+	insertQuery := "INSERT IGNORE INTO balances (user_id, currency) VALUES " + strings.Join(keyStrings, ",")
+	if _, err := db.ExecContext(ctx, insertQuery, keyArgs...); err != nil {
+		return err
+	}
+	fetchQuery := "SELECT user_id, currency, amount, reserved_amount, overdraft_limit FROM balances WHERE (user_id, currency) IN (" + strings.Join(keyStrings, ",") + ") ORDER BY user_id ASC, currency ASC FOR UPDATE"
+	rows, err := db.QueryContext(ctx, fetchQuery, keyArgs...)
+	if err != nil {
+		return err
+	}
+	var balances []*model.Balance
+	for rows.Next() {
+		b, err := rowsToBalance(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		balances = append(balances, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(balances) != len(keys) {
+		return domain.ErrNoSuchEntity
+	}
+
+	// 同じ通貨で同じ増減額のウォレットはまとめて更新する
+	type update struct {
+		currency domain.Currency
+		delta    int64
+	}
+	updates := map[update][]interface{}{}
+	var logStrings []string
+	var logArgs []interface{}
+	pointDeltas := map[uint]int64{}
+	for _, b := range balances {
+		delta := deltas[b.Key()]
+		after, err := b.Money().Add(domain.NewMoney(delta, b.Currency))
+		if err != nil {
+			return err
+		}
+		if b.IsOverdrawn(after.Amount) && delta < 0 && !entry.AllowNegativeBalance {
+			return domain.ErrShortBalance
+		}
+		if b.Currency == domain.Point {
+			pointDeltas[b.UserID] = pointLotDelta(b.Amount, after.Amount)
+		}
+		u := update{currency: b.Currency, delta: delta}
+		updates[u] = append(updates[u], b.UserID)
+		logStrings = append(logStrings, "(?, ?, ?, ?, ?, ?, ?, ?)")
+		logArgs = append(logArgs, b.UserID, b.Currency, b.Amount, after.Amount, delta, entry.SourceType, entry.SourceID, entry.Reason)
+	}
+	for u, ids := range updates {
+		updateQuery := "UPDATE balances SET amount = amount + ? WHERE currency = ? AND user_id IN (?" + strings.Repeat(",?", len(ids)-1) + ")"
+		if _, err := db.ExecContext(ctx, updateQuery, append([]interface{}{u.delta, u.currency}, ids...)...); err != nil {
+			return err
+		}
+	}
+	logQuery := "INSERT INTO balance_logs (user_id, currency, before_amount, after_amount, delta, source_type, source_id, reason) VALUES " + strings.Join(logStrings, ",")
+	if _, err := db.ExecContext(ctx, logQuery, logArgs...); err != nil {
+		return err
+	}
+	return applyPointLots(ctx, db, entry, pointDeltas)
+}
+
+// insertJournalEntry inserts the entry and its postings without touching the balances.
+func insertJournalEntry(ctx context.Context, db dbContext, entry *model.JournalEntry) error {
+	res, err := db.ExecContext(ctx,
+		"INSERT INTO journal_entries (source_type, source_id, reason, create_time) VALUES (?, ?, ?, ?)",
+		entry.SourceType, entry.SourceID, entry.Reason, entry.CreateTime,
+	)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	entry.ID = uint64(id)
+
+	valueStrings := make([]string, 0, len(entry.Postings))
+	valueArgs := make([]interface{}, 0, len(entry.Postings)*4)
+	for _, p := range entry.Postings {
+		valueStrings = append(valueStrings, "(?, ?, ?, ?)")
+		valueArgs = append(valueArgs, entry.ID, p.Account, p.Currency, p.Amount)
+	}
+	insertQuery := "INSERT INTO postings (journal_entry_id, account, currency, amount) VALUES " + strings.Join(valueStrings, ",")
+	if _, err := db.ExecContext(ctx, insertQuery, valueArgs...); err != nil {
+		return err
+	}
+	return nil
+}
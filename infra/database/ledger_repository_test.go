@@ -0,0 +1,193 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+func newLedgerRepo(t *testing.T) *LedgerRepository {
+	t.Helper()
+	db := newTestConnection(t)
+	return NewLedgerRepository(db)
+}
+
+func TestLedgerRepository_Post(t *testing.T) {
+	repo := newLedgerRepo(t)
+	users := createSampleUsers(t, repo.DB, 2)
+	ctx := context.Background()
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx   context.Context
+		entry *model.JournalEntry
+	}
+	type want struct {
+		Amounts   []int64
+		LogCounts []int
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    want
+		wantErr error
+	}{
+		{
+			"仕訳の分だけ残高が増減する",
+			fields{repo.DB},
+			args{ctx, model.NewJournalEntry(model.JournalSourceTransfer, "foo",
+				&model.Posting{Account: model.UserAccount(users[0].ID), Currency: domain.JPY, Amount: -100},
+				&model.Posting{Account: model.UserAccount(users[1].ID), Currency: domain.JPY, Amount: 100},
+			)},
+			want{[]int64{initBalanceAmount - 100, initBalanceAmount + 100}, []int{1, 1}},
+			nil,
+		},
+		{
+			"システム勘定は残高に影響しない",
+			fields{repo.DB},
+			args{ctx, model.NewJournalEntry(model.JournalSourcePayment, "bar",
+				&model.Posting{Account: model.UserAccount(users[0].ID), Currency: domain.JPY, Amount: 10},
+				&model.Posting{Account: model.AccountExternalSettlement, Currency: domain.JPY, Amount: -10},
+			)},
+			want{[]int64{initBalanceAmount - 90, initBalanceAmount + 100}, []int{2, 1}},
+			nil,
+		},
+		{
+			"合計が0にならない仕訳",
+			fields{repo.DB},
+			args{ctx, model.NewJournalEntry(model.JournalSourcePayment, "unbalanced",
+				&model.Posting{Account: model.UserAccount(users[0].ID), Currency: domain.JPY, Amount: 10},
+			)},
+			want{[]int64{initBalanceAmount - 90, initBalanceAmount + 100}, []int{2, 1}},
+			domain.ErrUnbalancedEntry,
+		},
+		{
+			"残高がマイナスになる仕訳",
+			fields{repo.DB},
+			args{ctx, model.NewJournalEntry(model.JournalSourceTransfer, "short",
+				&model.Posting{Account: model.UserAccount(users[0].ID), Currency: domain.JPY, Amount: -initBalanceAmount},
+				&model.Posting{Account: model.UserAccount(users[1].ID), Currency: domain.JPY, Amount: initBalanceAmount},
+			)},
+			want{[]int64{initBalanceAmount - 90, initBalanceAmount + 100}, []int{2, 1}},
+			domain.ErrShortBalance,
+		},
+		{
+			"存在しないユーザの勘定",
+			fields{repo.DB},
+			args{ctx, model.NewJournalEntry(model.JournalSourcePayment, "unknown",
+				&model.Posting{Account: model.UserAccount(999), Currency: domain.JPY, Amount: 10},
+				&model.Posting{Account: model.AccountExternalSettlement, Currency: domain.JPY, Amount: -10},
+			)},
+			want{[]int64{initBalanceAmount - 90, initBalanceAmount + 100}, []int{2, 1}},
+			domain.ErrNoSuchEntity,
+		},
+		{
+			"通貨ごとに合計が0にならない仕訳",
+			fields{repo.DB},
+			args{ctx, model.NewJournalEntry(model.JournalSourcePayment, "mixed",
+				&model.Posting{Account: model.UserAccount(users[0].ID), Currency: domain.JPY, Amount: 10},
+				&model.Posting{Account: model.AccountExternalSettlement, Currency: domain.USD, Amount: -10},
+			)},
+			want{[]int64{initBalanceAmount - 90, initBalanceAmount + 100}, []int{2, 1}},
+			domain.ErrUnbalancedEntry,
+		},
+		{
+			"他の通貨の仕訳はJPYの残高に影響しない",
+			fields{repo.DB},
+			args{ctx, model.NewJournalEntry(model.JournalSourcePayment, "usd",
+				&model.Posting{Account: model.UserAccount(users[1].ID), Currency: domain.USD, Amount: 10},
+				&model.Posting{Account: model.AccountExternalSettlement, Currency: domain.USD, Amount: -10},
+			)},
+			want{[]int64{initBalanceAmount - 90, initBalanceAmount + 100}, []int{2, 2}},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &LedgerRepository{
+				DB: tt.fields.DB,
+			}
+			got, err := r.Post(tt.args.ctx, tt.args.entry)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("LedgerRepository.Post() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.ID == 0 {
+				t.Error("LedgerRepository.Post() got.ID MUST NOT be zero")
+			}
+			var gotWant want
+			for _, u := range users {
+				b, err := findBalance(context.Background(), r.DB, u.ID, domain.JPY, false)
+				if err != nil {
+					t.Errorf("LedgerRepository.Post() findBalance error = %v", err)
+				}
+				c, err := countBalanceLog(context.Background(), r.DB, u.ID)
+				if err != nil {
+					t.Errorf("LedgerRepository.Post() countBalanceLog error = %v", err)
+				}
+				gotWant.Amounts = append(gotWant.Amounts, b.Amount)
+				gotWant.LogCounts = append(gotWant.LogCounts, c)
+			}
+			if diff := cmp.Diff(tt.want, gotWant, nil); diff != "" {
+				t.Errorf("LedgerRepository.Post() mismatch (-want +got): \n %s", diff)
+			}
+			if err := r.CheckInvariants(context.Background()); err != nil {
+				t.Errorf("LedgerRepository.CheckInvariants() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestLedgerRepository_ListBySource(t *testing.T) {
+	repo := newLedgerRepo(t)
+	users := createSampleUsers(t, repo.DB, 2)
+	ctx := context.Background()
+	posted, err := repo.Post(ctx, model.NewJournalEntry(model.JournalSourceTransfer, "foo",
+		&model.Posting{Account: model.UserAccount(users[0].ID), Currency: domain.JPY, Amount: -100},
+		&model.Posting{Account: model.UserAccount(users[1].ID), Currency: domain.JPY, Amount: 100},
+	))
+	if err != nil {
+		t.Fatalf("LedgerRepository.Post() error = %v", err)
+	}
+
+	got, err := repo.ListBySource(ctx, model.JournalSourceTransfer, "foo")
+	if err != nil {
+		t.Fatalf("LedgerRepository.ListBySource() error = %v", err)
+	}
+	opt := cmpopts.IgnoreFields(model.JournalEntry{}, "CreateTime")
+	if diff := cmp.Diff([]*model.JournalEntry{posted}, got, opt); diff != "" {
+		t.Errorf("LedgerRepository.ListBySource() mismatch (-want +got): \n %s", diff)
+	}
+}
+
+func TestLedgerRepository_CheckInvariants(t *testing.T) {
+	repo := newLedgerRepo(t)
+	users := createSampleUsers(t, repo.DB, 2)
+	ctx := context.Background()
+
+	// 仕訳を通した更新では不変条件が保たれる
+	if err := NewBalanceRepository(repo.DB).AddToUsers(ctx, domain.JPY, 10, time.Time{}, 10, 0); err != nil {
+		t.Fatalf("BalanceRepository.AddToUsers() error = %v", err)
+	}
+	if err := repo.CheckInvariants(ctx); err != nil {
+		t.Errorf("LedgerRepository.CheckInvariants() error = %v", err)
+	}
+
+	// 仕訳を通さずに残高を更新すると検出される
+	if _, err := repo.DB.ExecContext(ctx, "UPDATE balances SET amount = amount + 1 WHERE user_id = ?", users[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CheckInvariants(ctx); !errors.Is(err, domain.ErrLedgerInconsistent) {
+		t.Errorf("LedgerRepository.CheckInvariants() error = %v, wantErr %v", err, domain.ErrLedgerInconsistent)
+	}
+}
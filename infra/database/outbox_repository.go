@@ -0,0 +1,134 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type OutboxRepository struct {
+	DB *sql.DB
+}
+
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{DB: db}
+}
+
+const selectOutboxEventQuery = `
+	SELECT sequence, event_type, payload, status, attempts, next_attempt_time, last_error, create_time, publish_time
+	FROM outbox_events`
+
+func (r *OutboxRepository) ClaimPending(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.OutboxEvent, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// 他のリレーが取得中の行はスキップする
+	rows, err := tx.QueryContext(ctx,
+		selectOutboxEventQuery+` WHERE status = ? AND next_attempt_time <= ? ORDER BY sequence ASC LIMIT ? FOR UPDATE SKIP LOCKED`,
+		model.OutboxEventStatusPending, now, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var events []*model.OutboxEvent
+	for rows.Next() {
+		event, err := rowsToOutboxEvent(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	// 配信の結果が保存されないまま期限を過ぎたイベント（リレーが落ちた場合など）は、もう一度配信する
+	nextAttemptTime := now.Add(lease)
+	args := make([]interface{}, 0, len(events)+1)
+	args = append(args, nextAttemptTime)
+	for _, event := range events {
+		event.NextAttemptTime = nextAttemptTime
+		args = append(args, event.Sequence)
+	}
+	updateQuery := "UPDATE outbox_events SET next_attempt_time = ? WHERE sequence IN (?" + strings.Repeat(",?", len(events)-1) + ")"
+	if _, err := tx.ExecContext(ctx, updateQuery, args...); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *OutboxRepository) Update(ctx context.Context, event *model.OutboxEvent) error {
+	_, err := r.DB.ExecContext(ctx,
+		`UPDATE outbox_events SET status = ?, attempts = ?, next_attempt_time = ?, last_error = ?, publish_time = ? WHERE sequence = ?`,
+		event.Status, event.Attempts, event.NextAttemptTime, truncateMessage(event.LastError), nullTime(event.PublishTime), event.Sequence,
+	)
+	return err
+}
+
+// insertOutboxEvent records the event in the DB transaction which makes the change, and sets its sequence.
+// The deliveries to the webhooks subscribing to the event are created in the same transaction.
+func insertOutboxEvent(ctx context.Context, db dbContext, event *model.OutboxEvent) error {
+	res, err := db.ExecContext(ctx,
+		"INSERT INTO outbox_events (event_type, payload, status, next_attempt_time, create_time) VALUES (?, ?, ?, ?, ?)",
+		event.Type, event.Payload, event.Status, event.NextAttemptTime, event.CreateTime,
+	)
+	if err != nil {
+		return err
+	}
+	sequence, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	event.Sequence = uint64(sequence)
+	return enqueueWebhookDeliveries(ctx, db, event)
+}
+
+// insertPaymentEvent records the event of the payment which has just been confirmed, cancelled or expired.
+func insertPaymentEvent(ctx context.Context, db dbContext, pt *model.PaymentTransaction, now time.Time) error {
+	event, err := model.NewPaymentEvent(pt, now)
+	if err != nil {
+		return err
+	}
+	return insertOutboxEvent(ctx, db, event)
+}
+
+// insertTransferEvent records the event of the transfer which has just been confirmed, cancelled or expired.
+func insertTransferEvent(ctx context.Context, db dbContext, t *model.Transfer, now time.Time) error {
+	event, err := model.NewTransferEvent(t, now)
+	if err != nil {
+		return err
+	}
+	return insertOutboxEvent(ctx, db, event)
+}
+
+func rowsToOutboxEvent(rows *sql.Rows) (*model.OutboxEvent, error) {
+	event := &model.OutboxEvent{}
+	var publishTime sql.NullTime
+	if err := rows.Scan(
+		&event.Sequence, &event.Type, &event.Payload, &event.Status, &event.Attempts, &event.NextAttemptTime,
+		&event.LastError, &event.CreateTime, &publishTime,
+	); err != nil {
+		return nil, err
+	}
+	if publishTime.Valid {
+		event.PublishTime = publishTime.Time
+	}
+	return event, nil
+}
@@ -0,0 +1,181 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+func newOutboxRepo(t *testing.T) *OutboxRepository {
+	t.Helper()
+	db := newTestConnection(t)
+	return NewOutboxRepository(db)
+}
+
+func TestOutboxRepository_RecordedWithChanges(t *testing.T) {
+	repo := newOutboxRepo(t)
+	users := createSampleUsers(t, repo.DB, 2)
+	ctx := context.Background()
+	now := time.Now()
+	createSamplePaymentTransaction(t, repo.DB, &model.PaymentTransaction{UUID: "confirm", UserID: users[0].ID, Amount: -100, TryTime: now})
+	createSamplePaymentTransaction(t, repo.DB, &model.PaymentTransaction{UUID: "cancel", UserID: users[1].ID, Amount: -200, TryTime: now})
+
+	paymentRepo := NewPaymentTransactionRepository(repo.DB)
+	balanceRepo := NewBalanceRepository(repo.DB)
+	if _, err := paymentRepo.Confirm(ctx, "confirm"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := paymentRepo.Cancel(ctx, "cancel"); err != nil {
+		t.Fatal(err)
+	}
+	if err := balanceRepo.AddToUsers(ctx, domain.JPY, 50, time.Time{}, 10, 0); err != nil {
+		t.Fatal(err)
+	}
+	// 変更に失敗した場合はイベントも記録されない
+	if _, err := paymentRepo.Confirm(ctx, "confirm"); !errors.Is(err, domain.ErrIllegalTransition) {
+		t.Fatalf("Confirm() error = %v", err)
+	}
+
+	events, err := repo.ClaimPending(ctx, time.Now(), time.Minute, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantTypes := []model.OutboxEventType{model.EventPaymentConfirmed, model.EventPaymentCancelled, model.EventBalancesAddedToUsers}
+	if len(events) != len(wantTypes) {
+		t.Fatalf("OutboxRepository.ClaimPending() len = %v, want %v", len(events), len(wantTypes))
+	}
+	for i, event := range events {
+		if event.Type != wantTypes[i] {
+			t.Errorf("events[%d].Type = %v, want %v", i, event.Type, wantTypes[i])
+		}
+		if i > 0 && event.Sequence <= events[i-1].Sequence {
+			t.Errorf("events[%d].Sequence = %v, want greater than %v", i, event.Sequence, events[i-1].Sequence)
+		}
+	}
+	var payment model.PaymentEventPayload
+	if err := json.Unmarshal(events[0].Payload, &payment); err != nil {
+		t.Fatal(err)
+	}
+	if payment.UUID != "confirm" || payment.UserID != users[0].ID || payment.Amount != -100 || payment.Status != model.PaymentStatusConfirmed {
+		t.Errorf("payload = %+v", payment)
+	}
+	var added model.AddToUsersEventPayload
+	if err := json.Unmarshal(events[2].Payload, &added); err != nil {
+		t.Fatal(err)
+	}
+	if added.Amount != 50 || len(added.UserIDs) != 2 || added.PointExpireTime != nil {
+		t.Errorf("payload = %+v", added)
+	}
+}
+
+func TestOutboxRepository_RecordedWithOtherChanges(t *testing.T) {
+	repo := newOutboxRepo(t)
+	users := createSampleUsers(t, repo.DB, 2)
+	ctx := context.Background()
+	now := time.Now()
+	createSamplePaymentTransaction(t, repo.DB, &model.PaymentTransaction{UUID: "expire", UserID: users[0].ID, Amount: -100, TryTime: now.Add(-time.Hour), ExpireTime: now.Add(-time.Minute)})
+	createSamplePaymentTransaction(t, repo.DB, &model.PaymentTransaction{UUID: "refund", UserID: users[1].ID, Amount: -100, TryTime: now, ConfirmTime: now})
+	createSampleTransfer(t, repo.DB, model.NewTransfer("transfer", users[0].ID, users[1].ID, domain.JPY, 10, time.Minute))
+
+	if _, err := NewPaymentTransactionRepository(repo.DB).ExpireTries(ctx, now, 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := NewRefundRepository(repo.DB).Refund(ctx, "refund-1", "refund", 40, now); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewTransferRepository(repo.DB).Confirm(ctx, "transfer"); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := repo.ClaimPending(ctx, time.Now(), time.Minute, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantTypes := []model.OutboxEventType{model.EventPaymentExpired, model.EventRefundCreated, model.EventTransferConfirmed}
+	if len(events) != len(wantTypes) {
+		t.Fatalf("OutboxRepository.ClaimPending() len = %v, want %v", len(events), len(wantTypes))
+	}
+	for i, event := range events {
+		if event.Type != wantTypes[i] {
+			t.Errorf("events[%d].Type = %v, want %v", i, event.Type, wantTypes[i])
+		}
+	}
+	var refund model.RefundEventPayload
+	if err := json.Unmarshal(events[1].Payload, &refund); err != nil {
+		t.Fatal(err)
+	}
+	if refund.UUID != "refund-1" || refund.PaymentUUID != "refund" || refund.UserID != users[1].ID || refund.Amount != 40 {
+		t.Errorf("payload = %+v", refund)
+	}
+	var transfer model.TransferEventPayload
+	if err := json.Unmarshal(events[2].Payload, &transfer); err != nil {
+		t.Fatal(err)
+	}
+	if transfer.FromUserID != users[0].ID || transfer.ToUserID != users[1].ID || transfer.Amount != 10 || transfer.Status != model.PaymentStatusConfirmed {
+		t.Errorf("payload = %+v", transfer)
+	}
+}
+
+func TestOutboxRepository_ClaimPending(t *testing.T) {
+	repo := newOutboxRepo(t)
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	var events []*model.OutboxEvent
+	for i := 0; i < 3; i++ {
+		event, err := model.NewOutboxEvent(model.EventPaymentConfirmed, map[string]int{"i": i}, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := insertOutboxEvent(ctx, repo.DB, event); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, event)
+	}
+
+	claimed, err := repo.ClaimPending(ctx, now, time.Minute, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claimed) != 2 || claimed[0].Sequence != events[0].Sequence || claimed[1].Sequence != events[1].Sequence {
+		t.Fatalf("OutboxRepository.ClaimPending() got = %+v", claimed)
+	}
+	// 1つ目は配信に成功し、2つ目は失敗した
+	claimed[0].Published(now)
+	if err := repo.Update(ctx, claimed[0]); err != nil {
+		t.Fatal(err)
+	}
+	claimed[1].Failed(errors.New("unavailable"), 3, now)
+	if err := repo.Update(ctx, claimed[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want []uint64
+	}{
+		{"取得済みのイベントは取得されない", now, []uint64{events[2].Sequence}},
+		{"再試行の時刻になると失敗したイベントが取得される", now.Add(model.RetryDelay(1)), []uint64{events[1].Sequence}},
+		{"配信の結果が保存されないまま期限を過ぎると再び取得される", now.Add(time.Minute), []uint64{events[2].Sequence}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.ClaimPending(ctx, tt.now, time.Minute, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var sequences []uint64
+			for _, event := range got {
+				sequences = append(sequences, event.Sequence)
+			}
+			if len(sequences) != len(tt.want) || (len(sequences) > 0 && sequences[0] != tt.want[0]) {
+				t.Errorf("OutboxRepository.ClaimPending() = %v, want %v", sequences, tt.want)
+			}
+		})
+	}
+}
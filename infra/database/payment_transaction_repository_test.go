@@ -3,11 +3,13 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/kawabatas/m-bank/domain"
 	"github.com/kawabatas/m-bank/domain/model"
 )
 
@@ -21,13 +23,14 @@ func TestPaymentTransactionRepository_Get(t *testing.T) {
 	repo := newPaymentTransactionRepo(t)
 	users := createSampleUsers(t, repo.DB, 1)
 	sampleUuid := "foo"
-	sampleAmount := 100
+	sampleAmount := int64(100)
 	createSamplePaymentTransaction(t, repo.DB,
 		&model.PaymentTransaction{
-			UUID:    sampleUuid,
-			UserID:  users[0].ID,
-			Amount:  sampleAmount,
-			TryTime: time.Now(),
+			UUID:     sampleUuid,
+			UserID:   users[0].ID,
+			Currency: domain.JPY,
+			Amount:   sampleAmount,
+			TryTime:  time.Now(),
 		},
 	)
 	ctx := context.Background()
@@ -51,9 +54,11 @@ func TestPaymentTransactionRepository_Get(t *testing.T) {
 			fields{repo.DB},
 			args{ctx, sampleUuid},
 			&model.PaymentTransaction{
-				UUID:   sampleUuid,
-				UserID: users[0].ID,
-				Amount: sampleAmount,
+				UUID:     sampleUuid,
+				UserID:   users[0].ID,
+				Currency: domain.JPY,
+				Amount:   sampleAmount,
+				Status:   model.PaymentStatusTried,
 			},
 			false,
 		},
@@ -75,7 +80,7 @@ func TestPaymentTransactionRepository_Get(t *testing.T) {
 				t.Errorf("PaymentTransactionRepository.Get() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			opt := cmpopts.IgnoreFields(model.PaymentTransaction{}, "TryTime", "ConfirmTime", "CancelTime")
+			opt := cmpopts.IgnoreFields(model.PaymentTransaction{}, "RequestFingerprint", "TryTime", "ExpireTime", "ConfirmTime", "CancelTime", "ExpiredTime", "ResultBalance")
 			if diff := cmp.Diff(tt.want, got, opt); diff != "" {
 				t.Errorf("PaymentTransactionRepository.Get() mismatch (-want +got): \n %s", diff)
 			}
@@ -83,11 +88,107 @@ func TestPaymentTransactionRepository_Get(t *testing.T) {
 	}
 }
 
+func TestPaymentTransactionRepository_List(t *testing.T) {
+	repo := newPaymentTransactionRepo(t)
+	users := createSampleUsers(t, repo.DB, 2)
+	// MySQLのDATETIMEは秒単位のため、秒で切り捨てた時刻で作成する
+	now := time.Now().Truncate(time.Second)
+	samples := []*model.PaymentTransaction{
+		{UUID: "a", UserID: users[0].ID, Amount: 10, TryTime: now.Add(-3 * time.Minute), ConfirmTime: now},
+		{UUID: "b", UserID: users[0].ID, Amount: -20, TryTime: now.Add(-2 * time.Minute), CancelTime: now},
+		{UUID: "c", UserID: users[0].ID, Amount: 30, TryTime: now.Add(-1 * time.Minute)},
+		{UUID: "d", UserID: users[0].ID, Amount: -40, TryTime: now.Add(-1 * time.Minute)},
+		{UUID: "e", UserID: users[1].ID, Amount: 50, TryTime: now},
+	}
+	for _, pt := range samples {
+		createSamplePaymentTransaction(t, repo.DB, pt)
+	}
+	ctx := context.Background()
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx    context.Context
+		userID uint
+		filter model.PaymentTransactionFilter
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantUUID []string
+		wantErr  bool
+	}{
+		{
+			"新しい順に取得できる",
+			fields{repo.DB},
+			args{ctx, users[0].ID, model.PaymentTransactionFilter{Limit: 10}},
+			[]string{"d", "c", "b", "a"},
+			false,
+		},
+		{
+			"ステータスで絞り込める",
+			fields{repo.DB},
+			args{ctx, users[0].ID, model.PaymentTransactionFilter{Status: model.PaymentStatusConfirmed, Limit: 10}},
+			[]string{"a"},
+			false,
+		},
+		{
+			"金額の符号で絞り込める",
+			fields{repo.DB},
+			args{ctx, users[0].ID, model.PaymentTransactionFilter{Sign: model.AmountSignNegative, Limit: 10}},
+			[]string{"d", "b"},
+			false,
+		},
+		{
+			"期間で絞り込める",
+			fields{repo.DB},
+			args{ctx, users[0].ID, model.PaymentTransactionFilter{From: now.Add(-2 * time.Minute), To: now.Add(-1 * time.Minute), Limit: 10}},
+			[]string{"b"},
+			false,
+		},
+		{
+			"Tryの時刻が同じ取引もカーソルの続きから取得できる",
+			fields{repo.DB},
+			args{ctx, users[0].ID, model.PaymentTransactionFilter{BeforeTryTime: now.Add(-1 * time.Minute), BeforeUUID: "d", Limit: 2}},
+			[]string{"c", "b"},
+			false,
+		},
+		{
+			"他のユーザの取引は取得しない",
+			fields{repo.DB},
+			args{ctx, users[1].ID, model.PaymentTransactionFilter{Limit: 10}},
+			[]string{"e"},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &PaymentTransactionRepository{
+				DB: tt.fields.DB,
+			}
+			got, err := r.List(tt.args.ctx, tt.args.userID, tt.args.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PaymentTransactionRepository.List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			var gotUUID []string
+			for _, pt := range got {
+				gotUUID = append(gotUUID, pt.UUID)
+			}
+			if diff := cmp.Diff(tt.wantUUID, gotUUID); diff != "" {
+				t.Errorf("PaymentTransactionRepository.List() mismatch (-want +got): \n %s", diff)
+			}
+		})
+	}
+}
+
 func TestPaymentTransactionRepository_Try(t *testing.T) {
 	repo := newPaymentTransactionRepo(t)
 	users := createSampleUsers(t, repo.DB, 2)
 	sampleUuid := "foo"
-	sampleAmount := 100
+	sampleAmount := int64(100)
 	ctx := context.Background()
 
 	type fields struct {
@@ -97,13 +198,17 @@ func TestPaymentTransactionRepository_Try(t *testing.T) {
 		ctx    context.Context
 		uuid   string
 		userID uint
-		amount int
+		amount int64
+	}
+	type want2 struct {
+		ReservedAmount int64
 	}
 	tests := []struct {
 		name    string
 		fields  fields
 		args    args
 		want    *model.PaymentTransaction
+		want2   want2
 		wantErr bool
 	}{
 		{
@@ -111,10 +216,13 @@ func TestPaymentTransactionRepository_Try(t *testing.T) {
 			fields{repo.DB},
 			args{ctx, sampleUuid, users[0].ID, sampleAmount},
 			&model.PaymentTransaction{
-				UUID:   sampleUuid,
-				UserID: users[0].ID,
-				Amount: sampleAmount,
+				UUID:     sampleUuid,
+				UserID:   users[0].ID,
+				Currency: domain.JPY,
+				Amount:   sampleAmount,
+				Status:   model.PaymentStatusTried,
 			},
+			want2{0},
 			false,
 		},
 		{
@@ -122,20 +230,57 @@ func TestPaymentTransactionRepository_Try(t *testing.T) {
 			fields{repo.DB},
 			args{ctx, sampleUuid, users[1].ID, sampleAmount},
 			nil,
+			want2{0},
+			true,
+		},
+		{
+			"減算は残高を仮押さえする",
+			fields{repo.DB},
+			args{ctx, "sub", users[1].ID, -400},
+			&model.PaymentTransaction{
+				UUID:     "sub",
+				UserID:   users[1].ID,
+				Currency: domain.JPY,
+				Amount:   -400,
+				Status:   model.PaymentStatusTried,
+			},
+			want2{400},
+			false,
+		},
+		{
+			"利用可能残高を超える減算はできない",
+			fields{repo.DB},
+			args{ctx, "sub over", users[1].ID, -(initBalanceAmount - 400 + 1)},
+			nil,
+			want2{400},
 			true,
 		},
+		{
+			"利用可能残高の範囲内なら減算できる",
+			fields{repo.DB},
+			args{ctx, "sub rest", users[1].ID, -(initBalanceAmount - 400)},
+			&model.PaymentTransaction{
+				UUID:     "sub rest",
+				UserID:   users[1].ID,
+				Currency: domain.JPY,
+				Amount:   -(initBalanceAmount - 400),
+				Status:   model.PaymentStatusTried,
+			},
+			want2{initBalanceAmount},
+			false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &PaymentTransactionRepository{
 				DB: tt.fields.DB,
 			}
-			got, err := r.Try(tt.args.ctx, tt.args.uuid, tt.args.userID, tt.args.amount)
+			got, err := r.Try(tt.args.ctx, tt.args.uuid, tt.args.userID, domain.JPY, tt.args.amount, time.Minute)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("PaymentTransactionRepository.Try() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			opt := cmpopts.IgnoreFields(model.PaymentTransaction{}, "TryTime", "ConfirmTime", "CancelTime")
+			opt := cmpopts.IgnoreFields(model.PaymentTransaction{}, "RequestFingerprint", "TryTime", "ExpireTime", "ConfirmTime", "CancelTime", "ExpiredTime", "ResultBalance")
 			if diff := cmp.Diff(tt.want, got, opt); diff != "" {
 				t.Errorf("PaymentTransactionRepository.Try() mismatch (-want +got): \n %s", diff)
 			}
@@ -143,6 +288,12 @@ func TestPaymentTransactionRepository_Try(t *testing.T) {
 				if got.TryTime.IsZero() {
 					t.Error("PaymentTransactionRepository.Try() got.TryTime MUST NOT IsZero")
 				}
+				if !got.ExpireTime.After(got.TryTime) {
+					t.Error("PaymentTransactionRepository.Try() got.ExpireTime MUST be after got.TryTime")
+				}
+				if !got.MatchesRequest(tt.args.userID, domain.JPY, tt.args.amount) {
+					t.Error("PaymentTransactionRepository.Try() got.RequestFingerprint MUST match the request")
+				}
 				if !got.ConfirmTime.IsZero() {
 					t.Error("PaymentTransactionRepository.Try() got.ConfirmTime MUST IsZero")
 				}
@@ -150,6 +301,14 @@ func TestPaymentTransactionRepository_Try(t *testing.T) {
 					t.Error("PaymentTransactionRepository.Try() got.CancelTime MUST IsZero")
 				}
 			}
+			b, err := findBalance(context.Background(), r.DB, tt.args.userID, domain.JPY, false)
+			if err != nil {
+				t.Errorf("PaymentTransactionRepository.Try() findBalance error = %v", err)
+			}
+			got2 := want2{ReservedAmount: b.ReservedAmount}
+			if diff2 := cmp.Diff(tt.want2, got2, nil); diff2 != "" {
+				t.Errorf("PaymentTransactionRepository.Try() mismatch (-want2 +got2): \n %s", diff2)
+			}
 		})
 	}
 }
@@ -161,18 +320,21 @@ func TestPaymentTransactionRepository_Confirm(t *testing.T) {
 	notTryUuid := "not try"
 	subUuid := "sub"
 	subUuid2 := "sub minus"
+	expiredUuid := "expired"
 	createSamplePaymentTransaction(t, repo.DB,
 		&model.PaymentTransaction{
-			UUID:    addUuid,
-			UserID:  users[0].ID,
-			Amount:  1,
-			TryTime: time.Now(),
+			UUID:     addUuid,
+			UserID:   users[0].ID,
+			Currency: domain.JPY,
+			Amount:   1,
+			TryTime:  time.Now(),
 		},
 	)
 	createSamplePaymentTransaction(t, repo.DB,
 		&model.PaymentTransaction{
 			UUID:        notTryUuid,
 			UserID:      users[0].ID,
+			Currency:    domain.JPY,
 			Amount:      1,
 			TryTime:     time.Now(),
 			ConfirmTime: time.Now(),
@@ -180,18 +342,30 @@ func TestPaymentTransactionRepository_Confirm(t *testing.T) {
 	)
 	createSamplePaymentTransaction(t, repo.DB,
 		&model.PaymentTransaction{
-			UUID:    subUuid,
-			UserID:  users[0].ID,
-			Amount:  -(initBalanceAmount + 1),
-			TryTime: time.Now(),
+			UUID:     subUuid,
+			UserID:   users[0].ID,
+			Currency: domain.JPY,
+			Amount:   -(initBalanceAmount + 1),
+			TryTime:  time.Now(),
+		},
+	)
+	createSamplePaymentTransaction(t, repo.DB,
+		&model.PaymentTransaction{
+			UUID:     subUuid2,
+			UserID:   users[0].ID,
+			Currency: domain.JPY,
+			Amount:   -1,
+			TryTime:  time.Now(),
 		},
 	)
 	createSamplePaymentTransaction(t, repo.DB,
 		&model.PaymentTransaction{
-			UUID:    subUuid2,
-			UserID:  users[0].ID,
-			Amount:  -1,
-			TryTime: time.Now(),
+			UUID:       expiredUuid,
+			UserID:     users[0].ID,
+			Currency:   domain.JPY,
+			Amount:     1,
+			TryTime:    time.Now().Add(-2 * time.Minute),
+			ExpireTime: time.Now().Add(-time.Minute),
 		},
 	)
 	ctx := context.Background()
@@ -204,7 +378,7 @@ func TestPaymentTransactionRepository_Confirm(t *testing.T) {
 		uuid string
 	}
 	type want2 struct {
-		Balance  uint
+		Balance  int64
 		LogCount int
 	}
 	tests := []struct {
@@ -220,9 +394,11 @@ func TestPaymentTransactionRepository_Confirm(t *testing.T) {
 			fields{repo.DB},
 			args{ctx, addUuid},
 			&model.PaymentTransaction{
-				UUID:   addUuid,
-				UserID: users[0].ID,
-				Amount: 1,
+				UUID:     addUuid,
+				UserID:   users[0].ID,
+				Currency: domain.JPY,
+				Amount:   1,
+				Status:   model.PaymentStatusConfirmed,
 			},
 			want2{initBalanceAmount + 1, 1},
 			false,
@@ -240,9 +416,11 @@ func TestPaymentTransactionRepository_Confirm(t *testing.T) {
 			fields{repo.DB},
 			args{ctx, subUuid},
 			&model.PaymentTransaction{
-				UUID:   subUuid,
-				UserID: users[0].ID,
-				Amount: -(initBalanceAmount + 1),
+				UUID:     subUuid,
+				UserID:   users[0].ID,
+				Currency: domain.JPY,
+				Amount:   -(initBalanceAmount + 1),
+				Status:   model.PaymentStatusConfirmed,
 			},
 			want2{0, 2},
 			false,
@@ -263,6 +441,14 @@ func TestPaymentTransactionRepository_Confirm(t *testing.T) {
 			want2{0, 2},
 			true,
 		},
+		{
+			"有効期限切れのUUID",
+			fields{repo.DB},
+			args{ctx, expiredUuid},
+			nil,
+			want2{0, 2},
+			true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -274,7 +460,7 @@ func TestPaymentTransactionRepository_Confirm(t *testing.T) {
 				t.Errorf("PaymentTransactionRepository.Confirm() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			opt := cmpopts.IgnoreFields(model.PaymentTransaction{}, "TryTime", "ConfirmTime", "CancelTime")
+			opt := cmpopts.IgnoreFields(model.PaymentTransaction{}, "RequestFingerprint", "TryTime", "ExpireTime", "ConfirmTime", "CancelTime", "ExpiredTime", "ResultBalance")
 			if diff := cmp.Diff(tt.want, got, opt); diff != "" {
 				t.Errorf("PaymentTransactionRepository.Confirm() mismatch (-want +got): \n %s", diff)
 			}
@@ -282,7 +468,7 @@ func TestPaymentTransactionRepository_Confirm(t *testing.T) {
 				if got.ConfirmTime.IsZero() {
 					t.Error("PaymentTransactionRepository.Confirm() got.ConfirmTime MUST NOT IsZero")
 				}
-				b, err := findBalance(context.Background(), r.DB, got.UserID)
+				b, err := findBalance(context.Background(), r.DB, got.UserID, domain.JPY, false)
 				if err != nil {
 					t.Errorf("PaymentTransactionRepository.Confirm() findBalance error = %v", err)
 				}
@@ -304,24 +490,36 @@ func TestPaymentTransactionRepository_Cancel(t *testing.T) {
 	users := createSampleUsers(t, repo.DB, 1)
 	tryUuid := "try"
 	notTryUuid := "not try"
-	sampleAmount := 100
+	subUuid := "sub"
+	sampleAmount := int64(100)
 	createSamplePaymentTransaction(t, repo.DB,
 		&model.PaymentTransaction{
-			UUID:    tryUuid,
-			UserID:  users[0].ID,
-			Amount:  sampleAmount,
-			TryTime: time.Now(),
+			UUID:     tryUuid,
+			UserID:   users[0].ID,
+			Currency: domain.JPY,
+			Amount:   sampleAmount,
+			TryTime:  time.Now(),
 		},
 	)
 	createSamplePaymentTransaction(t, repo.DB,
 		&model.PaymentTransaction{
 			UUID:       notTryUuid,
 			UserID:     users[0].ID,
+			Currency:   domain.JPY,
 			Amount:     sampleAmount,
 			TryTime:    time.Now(),
 			CancelTime: time.Now(),
 		},
 	)
+	createSamplePaymentTransaction(t, repo.DB,
+		&model.PaymentTransaction{
+			UUID:     subUuid,
+			UserID:   users[0].ID,
+			Currency: domain.JPY,
+			Amount:   -sampleAmount,
+			TryTime:  time.Now(),
+		},
+	)
 	ctx := context.Background()
 
 	type fields struct {
@@ -331,11 +529,15 @@ func TestPaymentTransactionRepository_Cancel(t *testing.T) {
 		ctx  context.Context
 		uuid string
 	}
+	type want2 struct {
+		ReservedAmount int64
+	}
 	tests := []struct {
 		name    string
 		fields  fields
 		args    args
 		want    *model.PaymentTransaction
+		want2   want2
 		wantErr bool
 	}{
 		{
@@ -343,10 +545,13 @@ func TestPaymentTransactionRepository_Cancel(t *testing.T) {
 			fields{repo.DB},
 			args{ctx, tryUuid},
 			&model.PaymentTransaction{
-				UUID:   tryUuid,
-				UserID: users[0].ID,
-				Amount: sampleAmount,
+				UUID:     tryUuid,
+				UserID:   users[0].ID,
+				Currency: domain.JPY,
+				Amount:   sampleAmount,
+				Status:   model.PaymentStatusCancelled,
 			},
+			want2{sampleAmount},
 			false,
 		},
 		{
@@ -354,6 +559,7 @@ func TestPaymentTransactionRepository_Cancel(t *testing.T) {
 			fields{repo.DB},
 			args{ctx, notTryUuid},
 			nil,
+			want2{sampleAmount},
 			true,
 		},
 		{
@@ -361,8 +567,23 @@ func TestPaymentTransactionRepository_Cancel(t *testing.T) {
 			fields{repo.DB},
 			args{ctx, "wrong"},
 			nil,
+			want2{sampleAmount},
 			true,
 		},
+		{
+			"減算をキャンセルすると仮押さえが解放される",
+			fields{repo.DB},
+			args{ctx, subUuid},
+			&model.PaymentTransaction{
+				UUID:     subUuid,
+				UserID:   users[0].ID,
+				Currency: domain.JPY,
+				Amount:   -sampleAmount,
+				Status:   model.PaymentStatusCancelled,
+			},
+			want2{0},
+			false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -374,7 +595,7 @@ func TestPaymentTransactionRepository_Cancel(t *testing.T) {
 				t.Errorf("PaymentTransactionRepository.Cancel() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			opt := cmpopts.IgnoreFields(model.PaymentTransaction{}, "TryTime", "ConfirmTime", "CancelTime")
+			opt := cmpopts.IgnoreFields(model.PaymentTransaction{}, "RequestFingerprint", "TryTime", "ExpireTime", "ConfirmTime", "CancelTime", "ExpiredTime", "ResultBalance")
 			if diff := cmp.Diff(tt.want, got, opt); diff != "" {
 				t.Errorf("PaymentTransactionRepository.Cancel() mismatch (-want +got): \n %s", diff)
 			}
@@ -383,6 +604,153 @@ func TestPaymentTransactionRepository_Cancel(t *testing.T) {
 					t.Error("PaymentTransactionRepository.Cancel() got.CancelTime MUST NOT IsZero")
 				}
 			}
+			b, err := findBalance(context.Background(), r.DB, users[0].ID, domain.JPY, false)
+			if err != nil {
+				t.Errorf("PaymentTransactionRepository.Cancel() findBalance error = %v", err)
+			}
+			got2 := want2{ReservedAmount: b.ReservedAmount}
+			if diff2 := cmp.Diff(tt.want2, got2, nil); diff2 != "" {
+				t.Errorf("PaymentTransactionRepository.Cancel() mismatch (-want2 +got2): \n %s", diff2)
+			}
+		})
+	}
+}
+
+func TestPaymentTransactionRepository_ExpireTries(t *testing.T) {
+	repo := newPaymentTransactionRepo(t)
+	users := createSampleUsers(t, repo.DB, 2)
+	past := time.Now().Add(-time.Minute)
+	samples := []*model.PaymentTransaction{
+		// 期限切れの減算
+		{UUID: "expired sub", UserID: users[0].ID, Amount: -100, TryTime: past.Add(-time.Minute), ExpireTime: past},
+		{UUID: "expired sub2", UserID: users[1].ID, Amount: -200, TryTime: past.Add(-time.Minute), ExpireTime: past.Add(time.Second)},
+		// 期限切れの加算
+		{UUID: "expired add", UserID: users[0].ID, Amount: 100, TryTime: past.Add(-time.Minute), ExpireTime: past.Add(2 * time.Second)},
+		// 期限内の減算
+		{UUID: "alive sub", UserID: users[0].ID, Amount: -10, TryTime: time.Now(), ExpireTime: time.Now().Add(time.Hour)},
+		// Confirm済み
+		{UUID: "confirmed", UserID: users[0].ID, Amount: -1, TryTime: past.Add(-time.Minute), ExpireTime: past, ConfirmTime: past},
+	}
+	for _, pt := range samples {
+		createSamplePaymentTransaction(t, repo.DB, pt)
+	}
+	ctx := context.Background()
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx   context.Context
+		now   time.Time
+		limit int
+	}
+	type want2 struct {
+		ReservedAmounts []int64
+		ExpiredUUIDs    []string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    int
+		want2   want2
+		wantErr bool
+	}{
+		{
+			"limitの件数だけ期限切れにする",
+			fields{repo.DB},
+			args{ctx, time.Now(), 1},
+			1,
+			want2{[]int64{10, 200}, []string{"expired sub"}},
+			false,
+		},
+		{
+			"残りの期限切れのTryを期限切れにする",
+			fields{repo.DB},
+			args{ctx, time.Now(), 10},
+			2,
+			want2{[]int64{10, 0}, []string{"expired sub", "expired sub2", "expired add"}},
+			false,
+		},
+		{
+			"期限切れのTryがない",
+			fields{repo.DB},
+			args{ctx, time.Now(), 10},
+			0,
+			want2{[]int64{10, 0}, []string{"expired sub", "expired sub2", "expired add"}},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &PaymentTransactionRepository{
+				DB: tt.fields.DB,
+			}
+			got, err := r.ExpireTries(tt.args.ctx, tt.args.now, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PaymentTransactionRepository.ExpireTries() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("PaymentTransactionRepository.ExpireTries() = %v, want %v", got, tt.want)
+			}
+
+			var got2 want2
+			for _, u := range users {
+				b, err := findBalance(context.Background(), r.DB, u.ID, domain.JPY, false)
+				if err != nil {
+					t.Errorf("PaymentTransactionRepository.ExpireTries() findBalance error = %v", err)
+				}
+				got2.ReservedAmounts = append(got2.ReservedAmounts, b.ReservedAmount)
+			}
+			for _, sample := range samples {
+				pt, err := r.Get(context.Background(), sample.UUID)
+				if err != nil {
+					t.Errorf("PaymentTransactionRepository.ExpireTries() r.Get error = %v", err)
+				}
+				if pt.Status == model.PaymentStatusExpired && !pt.ExpiredTime.IsZero() {
+					got2.ExpiredUUIDs = append(got2.ExpiredUUIDs, pt.UUID)
+				}
+			}
+			if diff2 := cmp.Diff(tt.want2, got2, nil); diff2 != "" {
+				t.Errorf("PaymentTransactionRepository.ExpireTries() mismatch (-want2 +got2): \n %s", diff2)
+			}
 		})
 	}
 }
+
+func TestPaymentTransactionRepository_Currency(t *testing.T) {
+	repo := newPaymentTransactionRepo(t)
+	users := createSampleUsers(t, repo.DB, 1)
+	ctx := context.Background()
+
+	// まだ持っていない通貨のウォレットからは減算できない
+	if _, err := repo.Try(ctx, "usd sub", users[0].ID, domain.USD, -1, time.Minute); !errors.Is(err, domain.ErrShortBalance) {
+		t.Fatalf("PaymentTransactionRepository.Try() error = %v, wantErr %v", err, domain.ErrShortBalance)
+	}
+	// 加算するとウォレットが作られる
+	if _, err := repo.Try(ctx, "usd add", users[0].ID, domain.USD, 250, time.Minute); err != nil {
+		t.Fatalf("PaymentTransactionRepository.Try() error = %v", err)
+	}
+	if _, err := repo.Confirm(ctx, "usd add"); err != nil {
+		t.Fatalf("PaymentTransactionRepository.Confirm() error = %v", err)
+	}
+	if _, err := repo.Try(ctx, "usd sub2", users[0].ID, domain.USD, -50, time.Minute); err != nil {
+		t.Fatalf("PaymentTransactionRepository.Try() error = %v", err)
+	}
+
+	balances, err := NewBalanceRepository(repo.DB).List(ctx, users[0].ID)
+	if err != nil {
+		t.Fatalf("BalanceRepository.List() error = %v", err)
+	}
+	want := []*model.Balance{
+		{UserID: users[0].ID, Currency: domain.JPY, Amount: initBalanceAmount},
+		{UserID: users[0].ID, Currency: domain.USD, Amount: 250, ReservedAmount: 50},
+	}
+	if diff := cmp.Diff(want, balances); diff != "" {
+		t.Errorf("PaymentTransactionRepository.Confirm() mismatch (-want +got): \n %s", diff)
+	}
+	if err := NewLedgerRepository(repo.DB).CheckInvariants(ctx); err != nil {
+		t.Errorf("LedgerRepository.CheckInvariants() error = %v", err)
+	}
+}
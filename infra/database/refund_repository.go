@@ -0,0 +1,128 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type RefundRepository struct {
+	DB *sql.DB
+}
+
+func NewRefundRepository(db *sql.DB) *RefundRepository {
+	return &RefundRepository{DB: db}
+}
+
+func (r *RefundRepository) Get(ctx context.Context, uuid string) (*model.Refund, error) {
+	query := `
+	SELECT uuid, payment_uuid, user_id, currency, amount, create_time
+	FROM refunds WHERE uuid = ?`
+	rows, err := r.DB.QueryContext(ctx, query, uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, domain.ErrInvalidUUID
+	}
+	return rowsToRefund(rows)
+}
+
+func (r *RefundRepository) ListByPayment(ctx context.Context, paymentUUID string) ([]*model.Refund, error) {
+	query := `
+	SELECT uuid, payment_uuid, user_id, currency, amount, create_time
+	FROM refunds WHERE payment_uuid = ? ORDER BY create_time ASC, uuid ASC`
+	rows, err := r.DB.QueryContext(ctx, query, paymentUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refunds []*model.Refund
+	for rows.Next() {
+		refund, err := rowsToRefund(rows)
+		if err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, refund)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
+
+func (r *RefundRepository) Refund(ctx context.Context, uuid, paymentUUID string, amount int64, now time.Time) (*model.Refund, *model.PaymentTransaction, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// 支払いの行をロックするので、同じ支払いへの返金は直列に処理され、返金額の合計が元の額を超えることはない
+	pt, err := findPaymentTransaction(ctx, tx, paymentUUID, true)
+	if errors.Is(err, domain.ErrInvalidUUID) {
+		return nil, nil, domain.ErrNoSuchEntity
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := pt.Refund(amount); err != nil {
+		return nil, nil, err
+	}
+	refund := model.NewRefund(uuid, pt, amount, now)
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO refunds (uuid, payment_uuid, user_id, currency, amount, create_time) VALUES (?, ?, ?, ?, ?, ?)`,
+		refund.UUID, refund.PaymentUUID, refund.UserID, refund.Currency, refund.Amount, refund.CreateTime,
+	); err != nil {
+		if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1062 {
+			return nil, nil, domain.ErrDuplicateUUID
+		}
+		return nil, nil, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE payment_transactions SET status = ?, refunded_amount = ? WHERE uuid = ?`,
+		pt.Status, pt.RefundedAmount, pt.UUID,
+	); err != nil {
+		return nil, nil, err
+	}
+	// 返金は支払いと逆向きに、外部との精算勘定を相手にした仕訳として残高を加算する
+	if err := postJournalEntry(ctx, tx, refund.JournalEntry()); err != nil {
+		return nil, nil, err
+	}
+	event, err := model.NewRefundEvent(refund, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := insertOutboxEvent(ctx, tx, event); err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+	if refund, err = r.Get(ctx, uuid); err != nil {
+		return nil, nil, err
+	}
+	if pt, err = findPaymentTransaction(ctx, r.DB, paymentUUID, false); err != nil {
+		return nil, nil, err
+	}
+	return refund, pt, nil
+}
+
+func rowsToRefund(rows *sql.Rows) (*model.Refund, error) {
+	refund := &model.Refund{}
+	if err := rows.Scan(&refund.UUID, &refund.PaymentUUID, &refund.UserID, &refund.Currency, &refund.Amount, &refund.CreateTime); err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
@@ -0,0 +1,126 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+func newRefundRepo(t *testing.T) *RefundRepository {
+	t.Helper()
+	db := newTestConnection(t)
+	return NewRefundRepository(db)
+}
+
+func TestRefundRepository_Refund(t *testing.T) {
+	repo := newRefundRepo(t)
+	users := createSampleUsers(t, repo.DB, 1)
+	ctx := context.Background()
+	now := time.Now()
+	createSamplePaymentTransaction(t, repo.DB, &model.PaymentTransaction{UUID: "confirmed", UserID: users[0].ID, Amount: -500, TryTime: now, ConfirmTime: now})
+	createSamplePaymentTransaction(t, repo.DB, &model.PaymentTransaction{UUID: "credit", UserID: users[0].ID, Amount: 500, TryTime: now, ConfirmTime: now})
+	createSamplePaymentTransaction(t, repo.DB, &model.PaymentTransaction{UUID: "tried", UserID: users[0].ID, Amount: -100, TryTime: now})
+
+	tests := []struct {
+		name        string
+		uuid        string
+		paymentUUID string
+		amount      int64
+		wantStatus  model.PaymentStatus
+		wantRefund  int64 // 支払いの返金済みの額
+		wantBalance int64
+		wantErr     error
+	}{
+		{"一部を返金できる", "r1", "confirmed", 200, model.PaymentStatusPartiallyRefunded, 200, initBalanceAmount + 200, nil},
+		{"同じ冪等キー", "r1", "confirmed", 200, "", 0, 0, domain.ErrDuplicateUUID},
+		{"返金できる額を超える", "r2", "confirmed", 301, "", 0, 0, domain.ErrInvalidParam},
+		{"残りを返金すると返金済みになる", "r3", "confirmed", 300, model.PaymentStatusRefunded, 500, initBalanceAmount + 500, nil},
+		{"返金済みの支払い", "r4", "confirmed", 1, "", 0, 0, domain.ErrIllegalTransition},
+		{"加算は返金できない", "r5", "credit", 100, "", 0, 0, domain.ErrInvalidParam},
+		{"Confirmしていない支払い", "r6", "tried", 100, "", 0, 0, domain.ErrIllegalTransition},
+		{"存在しない支払い", "r7", "none", 100, "", 0, 0, domain.ErrNoSuchEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refund, pt, err := repo.Refund(ctx, tt.uuid, tt.paymentUUID, tt.amount, time.Now())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RefundRepository.Refund() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if refund.Amount != tt.amount || refund.UserID != users[0].ID || refund.PaymentUUID != tt.paymentUUID {
+				t.Errorf("RefundRepository.Refund() got = %+v", refund)
+			}
+			if pt.Status != tt.wantStatus || pt.RefundedAmount != tt.wantRefund {
+				t.Errorf("RefundRepository.Refund() status = %v, refunded = %v, want %v, %v", pt.Status, pt.RefundedAmount, tt.wantStatus, tt.wantRefund)
+			}
+			balance, err := findBalance(ctx, repo.DB, users[0].ID, domain.JPY, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if balance.Amount != tt.wantBalance {
+				t.Errorf("balance = %v, want %v", balance.Amount, tt.wantBalance)
+			}
+		})
+	}
+
+	refunds, err := repo.ListByPayment(ctx, "confirmed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(refunds) != 2 {
+		t.Errorf("RefundRepository.ListByPayment() len = %v, want 2", len(refunds))
+	}
+}
+
+func TestRefundRepository_RefundConcurrently(t *testing.T) {
+	repo := newRefundRepo(t)
+	users := createSampleUsers(t, repo.DB, 1)
+	ctx := context.Background()
+	now := time.Now()
+	createSamplePaymentTransaction(t, repo.DB, &model.PaymentTransaction{UUID: "confirmed", UserID: users[0].ID, Amount: -500, TryTime: now, ConfirmTime: now})
+
+	// 同時に返金しても、返金額の合計は元の減算額を超えない
+	const n = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, _, err := repo.Refund(ctx, fmt.Sprintf("refund%d", i), "confirmed", 100, time.Now())
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	var succeeded int
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, domain.ErrIllegalTransition):
+		default:
+			t.Fatalf("RefundRepository.Refund() error = %v", err)
+		}
+	}
+	if succeeded != 5 {
+		t.Errorf("succeeded = %d, want 5", succeeded)
+	}
+	pt, err := findPaymentTransaction(ctx, repo.DB, "confirmed", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pt.Status != model.PaymentStatusRefunded || pt.RefundedAmount != 500 {
+		t.Errorf("status = %v, refunded = %v, want %v, 500", pt.Status, pt.RefundedAmount, model.PaymentStatusRefunded)
+	}
+}
@@ -0,0 +1,164 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type SpendingLimitRepository struct {
+	DB *sql.DB
+}
+
+func NewSpendingLimitRepository(db *sql.DB) *SpendingLimitRepository {
+	return &SpendingLimitRepository{DB: db}
+}
+
+func (r *SpendingLimitRepository) Set(ctx context.Context, limit *model.SpendingLimit) (*model.SpendingLimit, error) {
+	if err := limit.Validate(); err != nil {
+		return nil, err
+	}
+	limit.UpdateTime = time.Now()
+	if _, err := r.DB.ExecContext(ctx,
+		`INSERT INTO spending_limits (scope, scope_id, currency, max_single_debit, daily_debit_limit, monthly_debit_limit, hourly_debit_count, update_time)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			max_single_debit = VALUES(max_single_debit), daily_debit_limit = VALUES(daily_debit_limit),
+			monthly_debit_limit = VALUES(monthly_debit_limit), hourly_debit_count = VALUES(hourly_debit_count),
+			update_time = VALUES(update_time)`,
+		limit.Scope, limit.ScopeID, limit.Currency, limit.MaxSingleDebit, limit.DailyDebitLimit, limit.MonthlyDebitLimit, limit.HourlyDebitCount, limit.UpdateTime,
+	); err != nil {
+		return nil, err
+	}
+	return limit, nil
+}
+
+func (r *SpendingLimitRepository) List(ctx context.Context, scope model.SpendingLimitScope, scopeID string) ([]*model.SpendingLimit, error) {
+	query := `
+	SELECT scope, scope_id, currency, max_single_debit, daily_debit_limit, monthly_debit_limit, hourly_debit_count, update_time
+	FROM spending_limits WHERE scope = ? AND scope_id = ? ORDER BY currency ASC`
+	rows, err := r.DB.QueryContext(ctx, query, scope, scopeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var limits []*model.SpendingLimit
+	for rows.Next() {
+		limit, err := rowsToSpendingLimit(rows)
+		if err != nil {
+			return nil, err
+		}
+		limits = append(limits, limit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+func (r *SpendingLimitRepository) SetUserTier(ctx context.Context, userID uint, tier string) (*model.User, error) {
+	// 同じ区分を設定した場合は更新件数が0になるので、ユーザが存在するかどうかは取得して確認する
+	if _, err := r.DB.ExecContext(ctx, `UPDATE users SET tier = ? WHERE id = ?`, tier, userID); err != nil {
+		return nil, err
+	}
+	user := &model.User{}
+	err := r.DB.QueryRowContext(ctx, `SELECT id, name, tier FROM users WHERE id = ?`, userID).Scan(&user.ID, &user.Name, &user.Tier)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNoSuchEntity
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// checkSpendingLimit checks the debit of the amount which the user tries at tryTime against the limit of the user,
+// or of the user's tier. The usage counts every debit of the user: payments, transfers sent and exchanges.
+// It includes the debit itself, so the payment, transfer or exchange must be inserted or tried before.
+// The caller must lock the row of the user so that concurrent debits of the user are checked one by one.
+func checkSpendingLimit(ctx context.Context, db dbContext, userID uint, currency domain.Currency, amount int64, tryTime time.Time) error {
+	limit, err := findEffectiveSpendingLimit(ctx, db, userID, currency)
+	if err != nil {
+		return err
+	}
+	if limit == nil {
+		return nil
+	}
+
+	// 返金された減算も、減算した時点で上限を使ったものとして集計する。
+	// ロックを取った後の最初の読み取りなので、先にコミットされた同じユーザの減算も集計に含まれる
+	w := model.NewSpendingWindows(tryTime)
+	query := `
+	SELECT
+		COALESCE(SUM(CASE WHEN debit_time >= ? THEN amount ELSE 0 END), 0),
+		COALESCE(SUM(CASE WHEN debit_time >= ? THEN amount ELSE 0 END), 0),
+		COALESCE(SUM(CASE WHEN debit_time > ? THEN 1 ELSE 0 END), 0)
+	FROM (
+		SELECT try_time AS debit_time, -amount AS amount FROM payment_transactions
+		WHERE user_id = ? AND currency = ? AND amount < 0 AND status IN (?, ?, ?, ?) AND try_time >= ?
+		UNION ALL
+		SELECT try_time, amount FROM transfers
+		WHERE from_user_id = ? AND currency = ? AND status IN (?, ?) AND try_time >= ?
+		UNION ALL
+		SELECT create_time, from_amount FROM exchanges
+		WHERE user_id = ? AND from_currency = ? AND create_time >= ?
+	) debits`
+	rows, err := db.QueryContext(ctx, query,
+		w.DayStart, w.MonthStart, w.HourStart,
+		userID, currency, model.PaymentStatusTried, model.PaymentStatusConfirmed, model.PaymentStatusPartiallyRefunded, model.PaymentStatusRefunded, w.Start(),
+		userID, currency, model.PaymentStatusTried, model.PaymentStatusConfirmed, w.Start(),
+		userID, currency, w.Start(),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var usage model.SpendingUsage
+	if rows.Next() {
+		if err := rows.Scan(&usage.DailyDebit, &usage.MonthlyDebit, &usage.HourlyDebitCount); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return limit.Check(amount, usage)
+}
+
+// findEffectiveSpendingLimit returns the limit of the user, or of the user's tier when the user has no limit.
+// It returns nil when neither has a limit in the currency.
+func findEffectiveSpendingLimit(ctx context.Context, db dbContext, userID uint, currency domain.Currency) (*model.SpendingLimit, error) {
+	query := `
+	SELECT l.scope, l.scope_id, l.currency, l.max_single_debit, l.daily_debit_limit, l.monthly_debit_limit, l.hourly_debit_count, l.update_time
+	FROM users u JOIN spending_limits l
+		ON (l.scope = ? AND l.scope_id = ?) OR (l.scope = ? AND l.scope_id = u.tier)
+	WHERE u.id = ? AND l.currency = ?
+	ORDER BY l.scope = ? DESC LIMIT 1`
+	rows, err := db.QueryContext(ctx, query,
+		model.SpendingLimitScopeUser, strconv.FormatUint(uint64(userID), 10), model.SpendingLimitScopeTier,
+		userID, currency, model.SpendingLimitScopeUser,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	return rowsToSpendingLimit(rows)
+}
+
+func rowsToSpendingLimit(rows *sql.Rows) (*model.SpendingLimit, error) {
+	limit := &model.SpendingLimit{}
+	if err := rows.Scan(&limit.Scope, &limit.ScopeID, &limit.Currency, &limit.MaxSingleDebit, &limit.DailyDebitLimit, &limit.MonthlyDebitLimit, &limit.HourlyDebitCount, &limit.UpdateTime); err != nil {
+		return nil, err
+	}
+	return limit, nil
+}
@@ -0,0 +1,388 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+func newSpendingLimitRepo(t *testing.T) *SpendingLimitRepository {
+	t.Helper()
+	db := newTestConnection(t)
+	return NewSpendingLimitRepository(db)
+}
+
+func TestSpendingLimitRepository_Try(t *testing.T) {
+	ctx := context.Background()
+	userLimit := func(userID uint, l model.SpendingLimit) *model.SpendingLimit {
+		l.Scope = model.SpendingLimitScopeUser
+		l.ScopeID = strconv.FormatUint(uint64(userID), 10)
+		l.Currency = domain.JPY
+		return &l
+	}
+	tierLimit := func(l model.SpendingLimit) *model.SpendingLimit {
+		l.Scope = model.SpendingLimitScopeTier
+		l.ScopeID = model.DefaultUserTier
+		l.Currency = domain.JPY
+		return &l
+	}
+
+	tests := []struct {
+		name    string
+		limits  func(userID uint) []*model.SpendingLimit
+		amounts []int64 // 順にTryする減算額
+		wantErr error   // 最後のTryのエラー
+	}{
+		{
+			"上限がなければ残高まで減算できる",
+			func(uint) []*model.SpendingLimit { return nil },
+			[]int64{-500, -500},
+			nil,
+		},
+		{
+			"1回の減算額の上限を超える",
+			func(id uint) []*model.SpendingLimit {
+				return []*model.SpendingLimit{userLimit(id, model.SpendingLimit{MaxSingleDebit: 300})}
+			},
+			[]int64{-301},
+			domain.ErrLimitExceeded,
+		},
+		{
+			"1日の減算額の合計がちょうど上限になる",
+			func(id uint) []*model.SpendingLimit {
+				return []*model.SpendingLimit{userLimit(id, model.SpendingLimit{DailyDebitLimit: 500})}
+			},
+			[]int64{-200, -300},
+			nil,
+		},
+		{
+			"1日の減算額の合計が上限を超える",
+			func(id uint) []*model.SpendingLimit {
+				return []*model.SpendingLimit{userLimit(id, model.SpendingLimit{DailyDebitLimit: 500})}
+			},
+			[]int64{-200, -301},
+			domain.ErrLimitExceeded,
+		},
+		{
+			"1ヶ月の減算額の合計が上限を超える",
+			func(id uint) []*model.SpendingLimit {
+				return []*model.SpendingLimit{userLimit(id, model.SpendingLimit{MonthlyDebitLimit: 100})}
+			},
+			[]int64{-100, -1},
+			domain.ErrLimitExceeded,
+		},
+		{
+			"直近1時間の減算の回数が上限を超える",
+			func(id uint) []*model.SpendingLimit {
+				return []*model.SpendingLimit{userLimit(id, model.SpendingLimit{HourlyDebitCount: 2})}
+			},
+			[]int64{-1, -1, -1},
+			domain.ErrLimitExceeded,
+		},
+		{
+			"加算は上限の対象外",
+			func(id uint) []*model.SpendingLimit {
+				return []*model.SpendingLimit{userLimit(id, model.SpendingLimit{MaxSingleDebit: 1, HourlyDebitCount: 1})}
+			},
+			[]int64{1000, 1000},
+			nil,
+		},
+		{
+			"ユーザ区分の上限が適用される",
+			func(uint) []*model.SpendingLimit {
+				return []*model.SpendingLimit{tierLimit(model.SpendingLimit{MaxSingleDebit: 100})}
+			},
+			[]int64{-101},
+			domain.ErrLimitExceeded,
+		},
+		{
+			"ユーザ個別の上限がユーザ区分の上限より優先する",
+			func(id uint) []*model.SpendingLimit {
+				return []*model.SpendingLimit{
+					tierLimit(model.SpendingLimit{MaxSingleDebit: 100}),
+					userLimit(id, model.SpendingLimit{MaxSingleDebit: 500}),
+				}
+			},
+			[]int64{-500},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newSpendingLimitRepo(t)
+			users := createSampleUsers(t, repo.DB, 1)
+			for _, l := range tt.limits(users[0].ID) {
+				if _, err := repo.Set(ctx, l); err != nil {
+					t.Fatalf("SpendingLimitRepository.Set() error = %v", err)
+				}
+			}
+			paymentRepo := NewPaymentTransactionRepository(repo.DB)
+			var err error
+			for i, amount := range tt.amounts {
+				if _, err = paymentRepo.Try(ctx, fmt.Sprintf("try%d", i), users[0].ID, domain.JPY, amount, time.Minute); err != nil && i < len(tt.amounts)-1 {
+					t.Fatalf("PaymentTransactionRepository.Try() error = %v", err)
+				}
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PaymentTransactionRepository.Try() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSpendingLimitRepository_TryConcurrently(t *testing.T) {
+	repo := newSpendingLimitRepo(t)
+	users := createSampleUsers(t, repo.DB, 1)
+	ctx := context.Background()
+	if _, err := repo.Set(ctx, &model.SpendingLimit{
+		Scope:           model.SpendingLimitScopeUser,
+		ScopeID:         strconv.FormatUint(uint64(users[0].ID), 10),
+		Currency:        domain.JPY,
+		DailyDebitLimit: 300,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 残高は足りていても、同時に減算した合計が上限を超えないこと
+	paymentRepo := NewPaymentTransactionRepository(repo.DB)
+	const n = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := paymentRepo.Try(ctx, fmt.Sprintf("try%d", i), users[0].ID, domain.JPY, -100, time.Minute)
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	var succeeded, exceeded int
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, domain.ErrLimitExceeded):
+			exceeded++
+		default:
+			t.Fatalf("PaymentTransactionRepository.Try() error = %v", err)
+		}
+	}
+	if succeeded != 3 || exceeded != n-3 {
+		t.Errorf("succeeded = %d, exceeded = %d, want 3, %d", succeeded, exceeded, n-3)
+	}
+}
+
+// setDailyDebitLimit sets the daily debit limit of the user in JPY.
+func setDailyDebitLimit(t *testing.T, repo *SpendingLimitRepository, userID uint, limit int64) {
+	t.Helper()
+	if _, err := repo.Set(context.Background(), &model.SpendingLimit{
+		Scope:           model.SpendingLimitScopeUser,
+		ScopeID:         strconv.FormatUint(uint64(userID), 10),
+		Currency:        domain.JPY,
+		DailyDebitLimit: limit,
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// countLimitExceeded runs the debits concurrently and counts the succeeded ones and the ones over the limit.
+func countLimitExceeded(t *testing.T, n int, debit func(i int) error) (succeeded, exceeded int) {
+	t.Helper()
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- debit(i)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, domain.ErrLimitExceeded):
+			exceeded++
+		default:
+			t.Fatalf("debit error = %v", err)
+		}
+	}
+	return succeeded, exceeded
+}
+
+func TestSpendingLimitRepository_TransferConcurrently(t *testing.T) {
+	repo := newSpendingLimitRepo(t)
+	users := createSampleUsers(t, repo.DB, 2)
+	ctx := context.Background()
+	setDailyDebitLimit(t, repo, users[0].ID, 300)
+
+	// 送金も同時に減算した合計が上限を超えないこと
+	transferRepo := NewTransferRepository(repo.DB)
+	const n = 10
+	succeeded, exceeded := countLimitExceeded(t, n, func(i int) error {
+		_, err := transferRepo.Try(ctx, fmt.Sprintf("transfer%d", i), users[0].ID, users[1].ID, domain.JPY, 100, time.Minute)
+		return err
+	})
+	if succeeded != 3 || exceeded != n-3 {
+		t.Errorf("succeeded = %d, exceeded = %d, want 3, %d", succeeded, exceeded, n-3)
+	}
+}
+
+func TestSpendingLimitRepository_ExchangeConcurrently(t *testing.T) {
+	repo := newSpendingLimitRepo(t)
+	users := createSampleUsers(t, repo.DB, 1)
+	ctx := context.Background()
+	now := time.Now()
+	setDailyDebitLimit(t, repo, users[0].ID, 300)
+	rates := createSampleExchangeRates(t, NewExchangeRateRepository(repo.DB),
+		&model.ExchangeRate{FromCurrency: domain.JPY, ToCurrency: domain.USD, Rate: "0.0067", Rounding: domain.RoundHalfUp, ValidFrom: now.Add(-time.Hour), ValidTo: now.Add(time.Hour)},
+	)
+
+	// 両替も同時に減算した合計が上限を超えないこと
+	exchangeRepo := NewExchangeRepository(repo.DB)
+	const n = 10
+	succeeded, exceeded := countLimitExceeded(t, n, func(i int) error {
+		_, err := exchangeRepo.Exchange(ctx, fmt.Sprintf("exchange%d", i), users[0].ID, rates[0].ID, 100, now)
+		return err
+	})
+	if succeeded != 3 || exceeded != n-3 {
+		t.Errorf("succeeded = %d, exceeded = %d, want 3, %d", succeeded, exceeded, n-3)
+	}
+}
+
+func TestSpendingLimitRepository_AllDebits(t *testing.T) {
+	repo := newSpendingLimitRepo(t)
+	users := createSampleUsers(t, repo.DB, 2)
+	ctx := context.Background()
+	now := time.Now()
+	setDailyDebitLimit(t, repo, users[0].ID, 300)
+	rates := createSampleExchangeRates(t, NewExchangeRateRepository(repo.DB),
+		&model.ExchangeRate{FromCurrency: domain.JPY, ToCurrency: domain.USD, Rate: "0.0067", Rounding: domain.RoundHalfUp, ValidFrom: now.Add(-time.Hour), ValidTo: now.Add(time.Hour)},
+	)
+	paymentRepo := NewPaymentTransactionRepository(repo.DB)
+	transferRepo := NewTransferRepository(repo.DB)
+	exchangeRepo := NewExchangeRepository(repo.DB)
+
+	// 支払い、送金、両替の減算をまとめて集計する
+	if _, err := paymentRepo.Try(ctx, "payment", users[0].ID, domain.JPY, -100, time.Minute); err != nil {
+		t.Fatalf("PaymentTransactionRepository.Try() error = %v", err)
+	}
+	if _, err := transferRepo.Try(ctx, "transfer", users[0].ID, users[1].ID, domain.JPY, 100, time.Minute); err != nil {
+		t.Fatalf("TransferRepository.Try() error = %v", err)
+	}
+	if _, err := exchangeRepo.Exchange(ctx, "exchange", users[0].ID, rates[0].ID, 100, now); err != nil {
+		t.Fatalf("ExchangeRepository.Exchange() error = %v", err)
+	}
+	if _, err := paymentRepo.Try(ctx, "payment2", users[0].ID, domain.JPY, -1, time.Minute); !errors.Is(err, domain.ErrLimitExceeded) {
+		t.Errorf("PaymentTransactionRepository.Try() error = %v, wantErr %v", err, domain.ErrLimitExceeded)
+	}
+	if _, err := transferRepo.Try(ctx, "transfer2", users[0].ID, users[1].ID, domain.JPY, 1, time.Minute); !errors.Is(err, domain.ErrLimitExceeded) {
+		t.Errorf("TransferRepository.Try() error = %v, wantErr %v", err, domain.ErrLimitExceeded)
+	}
+	if _, err := exchangeRepo.Exchange(ctx, "exchange2", users[0].ID, rates[0].ID, 1, now); !errors.Is(err, domain.ErrLimitExceeded) {
+		t.Errorf("ExchangeRepository.Exchange() error = %v, wantErr %v", err, domain.ErrLimitExceeded)
+	}
+
+	// Tryの後に上限が下げられると、送金もConfirmできない
+	setDailyDebitLimit(t, repo, users[0].ID, 200)
+	if _, err := transferRepo.Confirm(ctx, "transfer"); !errors.Is(err, domain.ErrLimitExceeded) {
+		t.Errorf("TransferRepository.Confirm() error = %v, wantErr %v", err, domain.ErrLimitExceeded)
+	}
+	setDailyDebitLimit(t, repo, users[0].ID, 300)
+	if _, err := transferRepo.Confirm(ctx, "transfer"); err != nil {
+		t.Errorf("TransferRepository.Confirm() error = %v", err)
+	}
+}
+
+func TestSpendingLimitRepository_Confirm(t *testing.T) {
+	repo := newSpendingLimitRepo(t)
+	users := createSampleUsers(t, repo.DB, 1)
+	ctx := context.Background()
+	limit := &model.SpendingLimit{
+		Scope:          model.SpendingLimitScopeUser,
+		ScopeID:        strconv.FormatUint(uint64(users[0].ID), 10),
+		Currency:       domain.JPY,
+		MaxSingleDebit: 500,
+	}
+	if _, err := repo.Set(ctx, limit); err != nil {
+		t.Fatal(err)
+	}
+	paymentRepo := NewPaymentTransactionRepository(repo.DB)
+	if _, err := paymentRepo.Try(ctx, "foo", users[0].ID, domain.JPY, -500, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	// Tryの後に上限が下げられると、Confirmできずに取引は失敗になる
+	limit.MaxSingleDebit = 400
+	if _, err := repo.Set(ctx, limit); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := paymentRepo.Confirm(ctx, "foo"); !errors.Is(err, domain.ErrLimitExceeded) {
+		t.Errorf("PaymentTransactionRepository.Confirm() error = %v, wantErr %v", err, domain.ErrLimitExceeded)
+	}
+	pt, err := paymentRepo.Get(ctx, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pt.Status != model.PaymentStatusFailed {
+		t.Errorf("PaymentTransactionRepository.Get() status = %v, want %v", pt.Status, model.PaymentStatusFailed)
+	}
+	balance, err := NewBalanceRepository(repo.DB).Get(ctx, users[0].ID, domain.JPY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.ReservedAmount != 0 {
+		t.Errorf("BalanceRepository.Get() reserved = %v, want 0", balance.ReservedAmount)
+	}
+
+	// 失敗した取引は上限を戻してもConfirmできない
+	limit.MaxSingleDebit = 0
+	if _, err := repo.Set(ctx, limit); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := paymentRepo.Confirm(ctx, "foo"); !errors.Is(err, domain.ErrIllegalTransition) {
+		t.Errorf("PaymentTransactionRepository.Confirm() error = %v, wantErr %v", err, domain.ErrIllegalTransition)
+	}
+}
+
+func TestSpendingLimitRepository_SetUserTier(t *testing.T) {
+	repo := newSpendingLimitRepo(t)
+	users := createSampleUsers(t, repo.DB, 1)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		userID  uint
+		tier    string
+		wantErr error
+	}{
+		{"変更できる", users[0].ID, "premium", nil},
+		{"同じ区分に変更できる", users[0].ID, "premium", nil},
+		{"存在しないユーザ", users[0].ID + 1, "premium", domain.ErrNoSuchEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.SetUserTier(ctx, tt.userID, tt.tier)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SpendingLimitRepository.SetUserTier() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.Tier != tt.tier {
+				t.Errorf("SpendingLimitRepository.SetUserTier() tier = %v, want %v", got.Tier, tt.tier)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kawabatas/m-bank/domain"
 	"github.com/kawabatas/m-bank/domain/model"
 )
 
@@ -91,6 +92,7 @@ func createSampleUsers(t *testing.T, db *sql.DB, count int) []*model.User {
 		user := &model.User{
 			ID:   uint(i),
 			Name: fmt.Sprintf("sample%d", i),
+			Tier: model.DefaultUserTier,
 		}
 		balance := &model.Balance{
 			UserID: uint(i),
@@ -122,13 +124,21 @@ func createSampleUsers(t *testing.T, db *sql.DB, count int) []*model.User {
 	if _, err := db.ExecContext(ctx, balanceQuery, balanceArgs...); err != nil {
 		t.Fatalf("insert balances error: %v", err)
 	}
+	// 初期残高を開始残高として仕訳しておく
+	postings := []*model.Posting{{Account: model.AccountOpeningBalance, Currency: domain.JPY, Amount: -initBalanceAmount * int64(len(balances))}}
+	for _, b := range balances {
+		postings = append(postings, &model.Posting{Account: model.UserAccount(b.UserID), Currency: domain.JPY, Amount: b.Amount})
+	}
+	if err := insertJournalEntry(ctx, db, model.NewJournalEntry(model.JournalSourceOpeningBalance, "sample", postings...)); err != nil {
+		t.Fatalf("insert journal entry error: %v", err)
+	}
 	return users
 }
 
 func createSamplePaymentTransaction(t *testing.T, db *sql.DB, pt *model.PaymentTransaction) {
 	t.Helper()
 	ctx := context.Background()
-	var confirmTime, cancelTime sql.NullTime
+	var confirmTime, cancelTime, expiredTime sql.NullTime
 	if !pt.ConfirmTime.IsZero() {
 		confirmTime.Valid = true
 		confirmTime.Time = pt.ConfirmTime
@@ -137,10 +147,79 @@ func createSamplePaymentTransaction(t *testing.T, db *sql.DB, pt *model.PaymentT
 		cancelTime.Valid = true
 		cancelTime.Time = pt.CancelTime
 	}
+	if !pt.ExpiredTime.IsZero() {
+		expiredTime.Valid = true
+		expiredTime.Time = pt.ExpiredTime
+	}
+	expireTime := pt.ExpireTime
+	if expireTime.IsZero() {
+		expireTime = pt.TryTime.Add(model.DefaultTryTTL)
+	}
+	if pt.Currency == "" {
+		pt.Currency = domain.DefaultCurrency
+	}
+	// ステータスの指定がなければ、時刻から決める
+	if pt.Status == "" {
+		switch {
+		case confirmTime.Valid:
+			pt.Status = model.PaymentStatusConfirmed
+		case cancelTime.Valid:
+			pt.Status = model.PaymentStatusCancelled
+		case expiredTime.Valid:
+			pt.Status = model.PaymentStatusExpired
+		default:
+			pt.Status = model.PaymentStatusTried
+		}
+	}
 	if _, err := db.ExecContext(ctx,
-		"INSERT INTO payment_transactions (uuid, user_id, amount, try_time, confirm_time, cancel_time) VALUES (?, ?, ?, ?, ?, ?)",
-		pt.UUID, pt.UserID, pt.Amount, pt.TryTime, confirmTime, cancelTime,
+		"INSERT INTO payment_transactions (uuid, user_id, currency, amount, request_fingerprint, status, try_time, expire_time, confirm_time, cancel_time, expired_time) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		pt.UUID, pt.UserID, pt.Currency, pt.Amount, model.RequestFingerprint(pt.UserID, pt.Amount), pt.Status, pt.TryTime, expireTime, confirmTime, cancelTime, expiredTime,
 	); err != nil {
 		t.Fatalf("insert payment_transactions error: %v", err)
 	}
+	// Try状態の減算は残高を仮押さえしている
+	if pt.IsTryStatus() && pt.Amount < 0 {
+		if _, err := db.ExecContext(ctx,
+			"UPDATE balances SET reserved_amount = reserved_amount + ? WHERE user_id = ? AND currency = ?",
+			-pt.Amount, pt.UserID, pt.Currency,
+		); err != nil {
+			t.Fatalf("update balances error: %v", err)
+		}
+	}
+}
+
+func createSampleTransfer(t *testing.T, db *sql.DB, transfer *model.Transfer) {
+	t.Helper()
+	ctx := context.Background()
+	var confirmTime, cancelTime, expiredTime sql.NullTime
+	if !transfer.ConfirmTime.IsZero() {
+		confirmTime.Valid = true
+		confirmTime.Time = transfer.ConfirmTime
+	}
+	if !transfer.CancelTime.IsZero() {
+		cancelTime.Valid = true
+		cancelTime.Time = transfer.CancelTime
+	}
+	if !transfer.ExpiredTime.IsZero() {
+		expiredTime.Valid = true
+		expiredTime.Time = transfer.ExpiredTime
+	}
+	if transfer.Currency == "" {
+		transfer.Currency = domain.DefaultCurrency
+	}
+	if _, err := db.ExecContext(ctx,
+		"INSERT INTO transfers (uuid, from_user_id, to_user_id, currency, amount, request_fingerprint, status, try_time, expire_time, confirm_time, cancel_time, expired_time) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		transfer.UUID, transfer.FromUserID, transfer.ToUserID, transfer.Currency, transfer.Amount, transfer.RequestFingerprint, transfer.Status, transfer.TryTime, transfer.ExpireTime, confirmTime, cancelTime, expiredTime,
+	); err != nil {
+		t.Fatalf("insert transfers error: %v", err)
+	}
+	// Try状態の送金は送金元の残高を仮押さえしている
+	if transfer.IsTryStatus() {
+		if _, err := db.ExecContext(ctx,
+			"UPDATE balances SET reserved_amount = reserved_amount + ? WHERE user_id = ? AND currency = ?",
+			transfer.Amount, transfer.FromUserID, transfer.Currency,
+		); err != nil {
+			t.Fatalf("update balances error: %v", err)
+		}
+	}
 }
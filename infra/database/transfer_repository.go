@@ -0,0 +1,331 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type TransferRepository struct {
+	DB *sql.DB
+}
+
+func NewTransferRepository(db *sql.DB) *TransferRepository {
+	return &TransferRepository{DB: db}
+}
+
+func (r *TransferRepository) Get(ctx context.Context, uuid string) (*model.Transfer, error) {
+	return findTransfer(ctx, r.DB, uuid, false)
+}
+
+func (r *TransferRepository) Try(ctx context.Context, uuid string, fromUserID, toUserID uint, currency domain.Currency, amount int64, ttl time.Duration) (*model.Transfer, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	t := model.NewTransfer(uuid, fromUserID, toUserID, currency, amount, ttl)
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO transfers (uuid, from_user_id, to_user_id, currency, amount, request_fingerprint, status, try_time, expire_time) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		t.UUID, t.FromUserID, t.ToUserID, t.Currency, t.Amount, t.RequestFingerprint, t.Status, t.TryTime, t.ExpireTime,
+	); err != nil {
+		if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1062 {
+			return nil, domain.ErrDuplicateUUID
+		}
+		return nil, err
+	}
+
+	// 送金元と送金先の両方のウォレットをロックし、送金元の利用可能残高と利用上限をチェックして仮押さえする
+	balances, err := lockBalances(ctx, tx, t.Currency, t.FromUserID, t.ToUserID)
+	if err != nil {
+		return nil, err
+	}
+	if balances[t.FromUserID].AvailableAmount() < t.Amount {
+		return nil, domain.ErrShortBalance
+	}
+	if err := checkSpendingLimit(ctx, tx, t.FromUserID, t.Currency, t.Amount, t.TryTime); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE balances SET reserved_amount = reserved_amount + ? WHERE user_id = ? AND currency = ?`,
+		t.Amount, t.FromUserID, t.Currency,
+	); err != nil {
+		return nil, err
+	}
+	if err := saveTransferResultBalances(ctx, tx, t); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return findTransfer(ctx, r.DB, uuid, false)
+}
+
+func (r *TransferRepository) Confirm(ctx context.Context, uuid string) (*model.Transfer, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	t, err := findTransfer(ctx, tx, uuid, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Confirm(time.Now()); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE transfers SET status = ?, confirm_time = ? WHERE uuid = ?`,
+		t.Status, t.ConfirmTime, t.UUID,
+	); err != nil {
+		return nil, err
+	}
+
+	// 両方の残高をuser_id順にロックしてから、同じトランザクション内で加減算する。
+	// Tryの後に利用上限が下げられていることがあるので、上限をもう一度チェックする
+	if _, err := lockBalances(ctx, tx, t.Currency, t.FromUserID, t.ToUserID); err != nil {
+		return nil, err
+	}
+	if err := checkSpendingLimit(ctx, tx, t.FromUserID, t.Currency, t.Amount, t.TryTime); err != nil {
+		return nil, err
+	}
+	// Tryで仮押さえしていた分を解放し、送金元から送金先への仕訳として確定する
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE balances SET reserved_amount = reserved_amount - ? WHERE user_id = ? AND currency = ?`,
+		t.Amount, t.FromUserID, t.Currency,
+	); err != nil {
+		return nil, err
+	}
+	entry := model.NewJournalEntry(model.JournalSourceTransfer, t.UUID,
+		&model.Posting{Account: model.UserAccount(t.FromUserID), Currency: t.Currency, Amount: -t.Amount},
+		&model.Posting{Account: model.UserAccount(t.ToUserID), Currency: t.Currency, Amount: t.Amount},
+	)
+	if err := postJournalEntry(ctx, tx, entry); err != nil {
+		return nil, err
+	}
+	if err := saveTransferResultBalances(ctx, tx, t); err != nil {
+		return nil, err
+	}
+	if err := insertTransferEvent(ctx, tx, t, t.ConfirmTime); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// 再取得
+	return findTransfer(ctx, r.DB, uuid, false)
+}
+
+func (r *TransferRepository) Cancel(ctx context.Context, uuid string) (*model.Transfer, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	t, err := findTransfer(ctx, tx, uuid, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Cancel(time.Now()); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE transfers SET status = ?, cancel_time = ? WHERE uuid = ?`,
+		t.Status, t.CancelTime, t.UUID,
+	); err != nil {
+		return nil, err
+	}
+	// Tryで仮押さえしていた分を解放する
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE balances SET reserved_amount = reserved_amount - ? WHERE user_id = ? AND currency = ?`,
+		t.Amount, t.FromUserID, t.Currency,
+	); err != nil {
+		return nil, err
+	}
+	if err := saveTransferResultBalances(ctx, tx, t); err != nil {
+		return nil, err
+	}
+	if err := insertTransferEvent(ctx, tx, t, t.CancelTime); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// 再取得
+	return findTransfer(ctx, r.DB, uuid, false)
+}
+
+func (r *TransferRepository) ExpireTries(ctx context.Context, now time.Time, limit int) (int, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// 他のトランザクションがConfirm/Cancel中の行はスキップする
+	query := `
+	SELECT
+		uuid, from_user_id, to_user_id, currency, amount, request_fingerprint, status,
+		try_time, expire_time, confirm_time, cancel_time, expired_time,
+		from_balance_amount, from_balance_reserved_amount, from_balance_overdraft_limit,
+		to_balance_amount, to_balance_reserved_amount, to_balance_overdraft_limit
+	FROM transfers
+	WHERE status = ? AND expire_time <= ?
+	ORDER BY expire_time ASC LIMIT ? FOR UPDATE SKIP LOCKED`
+	rows, err := tx.QueryContext(ctx, query, model.PaymentStatusTried, now, limit)
+	if err != nil {
+		return 0, err
+	}
+	var transfers []*model.Transfer
+	for rows.Next() {
+		t, err := rowsToTransfer(rows)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if err := t.Expire(now); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		transfers = append(transfers, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(transfers) == 0 {
+		return 0, nil
+	}
+
+	// 仮押さえの解放
+	reserved := map[model.WalletKey]int64{}
+	for _, t := range transfers {
+		reserved[model.WalletKey{UserID: t.FromUserID, Currency: t.Currency}] += t.Amount
+	}
+	if err := releaseReserved(ctx, tx, reserved); err != nil {
+		return 0, err
+	}
+
+	args := make([]interface{}, 0, len(transfers)+2)
+	args = append(args, model.PaymentStatusExpired, now)
+	for _, t := range transfers {
+		args = append(args, t.UUID)
+	}
+	updateQuery := "UPDATE transfers SET status = ?, expired_time = ? WHERE uuid IN (?" + strings.Repeat(",?", len(transfers)-1) + ")"
+	if _, err := tx.ExecContext(ctx, updateQuery, args...); err != nil {
+		return 0, err
+	}
+	for _, t := range transfers {
+		if err := insertTransferEvent(ctx, tx, t, now); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(transfers), nil
+}
+
+func findTransfer(ctx context.Context, db dbContext, uuid string, withLock bool) (*model.Transfer, error) {
+	query := `
+	SELECT
+		uuid, from_user_id, to_user_id, currency, amount, request_fingerprint, status,
+		try_time, expire_time, confirm_time, cancel_time, expired_time,
+		from_balance_amount, from_balance_reserved_amount, from_balance_overdraft_limit,
+		to_balance_amount, to_balance_reserved_amount, to_balance_overdraft_limit
+	FROM transfers WHERE uuid = ?`
+	if withLock {
+		query = query + ` FOR UPDATE`
+	}
+	rows, err := db.QueryContext(ctx, query, uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, domain.ErrInvalidUUID
+	}
+	return rowsToTransfer(rows)
+}
+
+func rowsToTransfer(rows *sql.Rows) (*model.Transfer, error) {
+	t := &model.Transfer{}
+	var confirmTime, cancelTime, expiredTime sql.NullTime
+	var fromAmount, fromReservedAmount, fromOverdraftLimit, toAmount, toReservedAmount, toOverdraftLimit sql.NullInt64
+	if err := rows.Scan(
+		&t.UUID, &t.FromUserID, &t.ToUserID, &t.Currency, &t.Amount, &t.RequestFingerprint, &t.Status, &t.TryTime, &t.ExpireTime, &confirmTime, &cancelTime, &expiredTime,
+		&fromAmount, &fromReservedAmount, &fromOverdraftLimit, &toAmount, &toReservedAmount, &toOverdraftLimit,
+	); err != nil {
+		return nil, err
+	}
+	if fromAmount.Valid && toAmount.Valid {
+		t.FromResultBalance = &model.Balance{
+			UserID:         t.FromUserID,
+			Currency:       t.Currency,
+			Amount:         fromAmount.Int64,
+			ReservedAmount: fromReservedAmount.Int64,
+			OverdraftLimit: fromOverdraftLimit.Int64,
+		}
+		t.ToResultBalance = &model.Balance{
+			UserID:         t.ToUserID,
+			Currency:       t.Currency,
+			Amount:         toAmount.Int64,
+			ReservedAmount: toReservedAmount.Int64,
+			OverdraftLimit: toOverdraftLimit.Int64,
+		}
+	}
+	if confirmTime.Valid {
+		t.ConfirmTime = confirmTime.Time
+	}
+	if cancelTime.Valid {
+		t.CancelTime = cancelTime.Time
+	}
+	if expiredTime.Valid {
+		t.ExpiredTime = expiredTime.Time
+	}
+	return t, nil
+}
+
+// saveTransferResultBalances saves the wallets of both users as changed in the transaction, which the retries of the request return.
+func saveTransferResultBalances(ctx context.Context, db dbContext, t *model.Transfer) error {
+	from, err := findBalance(ctx, db, t.FromUserID, t.Currency, false)
+	if err != nil {
+		return err
+	}
+	to, err := findBalance(ctx, db, t.ToUserID, t.Currency, false)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx,
+		`UPDATE transfers SET
+			from_balance_amount = ?, from_balance_reserved_amount = ?, from_balance_overdraft_limit = ?,
+			to_balance_amount = ?, to_balance_reserved_amount = ?, to_balance_overdraft_limit = ?
+		WHERE uuid = ?`,
+		from.Amount, from.ReservedAmount, from.OverdraftLimit,
+		to.Amount, to.ReservedAmount, to.OverdraftLimit,
+		t.UUID,
+	)
+	return err
+}
@@ -0,0 +1,379 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+func newTransferRepo(t *testing.T) *TransferRepository {
+	t.Helper()
+	db := newTestConnection(t)
+	return NewTransferRepository(db)
+}
+
+func TestTransferRepository_Try(t *testing.T) {
+	repo := newTransferRepo(t)
+	users := createSampleUsers(t, repo.DB, 2)
+	ctx := context.Background()
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx        context.Context
+		uuid       string
+		fromUserID uint
+		toUserID   uint
+		amount     int64
+	}
+	type want2 struct {
+		ReservedAmounts []int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *model.Transfer
+		want2   want2
+		wantErr bool
+	}{
+		{
+			"送金元の残高を仮押さえできる",
+			fields{repo.DB},
+			args{ctx, "foo", users[0].ID, users[1].ID, 400},
+			&model.Transfer{
+				UUID:              "foo",
+				FromUserID:        users[0].ID,
+				ToUserID:          users[1].ID,
+				Currency:          domain.JPY,
+				Amount:            400,
+				Status:            model.PaymentStatusTried,
+				FromResultBalance: &model.Balance{UserID: users[0].ID, Currency: domain.JPY, Amount: initBalanceAmount, ReservedAmount: 400},
+				ToResultBalance:   &model.Balance{UserID: users[1].ID, Currency: domain.JPY, Amount: initBalanceAmount},
+			},
+			want2{[]int64{400, 0}},
+			false,
+		},
+		{
+			"UUIDの重複",
+			fields{repo.DB},
+			args{ctx, "foo", users[0].ID, users[1].ID, 400},
+			nil,
+			want2{[]int64{400, 0}},
+			true,
+		},
+		{
+			"仮押さえ分を除いた残高が足りない",
+			fields{repo.DB},
+			args{ctx, "short", users[0].ID, users[1].ID, initBalanceAmount - 400 + 1},
+			nil,
+			want2{[]int64{400, 0}},
+			true,
+		},
+		{
+			"逆向きの送金も仮押さえできる",
+			fields{repo.DB},
+			args{ctx, "reverse", users[1].ID, users[0].ID, initBalanceAmount},
+			&model.Transfer{
+				UUID:              "reverse",
+				FromUserID:        users[1].ID,
+				ToUserID:          users[0].ID,
+				Currency:          domain.JPY,
+				Amount:            initBalanceAmount,
+				Status:            model.PaymentStatusTried,
+				FromResultBalance: &model.Balance{UserID: users[1].ID, Currency: domain.JPY, Amount: initBalanceAmount, ReservedAmount: initBalanceAmount},
+				ToResultBalance:   &model.Balance{UserID: users[0].ID, Currency: domain.JPY, Amount: initBalanceAmount, ReservedAmount: 400},
+			},
+			want2{[]int64{400, initBalanceAmount}},
+			false,
+		},
+		{
+			"存在しない送金先",
+			fields{repo.DB},
+			args{ctx, "unknown", users[0].ID, 999, 1},
+			nil,
+			want2{[]int64{400, initBalanceAmount}},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &TransferRepository{
+				DB: tt.fields.DB,
+			}
+			got, err := r.Try(tt.args.ctx, tt.args.uuid, tt.args.fromUserID, tt.args.toUserID, domain.JPY, tt.args.amount, 0)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TransferRepository.Try() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			opt := cmpopts.IgnoreFields(model.Transfer{}, "RequestFingerprint", "TryTime", "ExpireTime", "ConfirmTime", "CancelTime", "ExpiredTime")
+			if diff := cmp.Diff(tt.want, got, opt); diff != "" {
+				t.Errorf("TransferRepository.Try() mismatch (-want +got): \n %s", diff)
+			}
+			if got != nil {
+				if !got.MatchesRequest(tt.args.fromUserID, tt.args.toUserID, domain.JPY, tt.args.amount) {
+					t.Error("TransferRepository.Try() got.RequestFingerprint MUST match the request")
+				}
+			}
+			var got2 want2
+			for _, u := range users {
+				b, err := findBalance(context.Background(), r.DB, u.ID, domain.JPY, false)
+				if err != nil {
+					t.Errorf("TransferRepository.Try() findBalance error = %v", err)
+				}
+				got2.ReservedAmounts = append(got2.ReservedAmounts, b.ReservedAmount)
+			}
+			if diff2 := cmp.Diff(tt.want2, got2, nil); diff2 != "" {
+				t.Errorf("TransferRepository.Try() mismatch (-want2 +got2): \n %s", diff2)
+			}
+		})
+	}
+}
+
+func TestTransferRepository_Confirm(t *testing.T) {
+	repo := newTransferRepo(t)
+	users := createSampleUsers(t, repo.DB, 2)
+	tried := model.NewTransfer("tried", users[0].ID, users[1].ID, domain.JPY, 300, 0)
+	expired := model.NewTransfer("expired", users[0].ID, users[1].ID, domain.JPY, 1, 0)
+	expired.ExpireTime = time.Now().Add(-time.Minute)
+	cancelled := model.NewTransfer("cancelled", users[0].ID, users[1].ID, domain.JPY, 1, 0)
+	_ = cancelled.Cancel(time.Now())
+	for _, transfer := range []*model.Transfer{tried, expired, cancelled} {
+		createSampleTransfer(t, repo.DB, transfer)
+	}
+	ctx := context.Background()
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx  context.Context
+		uuid string
+	}
+	type want2 struct {
+		Amounts         []int64
+		ReservedAmounts []int64
+		LogCounts       []int
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *model.Transfer
+		want2   want2
+		wantErr bool
+	}{
+		{
+			"送金元から減算し送金先に加算する",
+			fields{repo.DB},
+			args{ctx, tried.UUID},
+			&model.Transfer{
+				UUID:              tried.UUID,
+				FromUserID:        users[0].ID,
+				ToUserID:          users[1].ID,
+				Currency:          domain.JPY,
+				Amount:            300,
+				Status:            model.PaymentStatusConfirmed,
+				FromResultBalance: &model.Balance{UserID: users[0].ID, Currency: domain.JPY, Amount: initBalanceAmount - 300, ReservedAmount: 1},
+				ToResultBalance:   &model.Balance{UserID: users[1].ID, Currency: domain.JPY, Amount: initBalanceAmount + 300},
+			},
+			want2{[]int64{initBalanceAmount - 300, initBalanceAmount + 300}, []int64{1, 0}, []int{1, 1}},
+			false,
+		},
+		{
+			"確定済みのUUID",
+			fields{repo.DB},
+			args{ctx, tried.UUID},
+			nil,
+			want2{[]int64{initBalanceAmount - 300, initBalanceAmount + 300}, []int64{1, 0}, []int{1, 1}},
+			true,
+		},
+		{
+			"有効期限切れのUUID",
+			fields{repo.DB},
+			args{ctx, expired.UUID},
+			nil,
+			want2{[]int64{initBalanceAmount - 300, initBalanceAmount + 300}, []int64{1, 0}, []int{1, 1}},
+			true,
+		},
+		{
+			"キャンセル済みのUUID",
+			fields{repo.DB},
+			args{ctx, cancelled.UUID},
+			nil,
+			want2{[]int64{initBalanceAmount - 300, initBalanceAmount + 300}, []int64{1, 0}, []int{1, 1}},
+			true,
+		},
+		{
+			"存在しないUUID",
+			fields{repo.DB},
+			args{ctx, "wrong"},
+			nil,
+			want2{[]int64{initBalanceAmount - 300, initBalanceAmount + 300}, []int64{1, 0}, []int{1, 1}},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &TransferRepository{
+				DB: tt.fields.DB,
+			}
+			got, err := r.Confirm(tt.args.ctx, tt.args.uuid)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TransferRepository.Confirm() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			opt := cmpopts.IgnoreFields(model.Transfer{}, "RequestFingerprint", "TryTime", "ExpireTime", "ConfirmTime", "CancelTime", "ExpiredTime")
+			if diff := cmp.Diff(tt.want, got, opt); diff != "" {
+				t.Errorf("TransferRepository.Confirm() mismatch (-want +got): \n %s", diff)
+			}
+			if got != nil && got.ConfirmTime.IsZero() {
+				t.Error("TransferRepository.Confirm() got.ConfirmTime MUST NOT IsZero")
+			}
+			var got2 want2
+			for _, u := range users {
+				b, err := findBalance(context.Background(), r.DB, u.ID, domain.JPY, false)
+				if err != nil {
+					t.Errorf("TransferRepository.Confirm() findBalance error = %v", err)
+				}
+				c, err := countBalanceLog(context.Background(), r.DB, u.ID)
+				if err != nil {
+					t.Errorf("TransferRepository.Confirm() countBalanceLog error = %v", err)
+				}
+				got2.Amounts = append(got2.Amounts, b.Amount)
+				got2.ReservedAmounts = append(got2.ReservedAmounts, b.ReservedAmount)
+				got2.LogCounts = append(got2.LogCounts, c)
+			}
+			if diff2 := cmp.Diff(tt.want2, got2, nil); diff2 != "" {
+				t.Errorf("TransferRepository.Confirm() mismatch (-want2 +got2): \n %s", diff2)
+			}
+		})
+	}
+}
+
+func TestTransferRepository_Cancel(t *testing.T) {
+	repo := newTransferRepo(t)
+	users := createSampleUsers(t, repo.DB, 2)
+	tried := model.NewTransfer("tried", users[0].ID, users[1].ID, domain.JPY, 300, 0)
+	createSampleTransfer(t, repo.DB, tried)
+	ctx := context.Background()
+
+	type fields struct {
+		DB *sql.DB
+	}
+	type args struct {
+		ctx  context.Context
+		uuid string
+	}
+	type want2 struct {
+		ReservedAmount int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *model.Transfer
+		want2   want2
+		wantErr bool
+	}{
+		{
+			"キャンセルすると仮押さえが解放される",
+			fields{repo.DB},
+			args{ctx, tried.UUID},
+			&model.Transfer{
+				UUID:              tried.UUID,
+				FromUserID:        users[0].ID,
+				ToUserID:          users[1].ID,
+				Currency:          domain.JPY,
+				Amount:            300,
+				Status:            model.PaymentStatusCancelled,
+				FromResultBalance: &model.Balance{UserID: users[0].ID, Currency: domain.JPY, Amount: initBalanceAmount},
+				ToResultBalance:   &model.Balance{UserID: users[1].ID, Currency: domain.JPY, Amount: initBalanceAmount},
+			},
+			want2{0},
+			false,
+		},
+		{
+			"キャンセル済みのUUID",
+			fields{repo.DB},
+			args{ctx, tried.UUID},
+			nil,
+			want2{0},
+			true,
+		},
+		{
+			"存在しないUUID",
+			fields{repo.DB},
+			args{ctx, "wrong"},
+			nil,
+			want2{0},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &TransferRepository{
+				DB: tt.fields.DB,
+			}
+			got, err := r.Cancel(tt.args.ctx, tt.args.uuid)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TransferRepository.Cancel() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			opt := cmpopts.IgnoreFields(model.Transfer{}, "RequestFingerprint", "TryTime", "ExpireTime", "ConfirmTime", "CancelTime", "ExpiredTime")
+			if diff := cmp.Diff(tt.want, got, opt); diff != "" {
+				t.Errorf("TransferRepository.Cancel() mismatch (-want +got): \n %s", diff)
+			}
+			b, err := findBalance(context.Background(), r.DB, users[0].ID, domain.JPY, false)
+			if err != nil {
+				t.Errorf("TransferRepository.Cancel() findBalance error = %v", err)
+			}
+			got2 := want2{ReservedAmount: b.ReservedAmount}
+			if diff2 := cmp.Diff(tt.want2, got2, nil); diff2 != "" {
+				t.Errorf("TransferRepository.Cancel() mismatch (-want2 +got2): \n %s", diff2)
+			}
+		})
+	}
+}
+
+func TestTransferRepository_ExpireTries(t *testing.T) {
+	repo := newTransferRepo(t)
+	users := createSampleUsers(t, repo.DB, 2)
+	expired := model.NewTransfer("expired", users[0].ID, users[1].ID, domain.JPY, 100, 0)
+	expired.ExpireTime = time.Now().Add(-time.Minute)
+	alive := model.NewTransfer("alive", users[0].ID, users[1].ID, domain.JPY, 10, time.Hour)
+	for _, transfer := range []*model.Transfer{expired, alive} {
+		createSampleTransfer(t, repo.DB, transfer)
+	}
+	ctx := context.Background()
+
+	r := &TransferRepository{DB: repo.DB}
+	got, err := r.ExpireTries(ctx, time.Now(), 10)
+	if err != nil {
+		t.Fatalf("TransferRepository.ExpireTries() error = %v", err)
+	}
+	if got != 1 {
+		t.Errorf("TransferRepository.ExpireTries() = %v, want %v", got, 1)
+	}
+	b, err := findBalance(ctx, r.DB, users[0].ID, domain.JPY, false)
+	if err != nil {
+		t.Fatalf("TransferRepository.ExpireTries() findBalance error = %v", err)
+	}
+	if b.ReservedAmount != alive.Amount {
+		t.Errorf("TransferRepository.ExpireTries() ReservedAmount = %v, want %v", b.ReservedAmount, alive.Amount)
+	}
+	transfer, err := r.Get(ctx, expired.UUID)
+	if err != nil {
+		t.Fatalf("TransferRepository.ExpireTries() r.Get error = %v", err)
+	}
+	if transfer.Status != model.PaymentStatusExpired {
+		t.Errorf("TransferRepository.ExpireTries() Status = %v, want %v", transfer.Status, model.PaymentStatusExpired)
+	}
+}
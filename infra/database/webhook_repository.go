@@ -0,0 +1,241 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type WebhookRepository struct {
+	DB *sql.DB
+}
+
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{DB: db}
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, subscription *model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	res, err := tx.ExecContext(ctx,
+		"INSERT INTO webhook_subscriptions (url, event_types, secret, create_time) VALUES (?, ?, ?, ?)",
+		subscription.URL, joinEventTypes(subscription.EventTypes), subscription.Secret, subscription.CreateTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	if len(subscription.UserIDs) > 0 {
+		args := make([]interface{}, 0, len(subscription.UserIDs)*2)
+		for _, userID := range subscription.UserIDs {
+			args = append(args, id, userID)
+		}
+		insertQuery := "INSERT INTO webhook_subscription_users (subscription_id, user_id) VALUES (?, ?)" + strings.Repeat(", (?, ?)", len(subscription.UserIDs)-1)
+		if _, err := tx.ExecContext(ctx, insertQuery, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.Get(ctx, uint64(id))
+}
+
+func (r *WebhookRepository) Get(ctx context.Context, id uint64) (*model.WebhookSubscription, error) {
+	subscription := &model.WebhookSubscription{}
+	var eventTypes string
+	err := r.DB.QueryRowContext(ctx,
+		"SELECT id, url, event_types, secret, create_time FROM webhook_subscriptions WHERE id = ?", id,
+	).Scan(&subscription.ID, &subscription.URL, &eventTypes, &subscription.Secret, &subscription.CreateTime)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNoSuchEntity
+	}
+	if err != nil {
+		return nil, err
+	}
+	subscription.EventTypes = splitEventTypes(eventTypes)
+	subscription.UserIDs, err = queryUserIDs(ctx, r.DB, "SELECT user_id FROM webhook_subscription_users WHERE subscription_id = ? ORDER BY user_id ASC", id)
+	if err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+const selectWebhookDeliveryColumns = `
+	d.id, d.subscription_id, d.event_sequence, d.event_type, d.status, d.attempts, d.next_attempt_time,
+	d.last_status_code, d.last_error, d.create_time, d.deliver_time`
+
+func (r *WebhookRepository) ClaimPendingDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.WebhookDelivery, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// 他のディスパッチャーが取得中の行はスキップする
+	query := `
+	SELECT` + selectWebhookDeliveryColumns + `,
+		s.id, s.url, s.event_types, s.secret, s.create_time,
+		e.sequence, e.event_type, e.payload, e.status, e.attempts, e.next_attempt_time, e.last_error, e.create_time, e.publish_time
+	FROM webhook_deliveries d
+	JOIN webhook_subscriptions s ON s.id = d.subscription_id
+	JOIN outbox_events e ON e.sequence = d.event_sequence
+	WHERE d.status = ? AND d.next_attempt_time <= ?
+	ORDER BY d.next_attempt_time ASC, d.id ASC LIMIT ? FOR UPDATE OF d SKIP LOCKED`
+	rows, err := tx.QueryContext(ctx, query, model.WebhookDeliveryStatusPending, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	var deliveries []*model.WebhookDelivery
+	for rows.Next() {
+		d := &model.WebhookDelivery{Subscription: &model.WebhookSubscription{}, Event: &model.OutboxEvent{}}
+		var deliverTime, publishTime sql.NullTime
+		var eventTypes string
+		if err := rows.Scan(
+			&d.ID, &d.SubscriptionID, &d.EventSequence, &d.EventType, &d.Status, &d.Attempts, &d.NextAttemptTime,
+			&d.LastStatusCode, &d.LastError, &d.CreateTime, &deliverTime,
+			&d.Subscription.ID, &d.Subscription.URL, &eventTypes, &d.Subscription.Secret, &d.Subscription.CreateTime,
+			&d.Event.Sequence, &d.Event.Type, &d.Event.Payload, &d.Event.Status, &d.Event.Attempts, &d.Event.NextAttemptTime,
+			&d.Event.LastError, &d.Event.CreateTime, &publishTime,
+		); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if deliverTime.Valid {
+			d.DeliverTime = deliverTime.Time
+		}
+		if publishTime.Valid {
+			d.Event.PublishTime = publishTime.Time
+		}
+		d.Subscription.EventTypes = splitEventTypes(eventTypes)
+		deliveries = append(deliveries, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, nil
+	}
+
+	// 配信の結果が保存されないまま期限を過ぎた配信（ディスパッチャーが落ちた場合など）は、もう一度送る
+	nextAttemptTime := now.Add(lease)
+	args := make([]interface{}, 0, len(deliveries)+1)
+	args = append(args, nextAttemptTime)
+	for _, d := range deliveries {
+		d.NextAttemptTime = nextAttemptTime
+		args = append(args, d.ID)
+	}
+	updateQuery := "UPDATE webhook_deliveries SET next_attempt_time = ? WHERE id IN (?" + strings.Repeat(",?", len(deliveries)-1) + ")"
+	if _, err := tx.ExecContext(ctx, updateQuery, args...); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	_, err := r.DB.ExecContext(ctx,
+		`UPDATE webhook_deliveries SET status = ?, attempts = ?, next_attempt_time = ?, last_status_code = ?, last_error = ?, deliver_time = ? WHERE id = ?`,
+		delivery.Status, delivery.Attempts, delivery.NextAttemptTime, delivery.LastStatusCode, truncateMessage(delivery.LastError), nullTime(delivery.DeliverTime), delivery.ID,
+	)
+	return err
+}
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, subscriptionID, beforeID uint64, limit int) ([]*model.WebhookDelivery, error) {
+	query := `SELECT` + selectWebhookDeliveryColumns + ` FROM webhook_deliveries d WHERE d.subscription_id = ?`
+	args := []interface{}{subscriptionID}
+	if beforeID > 0 {
+		query += ` AND d.id < ?`
+		args = append(args, beforeID)
+	}
+	query += ` ORDER BY d.id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*model.WebhookDelivery
+	for rows.Next() {
+		d := &model.WebhookDelivery{}
+		var deliverTime sql.NullTime
+		if err := rows.Scan(
+			&d.ID, &d.SubscriptionID, &d.EventSequence, &d.EventType, &d.Status, &d.Attempts, &d.NextAttemptTime,
+			&d.LastStatusCode, &d.LastError, &d.CreateTime, &deliverTime,
+		); err != nil {
+			return nil, err
+		}
+		if deliverTime.Valid {
+			d.DeliverTime = deliverTime.Time
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// enqueueWebhookDeliveries creates a pending delivery of the event for each subscription to its type and one of its users,
+// in the DB transaction which records the event. The deliveries are retried on their own, apart from the relay to OUTBOX_PUBLISHER.
+func enqueueWebhookDeliveries(ctx context.Context, db dbContext, event *model.OutboxEvent) error {
+	// 複数のユーザの一斉加算などのイベントはWebhookでは購読できない
+	if !event.Type.IsUserEvent() || len(event.UserIDs) == 0 {
+		return nil
+	}
+	args := []interface{}{event.Sequence, event.Type, model.WebhookDeliveryStatusPending, event.CreateTime, event.CreateTime, event.Type}
+	for _, userID := range event.UserIDs {
+		args = append(args, userID)
+	}
+	_, err := db.ExecContext(ctx,
+		`INSERT INTO webhook_deliveries (subscription_id, event_sequence, event_type, status, next_attempt_time, create_time)
+		SELECT DISTINCT s.id, ?, ?, ?, ?, ? FROM webhook_subscriptions s
+		JOIN webhook_subscription_users u ON u.subscription_id = s.id
+		WHERE FIND_IN_SET(?, s.event_types) AND u.user_id IN (?`+strings.Repeat(",?", len(event.UserIDs)-1)+`)`,
+		args...,
+	)
+	return err
+}
+
+// webhook_subscriptions.event_types は昇順のイベントの種類のカンマ区切り
+func joinEventTypes(eventTypes []model.OutboxEventType) string {
+	types := make([]string, 0, len(eventTypes))
+	for _, t := range eventTypes {
+		types = append(types, string(t))
+	}
+	return strings.Join(types, ",")
+}
+
+func splitEventTypes(s string) []model.OutboxEventType {
+	if s == "" {
+		return nil
+	}
+	types := strings.Split(s, ",")
+	eventTypes := make([]model.OutboxEventType, 0, len(types))
+	for _, t := range types {
+		eventTypes = append(eventTypes, model.OutboxEventType(t))
+	}
+	return eventTypes
+}
@@ -0,0 +1,196 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+func newWebhookRepo(t *testing.T) *WebhookRepository {
+	t.Helper()
+	db := newTestConnection(t)
+	return NewWebhookRepository(db)
+}
+
+func createSampleWebhook(t *testing.T, repo *WebhookRepository, userIDs []uint, eventTypes ...model.OutboxEventType) *model.WebhookSubscription {
+	t.Helper()
+	subscription, err := model.NewWebhookSubscription("https://example.com/hook", eventTypes, userIDs, "secret", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := repo.Create(context.Background(), subscription)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return created
+}
+
+func TestWebhookRepository_Create(t *testing.T) {
+	repo := newWebhookRepo(t)
+	ctx := context.Background()
+	created := createSampleWebhook(t, repo, []uint{2, 1}, model.EventPaymentConfirmed, model.EventPaymentCancelled)
+
+	got, err := repo.Get(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.URL != "https://example.com/hook" || got.Secret != "secret" || len(got.EventTypes) != 2 ||
+		got.EventTypes[0] != model.EventPaymentCancelled || got.EventTypes[1] != model.EventPaymentConfirmed ||
+		len(got.UserIDs) != 2 || got.UserIDs[0] != 1 || got.UserIDs[1] != 2 {
+		t.Errorf("WebhookRepository.Get() got = %+v", got)
+	}
+	if _, err := repo.Get(ctx, created.ID+1); !errors.Is(err, domain.ErrNoSuchEntity) {
+		t.Errorf("WebhookRepository.Get() error = %v, want %v", err, domain.ErrNoSuchEntity)
+	}
+}
+
+func Test_enqueueWebhookDeliveries(t *testing.T) {
+	repo := newWebhookRepo(t)
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	payments := createSampleWebhook(t, repo, []uint{1, 2}, model.EventPaymentConfirmed, model.EventPaymentCancelled)
+	cancellations := createSampleWebhook(t, repo, []uint{3}, model.EventPaymentCancelled)
+
+	// イベントを記録すると、同じトランザクションで種類とユーザが一致する購読にだけ配信が作られる
+	var confirmed *model.OutboxEvent
+	for _, pt := range []*model.PaymentTransaction{
+		{UUID: "foo", UserID: 1, Currency: domain.JPY, Amount: -100, Status: model.PaymentStatusConfirmed},
+		{UUID: "bar", UserID: 3, Currency: domain.JPY, Amount: -100, Status: model.PaymentStatusConfirmed},
+		{UUID: "baz", UserID: 3, Currency: domain.JPY, Amount: -100, Status: model.PaymentStatusCancelled},
+		{UUID: "qux", UserID: 4, Currency: domain.JPY, Amount: -100, Status: model.PaymentStatusCancelled},
+	} {
+		event, err := model.NewPaymentEvent(pt, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := insertOutboxEvent(ctx, repo.DB, event); err != nil {
+			t.Fatal(err)
+		}
+		if pt.UUID == "foo" {
+			confirmed = event
+		}
+	}
+	// 複数のユーザのイベントはWebhookでは配信しない
+	added, err := model.NewAddToUsersEvent(domain.JPY, 100, []uint{1, 2, 3}, time.Time{}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := insertOutboxEvent(ctx, repo.DB, added); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		subscription *model.WebhookSubscription
+		want         int
+	}{
+		{"自分のユーザのイベントだけを受け取る", payments, 1},
+		{"購読している種類のイベントだけを受け取る", cancellations, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.ListDeliveries(ctx, tt.subscription.ID, 0, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("WebhookRepository.ListDeliveries() len = %v, want %v", len(got), tt.want)
+			}
+		})
+	}
+
+	deliveries, err := repo.ClaimPendingDeliveries(ctx, now, time.Minute, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 2 {
+		t.Fatalf("WebhookRepository.ClaimPendingDeliveries() len = %v, want 2", len(deliveries))
+	}
+	d := deliveries[0]
+	if d.SubscriptionID != payments.ID || d.EventSequence != confirmed.Sequence || d.Status != model.WebhookDeliveryStatusPending ||
+		d.Subscription.Secret != "secret" || d.Event.Type != model.EventPaymentConfirmed || string(d.Event.Payload) != string(confirmed.Payload) {
+		t.Errorf("WebhookRepository.ClaimPendingDeliveries() got = %+v", d)
+	}
+}
+
+func TestWebhookRepository_ClaimPendingDeliveries(t *testing.T) {
+	repo := newWebhookRepo(t)
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	subscription := createSampleWebhook(t, repo, []uint{1}, model.EventPaymentConfirmed)
+	for i := 0; i < 3; i++ {
+		pt := &model.PaymentTransaction{UUID: fmt.Sprintf("foo%d", i), UserID: 1, Currency: domain.JPY, Amount: -100, Status: model.PaymentStatusConfirmed}
+		event, err := model.NewPaymentEvent(pt, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := insertOutboxEvent(ctx, repo.DB, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	claimed, err := repo.ClaimPendingDeliveries(ctx, now, time.Minute, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claimed) != 3 {
+		t.Fatalf("WebhookRepository.ClaimPendingDeliveries() len = %v, want 3", len(claimed))
+	}
+	// 1つ目は成功し、2つ目は失敗してリトライを待ち、3つ目は最大試行回数に達した
+	claimed[0].Succeeded(204, now)
+	claimed[1].Failed(500, errors.New("webhook responded with status 500"), 3, now)
+	claimed[2].Attempts = 2
+	claimed[2].Failed(0, errors.New("timeout"), 3, now)
+	for _, d := range claimed {
+		if err := repo.UpdateDelivery(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want int
+	}{
+		{"取得済みの配信は取得されない", now, 0},
+		{"再試行の時刻になると失敗した配信が取得される", now.Add(model.RetryDelay(1)), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.ClaimPendingDeliveries(ctx, tt.now, time.Minute, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("WebhookRepository.ClaimPendingDeliveries() len = %v, want %v", len(got), tt.want)
+			}
+		})
+	}
+
+	// 新しい順に、idをカーソルとしてページングする
+	page, err := repo.ListDeliveries(ctx, subscription.ID, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 2 || page[0].ID != claimed[2].ID || page[1].ID != claimed[1].ID {
+		t.Fatalf("WebhookRepository.ListDeliveries() got = %+v", page)
+	}
+	if page[0].Status != model.WebhookDeliveryStatusDead || page[0].Attempts != 3 || page[0].LastError != "timeout" {
+		t.Errorf("dead delivery = %+v", page[0])
+	}
+	if page[1].Status != model.WebhookDeliveryStatusPending || page[1].LastStatusCode != 500 {
+		t.Errorf("failed delivery = %+v", page[1])
+	}
+	next, err := repo.ListDeliveries(ctx, subscription.ID, page[1].ID, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(next) != 1 || next[0].Status != model.WebhookDeliveryStatusSucceeded || next[0].DeliverTime.IsZero() {
+		t.Errorf("WebhookRepository.ListDeliveries() got = %+v", next)
+	}
+}
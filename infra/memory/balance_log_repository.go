@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type BalanceLogRepository struct {
+	Store *Store
+}
+
+func NewBalanceLogRepository(s *Store) *BalanceLogRepository {
+	return &BalanceLogRepository{Store: s}
+}
+
+func (r *BalanceLogRepository) List(ctx context.Context, userID uint, filter model.BalanceLogFilter) ([]*model.BalanceLog, error) {
+	r.Store.mu.Lock()
+	defer r.Store.mu.Unlock()
+
+	// 新しい順に、IDをカーソルとしてページングする
+	var logs []*model.BalanceLog
+	for i := len(r.Store.logs) - 1; i >= 0 && len(logs) < filter.Limit; i-- {
+		l := r.Store.logs[i]
+		if l.UserID != userID {
+			continue
+		}
+		if filter.Currency != "" && l.Currency != filter.Currency {
+			continue
+		}
+		if filter.BeforeID > 0 && l.ID >= filter.BeforeID {
+			continue
+		}
+		if !filter.From.IsZero() && l.CreateTime.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !l.CreateTime.Before(filter.To) {
+			continue
+		}
+		log := *l
+		logs = append(logs, &log)
+	}
+	return logs, nil
+}
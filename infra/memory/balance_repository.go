@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type BalanceRepository struct {
+	Store *Store
+}
+
+func NewBalanceRepository(s *Store) *BalanceRepository {
+	return &BalanceRepository{Store: s}
+}
+
+func (r *BalanceRepository) Get(ctx context.Context, userID uint, currency domain.Currency) (*model.Balance, error) {
+	r.Store.mu.Lock()
+	defer r.Store.mu.Unlock()
+
+	return r.Store.findBalance(userID, currency)
+}
+
+func (r *BalanceRepository) List(ctx context.Context, userID uint) ([]*model.Balance, error) {
+	r.Store.mu.Lock()
+	defer r.Store.mu.Unlock()
+
+	if _, ok := r.Store.users[userID]; !ok {
+		return nil, domain.ErrNoSuchEntity
+	}
+	balances := []*model.Balance{}
+	for key, b := range r.Store.balances {
+		if key.UserID == userID {
+			balance := *b
+			balances = append(balances, &balance)
+		}
+	}
+	sort.Slice(balances, func(i, j int) bool { return balances[i].Currency < balances[j].Currency })
+	return balances, nil
+}
+
+func (r *BalanceRepository) SetOverdraftLimit(ctx context.Context, userID uint, currency domain.Currency, limit int64) (*model.Balance, error) {
+	r.Store.mu.Lock()
+	defer r.Store.mu.Unlock()
+
+	if _, ok := r.Store.users[userID]; !ok {
+		return nil, domain.ErrNoSuchEntity
+	}
+	// 与信枠を下げて残高が枠を超えて負になっていても、そのままにする（以降の減算が残高不足になる）
+	r.Store.createWallet(model.WalletKey{UserID: userID, Currency: currency}).OverdraftLimit = limit
+	return r.Store.findBalance(userID, currency)
+}
+
+func (r *BalanceRepository) AddToUsers(ctx context.Context, currency domain.Currency, amount int64, pointExpireTime time.Time, limit, offset int) error {
+	r.Store.mu.Lock()
+	defer r.Store.mu.Unlock()
+
+	// 対象のユーザ取得（その通貨のウォレットがなければ加算時に作られる）
+	userIDs := r.Store.userIDs()
+	if offset >= len(userIDs) {
+		return nil
+	}
+	userIDs = userIDs[offset:]
+	if limit < len(userIDs) {
+		userIDs = userIDs[:limit]
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	// キャンペーンの原資の勘定を相手にした仕訳として残高を加算する
+	postings := make([]*model.Posting, 0, len(userIDs)+1)
+	for _, userID := range userIDs {
+		postings = append(postings, &model.Posting{Account: model.UserAccount(userID), Currency: currency, Amount: amount})
+	}
+	total, err := domain.NewMoney(-amount, currency).Mul(int64(len(userIDs)))
+	if err != nil {
+		return err
+	}
+	postings = append(postings, &model.Posting{Account: model.AccountCampaignFunding, Currency: currency, Amount: total.Amount})
+	entry := model.NewJournalEntry(model.JournalSourceAddToUsers, fmt.Sprintf("currency=%s,limit=%d,offset=%d", currency, limit, offset), postings...)
+	entry.PointExpireTime = pointExpireTime
+	return r.Store.post(entry)
+}
+
+func (r *BalanceRepository) CountTargets(ctx context.Context, currency domain.Currency, target model.BulkCreditTarget) (int, error) {
+	r.Store.mu.Lock()
+	defer r.Store.mu.Unlock()
+
+	count := 0
+	for _, userID := range r.Store.userIDs() {
+		if r.Store.isTarget(userID, currency, target) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *BalanceRepository) ListTargets(ctx context.Context, currency domain.Currency, target model.BulkCreditTarget, afterUserID uint, limit int) ([]uint, error) {
+	r.Store.mu.Lock()
+	defer r.Store.mu.Unlock()
+
+	var userIDs []uint
+	for _, userID := range r.Store.userIDs() {
+		if len(userIDs) >= limit {
+			break
+		}
+		if userID > afterUserID && r.Store.isTarget(userID, currency, target) {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs, nil
+}
+
+// isTarget reports whether the user is a target of the bulk credit in the currency.
+func (s *Store) isTarget(userID uint, currency domain.Currency, target model.BulkCreditTarget) bool {
+	u := s.users[userID]
+	switch target.Type {
+	case model.BulkCreditTargetUserIDs:
+		i := sort.Search(len(target.UserIDs), func(i int) bool { return target.UserIDs[i] >= userID })
+		return i < len(target.UserIDs) && target.UserIDs[i] == userID
+	case model.BulkCreditTargetCreatedBetween:
+		if !target.CreatedFrom.IsZero() && u.createTime.Before(target.CreatedFrom) {
+			return false
+		}
+		if !target.CreatedTo.IsZero() && !u.createTime.Before(target.CreatedTo) {
+			return false
+		}
+	case model.BulkCreditTargetBalanceBelow:
+		// ウォレットがなければ残高0として扱う
+		return s.wallet(model.WalletKey{UserID: userID, Currency: currency}).Amount < target.BalanceBelow
+	}
+	return true
+}
@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+	"github.com/kawabatas/m-bank/domain/repository/repositorytest"
+)
+
+func newConformanceFixture(t *testing.T) *repositorytest.Fixture {
+	s := NewStore()
+	return &repositorytest.Fixture{
+		BalanceRepo: NewBalanceRepository(s),
+		PaymentRepo: NewPaymentTransactionRepository(s),
+		CreateUsers: func(t *testing.T, count int) []*model.User {
+			t.Helper()
+			var users []*model.User
+			for i := 1; i <= count; i++ {
+				user := &model.User{ID: uint(i), Name: fmt.Sprintf("sample%d", i), Tier: model.DefaultUserTier}
+				if err := s.AddUser(user, domain.NewMoney(repositorytest.InitialBalance, domain.JPY)); err != nil {
+					t.Fatal(err)
+				}
+				users = append(users, user)
+			}
+			return users
+		},
+	}
+}
+
+func TestConformance(t *testing.T) {
+	repositorytest.Run(t, newConformanceFixture)
+}
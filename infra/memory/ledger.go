@@ -0,0 +1,142 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+// post applies the user postings of the entry to the wallets. A wallet which the user has never held is created on the first posting to it.
+// All the postings are checked before any wallet changes, so a failed entry leaves the store unchanged like a rolled back transaction.
+func (s *Store) post(entry *model.JournalEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	deltas, err := entry.UserDeltas()
+	if err != nil {
+		return err
+	}
+	keys := make([]model.WalletKey, 0, len(deltas))
+	for key := range deltas {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].UserID != keys[j].UserID {
+			return keys[i].UserID < keys[j].UserID
+		}
+		return keys[i].Currency < keys[j].Currency
+	})
+
+	afters := make(map[model.WalletKey]int64, len(keys))
+	for _, key := range keys {
+		b, err := s.findBalance(key.UserID, key.Currency)
+		if err != nil {
+			return err
+		}
+		delta := deltas[key]
+		after, err := b.Money().Add(domain.NewMoney(delta, b.Currency))
+		if err != nil {
+			return err
+		}
+		if b.IsOverdrawn(after.Amount) && delta < 0 && !entry.AllowNegativeBalance {
+			return domain.ErrShortBalance
+		}
+		afters[key] = after.Amount
+	}
+
+	s.lastEntryID++
+	entry.ID = s.lastEntryID
+	for _, key := range keys {
+		b := s.createWallet(key)
+		before := b.Amount
+		b.Amount = afters[key]
+		s.logs = append(s.logs, &model.BalanceLog{
+			ID:           uint64(len(s.logs) + 1),
+			UserID:       key.UserID,
+			Currency:     key.Currency,
+			BeforeAmount: before,
+			AfterAmount:  b.Amount,
+			Delta:        deltas[key],
+			SourceType:   entry.SourceType,
+			SourceID:     entry.SourceID,
+			Reason:       entry.Reason,
+			CreateTime:   entry.CreateTime,
+		})
+		if key.Currency == domain.Point {
+			if err := s.applyPointLots(entry, key.UserID, pointLotDelta(before, b.Amount)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// pointLotDelta returns the change of the points covered by the lots when the point balance changes from before to after.
+// The lots cover only the positive part of the balance, so a credit to a negative balance first fills the deficit.
+func pointLotDelta(before, after int64) int64 {
+	if before < 0 {
+		before = 0
+	}
+	if after < 0 {
+		after = 0
+	}
+	return after - before
+}
+
+// applyPointLots creates a lot when the points of the user increase and consumes the lots when they decrease.
+func (s *Store) applyPointLots(entry *model.JournalEntry, userID uint, delta int64) error {
+	switch {
+	case delta > 0:
+		s.lots = append(s.lots, &model.PointLot{
+			ID:              uint64(len(s.lots) + 1),
+			UserID:          userID,
+			Amount:          delta,
+			RemainingAmount: delta,
+			ExpireTime:      entry.PointExpireTime,
+			SourceType:      entry.SourceType,
+			SourceID:        entry.SourceID,
+			CreateTime:      entry.CreateTime,
+		})
+	case delta < 0:
+		return s.consumePointLots(userID, -delta)
+	}
+	return nil
+}
+
+// consumePointLots consumes the amount from the lots of the user in order of expiry. The lots which never expire are consumed last.
+func (s *Store) consumePointLots(userID uint, amount int64) error {
+	var lots []*model.PointLot
+	for _, lot := range s.lots {
+		if lot.UserID == userID && lot.RemainingAmount > 0 {
+			lots = append(lots, lot)
+		}
+	}
+	sort.SliceStable(lots, func(i, j int) bool {
+		if lots[i].ExpireTime.IsZero() != lots[j].ExpireTime.IsZero() {
+			return !lots[i].ExpireTime.IsZero()
+		}
+		return lots[i].ExpireTime.Before(lots[j].ExpireTime)
+	})
+	var total int64
+	for _, lot := range lots {
+		total += lot.RemainingAmount
+	}
+	// ロットの合計は残高の正の部分と一致するので、足りなくなることはない
+	if total < amount {
+		return fmt.Errorf("%w: point lots of user %d are short by %d", domain.ErrLedgerInconsistent, userID, amount-total)
+	}
+	for _, lot := range lots {
+		if amount == 0 {
+			break
+		}
+		consumed := lot.RemainingAmount
+		if consumed > amount {
+			consumed = amount
+		}
+		lot.RemainingAmount -= consumed
+		amount -= consumed
+	}
+	return nil
+}
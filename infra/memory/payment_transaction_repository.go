@@ -0,0 +1,218 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+// PaymentTransactionRepository stores the payments in memory.
+// The spending limits are not checked because the memory store does not hold them.
+type PaymentTransactionRepository struct {
+	Store *Store
+}
+
+func NewPaymentTransactionRepository(s *Store) *PaymentTransactionRepository {
+	return &PaymentTransactionRepository{Store: s}
+}
+
+func (r *PaymentTransactionRepository) Get(ctx context.Context, uuid string) (*model.PaymentTransaction, error) {
+	r.Store.mu.Lock()
+	defer r.Store.mu.Unlock()
+
+	return r.Store.findPaymentTransaction(uuid)
+}
+
+func (r *PaymentTransactionRepository) List(ctx context.Context, userID uint, filter model.PaymentTransactionFilter) ([]*model.PaymentTransaction, error) {
+	r.Store.mu.Lock()
+	defer r.Store.mu.Unlock()
+
+	var pts []*model.PaymentTransaction
+	for _, pt := range r.Store.payments {
+		if pt.UserID != userID {
+			continue
+		}
+		if filter.Status != "" && pt.Status != filter.Status {
+			continue
+		}
+		switch filter.Sign {
+		case model.AmountSignPositive:
+			if pt.Amount <= 0 {
+				continue
+			}
+		case model.AmountSignNegative:
+			if pt.Amount >= 0 {
+				continue
+			}
+		}
+		if !filter.From.IsZero() && pt.TryTime.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !pt.TryTime.Before(filter.To) {
+			continue
+		}
+		if !filter.BeforeTryTime.IsZero() && !isBefore(pt, filter.BeforeTryTime, filter.BeforeUUID) {
+			continue
+		}
+		found := *pt
+		pts = append(pts, &found)
+	}
+	// 新しい順に、(try_time, uuid) をカーソルとしてページングする
+	sort.Slice(pts, func(i, j int) bool { return isBefore(pts[j], pts[i].TryTime, pts[i].UUID) })
+	if filter.Limit < len(pts) {
+		pts = pts[:filter.Limit]
+	}
+	return pts, nil
+}
+
+// isBefore reports whether (try_time, uuid) of the transaction is less than the cursor.
+func isBefore(pt *model.PaymentTransaction, tryTime time.Time, uuid string) bool {
+	if !pt.TryTime.Equal(tryTime) {
+		return pt.TryTime.Before(tryTime)
+	}
+	return pt.UUID < uuid
+}
+
+func (r *PaymentTransactionRepository) Try(ctx context.Context, uuid string, userID uint, currency domain.Currency, amount int64, ttl time.Duration) (*model.PaymentTransaction, error) {
+	r.Store.mu.Lock()
+	defer r.Store.mu.Unlock()
+
+	if _, ok := r.Store.payments[uuid]; ok {
+		return nil, domain.ErrDuplicateUUID
+	}
+	balance, err := r.Store.findBalance(userID, currency)
+	if err != nil {
+		return nil, err
+	}
+	pt := model.NewPaymentTransaction(uuid, userID, currency, amount, ttl)
+	// 減算の場合は、利用可能残高をチェックして仮押さえする
+	if pt.Amount < 0 {
+		if balance.AvailableAmount() < -pt.Amount {
+			return nil, domain.ErrShortBalance
+		}
+		r.Store.createWallet(balance.Key()).ReservedAmount += -pt.Amount
+	}
+	r.Store.saveResultBalance(pt)
+	r.Store.payments[uuid] = pt
+	return r.Store.findPaymentTransaction(uuid)
+}
+
+func (r *PaymentTransactionRepository) Confirm(ctx context.Context, uuid string) (*model.PaymentTransaction, error) {
+	r.Store.mu.Lock()
+	defer r.Store.mu.Unlock()
+
+	pt, err := r.Store.findPaymentTransaction(uuid)
+	if err != nil {
+		return nil, err
+	}
+	// 期限切れの仮押さえは、スイーパーが解放するまでそのままにしておく
+	if err := pt.Confirm(time.Now()); err != nil {
+		return nil, err
+	}
+	// 残高の加減算は、外部との精算勘定を相手にした仕訳として記録する
+	// (残高不足なら何も変えずにエラーになる)
+	entry := model.NewJournalEntry(model.JournalSourcePayment, pt.UUID,
+		&model.Posting{Account: model.UserAccount(pt.UserID), Currency: pt.Currency, Amount: pt.Amount},
+		&model.Posting{Account: model.AccountExternalSettlement, Currency: pt.Currency, Amount: -pt.Amount},
+	)
+	if err := r.Store.post(entry); err != nil {
+		// 残高不足などの業務上のエラーでConfirmできない取引は、失敗にして仮押さえを解放する
+		if model.IsConfirmFailure(err) {
+			failed, findErr := r.Store.findPaymentTransaction(uuid)
+			if findErr != nil {
+				return nil, findErr
+			}
+			if failErr := failed.Fail(); failErr != nil {
+				return nil, failErr
+			}
+			r.Store.releaseReserved(failed)
+			r.Store.saveResultBalance(failed)
+			r.Store.payments[uuid] = failed
+		}
+		return nil, err
+	}
+	// Tryで仮押さえしていた分を解放する
+	r.Store.releaseReserved(pt)
+	r.Store.saveResultBalance(pt)
+	r.Store.payments[uuid] = pt
+	return r.Store.findPaymentTransaction(uuid)
+}
+
+func (r *PaymentTransactionRepository) Cancel(ctx context.Context, uuid string) (*model.PaymentTransaction, error) {
+	r.Store.mu.Lock()
+	defer r.Store.mu.Unlock()
+
+	pt, err := r.Store.findPaymentTransaction(uuid)
+	if err != nil {
+		return nil, err
+	}
+	if err := pt.Cancel(time.Now()); err != nil {
+		return nil, err
+	}
+	// Tryで仮押さえしていた分を解放する
+	r.Store.releaseReserved(pt)
+	r.Store.saveResultBalance(pt)
+	r.Store.payments[uuid] = pt
+	return r.Store.findPaymentTransaction(uuid)
+}
+
+func (r *PaymentTransactionRepository) ExpireTries(ctx context.Context, now time.Time, limit int) (int, error) {
+	r.Store.mu.Lock()
+	defer r.Store.mu.Unlock()
+
+	var pts []*model.PaymentTransaction
+	for _, pt := range r.Store.payments {
+		if pt.IsTryStatus() && !pt.ExpireTime.After(now) {
+			pts = append(pts, pt)
+		}
+	}
+	sort.Slice(pts, func(i, j int) bool {
+		if !pts[i].ExpireTime.Equal(pts[j].ExpireTime) {
+			return pts[i].ExpireTime.Before(pts[j].ExpireTime)
+		}
+		return pts[i].UUID < pts[j].UUID
+	})
+	if limit < len(pts) {
+		pts = pts[:limit]
+	}
+
+	for _, pt := range pts {
+		if err := pt.Expire(now); err != nil {
+			return 0, err
+		}
+		// 仮押さえの解放
+		r.Store.releaseReserved(pt)
+	}
+	return len(pts), nil
+}
+
+// findPaymentTransaction returns a copy of the stored transaction. It returns domain.ErrInvalidUUID when it is not found.
+func (s *Store) findPaymentTransaction(uuid string) (*model.PaymentTransaction, error) {
+	stored, ok := s.payments[uuid]
+	if !ok {
+		return nil, domain.ErrInvalidUUID
+	}
+	pt := *stored
+	if stored.ResultBalance != nil {
+		b := *stored.ResultBalance
+		pt.ResultBalance = &b
+	}
+	return &pt, nil
+}
+
+// saveResultBalance saves the wallet of the payment as changed, which the retries of the request return.
+func (s *Store) saveResultBalance(pt *model.PaymentTransaction) {
+	b := *s.wallet(model.WalletKey{UserID: pt.UserID, Currency: pt.Currency})
+	b.Expirations = nil
+	pt.ResultBalance = &b
+}
+
+// releaseReserved releases the amount reserved by the debit when it was tried.
+func (s *Store) releaseReserved(pt *model.PaymentTransaction) {
+	if pt.Amount < 0 {
+		s.createWallet(model.WalletKey{UserID: pt.UserID, Currency: pt.Currency}).ReservedAmount -= -pt.Amount
+	}
+}
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type PointLotRepository struct {
+	Store *Store
+}
+
+func NewPointLotRepository(s *Store) *PointLotRepository {
+	return &PointLotRepository{Store: s}
+}
+
+func (r *PointLotRepository) ListByUser(ctx context.Context, userID uint) ([]*model.PointLot, error) {
+	r.Store.mu.Lock()
+	defer r.Store.mu.Unlock()
+
+	var lots []*model.PointLot
+	for _, l := range r.Store.lots {
+		if l.UserID == userID {
+			lot := *l
+			lots = append(lots, &lot)
+		}
+	}
+	return lots, nil
+}
+
+func (r *PointLotRepository) ListExpirations(ctx context.Context, userID uint, now time.Time, limit int) ([]*model.PointExpiration, error) {
+	r.Store.mu.Lock()
+	defer r.Store.mu.Unlock()
+
+	amounts := map[time.Time]int64{}
+	var expireTimes []time.Time
+	for _, lot := range r.Store.lots {
+		if lot.UserID != userID || lot.RemainingAmount <= 0 || lot.ExpireTime.IsZero() || !lot.ExpireTime.After(now) {
+			continue
+		}
+		if _, ok := amounts[lot.ExpireTime]; !ok {
+			expireTimes = append(expireTimes, lot.ExpireTime)
+		}
+		amounts[lot.ExpireTime] += lot.RemainingAmount
+	}
+	sort.Slice(expireTimes, func(i, j int) bool { return expireTimes[i].Before(expireTimes[j]) })
+	if limit < len(expireTimes) {
+		expireTimes = expireTimes[:limit]
+	}
+
+	var expirations []*model.PointExpiration
+	for _, t := range expireTimes {
+		expirations = append(expirations, &model.PointExpiration{Amount: amounts[t], ExpireTime: t})
+	}
+	return expirations, nil
+}
+
+func (r *PointLotRepository) ExpireLots(ctx context.Context, now time.Time, limit int) (int, error) {
+	r.Store.mu.Lock()
+	defer r.Store.mu.Unlock()
+
+	// 仮押さえ中のポイントしか残っていないユーザのロットは選ばない
+	available := map[uint]int64{}
+	var lots []*model.PointLot
+	for _, lot := range r.Store.lots {
+		if lot.RemainingAmount <= 0 || lot.ExpireTime.IsZero() || lot.ExpireTime.After(now) {
+			continue
+		}
+		if _, ok := available[lot.UserID]; !ok {
+			b := r.Store.wallet(model.WalletKey{UserID: lot.UserID, Currency: domain.Point})
+			available[lot.UserID] = b.Amount - b.ReservedAmount
+		}
+		if available[lot.UserID] > 0 {
+			lots = append(lots, lot)
+		}
+	}
+	sort.SliceStable(lots, func(i, j int) bool { return lots[i].ExpireTime.Before(lots[j].ExpireTime) })
+	if limit < len(lots) {
+		lots = lots[:limit]
+	}
+
+	// ロットごとに失効の勘定への仕訳として減算する。
+	// 期限の近いロットから消費するので、減算で消費されるのは失効したロット自身になる。
+	// 仮押さえ中のポイントは支払いのConfirmで使うので失効させず、次回以降に持ち越す
+	n := 0
+	for _, lot := range lots {
+		amount := lot.RemainingAmount
+		if amount > available[lot.UserID] {
+			amount = available[lot.UserID]
+		}
+		if amount <= 0 {
+			continue
+		}
+		entry := model.NewJournalEntry(model.JournalSourcePointExpiration, strconv.FormatUint(lot.ID, 10),
+			&model.Posting{Account: model.UserAccount(lot.UserID), Currency: domain.Point, Amount: -amount},
+			&model.Posting{Account: model.AccountPointExpiration, Currency: domain.Point, Amount: amount},
+		)
+		if err := r.Store.post(entry); err != nil {
+			return 0, err
+		}
+		available[lot.UserID] -= amount
+		n++
+	}
+	return n, nil
+}
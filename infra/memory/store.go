@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+// Store holds the data of the repositories in memory. Each method of the repositories holds the lock of the store
+// until it returns, so the methods are serialized like the DB transactions which lock the same rows.
+// The repositories return copies, and the stored data changes only through them.
+type Store struct {
+	mu          sync.Mutex
+	users       map[uint]*user
+	balances    map[model.WalletKey]*model.Balance
+	payments    map[string]*model.PaymentTransaction
+	logs        []*model.BalanceLog
+	lots        []*model.PointLot
+	lastEntryID uint64
+}
+
+type user struct {
+	model.User
+	createTime time.Time
+}
+
+func NewStore() *Store {
+	return &Store{
+		users:    map[uint]*user{},
+		balances: map[model.WalletKey]*model.Balance{},
+		payments: map[string]*model.PaymentTransaction{},
+	}
+}
+
+// AddUser adds the user holding the opening balances. The balances are posted against the opening balance account.
+func (s *Store) AddUser(u *model.User, balances ...domain.Money) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[u.ID]; ok {
+		return fmt.Errorf("user %d already exists", u.ID)
+	}
+	stored := &user{User: *u, createTime: time.Now()}
+	if stored.Tier == "" {
+		stored.Tier = model.DefaultUserTier
+	}
+	s.users[u.ID] = stored
+	if len(balances) == 0 {
+		return nil
+	}
+
+	postings := make([]*model.Posting, 0, len(balances)*2)
+	for _, m := range balances {
+		postings = append(postings,
+			&model.Posting{Account: model.UserAccount(u.ID), Currency: m.Currency, Amount: m.Amount},
+			&model.Posting{Account: model.AccountOpeningBalance, Currency: m.Currency, Amount: -m.Amount},
+		)
+	}
+	entry := model.NewJournalEntry(model.JournalSourceOpeningBalance, strconv.FormatUint(uint64(u.ID), 10), postings...)
+	if err := s.post(entry); err != nil {
+		delete(s.users, u.ID)
+		return err
+	}
+	return nil
+}
+
+// findBalance returns a copy of the wallet of the user in the currency, or an empty one when the user has never held the currency.
+// It returns domain.ErrNoSuchEntity when the user does not exist.
+func (s *Store) findBalance(userID uint, currency domain.Currency) (*model.Balance, error) {
+	if _, ok := s.users[userID]; !ok {
+		return nil, domain.ErrNoSuchEntity
+	}
+	b := *s.wallet(model.WalletKey{UserID: userID, Currency: currency})
+	return &b, nil
+}
+
+// wallet returns the stored wallet, or an empty one which is not stored yet.
+func (s *Store) wallet(key model.WalletKey) *model.Balance {
+	if b, ok := s.balances[key]; ok {
+		return b
+	}
+	return &model.Balance{UserID: key.UserID, Currency: key.Currency}
+}
+
+// createWallet returns the stored wallet, storing an empty one when the user has never held the currency.
+func (s *Store) createWallet(key model.WalletKey) *model.Balance {
+	b, ok := s.balances[key]
+	if !ok {
+		b = &model.Balance{UserID: key.UserID, Currency: key.Currency}
+		s.balances[key] = b
+	}
+	return b
+}
+
+// userIDs returns the ids of all the users in ascending order.
+func (s *Store) userIDs() []uint {
+	ids := make([]uint, 0, len(s.users))
+	for id := range s.users {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
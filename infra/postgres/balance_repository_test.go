@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+)
+
+func newBalanceRepo(t *testing.T) *BalanceRepository {
+	t.Helper()
+	db := newTestConnection(t)
+	return NewBalanceRepository(db)
+}
+
+func TestBalanceRepository_List(t *testing.T) {
+	repo := newBalanceRepo(t)
+	users := createSampleUsers(t, repo.DB, 1)
+	ctx := context.Background()
+
+	got, err := repo.List(ctx, users[0].ID)
+	if err != nil {
+		t.Fatalf("BalanceRepository.List() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Currency != domain.JPY || got[0].Amount != initBalanceAmount {
+		t.Errorf("BalanceRepository.List() got = %+v", got)
+	}
+	if _, err := repo.List(ctx, users[0].ID+1); !errors.Is(err, domain.ErrNoSuchEntity) {
+		t.Errorf("BalanceRepository.List() error = %v, wantErr %v", err, domain.ErrNoSuchEntity)
+	}
+}
+
+func TestBalanceRepository_SetOverdraftLimit(t *testing.T) {
+	repo := newBalanceRepo(t)
+	users := createSampleUsers(t, repo.DB, 1)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		userID   uint
+		currency domain.Currency
+		limit    int64
+		wantErr  error
+	}{
+		{"既存のウォレットに設定できる", users[0].ID, domain.JPY, 500, nil},
+		{"まだないウォレットは作って設定する", users[0].ID, domain.USD, 100, nil},
+		{"存在しないユーザ", users[0].ID + 1, domain.JPY, 500, domain.ErrNoSuchEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.SetOverdraftLimit(ctx, tt.userID, tt.currency, tt.limit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("BalanceRepository.SetOverdraftLimit() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.OverdraftLimit != tt.limit {
+				t.Errorf("BalanceRepository.SetOverdraftLimit() limit = %v, want %v", got.OverdraftLimit, tt.limit)
+			}
+		})
+	}
+
+	// 与信枠の分だけ利用可能残高を超えて減算できる
+	paymentRepo := NewPaymentTransactionRepository(repo.DB)
+	if _, err := paymentRepo.Try(ctx, "overdraft", users[0].ID, domain.JPY, -(initBalanceAmount + 500), time.Minute); err != nil {
+		t.Errorf("PaymentTransactionRepository.Try() error = %v", err)
+	}
+}
+
+func TestBalanceRepository_AddToUsers(t *testing.T) {
+	repo := newBalanceRepo(t)
+	users := createSampleUsers(t, repo.DB, 3)
+	ctx := context.Background()
+	expireTime := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+
+	if err := repo.AddToUsers(ctx, domain.Point, 100, expireTime, 2, 1); err != nil {
+		t.Fatalf("BalanceRepository.AddToUsers() error = %v", err)
+	}
+	for i, want := range []int64{0, 100, 100} {
+		b, err := repo.Get(ctx, users[i].ID, domain.Point)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.Amount != want {
+			t.Errorf("user %d balance = %v, want %v", users[i].ID, b.Amount, want)
+		}
+	}
+	// 加算したポイントは有効期限つきのロットになる
+	lots, err := NewPointLotRepository(repo.DB).ListByUser(ctx, users[1].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lots) != 1 || lots[0].RemainingAmount != 100 || !lots[0].ExpireTime.Equal(expireTime) {
+		t.Errorf("PointLotRepository.ListByUser() got = %+v", lots)
+	}
+}
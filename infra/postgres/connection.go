@@ -0,0 +1,24 @@
+package postgres
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// DSN create PostgreSQL Data Source Name for the pgx driver.
+func DSN(host, user, password, dbname string) string {
+	if strings.HasPrefix(host, "/") {
+		// unix socket
+		return fmt.Sprintf("host=%s user=%s password=%s dbname=%s sslmode=disable", host, user, password, dbname)
+	}
+	// tcp
+	u := &url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(user, password),
+		Host:     host,
+		Path:     dbname,
+		RawQuery: "sslmode=disable",
+	}
+	return u.String()
+}
@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+// postJournalEntry records the entry and applies its user postings to the wallets in the same DB transaction.
+// A wallet which the user has never held is created on the first posting to it.
+func postJournalEntry(ctx context.Context, db dbContext, entry *model.JournalEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	if err := insertJournalEntry(ctx, db, entry); err != nil {
+		return err
+	}
+
+	deltas, err := entry.UserDeltas()
+	if err != nil {
+		return err
+	}
+	if len(deltas) == 0 {
+		return nil
+	}
+	// デッドロックを避けるため(user_id, currency)順にロックする
+	keys := make([]model.WalletKey, 0, len(deltas))
+	for key := range deltas {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].UserID != keys[j].UserID {
+			return keys[i].UserID < keys[j].UserID
+		}
+		return keys[i].Currency < keys[j].Currency
+	})
+	var keyArgs queryArgs
+	keyStrings := make([]string, 0, len(keys))
+	for _, key := range keys {
+		keyStrings = append(keyStrings, "("+keyArgs.bind(key.UserID)+"::integer, "+keyArgs.bind(key.Currency)+"::char(3))")
+	}
+	// まだないウォレットを作る。存在しないユーザは結合で除かれ、下の件数チェックでエラーになる
+	insertQuery := `
+	INSERT INTO balances (user_id, currency)
+	SELECT k.user_id, k.currency FROM (VALUES ` + strings.Join(keyStrings, ",") + `) AS k (user_id, currency)
+	JOIN users u ON u.id = k.user_id
+	ON CONFLICT (user_id, currency) DO NOTHING`
+	if _, err := db.ExecContext(ctx, insertQuery, keyArgs...); err != nil {
+		return err
+	}
+	fetchQuery := "SELECT user_id, currency, amount, reserved_amount, overdraft_limit FROM balances WHERE (user_id, currency) IN (" + strings.Join(keyStrings, ",") + ") ORDER BY user_id ASC, currency ASC FOR UPDATE"
+	rows, err := db.QueryContext(ctx, fetchQuery, keyArgs...)
+	if err != nil {
+		return err
+	}
+	var balances []*model.Balance
+	for rows.Next() {
+		b, err := rowsToBalance(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		balances = append(balances, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(balances) != len(keys) {
+		return domain.ErrNoSuchEntity
+	}
+
+	// 同じ通貨で同じ増減額のウォレットはまとめて更新する
+	type update struct {
+		currency domain.Currency
+		delta    int64
+	}
+	updates := map[update][]uint{}
+	var logArgs queryArgs
+	var logStrings []string
+	pointDeltas := map[uint]int64{}
+	for _, b := range balances {
+		delta := deltas[b.Key()]
+		after, err := b.Money().Add(domain.NewMoney(delta, b.Currency))
+		if err != nil {
+			return err
+		}
+		if b.IsOverdrawn(after.Amount) && delta < 0 && !entry.AllowNegativeBalance {
+			return domain.ErrShortBalance
+		}
+		if b.Currency == domain.Point {
+			pointDeltas[b.UserID] = pointLotDelta(b.Amount, after.Amount)
+		}
+		u := update{currency: b.Currency, delta: delta}
+		updates[u] = append(updates[u], b.UserID)
+		logStrings = append(logStrings, "("+strings.Join([]string{
+			logArgs.bind(b.UserID), logArgs.bind(b.Currency), logArgs.bind(b.Amount), logArgs.bind(after.Amount), logArgs.bind(delta),
+			logArgs.bind(entry.SourceType), logArgs.bind(entry.SourceID), logArgs.bind(entry.Reason),
+		}, ", ")+")")
+	}
+	for u, ids := range updates {
+		var args queryArgs
+		query := "UPDATE balances SET amount = amount + " + args.bind(u.delta) + " WHERE currency = " + args.bind(u.currency)
+		placeholders := make([]string, 0, len(ids))
+		for _, id := range ids {
+			placeholders = append(placeholders, args.bind(id))
+		}
+		query += " AND user_id IN (" + strings.Join(placeholders, ",") + ")"
+		if _, err := db.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+	logQuery := "INSERT INTO balance_logs (user_id, currency, before_amount, after_amount, delta, source_type, source_id, reason) VALUES " + strings.Join(logStrings, ",")
+	if _, err := db.ExecContext(ctx, logQuery, logArgs...); err != nil {
+		return err
+	}
+	return applyPointLots(ctx, db, entry, pointDeltas)
+}
+
+// insertJournalEntry inserts the entry and its postings without touching the balances.
+func insertJournalEntry(ctx context.Context, db dbContext, entry *model.JournalEntry) error {
+	if err := db.QueryRowContext(ctx,
+		"INSERT INTO journal_entries (source_type, source_id, reason, create_time) VALUES ($1, $2, $3, $4) RETURNING id",
+		entry.SourceType, entry.SourceID, entry.Reason, entry.CreateTime,
+	).Scan(&entry.ID); err != nil {
+		return err
+	}
+
+	var valueArgs queryArgs
+	valueStrings := make([]string, 0, len(entry.Postings))
+	for _, p := range entry.Postings {
+		valueStrings = append(valueStrings, "("+valueArgs.bind(entry.ID)+", "+valueArgs.bind(p.Account)+", "+valueArgs.bind(p.Currency)+", "+valueArgs.bind(p.Amount)+")")
+	}
+	insertQuery := "INSERT INTO postings (journal_entry_id, account, currency, amount) VALUES " + strings.Join(valueStrings, ",")
+	if _, err := db.ExecContext(ctx, insertQuery, valueArgs...); err != nil {
+		return err
+	}
+	return nil
+}
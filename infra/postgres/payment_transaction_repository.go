@@ -0,0 +1,424 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type PaymentTransactionRepository struct {
+	DB *sql.DB
+}
+
+func NewPaymentTransactionRepository(db *sql.DB) *PaymentTransactionRepository {
+	return &PaymentTransactionRepository{DB: db}
+}
+
+const paymentTransactionColumns = `
+		uuid, user_id, currency, amount, request_fingerprint, status,
+		try_time, expire_time, confirm_time, cancel_time, expired_time, refunded_amount,
+		balance_amount, balance_reserved_amount, balance_overdraft_limit`
+
+func (r *PaymentTransactionRepository) Get(ctx context.Context, uuid string) (*model.PaymentTransaction, error) {
+	return findPaymentTransaction(ctx, r.DB, uuid, false)
+}
+
+func (r *PaymentTransactionRepository) List(ctx context.Context, userID uint, filter model.PaymentTransactionFilter) ([]*model.PaymentTransaction, error) {
+	var args queryArgs
+	query := `SELECT` + paymentTransactionColumns + ` FROM payment_transactions WHERE user_id = ` + args.bind(userID)
+	if filter.Status != "" {
+		query += ` AND status = ` + args.bind(filter.Status)
+	}
+	switch filter.Sign {
+	case model.AmountSignPositive:
+		query += ` AND amount > 0`
+	case model.AmountSignNegative:
+		query += ` AND amount < 0`
+	}
+	if !filter.From.IsZero() {
+		query += ` AND try_time >= ` + args.bind(filter.From)
+	}
+	if !filter.To.IsZero() {
+		query += ` AND try_time < ` + args.bind(filter.To)
+	}
+	if !filter.BeforeTryTime.IsZero() {
+		query += ` AND (try_time, uuid) < (` + args.bind(filter.BeforeTryTime) + `, ` + args.bind(filter.BeforeUUID) + `)`
+	}
+	// 新しい順に、(try_time, uuid) をカーソルとしてページングする
+	query += ` ORDER BY try_time DESC, uuid DESC LIMIT ` + args.bind(filter.Limit)
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pts []*model.PaymentTransaction
+	for rows.Next() {
+		pt, err := rowsToPaymentTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		pts = append(pts, pt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return pts, nil
+}
+
+func (r *PaymentTransactionRepository) Try(ctx context.Context, uuid string, userID uint, currency domain.Currency, amount int64, ttl time.Duration) (*model.PaymentTransaction, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	pt := model.NewPaymentTransaction(uuid, userID, currency, amount, ttl)
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO payment_transactions (uuid, user_id, currency, amount, request_fingerprint, status, try_time, expire_time) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		pt.UUID, pt.UserID, pt.Currency, pt.Amount, pt.RequestFingerprint, pt.Status, pt.TryTime, pt.ExpireTime,
+	); err != nil {
+		if isUniqueViolation(err) {
+			return nil, domain.ErrDuplicateUUID
+		}
+		return nil, err
+	}
+
+	// 減算の場合は、トランザクション内で利用可能残高と利用上限をチェックして仮押さえする
+	if pt.Amount < 0 {
+		balance, err := findBalance(ctx, tx, pt.UserID, pt.Currency, true)
+		if err != nil {
+			return nil, err
+		}
+		if balance.AvailableAmount() < -pt.Amount {
+			return nil, domain.ErrShortBalance
+		}
+		if err := checkSpendingLimit(ctx, tx, pt); err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE balances SET reserved_amount = reserved_amount + $1 WHERE user_id = $2 AND currency = $3`,
+			-pt.Amount, pt.UserID, pt.Currency,
+		); err != nil {
+			return nil, err
+		}
+	}
+	if err := saveResultBalance(ctx, tx, pt); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return findPaymentTransaction(ctx, r.DB, uuid, false)
+}
+
+func (r *PaymentTransactionRepository) Confirm(ctx context.Context, uuid string) (*model.PaymentTransaction, error) {
+	pt, err := r.confirm(ctx, uuid)
+	// 残高不足や利用上限の超過などの業務上のエラーでConfirmできない取引は、失敗にして仮押さえを解放する
+	if model.IsConfirmFailure(err) {
+		if failErr := r.fail(ctx, uuid); failErr != nil {
+			return nil, failErr
+		}
+	}
+	return pt, err
+}
+
+func (r *PaymentTransactionRepository) confirm(ctx context.Context, uuid string) (*model.PaymentTransaction, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	pt, err := findPaymentTransaction(ctx, tx, uuid, true)
+	if err != nil {
+		return nil, err
+	}
+	// 期限切れの仮押さえは、スイーパーが解放するまでそのままにしておく
+	if err := pt.Confirm(time.Now()); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE payment_transactions SET status = $1, confirm_time = $2 WHERE uuid = $3`,
+		pt.Status, pt.ConfirmTime, pt.UUID,
+	); err != nil {
+		return nil, err
+	}
+
+	// Tryで仮押さえしていた分を解放し、減算として確定する。
+	// Tryの後に利用上限が下げられていることがあるので、ユーザの行をロックして上限をもう一度チェックする
+	if pt.Amount < 0 {
+		if _, err := findBalance(ctx, tx, pt.UserID, pt.Currency, true); err != nil {
+			return nil, err
+		}
+		if err := checkSpendingLimit(ctx, tx, pt); err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE balances SET reserved_amount = reserved_amount - $1 WHERE user_id = $2 AND currency = $3`,
+			-pt.Amount, pt.UserID, pt.Currency,
+		); err != nil {
+			return nil, err
+		}
+	}
+	// 残高の加減算は、外部との精算勘定を相手にした仕訳として記録する
+	// (残高不足のチェックも同じトランザクション内で行われる)
+	entry := model.NewJournalEntry(model.JournalSourcePayment, pt.UUID,
+		&model.Posting{Account: model.UserAccount(pt.UserID), Currency: pt.Currency, Amount: pt.Amount},
+		&model.Posting{Account: model.AccountExternalSettlement, Currency: pt.Currency, Amount: -pt.Amount},
+	)
+	if err := postJournalEntry(ctx, tx, entry); err != nil {
+		return nil, err
+	}
+	if err := saveResultBalance(ctx, tx, pt); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// 再取得
+	return findPaymentTransaction(ctx, r.DB, uuid, false)
+}
+
+// fail moves the tried transaction to failed and releases the amount reserved by the debit.
+func (r *PaymentTransactionRepository) fail(ctx context.Context, uuid string) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	pt, err := findPaymentTransaction(ctx, tx, uuid, true)
+	if err != nil {
+		return err
+	}
+	if err := pt.Fail(); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE payment_transactions SET status = $1 WHERE uuid = $2`, pt.Status, pt.UUID); err != nil {
+		return err
+	}
+	if pt.Amount < 0 {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE balances SET reserved_amount = reserved_amount - $1 WHERE user_id = $2 AND currency = $3`,
+			-pt.Amount, pt.UserID, pt.Currency,
+		); err != nil {
+			return err
+		}
+	}
+	if err := saveResultBalance(ctx, tx, pt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *PaymentTransactionRepository) Cancel(ctx context.Context, uuid string) (*model.PaymentTransaction, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	pt, err := findPaymentTransaction(ctx, tx, uuid, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := pt.Cancel(time.Now()); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE payment_transactions SET status = $1, cancel_time = $2 WHERE uuid = $3`,
+		pt.Status, pt.CancelTime, pt.UUID,
+	); err != nil {
+		return nil, err
+	}
+	// Tryで仮押さえしていた分を解放する
+	if pt.Amount < 0 {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE balances SET reserved_amount = reserved_amount - $1 WHERE user_id = $2 AND currency = $3`,
+			-pt.Amount, pt.UserID, pt.Currency,
+		); err != nil {
+			return nil, err
+		}
+	}
+	if err := saveResultBalance(ctx, tx, pt); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// 再取得
+	return findPaymentTransaction(ctx, r.DB, uuid, false)
+}
+
+func (r *PaymentTransactionRepository) ExpireTries(ctx context.Context, now time.Time, limit int) (int, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// 他のトランザクションがConfirm/Cancel中の行はスキップする
+	query := `SELECT` + paymentTransactionColumns + `
+	FROM payment_transactions
+	WHERE status = $1 AND expire_time <= $2
+	ORDER BY expire_time ASC LIMIT $3 FOR UPDATE SKIP LOCKED`
+	rows, err := tx.QueryContext(ctx, query, model.PaymentStatusTried, now, limit)
+	if err != nil {
+		return 0, err
+	}
+	var pts []*model.PaymentTransaction
+	for rows.Next() {
+		pt, err := rowsToPaymentTransaction(rows)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if err := pt.Expire(now); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		pts = append(pts, pt)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(pts) == 0 {
+		return 0, nil
+	}
+
+	// 仮押さえの解放
+	reserved := map[model.WalletKey]int64{}
+	for _, pt := range pts {
+		if pt.Amount < 0 {
+			reserved[model.WalletKey{UserID: pt.UserID, Currency: pt.Currency}] += -pt.Amount
+		}
+	}
+	if err := releaseReserved(ctx, tx, reserved); err != nil {
+		return 0, err
+	}
+
+	var args queryArgs
+	updateQuery := "UPDATE payment_transactions SET status = " + args.bind(model.PaymentStatusExpired) + ", expired_time = " + args.bind(now)
+	placeholders := make([]string, 0, len(pts))
+	for _, pt := range pts {
+		placeholders = append(placeholders, args.bind(pt.UUID))
+	}
+	updateQuery += " WHERE uuid IN (" + strings.Join(placeholders, ",") + ")"
+	if _, err := tx.ExecContext(ctx, updateQuery, args...); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(pts), nil
+}
+
+func findPaymentTransaction(ctx context.Context, db dbContext, uuid string, withLock bool) (*model.PaymentTransaction, error) {
+	query := `SELECT` + paymentTransactionColumns + ` FROM payment_transactions WHERE uuid = $1`
+	if withLock {
+		query = query + ` FOR UPDATE`
+	}
+	rows, err := db.QueryContext(ctx, query, uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrInvalidUUID
+	}
+	return rowsToPaymentTransaction(rows)
+}
+
+func rowsToPaymentTransaction(rows *sql.Rows) (*model.PaymentTransaction, error) {
+	pt := &model.PaymentTransaction{}
+	var confirmTime, cancelTime, expiredTime sql.NullTime
+	var balanceAmount, balanceReservedAmount, balanceOverdraftLimit sql.NullInt64
+	if err := rows.Scan(
+		&pt.UUID, &pt.UserID, &pt.Currency, &pt.Amount, &pt.RequestFingerprint, &pt.Status, &pt.TryTime, &pt.ExpireTime, &confirmTime, &cancelTime, &expiredTime, &pt.RefundedAmount,
+		&balanceAmount, &balanceReservedAmount, &balanceOverdraftLimit,
+	); err != nil {
+		return nil, err
+	}
+	if balanceAmount.Valid {
+		pt.ResultBalance = &model.Balance{
+			UserID:         pt.UserID,
+			Currency:       pt.Currency,
+			Amount:         balanceAmount.Int64,
+			ReservedAmount: balanceReservedAmount.Int64,
+			OverdraftLimit: balanceOverdraftLimit.Int64,
+		}
+	}
+	if confirmTime.Valid {
+		pt.ConfirmTime = confirmTime.Time
+	}
+	if cancelTime.Valid {
+		pt.CancelTime = cancelTime.Time
+	}
+	if expiredTime.Valid {
+		pt.ExpiredTime = expiredTime.Time
+	}
+	return pt, nil
+}
+
+// saveResultBalance saves the wallet of the payment as changed in the transaction, which the retries of the request return.
+func saveResultBalance(ctx context.Context, db dbContext, pt *model.PaymentTransaction) error {
+	balance, err := findBalance(ctx, db, pt.UserID, pt.Currency, false)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx,
+		`UPDATE payment_transactions SET balance_amount = $1, balance_reserved_amount = $2, balance_overdraft_limit = $3 WHERE uuid = $4`,
+		balance.Amount, balance.ReservedAmount, balance.OverdraftLimit, pt.UUID,
+	)
+	return err
+}
+
+// releaseReserved releases the reserved amounts of the wallets in (user_id, currency) order to avoid deadlocks.
+func releaseReserved(ctx context.Context, db dbContext, reserved map[model.WalletKey]int64) error {
+	keys := make([]model.WalletKey, 0, len(reserved))
+	for key := range reserved {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].UserID != keys[j].UserID {
+			return keys[i].UserID < keys[j].UserID
+		}
+		return keys[i].Currency < keys[j].Currency
+	})
+	for _, key := range keys {
+		if _, err := db.ExecContext(ctx,
+			`UPDATE balances SET reserved_amount = reserved_amount - $1 WHERE user_id = $2 AND currency = $3`,
+			reserved[key], key.UserID, key.Currency,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+func newPaymentTransactionRepo(t *testing.T) *PaymentTransactionRepository {
+	t.Helper()
+	db := newTestConnection(t)
+	return NewPaymentTransactionRepository(db)
+}
+
+func TestPaymentTransactionRepository_List(t *testing.T) {
+	repo := newPaymentTransactionRepo(t)
+	users := createSampleUsers(t, repo.DB, 2)
+	now := time.Now().Truncate(time.Second)
+	samples := []*model.PaymentTransaction{
+		{UUID: "a", UserID: users[0].ID, Amount: 10, TryTime: now.Add(-3 * time.Minute), ConfirmTime: now},
+		{UUID: "b", UserID: users[0].ID, Amount: -20, TryTime: now.Add(-2 * time.Minute), CancelTime: now},
+		{UUID: "c", UserID: users[0].ID, Amount: 30, TryTime: now.Add(-1 * time.Minute)},
+		{UUID: "d", UserID: users[0].ID, Amount: -40, TryTime: now.Add(-1 * time.Minute)},
+		{UUID: "e", UserID: users[1].ID, Amount: 50, TryTime: now},
+	}
+	for _, pt := range samples {
+		createSamplePaymentTransaction(t, repo.DB, pt)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		userID   uint
+		filter   model.PaymentTransactionFilter
+		wantUUID []string
+	}{
+		{"新しい順に取得できる", users[0].ID, model.PaymentTransactionFilter{Limit: 10}, []string{"d", "c", "b", "a"}},
+		{"ステータスで絞り込める", users[0].ID, model.PaymentTransactionFilter{Status: model.PaymentStatusConfirmed, Limit: 10}, []string{"a"}},
+		{"金額の符号で絞り込める", users[0].ID, model.PaymentTransactionFilter{Sign: model.AmountSignNegative, Limit: 10}, []string{"d", "b"}},
+		{"Tryの時刻が同じ取引もカーソルの続きから取得できる", users[0].ID, model.PaymentTransactionFilter{BeforeTryTime: now.Add(-1 * time.Minute), BeforeUUID: "d", Limit: 2}, []string{"c", "b"}},
+		{"他のユーザの取引は取得しない", users[1].ID, model.PaymentTransactionFilter{Limit: 10}, []string{"e"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.List(ctx, tt.userID, tt.filter)
+			if err != nil {
+				t.Fatalf("PaymentTransactionRepository.List() error = %v", err)
+			}
+			var gotUUID []string
+			for _, pt := range got {
+				gotUUID = append(gotUUID, pt.UUID)
+			}
+			if diff := cmp.Diff(tt.wantUUID, gotUUID); diff != "" {
+				t.Errorf("PaymentTransactionRepository.List() mismatch (-want +got): \n %s", diff)
+			}
+		})
+	}
+}
+
+func TestPaymentTransactionRepository_Try(t *testing.T) {
+	repo := newPaymentTransactionRepo(t)
+	users := createSampleUsers(t, repo.DB, 2)
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		uuid         string
+		userID       uint
+		amount       int64
+		wantReserved int64
+		wantErr      error
+	}{
+		{"作成できる", "foo", users[0].ID, 100, 0, nil},
+		{"同じUUIDでは作成できない", "foo", users[1].ID, 100, 0, domain.ErrDuplicateUUID},
+		{"減算は残高を仮押さえする", "sub", users[1].ID, -400, 400, nil},
+		{"利用可能残高を超える減算はできない", "sub over", users[1].ID, -(initBalanceAmount - 400 + 1), 400, domain.ErrShortBalance},
+		{"存在しないユーザ", "none", 100, -1, 0, domain.ErrNoSuchEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.Try(ctx, tt.uuid, tt.userID, domain.JPY, tt.amount, time.Minute)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PaymentTransactionRepository.Try() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.Status != model.PaymentStatusTried || !got.MatchesRequest(tt.userID, domain.JPY, tt.amount) || !got.ExpireTime.After(got.TryTime) {
+				t.Errorf("PaymentTransactionRepository.Try() got = %+v", got)
+			}
+			b, err := findBalance(ctx, repo.DB, tt.userID, domain.JPY, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b.ReservedAmount != tt.wantReserved {
+				t.Errorf("reserved = %v, want %v", b.ReservedAmount, tt.wantReserved)
+			}
+		})
+	}
+}
+
+func TestPaymentTransactionRepository_Confirm(t *testing.T) {
+	repo := newPaymentTransactionRepo(t)
+	users := createSampleUsers(t, repo.DB, 1)
+	ctx := context.Background()
+	now := time.Now()
+	createSamplePaymentTransaction(t, repo.DB, &model.PaymentTransaction{UUID: "add", UserID: users[0].ID, Amount: 100, TryTime: now})
+	createSamplePaymentTransaction(t, repo.DB, &model.PaymentTransaction{UUID: "sub", UserID: users[0].ID, Amount: -300, TryTime: now})
+	createSamplePaymentTransaction(t, repo.DB, &model.PaymentTransaction{UUID: "cancelled", UserID: users[0].ID, Amount: -1, TryTime: now, CancelTime: now})
+	createSamplePaymentTransaction(t, repo.DB, &model.PaymentTransaction{UUID: "expired", UserID: users[0].ID, Amount: -1, TryTime: now.Add(-time.Hour), ExpireTime: now.Add(-time.Minute)})
+
+	tests := []struct {
+		name         string
+		uuid         string
+		wantBalance  int64
+		wantReserved int64
+		wantErr      error
+	}{
+		{"加算を確定できる", "add", initBalanceAmount + 100, 301, nil},
+		{"減算を確定すると仮押さえが解放される", "sub", initBalanceAmount - 200, 1, nil},
+		{"確定済みの取引", "sub", 0, 0, domain.ErrIllegalTransition},
+		{"キャンセル済みの取引", "cancelled", 0, 0, domain.ErrIllegalTransition},
+		{"期限切れの取引", "expired", 0, 0, domain.ErrExpiredTransaction},
+		{"存在しない取引", "none", 0, 0, domain.ErrInvalidUUID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.Confirm(ctx, tt.uuid)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PaymentTransactionRepository.Confirm() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.Status != model.PaymentStatusConfirmed || got.ConfirmTime.IsZero() {
+				t.Errorf("PaymentTransactionRepository.Confirm() got = %+v", got)
+			}
+			b, err := findBalance(ctx, repo.DB, users[0].ID, domain.JPY, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b.Amount != tt.wantBalance || b.ReservedAmount != tt.wantReserved {
+				t.Errorf("balance = %v, reserved = %v, want %v, %v", b.Amount, b.ReservedAmount, tt.wantBalance, tt.wantReserved)
+			}
+		})
+	}
+}
+
+func TestPaymentTransactionRepository_Cancel(t *testing.T) {
+	repo := newPaymentTransactionRepo(t)
+	users := createSampleUsers(t, repo.DB, 1)
+	ctx := context.Background()
+	now := time.Now()
+	createSamplePaymentTransaction(t, repo.DB, &model.PaymentTransaction{UUID: "sub", UserID: users[0].ID, Amount: -300, TryTime: now})
+	createSamplePaymentTransaction(t, repo.DB, &model.PaymentTransaction{UUID: "confirmed", UserID: users[0].ID, Amount: -1, TryTime: now, ConfirmTime: now})
+
+	if _, err := repo.Cancel(ctx, "sub"); err != nil {
+		t.Fatalf("PaymentTransactionRepository.Cancel() error = %v", err)
+	}
+	b, err := findBalance(ctx, repo.DB, users[0].ID, domain.JPY, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Amount != initBalanceAmount || b.ReservedAmount != 0 {
+		t.Errorf("balance = %v, reserved = %v, want %v, 0", b.Amount, b.ReservedAmount, initBalanceAmount)
+	}
+	if _, err := repo.Cancel(ctx, "confirmed"); !errors.Is(err, domain.ErrIllegalTransition) {
+		t.Errorf("PaymentTransactionRepository.Cancel() error = %v, wantErr %v", err, domain.ErrIllegalTransition)
+	}
+}
+
+func TestPaymentTransactionRepository_ExpireTries(t *testing.T) {
+	repo := newPaymentTransactionRepo(t)
+	users := createSampleUsers(t, repo.DB, 1)
+	ctx := context.Background()
+	now := time.Now()
+	createSamplePaymentTransaction(t, repo.DB, &model.PaymentTransaction{UUID: "expired1", UserID: users[0].ID, Amount: -100, TryTime: now.Add(-time.Hour), ExpireTime: now.Add(-2 * time.Minute)})
+	createSamplePaymentTransaction(t, repo.DB, &model.PaymentTransaction{UUID: "expired2", UserID: users[0].ID, Amount: -200, TryTime: now.Add(-time.Hour), ExpireTime: now.Add(-time.Minute)})
+	createSamplePaymentTransaction(t, repo.DB, &model.PaymentTransaction{UUID: "alive", UserID: users[0].ID, Amount: -300, TryTime: now})
+
+	n, err := repo.ExpireTries(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("PaymentTransactionRepository.ExpireTries() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("PaymentTransactionRepository.ExpireTries() = %v, want 2", n)
+	}
+	pt, err := repo.Get(ctx, "expired1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pt.Status != model.PaymentStatusExpired {
+		t.Errorf("status = %v, want %v", pt.Status, model.PaymentStatusExpired)
+	}
+	b, err := findBalance(ctx, repo.DB, users[0].ID, domain.JPY, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.ReservedAmount != 300 {
+		t.Errorf("reserved = %v, want 300", b.ReservedAmount)
+	}
+}
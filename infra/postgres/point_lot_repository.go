@@ -0,0 +1,275 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type PointLotRepository struct {
+	DB *sql.DB
+}
+
+func NewPointLotRepository(db *sql.DB) *PointLotRepository {
+	return &PointLotRepository{DB: db}
+}
+
+func (r *PointLotRepository) ListByUser(ctx context.Context, userID uint) ([]*model.PointLot, error) {
+	query := `
+	SELECT id, user_id, amount, remaining_amount, expire_time, source_type, source_id, create_time
+	FROM point_lots WHERE user_id = $1 ORDER BY id ASC`
+	rows, err := r.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lots []*model.PointLot
+	for rows.Next() {
+		lot, err := rowsToPointLot(rows)
+		if err != nil {
+			return nil, err
+		}
+		lots = append(lots, lot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+
+func (r *PointLotRepository) ListExpirations(ctx context.Context, userID uint, now time.Time, limit int) ([]*model.PointExpiration, error) {
+	query := `
+	SELECT expire_time, SUM(remaining_amount)::bigint
+	FROM point_lots
+	WHERE user_id = $1 AND remaining_amount > 0 AND expire_time > $2
+	GROUP BY expire_time
+	ORDER BY expire_time ASC LIMIT $3`
+	rows, err := r.DB.QueryContext(ctx, query, userID, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var expirations []*model.PointExpiration
+	for rows.Next() {
+		e := &model.PointExpiration{}
+		if err := rows.Scan(&e.ExpireTime, &e.Amount); err != nil {
+			return nil, err
+		}
+		expirations = append(expirations, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return expirations, nil
+}
+
+func (r *PointLotRepository) ExpireLots(ctx context.Context, now time.Time, limit int) (int, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// 失効させるロットを持つユーザを選ぶ。仮押さえ中のポイントしか残っていないユーザは選ばない
+	query := `
+	SELECT DISTINCT l.user_id
+	FROM point_lots l JOIN balances b ON b.user_id = l.user_id AND b.currency = $1
+	WHERE l.expire_time <= $2 AND l.remaining_amount > 0 AND b.amount > b.reserved_amount
+	ORDER BY l.user_id ASC LIMIT $3`
+	userIDs, err := queryUserIDs(ctx, tx, query, domain.Point, now, limit)
+	if err != nil {
+		return 0, err
+	}
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+	// 支払いなどと同じく残高、ロットの順にロックしてデッドロックを避ける
+	available := make(map[uint]int64, len(userIDs))
+	for _, userID := range userIDs {
+		b, err := findBalance(ctx, tx, userID, domain.Point, true)
+		if err != nil {
+			return 0, err
+		}
+		available[userID] = b.Amount - b.ReservedAmount
+	}
+	lots, err := findExpiredPointLots(ctx, tx, userIDs, now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	// ロットごとに失効の勘定への仕訳として減算する。
+	// 期限の近いロットから消費するので、減算で消費されるのは失効したロット自身になる。
+	// 仮押さえ中のポイントは支払いのConfirmで使うので失効させず、次回以降に持ち越す
+	n := 0
+	for _, lot := range lots {
+		amount := lot.RemainingAmount
+		if amount > available[lot.UserID] {
+			amount = available[lot.UserID]
+		}
+		if amount <= 0 {
+			continue
+		}
+		entry := model.NewJournalEntry(model.JournalSourcePointExpiration, strconv.FormatUint(lot.ID, 10),
+			&model.Posting{Account: model.UserAccount(lot.UserID), Currency: domain.Point, Amount: -amount},
+			&model.Posting{Account: model.AccountPointExpiration, Currency: domain.Point, Amount: amount},
+		)
+		if err := postJournalEntry(ctx, tx, entry); err != nil {
+			return 0, err
+		}
+		available[lot.UserID] -= amount
+		n++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// findExpiredPointLots locks at most limit lots of the users whose expire time has passed at now, from the nearest one.
+func findExpiredPointLots(ctx context.Context, db dbContext, userIDs []uint, now time.Time, limit int) ([]*model.PointLot, error) {
+	var args queryArgs
+	placeholders := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		placeholders = append(placeholders, args.bind(userID))
+	}
+	query := `
+	SELECT id, user_id, amount, remaining_amount, expire_time, source_type, source_id, create_time
+	FROM point_lots
+	WHERE user_id IN (` + strings.Join(placeholders, ", ") + `) AND expire_time <= ` + args.bind(now) + ` AND remaining_amount > 0
+	ORDER BY expire_time ASC, id ASC LIMIT ` + args.bind(limit) + ` FOR UPDATE`
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lots []*model.PointLot
+	for rows.Next() {
+		lot, err := rowsToPointLot(rows)
+		if err != nil {
+			return nil, err
+		}
+		lots = append(lots, lot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+
+// pointLotDelta returns the change of the points covered by the lots when the point balance changes from before to after.
+// The lots cover only the positive part of the balance, so a credit to a negative balance first fills the deficit.
+func pointLotDelta(before, after int64) int64 {
+	if before < 0 {
+		before = 0
+	}
+	if after < 0 {
+		after = 0
+	}
+	return after - before
+}
+
+// applyPointLots creates a lot for each user whose points increase and consumes the lots of each user whose points decrease.
+func applyPointLots(ctx context.Context, db dbContext, entry *model.JournalEntry, deltas map[uint]int64) error {
+	userIDs := make([]uint, 0, len(deltas))
+	for userID := range deltas {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+
+	var expireTime sql.NullTime
+	if !entry.PointExpireTime.IsZero() {
+		expireTime.Valid = true
+		expireTime.Time = entry.PointExpireTime
+	}
+	var lotArgs queryArgs
+	var lotStrings []string
+	for _, userID := range userIDs {
+		delta := deltas[userID]
+		switch {
+		case delta > 0:
+			lotStrings = append(lotStrings, "("+strings.Join([]string{
+				lotArgs.bind(userID), lotArgs.bind(delta), lotArgs.bind(delta), lotArgs.bind(expireTime),
+				lotArgs.bind(entry.SourceType), lotArgs.bind(entry.SourceID), lotArgs.bind(entry.CreateTime),
+			}, ", ")+")")
+		case delta < 0:
+			if err := consumePointLots(ctx, db, userID, -delta); err != nil {
+				return err
+			}
+		}
+	}
+	if len(lotStrings) == 0 {
+		return nil
+	}
+	query := "INSERT INTO point_lots (user_id, amount, remaining_amount, expire_time, source_type, source_id, create_time) VALUES " + strings.Join(lotStrings, ",")
+	_, err := db.ExecContext(ctx, query, lotArgs...)
+	return err
+}
+
+// consumePointLots consumes the amount from the lots of the user in order of expiry. The lots which never expire are consumed last.
+func consumePointLots(ctx context.Context, db dbContext, userID uint, amount int64) error {
+	query := `
+	SELECT id, remaining_amount FROM point_lots
+	WHERE user_id = $1 AND remaining_amount > 0
+	ORDER BY expire_time ASC NULLS LAST, id ASC FOR UPDATE`
+	rows, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+	type consumption struct {
+		id     uint64
+		amount int64
+	}
+	var consumptions []consumption
+	for rows.Next() && amount > 0 {
+		var id uint64
+		var remaining int64
+		if err := rows.Scan(&id, &remaining); err != nil {
+			rows.Close()
+			return err
+		}
+		if remaining > amount {
+			remaining = amount
+		}
+		consumptions = append(consumptions, consumption{id, remaining})
+		amount -= remaining
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	// ロットの合計は残高の正の部分と一致するので、足りなくなることはない
+	if amount > 0 {
+		return fmt.Errorf("%w: point lots of user %d are short by %d", domain.ErrLedgerInconsistent, userID, amount)
+	}
+	for _, c := range consumptions {
+		if _, err := db.ExecContext(ctx, "UPDATE point_lots SET remaining_amount = remaining_amount - $1 WHERE id = $2", c.amount, c.id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func rowsToPointLot(rows *sql.Rows) (*model.PointLot, error) {
+	lot := &model.PointLot{}
+	var expireTime sql.NullTime
+	if err := rows.Scan(&lot.ID, &lot.UserID, &lot.Amount, &lot.RemainingAmount, &expireTime, &lot.SourceType, &lot.SourceID, &lot.CreateTime); err != nil {
+		return nil, err
+	}
+	if expireTime.Valid {
+		lot.ExpireTime = expireTime.Time
+	}
+	return lot, nil
+}
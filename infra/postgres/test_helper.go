@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+func testDBName() string {
+	dbname := os.Getenv("DB_NAME")
+	return fmt.Sprintf("%s_test", dbname)
+}
+
+func newTestConnection(t *testing.T) *sql.DB {
+	db := newTestDBConnection(t)
+	if err := truncateTables(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+var testDB *sql.DB
+var initTestDB sync.Once
+
+func newTestDBConnection(t *testing.T) *sql.DB {
+	// 初回のみDBの初期化を行う
+	initTestDB.Do(func() {
+		db, err := sql.Open("pgx", DSN(os.Getenv("DB_HOST"), os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"), testDBName()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		testDB = db
+		testDB.SetMaxOpenConns(100)
+		testDB.SetMaxIdleConns(100)
+		testDB.SetConnMaxLifetime(10 * time.Second)
+	})
+	return testDB
+}
+
+func truncateTables(db *sql.DB) error {
+	rows, err := db.Query("SELECT table_name FROM information_schema.tables WHERE table_schema = 'public' AND table_name != 'migrations'")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var tableNames []string
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return err
+		}
+		tableNames = append(tableNames, tableName)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(tableNames) == 0 {
+		return nil
+	}
+	// 外部キーで参照し合うテーブルはまとめて空にする
+	_, err = db.Exec(fmt.Sprintf("TRUNCATE TABLE %s RESTART IDENTITY CASCADE", strings.Join(tableNames, ", ")))
+	return err
+}
+
+const initBalanceAmount = 1000
+
+func createSampleUsers(t *testing.T, db *sql.DB, count int) []*model.User {
+	t.Helper()
+	ctx := context.Background()
+	var users []*model.User
+	var userArgs, balanceArgs queryArgs
+	var userStrings, balanceStrings []string
+	for i := 1; i <= count; i++ {
+		user := &model.User{
+			ID:   uint(i),
+			Name: fmt.Sprintf("sample%d", i),
+			Tier: model.DefaultUserTier,
+		}
+		users = append(users, user)
+		userStrings = append(userStrings, "("+userArgs.bind(user.ID)+", "+userArgs.bind(user.Name)+")")
+		balanceStrings = append(balanceStrings, "("+balanceArgs.bind(user.ID)+", "+balanceArgs.bind(initBalanceAmount)+")")
+	}
+	if _, err := db.ExecContext(ctx, "INSERT INTO users (id, name) VALUES "+strings.Join(userStrings, ","), userArgs...); err != nil {
+		t.Fatalf("insert users error: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, "INSERT INTO balances (user_id, amount) VALUES "+strings.Join(balanceStrings, ","), balanceArgs...); err != nil {
+		t.Fatalf("insert balances error: %v", err)
+	}
+	// 初期残高を開始残高として仕訳しておく
+	postings := []*model.Posting{{Account: model.AccountOpeningBalance, Currency: domain.JPY, Amount: -initBalanceAmount * int64(len(users))}}
+	for _, u := range users {
+		postings = append(postings, &model.Posting{Account: model.UserAccount(u.ID), Currency: domain.JPY, Amount: initBalanceAmount})
+	}
+	if err := insertJournalEntry(ctx, db, model.NewJournalEntry(model.JournalSourceOpeningBalance, "sample", postings...)); err != nil {
+		t.Fatalf("insert journal entry error: %v", err)
+	}
+	return users
+}
+
+func createSamplePaymentTransaction(t *testing.T, db *sql.DB, pt *model.PaymentTransaction) {
+	t.Helper()
+	ctx := context.Background()
+	var confirmTime, cancelTime sql.NullTime
+	if !pt.ConfirmTime.IsZero() {
+		confirmTime.Valid = true
+		confirmTime.Time = pt.ConfirmTime
+	}
+	if !pt.CancelTime.IsZero() {
+		cancelTime.Valid = true
+		cancelTime.Time = pt.CancelTime
+	}
+	expireTime := pt.ExpireTime
+	if expireTime.IsZero() {
+		expireTime = pt.TryTime.Add(model.DefaultTryTTL)
+	}
+	if pt.Currency == "" {
+		pt.Currency = domain.DefaultCurrency
+	}
+	// ステータスの指定がなければ、時刻から決める
+	if pt.Status == "" {
+		switch {
+		case confirmTime.Valid:
+			pt.Status = model.PaymentStatusConfirmed
+		case cancelTime.Valid:
+			pt.Status = model.PaymentStatusCancelled
+		default:
+			pt.Status = model.PaymentStatusTried
+		}
+	}
+	if _, err := db.ExecContext(ctx,
+		"INSERT INTO payment_transactions (uuid, user_id, currency, amount, request_fingerprint, status, try_time, expire_time, confirm_time, cancel_time) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
+		pt.UUID, pt.UserID, pt.Currency, pt.Amount, model.RequestFingerprint(pt.UserID, pt.Amount), pt.Status, pt.TryTime, expireTime, confirmTime, cancelTime,
+	); err != nil {
+		t.Fatalf("insert payment_transactions error: %v", err)
+	}
+	// Try状態の減算は残高を仮押さえしている
+	if pt.IsTryStatus() && pt.Amount < 0 {
+		if _, err := db.ExecContext(ctx,
+			"UPDATE balances SET reserved_amount = reserved_amount + $1 WHERE user_id = $2 AND currency = $3",
+			-pt.Amount, pt.UserID, pt.Currency,
+		); err != nil {
+			t.Fatalf("update balances error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package publisher
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+// FakePublisher is an in-process publisher for tests, which records the events instead of delivering them.
+type FakePublisher struct {
+	mu     sync.Mutex
+	events []*model.OutboxEvent
+	errs   []error
+}
+
+func NewFakePublisher() *FakePublisher {
+	return &FakePublisher{}
+}
+
+// FailNext makes the next calls of Publish fail with the errors in order.
+func (p *FakePublisher) FailNext(errs ...error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.errs = append(p.errs, errs...)
+}
+
+func (p *FakePublisher) Publish(ctx context.Context, event *model.OutboxEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.errs) > 0 {
+		err := p.errs[0]
+		p.errs = p.errs[1:]
+		return err
+	}
+	copied := *event
+	p.events = append(p.events, &copied)
+	return nil
+}
+
+// Events returns the events published so far, in the order of publication.
+func (p *FakePublisher) Events() []*model.OutboxEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*model.OutboxEvent(nil), p.events...)
+}
@@ -0,0 +1,54 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+// HTTPPublisher posts each event in JSON to the webhook URL.
+type HTTPPublisher struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewHTTPPublisher(url string, timeout time.Duration) *HTTPPublisher {
+	return &HTTPPublisher{
+		URL:    url,
+		Client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Publish posts the event, and fails unless the webhook responds with a 2xx status.
+func (p *HTTPPublisher) Publish(ctx context.Context, event *model.OutboxEvent) error {
+	body, err := event.Envelope()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	// 受け取り側が本文を読まずに重複を判定できるように、ヘッダにも付ける
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Sequence", strconv.FormatUint(event.Sequence, 10))
+	req.Header.Set("X-Event-Type", string(event.Type))
+
+	res, err := p.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	// コネクションを再利用するために本文を読み捨てる
+	_, _ = io.Copy(io.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+	return nil
+}
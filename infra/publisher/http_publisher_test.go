@@ -0,0 +1,83 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+func TestHTTPPublisher_Publish(t *testing.T) {
+	event, err := model.NewOutboxEvent(model.EventPaymentConfirmed, &model.PaymentEventPayload{UUID: "foo", UserID: 1, Amount: -100}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	event.Sequence = 42
+
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{"2xxなら成功", http.StatusNoContent, false},
+		{"4xxは失敗", http.StatusBadRequest, true},
+		{"5xxは失敗", http.StatusServiceUnavailable, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got struct {
+				header http.Header
+				body   []byte
+			}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got.header = r.Header
+				got.body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			p := NewHTTPPublisher(server.URL, time.Second)
+			if err := p.Publish(context.Background(), event); (err != nil) != tt.wantErr {
+				t.Errorf("HTTPPublisher.Publish() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.header.Get("X-Event-Sequence") != "42" || got.header.Get("X-Event-Type") != string(model.EventPaymentConfirmed) {
+				t.Errorf("header = %v", got.header)
+			}
+			var envelope struct {
+				Sequence uint64                    `json:"sequence"`
+				Type     model.OutboxEventType     `json:"type"`
+				Payload  model.PaymentEventPayload `json:"payload"`
+			}
+			if err := json.Unmarshal(got.body, &envelope); err != nil {
+				t.Fatal(err)
+			}
+			if envelope.Sequence != 42 || envelope.Type != model.EventPaymentConfirmed || envelope.Payload.UUID != "foo" {
+				t.Errorf("body = %s", got.body)
+			}
+		})
+	}
+}
+
+func TestHTTPPublisher_PublishTimeout(t *testing.T) {
+	event, err := model.NewOutboxEvent(model.EventPaymentConfirmed, &model.PaymentEventPayload{UUID: "foo"}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	// 応答のないWebhookは、タイムアウトで失敗にして後でリトライする
+	p := NewHTTPPublisher(server.URL, 10*time.Millisecond)
+	if err := p.Publish(context.Background(), event); err == nil {
+		t.Error("HTTPPublisher.Publish() error = nil, want timeout")
+	}
+}
@@ -0,0 +1,48 @@
+package publisher
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+// WriterPublisher writes each event in a line of JSON, to stdout or to a file.
+type WriterPublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterPublisher(w io.Writer) *WriterPublisher {
+	return &WriterPublisher{w: w}
+}
+
+// NewFilePublisher appends the events to the file, creating it when it does not exist.
+func NewFilePublisher(path string) (*WriterPublisher, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return NewWriterPublisher(f), nil
+}
+
+func (p *WriterPublisher) Publish(ctx context.Context, event *model.OutboxEvent) error {
+	line, err := event.Envelope()
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.w.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the file. It does nothing when writing to stdout or another writer which is not a file.
+func (p *WriterPublisher) Close() error {
+	if f, ok := p.w.(*os.File); ok && f != os.Stdout && f != os.Stderr {
+		return f.Close()
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package publisher
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+func TestFilePublisher_Publish(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	ctx := context.Background()
+
+	// 再起動してもファイルに追記する
+	for i := 1; i <= 2; i++ {
+		p, err := NewFilePublisher(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		event, err := model.NewOutboxEvent(model.EventBalancesAddedToUsers, &model.AddToUsersEventPayload{Amount: 10, UserIDs: []uint{1, 2}}, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		event.Sequence = uint64(i)
+		if err := p.Publish(ctx, event); err != nil {
+			t.Fatal(err)
+		}
+		if err := p.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var sequences []uint64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var envelope struct {
+			Sequence uint64                       `json:"sequence"`
+			Type     model.OutboxEventType        `json:"type"`
+			Payload  model.AddToUsersEventPayload `json:"payload"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &envelope); err != nil {
+			t.Fatal(err)
+		}
+		if envelope.Type != model.EventBalancesAddedToUsers || envelope.Payload.Amount != 10 {
+			t.Errorf("line = %s", scanner.Bytes())
+		}
+		sequences = append(sequences, envelope.Sequence)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(sequences) != 2 || sequences[0] != 1 || sequences[1] != 2 {
+		t.Errorf("sequences = %v, want [1 2]", sequences)
+	}
+}
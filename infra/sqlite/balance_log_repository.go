@@ -0,0 +1,63 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type BalanceLogRepository struct {
+	DB *sql.DB
+}
+
+func NewBalanceLogRepository(db *sql.DB) *BalanceLogRepository {
+	return &BalanceLogRepository{DB: db}
+}
+
+func (r *BalanceLogRepository) List(ctx context.Context, userID uint, filter model.BalanceLogFilter) ([]*model.BalanceLog, error) {
+	query := `
+	SELECT
+		id, user_id, currency, before_amount, after_amount, delta,
+		source_type, source_id, reason, create_time
+	FROM balance_logs WHERE user_id = ?`
+	args := []interface{}{userID}
+	if filter.Currency != "" {
+		query += ` AND currency = ?`
+		args = append(args, filter.Currency)
+	}
+	if filter.BeforeID > 0 {
+		query += ` AND id < ?`
+		args = append(args, filter.BeforeID)
+	}
+	if !filter.From.IsZero() {
+		query += ` AND create_time >= ?`
+		args = append(args, utc(filter.From))
+	}
+	if !filter.To.IsZero() {
+		query += ` AND create_time < ?`
+		args = append(args, utc(filter.To))
+	}
+	// 新しい順に、IDをカーソルとしてページングする
+	query += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, filter.Limit)
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []*model.BalanceLog
+	for rows.Next() {
+		l := &model.BalanceLog{}
+		if err := rows.Scan(&l.ID, &l.UserID, &l.Currency, &l.BeforeAmount, &l.AfterAmount, &l.Delta, &l.SourceType, &l.SourceID, &l.Reason, &l.CreateTime); err != nil {
+			return nil, err
+		}
+		logs = append(logs, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
@@ -0,0 +1,252 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+type BalanceRepository struct {
+	DB *sql.DB
+}
+
+func NewBalanceRepository(db *sql.DB) *BalanceRepository {
+	return &BalanceRepository{DB: db}
+}
+
+func (r *BalanceRepository) Get(ctx context.Context, userID uint, currency domain.Currency) (*model.Balance, error) {
+	return findBalance(ctx, r.DB, userID, currency)
+}
+
+func (r *BalanceRepository) List(ctx context.Context, userID uint) ([]*model.Balance, error) {
+	query := `
+	SELECT u.id, b.currency, b.amount, b.reserved_amount, b.overdraft_limit
+	FROM users u LEFT JOIN balances b ON b.user_id = u.id
+	WHERE u.id = ? ORDER BY b.currency ASC`
+	rows, err := r.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	found := false
+	balances := []*model.Balance{}
+	for rows.Next() {
+		found = true
+		var currency sql.NullString
+		var amount, reservedAmount, overdraftLimit sql.NullInt64
+		balance := &model.Balance{}
+		if err := rows.Scan(&balance.UserID, &currency, &amount, &reservedAmount, &overdraftLimit); err != nil {
+			return nil, err
+		}
+		// ウォレットを1つも持っていないユーザ
+		if !currency.Valid {
+			continue
+		}
+		balance.Currency = domain.Currency(currency.String)
+		balance.Amount = amount.Int64
+		balance.ReservedAmount = reservedAmount.Int64
+		balance.OverdraftLimit = overdraftLimit.Int64
+		balances = append(balances, balance)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, domain.ErrNoSuchEntity
+	}
+	return balances, nil
+}
+
+func (r *BalanceRepository) SetOverdraftLimit(ctx context.Context, userID uint, currency domain.Currency, limit int64) (*model.Balance, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := findBalance(ctx, tx, userID, currency); err != nil {
+		return nil, err
+	}
+	// 与信枠を下げて残高が枠を超えて負になっていても、そのままにする（以降の減算が残高不足になる）
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO balances (user_id, currency, overdraft_limit) VALUES (?, ?, ?)
+		ON CONFLICT (user_id, currency) DO UPDATE SET overdraft_limit = excluded.overdraft_limit`,
+		userID, currency, limit,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return findBalance(ctx, r.DB, userID, currency)
+}
+
+func (r *BalanceRepository) AddToUsers(ctx context.Context, currency domain.Currency, amount int64, pointExpireTime time.Time, limit, offset int) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// 対象のユーザ取得（その通貨のウォレットがなければ加算時に作られる）
+	userIDs, err := queryUserIDs(ctx, tx, `SELECT id FROM users ORDER BY id ASC LIMIT ? OFFSET ?`, limit, offset)
+	if err != nil {
+		return err
+	}
+
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	// キャンペーンの原資の勘定を相手にした仕訳として残高を加算する
+	postings := make([]*model.Posting, 0, len(userIDs)+1)
+	for _, userID := range userIDs {
+		postings = append(postings, &model.Posting{Account: model.UserAccount(userID), Currency: currency, Amount: amount})
+	}
+	total, err := domain.NewMoney(-amount, currency).Mul(int64(len(userIDs)))
+	if err != nil {
+		return err
+	}
+	postings = append(postings, &model.Posting{Account: model.AccountCampaignFunding, Currency: currency, Amount: total.Amount})
+	entry := model.NewJournalEntry(model.JournalSourceAddToUsers, fmt.Sprintf("currency=%s,limit=%d,offset=%d", currency, limit, offset), postings...)
+	entry.PointExpireTime = pointExpireTime
+	if err := postJournalEntry(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *BalanceRepository) CountTargets(ctx context.Context, currency domain.Currency, target model.BulkCreditTarget) (int, error) {
+	if target.Type == model.BulkCreditTargetUserIDs && len(target.UserIDs) == 0 {
+		return 0, nil
+	}
+	condition, conditionArgs := targetCondition(target, target.UserIDs)
+	query := `SELECT COUNT(u.id)` + targetFrom + ` WHERE 1 = 1` + condition
+	args := append([]interface{}{currency}, conditionArgs...)
+	var count int
+	if err := r.DB.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *BalanceRepository) ListTargets(ctx context.Context, currency domain.Currency, target model.BulkCreditTarget, afterUserID uint, limit int) ([]uint, error) {
+	for {
+		// ユーザの指定がある場合は、カーソルより後の指定されたユーザのうち、存在するユーザだけを対象にする
+		var candidates []uint
+		if target.Type == model.BulkCreditTargetUserIDs {
+			candidates = target.UserIDsAfter(afterUserID, limit)
+			if len(candidates) == 0 {
+				return nil, nil
+			}
+		}
+		condition, conditionArgs := targetCondition(target, candidates)
+		query := `SELECT u.id` + targetFrom + ` WHERE u.id > ?` + condition + ` ORDER BY u.id ASC LIMIT ?`
+		args := append([]interface{}{currency, afterUserID}, conditionArgs...)
+		args = append(args, limit)
+		userIDs, err := queryUserIDs(ctx, r.DB, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		if len(userIDs) > 0 || candidates == nil {
+			return userIDs, nil
+		}
+		// 存在しないユーザだけが指定されていた範囲は飛ばす
+		afterUserID = candidates[len(candidates)-1]
+	}
+}
+
+// ウォレットを持っていないユーザも対象にするため、usersを起点にその通貨のウォレットを結合する
+const targetFrom = ` FROM users u LEFT JOIN balances b ON b.user_id = u.id AND b.currency = ?`
+
+// targetCondition returns the conditions on users u and their wallets b which select the target users, and their arguments.
+func targetCondition(target model.BulkCreditTarget, userIDs []uint) (string, []interface{}) {
+	var condition string
+	var args []interface{}
+	switch target.Type {
+	case model.BulkCreditTargetUserIDs:
+		placeholders := make([]string, 0, len(userIDs))
+		for _, userID := range userIDs {
+			placeholders = append(placeholders, "?")
+			args = append(args, userID)
+		}
+		condition = ` AND u.id IN (` + strings.Join(placeholders, ",") + `)`
+	case model.BulkCreditTargetCreatedBetween:
+		if !target.CreatedFrom.IsZero() {
+			condition += ` AND u.create_time >= ?`
+			args = append(args, utc(target.CreatedFrom))
+		}
+		if !target.CreatedTo.IsZero() {
+			condition += ` AND u.create_time < ?`
+			args = append(args, utc(target.CreatedTo))
+		}
+	case model.BulkCreditTargetBalanceBelow:
+		// ウォレットがなければ残高0として扱う
+		condition = ` AND COALESCE(b.amount, 0) < ?`
+		args = append(args, target.BalanceBelow)
+	}
+	return condition, args
+}
+
+func queryUserIDs(ctx context.Context, db dbContext, query string, args ...interface{}) ([]uint, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []uint
+	for rows.Next() {
+		var userID uint
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+// findBalance returns the wallet of the user in the currency, or an empty one when the user has never held the currency.
+// It returns domain.ErrNoSuchEntity when the user does not exist.
+// SQLite has no row locks; the transactions are serialized by the single connection instead.
+func findBalance(ctx context.Context, db dbContext, userID uint, currency domain.Currency) (*model.Balance, error) {
+	query := `
+	SELECT u.id, COALESCE(b.amount, 0), COALESCE(b.reserved_amount, 0), COALESCE(b.overdraft_limit, 0)
+	FROM users u LEFT JOIN balances b ON b.user_id = u.id AND b.currency = ?
+	WHERE u.id = ?`
+	balance := &model.Balance{Currency: currency}
+	err := db.QueryRowContext(ctx, query, currency, userID).Scan(&balance.UserID, &balance.Amount, &balance.ReservedAmount, &balance.OverdraftLimit)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNoSuchEntity
+	}
+	if err != nil {
+		return nil, err
+	}
+	return balance, nil
+}
+
+func rowsToBalance(rows *sql.Rows) (*model.Balance, error) {
+	balance := &model.Balance{}
+	if err := rows.Scan(&balance.UserID, &balance.Currency, &balance.Amount, &balance.ReservedAmount, &balance.OverdraftLimit); err != nil {
+		return nil, err
+	}
+	return balance, nil
+}
@@ -0,0 +1,23 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/kawabatas/m-bank/domain/model"
+	"github.com/kawabatas/m-bank/domain/repository/repositorytest"
+)
+
+func newConformanceFixture(t *testing.T) *repositorytest.Fixture {
+	db := newTestConnection(t)
+	return &repositorytest.Fixture{
+		BalanceRepo: NewBalanceRepository(db),
+		PaymentRepo: NewPaymentTransactionRepository(db),
+		CreateUsers: func(t *testing.T, count int) []*model.User {
+			return createSampleUsers(t, db, count)
+		},
+	}
+}
+
+func TestConformance(t *testing.T) {
+	repositorytest.Run(t, newConformanceFixture)
+}
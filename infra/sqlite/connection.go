@@ -0,0 +1,48 @@
+package sqlite
+
+import (
+	"database/sql"
+	"embed"
+	"io/fs"
+	"net/http"
+
+	migrate "github.com/rubenv/sql-migrate"
+	_ "modernc.org/sqlite"
+)
+
+// マイグレーションはバイナリに埋め込み、起動時に適用する
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// DSN create SQLite Data Source Name for the modernc.org/sqlite driver from the path of the database file.
+func DSN(path string) string {
+	return path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_time_format=sqlite"
+}
+
+// Open opens the database file, creating it when it does not exist, and applies the migrations to it.
+// SQLite locks the whole database for writing, so the pool has a single connection which serializes the transactions
+// in place of the row locks of the other databases. Nothing may use the pool while a transaction is open on it.
+func Open(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", DSN(path))
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	if err := Migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// Migrate applies the migrations which have not been applied yet.
+func Migrate(db *sql.DB) error {
+	dir, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		return err
+	}
+	source := &migrate.HttpFileSystemMigrationSource{FileSystem: http.FS(dir)}
+	_, err = migrate.MigrationSet{TableName: "migrations"}.Exec(db, "sqlite3", source, migrate.Up)
+	return err
+}
@@ -0,0 +1,142 @@
+package sqlite
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+// postJournalEntry records the entry and applies its user postings to the wallets in the same DB transaction.
+// A wallet which the user has never held is created on the first posting to it.
+func postJournalEntry(ctx context.Context, db dbContext, entry *model.JournalEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	if err := insertJournalEntry(ctx, db, entry); err != nil {
+		return err
+	}
+
+	deltas, err := entry.UserDeltas()
+	if err != nil {
+		return err
+	}
+	if len(deltas) == 0 {
+		return nil
+	}
+	keys := make([]model.WalletKey, 0, len(deltas))
+	for key := range deltas {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].UserID != keys[j].UserID {
+			return keys[i].UserID < keys[j].UserID
+		}
+		return keys[i].Currency < keys[j].Currency
+	})
+	keyStrings := make([]string, 0, len(keys))
+	keyArgs := make([]interface{}, 0, len(keys)*2)
+	for _, key := range keys {
+		keyStrings = append(keyStrings, "(?, ?)")
+		keyArgs = append(keyArgs, key.UserID, key.Currency)
+	}
+	// まだないウォレットを作る。存在しないユーザは結合で除かれ、下の件数チェックでエラーになる
+	insertQuery := `
+	INSERT OR IGNORE INTO balances (user_id, currency)
+	SELECT u.id, k.column2 FROM (VALUES ` + strings.Join(keyStrings, ",") + `) AS k
+	JOIN users u ON u.id = k.column1`
+	if _, err := db.ExecContext(ctx, insertQuery, keyArgs...); err != nil {
+		return err
+	}
+	// SQLiteの行値のINは右辺に副問い合わせしか取れないので、VALUESで渡す
+	fetchQuery := "SELECT user_id, currency, amount, reserved_amount, overdraft_limit FROM balances WHERE (user_id, currency) IN (VALUES " + strings.Join(keyStrings, ",") + ") ORDER BY user_id ASC, currency ASC"
+	rows, err := db.QueryContext(ctx, fetchQuery, keyArgs...)
+	if err != nil {
+		return err
+	}
+	var balances []*model.Balance
+	for rows.Next() {
+		b, err := rowsToBalance(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		balances = append(balances, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(balances) != len(keys) {
+		return domain.ErrNoSuchEntity
+	}
+
+	// 同じ通貨で同じ増減額のウォレットはまとめて更新する
+	type update struct {
+		currency domain.Currency
+		delta    int64
+	}
+	updates := map[update][]interface{}{}
+	var logStrings []string
+	var logArgs []interface{}
+	pointDeltas := map[uint]int64{}
+	for _, b := range balances {
+		delta := deltas[b.Key()]
+		after, err := b.Money().Add(domain.NewMoney(delta, b.Currency))
+		if err != nil {
+			return err
+		}
+		if b.IsOverdrawn(after.Amount) && delta < 0 && !entry.AllowNegativeBalance {
+			return domain.ErrShortBalance
+		}
+		if b.Currency == domain.Point {
+			pointDeltas[b.UserID] = pointLotDelta(b.Amount, after.Amount)
+		}
+		u := update{currency: b.Currency, delta: delta}
+		updates[u] = append(updates[u], b.UserID)
+		logStrings = append(logStrings, "(?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		logArgs = append(logArgs, b.UserID, b.Currency, b.Amount, after.Amount, delta, entry.SourceType, entry.SourceID, entry.Reason, utc(entry.CreateTime))
+	}
+	for u, ids := range updates {
+		query := "UPDATE balances SET amount = amount + ? WHERE currency = ? AND user_id IN (?" + strings.Repeat(",?", len(ids)-1) + ")"
+		args := append([]interface{}{u.delta, u.currency}, ids...)
+		if _, err := db.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+	logQuery := "INSERT INTO balance_logs (user_id, currency, before_amount, after_amount, delta, source_type, source_id, reason, create_time) VALUES " + strings.Join(logStrings, ",")
+	if _, err := db.ExecContext(ctx, logQuery, logArgs...); err != nil {
+		return err
+	}
+	return applyPointLots(ctx, db, entry, pointDeltas)
+}
+
+// insertJournalEntry inserts the entry and its postings without touching the balances.
+func insertJournalEntry(ctx context.Context, db dbContext, entry *model.JournalEntry) error {
+	res, err := db.ExecContext(ctx,
+		"INSERT INTO journal_entries (source_type, source_id, reason, create_time) VALUES (?, ?, ?, ?)",
+		entry.SourceType, entry.SourceID, entry.Reason, utc(entry.CreateTime),
+	)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	entry.ID = uint64(id)
+
+	valueStrings := make([]string, 0, len(entry.Postings))
+	valueArgs := make([]interface{}, 0, len(entry.Postings)*4)
+	for _, p := range entry.Postings {
+		valueStrings = append(valueStrings, "(?, ?, ?, ?)")
+		valueArgs = append(valueArgs, entry.ID, p.Account, p.Currency, p.Amount)
+	}
+	insertQuery := "INSERT INTO postings (journal_entry_id, account, currency, amount) VALUES " + strings.Join(valueStrings, ",")
+	if _, err := db.ExecContext(ctx, insertQuery, valueArgs...); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+func newPointLotRepo(t *testing.T) *PointLotRepository {
+	t.Helper()
+	db := newTestConnection(t)
+	return NewPointLotRepository(db)
+}
+
+func TestPointLotRepository_Consume(t *testing.T) {
+	repo := newPointLotRepo(t)
+	users := createSampleUsers(t, repo.DB, 1)
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	balanceRepo := NewBalanceRepository(repo.DB)
+	paymentRepo := NewPaymentTransactionRepository(repo.DB)
+	// 失効しないポイント、10日後と5日後に失効するポイントの順に加算する
+	if _, err := paymentRepo.Try(ctx, "add", users[0].ID, domain.Point, 100, time.Minute); err != nil {
+		t.Fatalf("PaymentTransactionRepository.Try() error = %v", err)
+	}
+	if _, err := paymentRepo.Confirm(ctx, "add"); err != nil {
+		t.Fatalf("PaymentTransactionRepository.Confirm() error = %v", err)
+	}
+	if err := balanceRepo.AddToUsers(ctx, domain.Point, 200, now.AddDate(0, 0, 10), 10, 0); err != nil {
+		t.Fatalf("BalanceRepository.AddToUsers() error = %v", err)
+	}
+	if err := balanceRepo.AddToUsers(ctx, domain.Point, 300, now.AddDate(0, 0, 5), 10, 0); err != nil {
+		t.Fatalf("BalanceRepository.AddToUsers() error = %v", err)
+	}
+
+	// 失効日時の近いロットから消費し、失効しないロットは最後に消費する
+	if _, err := paymentRepo.Try(ctx, "sub", users[0].ID, domain.Point, -400, time.Minute); err != nil {
+		t.Fatalf("PaymentTransactionRepository.Try() error = %v", err)
+	}
+	if _, err := paymentRepo.Confirm(ctx, "sub"); err != nil {
+		t.Fatalf("PaymentTransactionRepository.Confirm() error = %v", err)
+	}
+
+	lots, err := repo.ListByUser(ctx, users[0].ID)
+	if err != nil {
+		t.Fatalf("PointLotRepository.ListByUser() error = %v", err)
+	}
+	want := []*model.PointLot{
+		{UserID: users[0].ID, Amount: 100, RemainingAmount: 100, SourceType: model.JournalSourcePayment, SourceID: "add"},
+		{UserID: users[0].ID, Amount: 200, RemainingAmount: 100, ExpireTime: now.AddDate(0, 0, 10), SourceType: model.JournalSourceAddToUsers, SourceID: "currency=PTS,limit=10,offset=0"},
+		{UserID: users[0].ID, Amount: 300, RemainingAmount: 0, ExpireTime: now.AddDate(0, 0, 5), SourceType: model.JournalSourceAddToUsers, SourceID: "currency=PTS,limit=10,offset=0"},
+	}
+	if diff := cmp.Diff(want, lots, cmpopts.IgnoreFields(model.PointLot{}, "ID", "CreateTime")); diff != "" {
+		t.Errorf("PointLotRepository.ListByUser() mismatch (-want +got): \n %s", diff)
+	}
+
+	expirations, err := repo.ListExpirations(ctx, users[0].ID, now, 10)
+	if err != nil {
+		t.Fatalf("PointLotRepository.ListExpirations() error = %v", err)
+	}
+	wantExpirations := []*model.PointExpiration{{Amount: 100, ExpireTime: now.AddDate(0, 0, 10)}}
+	if diff := cmp.Diff(wantExpirations, expirations); diff != "" {
+		t.Errorf("PointLotRepository.ListExpirations() mismatch (-want +got): \n %s", diff)
+	}
+}
+
+func TestPointLotRepository_ExpireLots(t *testing.T) {
+	repo := newPointLotRepo(t)
+	users := createSampleUsers(t, repo.DB, 2)
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	balanceRepo := NewBalanceRepository(repo.DB)
+	if err := balanceRepo.AddToUsers(ctx, domain.Point, 100, now.Add(-time.Hour), 10, 0); err != nil {
+		t.Fatalf("BalanceRepository.AddToUsers() error = %v", err)
+	}
+	if err := balanceRepo.AddToUsers(ctx, domain.Point, 50, now.Add(time.Hour), 1, 0); err != nil {
+		t.Fatalf("BalanceRepository.AddToUsers() error = %v", err)
+	}
+
+	n, err := repo.ExpireLots(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("PointLotRepository.ExpireLots() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("PointLotRepository.ExpireLots() = %d, want 2", n)
+	}
+	// 失効していないロットの分だけが残る
+	for _, tt := range []struct {
+		userID uint
+		want   int64
+	}{{users[0].ID, 50}, {users[1].ID, 0}} {
+		b, err := balanceRepo.Get(ctx, tt.userID, domain.Point)
+		if err != nil {
+			t.Fatalf("BalanceRepository.Get() error = %v", err)
+		}
+		if b.Amount != tt.want {
+			t.Errorf("PointLotRepository.ExpireLots() balance of user %d = %d, want %d", tt.userID, b.Amount, tt.want)
+		}
+	}
+	logs, err := NewBalanceLogRepository(repo.DB).List(ctx, users[1].ID, model.BalanceLogFilter{Currency: domain.Point, Limit: 10})
+	if err != nil {
+		t.Fatalf("BalanceLogRepository.List() error = %v", err)
+	}
+	if len(logs) != 2 || logs[0].SourceType != model.JournalSourcePointExpiration || logs[0].Delta != -100 {
+		t.Errorf("PointLotRepository.ExpireLots() logs = %v, want the expiration of 100 points", logs)
+	}
+
+	// 失効済みのロットは再度失効させない
+	if n, err := repo.ExpireLots(ctx, now, 10); err != nil || n != 0 {
+		t.Errorf("PointLotRepository.ExpireLots() = %d, %v, want 0", n, err)
+	}
+}
+
+func TestPointLotRepository_ExpireLots_reserved(t *testing.T) {
+	repo := newPointLotRepo(t)
+	users := createSampleUsers(t, repo.DB, 1)
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	balanceRepo := NewBalanceRepository(repo.DB)
+	if err := balanceRepo.AddToUsers(ctx, domain.Point, 100, now.Add(-time.Hour), 1, 0); err != nil {
+		t.Fatalf("BalanceRepository.AddToUsers() error = %v", err)
+	}
+	paymentRepo := NewPaymentTransactionRepository(repo.DB)
+	if _, err := paymentRepo.Try(ctx, "reserved", users[0].ID, domain.Point, -60, time.Minute); err != nil {
+		t.Fatalf("PaymentTransactionRepository.Try() error = %v", err)
+	}
+
+	// 仮押さえ中の60ポイントは失効させない
+	n, err := repo.ExpireLots(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("PointLotRepository.ExpireLots() error = %v", err)
+	}
+	if n != 1 {
+		t.Errorf("PointLotRepository.ExpireLots() = %d, want 1", n)
+	}
+	b, err := balanceRepo.Get(ctx, users[0].ID, domain.Point)
+	if err != nil {
+		t.Fatalf("BalanceRepository.Get() error = %v", err)
+	}
+	if b.Amount != 60 || b.ReservedAmount != 60 {
+		t.Errorf("PointLotRepository.ExpireLots() balance = %d (reserved %d), want 60 (reserved 60)", b.Amount, b.ReservedAmount)
+	}
+	// 使えるポイントが残っていないので、仮押さえ中のロットはスキップする
+	if n, err := repo.ExpireLots(ctx, now, 10); err != nil || n != 0 {
+		t.Errorf("PointLotRepository.ExpireLots() = %d, %v, want 0", n, err)
+	}
+
+	// 失効の後でもConfirmできる
+	if _, err := paymentRepo.Confirm(ctx, "reserved"); err != nil {
+		t.Fatalf("PaymentTransactionRepository.Confirm() error = %v", err)
+	}
+	b, err = balanceRepo.Get(ctx, users[0].ID, domain.Point)
+	if err != nil {
+		t.Fatalf("BalanceRepository.Get() error = %v", err)
+	}
+	if b.Amount != 0 || b.ReservedAmount != 0 {
+		t.Errorf("PaymentTransactionRepository.Confirm() balance = %d (reserved %d), want 0 (reserved 0)", b.Amount, b.ReservedAmount)
+	}
+}
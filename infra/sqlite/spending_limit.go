@@ -0,0 +1,64 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+// checkSpendingLimit checks the debit of the payment against the limit of the user, or of the user's tier.
+// The usage includes the payment itself, so it must be inserted or tried before.
+// It must be called in the transaction of the debit, which the single connection serializes with the other debits.
+func checkSpendingLimit(ctx context.Context, db dbContext, pt *model.PaymentTransaction) error {
+	limit, err := findEffectiveSpendingLimit(ctx, db, pt.UserID, pt.Currency)
+	if err != nil {
+		return err
+	}
+	if limit == nil {
+		return nil
+	}
+
+	// 返金された減算も、減算した時点で上限を使ったものとして集計する
+	w := model.NewSpendingWindows(pt.TryTime)
+	query := `
+	SELECT
+		COALESCE(SUM(CASE WHEN try_time >= ? THEN -amount ELSE 0 END), 0),
+		COALESCE(SUM(CASE WHEN try_time >= ? THEN -amount ELSE 0 END), 0),
+		COUNT(CASE WHEN try_time > ? THEN 1 END)
+	FROM payment_transactions
+	WHERE user_id = ? AND currency = ? AND amount < 0 AND status IN (?, ?, ?, ?) AND try_time >= ?`
+	var usage model.SpendingUsage
+	if err := db.QueryRowContext(ctx, query,
+		utc(w.DayStart), utc(w.MonthStart), utc(w.HourStart),
+		pt.UserID, pt.Currency, model.PaymentStatusTried, model.PaymentStatusConfirmed, model.PaymentStatusPartiallyRefunded, model.PaymentStatusRefunded, utc(w.Start()),
+	).Scan(&usage.DailyDebit, &usage.MonthlyDebit, &usage.HourlyDebitCount); err != nil {
+		return err
+	}
+	return limit.Check(-pt.Amount, usage)
+}
+
+// findEffectiveSpendingLimit returns the limit of the user, or of the user's tier when the user has no limit.
+// It returns nil when neither has a limit in the currency.
+func findEffectiveSpendingLimit(ctx context.Context, db dbContext, userID uint, currency domain.Currency) (*model.SpendingLimit, error) {
+	query := `
+	SELECT l.scope, l.scope_id, l.currency, l.max_single_debit, l.daily_debit_limit, l.monthly_debit_limit, l.hourly_debit_count, l.update_time
+	FROM users u JOIN spending_limits l
+		ON (l.scope = ? AND l.scope_id = ?) OR (l.scope = ? AND l.scope_id = u.tier)
+	WHERE u.id = ? AND l.currency = ?
+	ORDER BY l.scope = ? DESC LIMIT 1`
+	limit := &model.SpendingLimit{}
+	err := db.QueryRowContext(ctx, query,
+		model.SpendingLimitScopeUser, strconv.FormatUint(uint64(userID), 10), model.SpendingLimitScopeTier,
+		userID, currency, model.SpendingLimitScopeUser,
+	).Scan(&limit.Scope, &limit.ScopeID, &limit.Currency, &limit.MaxSingleDebit, &limit.DailyDebitLimit, &limit.MonthlyDebitLimit, &limit.HourlyDebitCount, &limit.UpdateTime)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return limit, nil
+}
@@ -0,0 +1,75 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kawabatas/m-bank/domain"
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+// newTestConnection opens a new in-memory database with the migrations applied and the seed removed.
+func newTestConnection(t *testing.T) *sql.DB {
+	db, err := Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+	if err := truncateTables(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func truncateTables(db *sql.DB) error {
+	// 外部キーで参照される側を後に消す
+	tableNames := []string{"spending_limits", "point_lots", "postings", "journal_entries", "balance_logs", "payment_transactions", "balances", "users"}
+	for _, tableName := range tableNames {
+		if _, err := db.Exec(fmt.Sprintf("DELETE FROM %s", tableName)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const initBalanceAmount = 1000
+
+func createSampleUsers(t *testing.T, db *sql.DB, count int) []*model.User {
+	t.Helper()
+	ctx := context.Background()
+	var users []*model.User
+	var userArgs, balanceArgs []interface{}
+	var userStrings, balanceStrings []string
+	for i := 1; i <= count; i++ {
+		user := &model.User{
+			ID:   uint(i),
+			Name: fmt.Sprintf("sample%d", i),
+			Tier: model.DefaultUserTier,
+		}
+		users = append(users, user)
+		userStrings = append(userStrings, "(?, ?)")
+		userArgs = append(userArgs, user.ID, user.Name)
+		balanceStrings = append(balanceStrings, "(?, ?)")
+		balanceArgs = append(balanceArgs, user.ID, initBalanceAmount)
+	}
+	if _, err := db.ExecContext(ctx, "INSERT INTO users (id, name) VALUES "+strings.Join(userStrings, ","), userArgs...); err != nil {
+		t.Fatalf("insert users error: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, "INSERT INTO balances (user_id, amount) VALUES "+strings.Join(balanceStrings, ","), balanceArgs...); err != nil {
+		t.Fatalf("insert balances error: %v", err)
+	}
+	// 初期残高を開始残高として仕訳しておく
+	postings := []*model.Posting{{Account: model.AccountOpeningBalance, Currency: domain.JPY, Amount: -initBalanceAmount * int64(len(users))}}
+	for _, u := range users {
+		postings = append(postings, &model.Posting{Account: model.UserAccount(u.ID), Currency: domain.JPY, Amount: initBalanceAmount})
+	}
+	if err := insertJournalEntry(ctx, db, model.NewJournalEntry(model.JournalSourceOpeningBalance, "sample", postings...)); err != nil {
+		t.Fatalf("insert journal entry error: %v", err)
+	}
+	return users
+}
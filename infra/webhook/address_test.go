@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		name string
+		ip   string
+		want bool
+	}{
+		{"グローバルなIPv4", "93.184.216.34", true},
+		{"グローバルなIPv6", "2606:2800:220:1:248:1893:25c8:1946", true},
+		{"ループバック", "127.0.0.1", false},
+		{"IPv6のループバック", "::1", false},
+		{"メタデータサーバ", "169.254.169.254", false},
+		{"プライベート", "10.0.0.1", false},
+		{"プライベート(172.16/12)", "172.31.255.255", false},
+		{"プライベート(192.168/16)", "192.168.0.1", false},
+		{"キャリアグレードNAT", "100.64.0.1", false},
+		{"IPv6のユニークローカル", "fd00::1", false},
+		{"IPv6のリンクローカル", "fe80::1", false},
+		{"IPv4射影のループバック", "::ffff:127.0.0.1", false},
+		{"未指定", "0.0.0.0", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPublicIP(net.ParseIP(tt.ip)); got != tt.want {
+				t.Errorf("IsPublicIP(%s) = %v, want %v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeResolver resolves the hosts to the fixed addresses.
+type fakeResolver map[string][]string
+
+func (r fakeResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	ips, ok := r[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	var addrs []net.IPAddr
+	for _, ip := range ips {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+	return addrs, nil
+}
+
+func TestCheckURL(t *testing.T) {
+	resolver := fakeResolver{
+		"example.com":    {"93.184.216.34"},
+		"internal.local": {"93.184.216.34", "10.0.0.1"},
+		"localhost":      {"127.0.0.1"},
+	}
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{"公開されたホスト", "https://example.com/hooks", false},
+		{"プライベートなアドレスを含むホスト", "https://internal.local/hooks", true},
+		{"ループバックのホスト", "http://localhost:8080/hooks", true},
+		{"解決できないホスト", "https://unknown.example/hooks", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckURL(context.Background(), resolver, tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrForbiddenAddress) {
+				t.Errorf("CheckURL() error = %v, want ErrForbiddenAddress", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+// Client sends the deliveries to the webhooks.
+type Client struct {
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client which connects only to the public addresses. It does not use the proxy of the environment,
+// which could reach the internal network on behalf of the client.
+func NewClient(timeout time.Duration) *Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: checkDialAddress}
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: timeout,
+		MaxIdleConns:        100,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	return &Client{HTTPClient: &http.Client{Timeout: timeout, Transport: transport}}
+}
+
+// Send posts the event of the delivery in JSON signed with the secret of the subscription, and returns the status code
+// of the response, which is 0 when the webhook does not respond. It fails unless the status is 2xx.
+func (c *Client) Send(ctx context.Context, d *model.WebhookDelivery, now time.Time) (int, error) {
+	body, err := d.Event.Envelope()
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(d.Subscription.Secret, timestamp, body))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(DeliveryIDHeader, strconv.FormatUint(d.ID, 10))
+	req.Header.Set(EventSequenceHeader, strconv.FormatUint(d.Event.Sequence, 10))
+	req.Header.Set(EventTypeHeader, string(d.Event.Type))
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	// コネクションを再利用するために本文を読み捨てる
+	_, _ = io.Copy(io.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return res.StatusCode, fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
@@ -0,0 +1,123 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain/model"
+)
+
+// newLoopbackClient creates a client without the address check, since the test receivers listen on the loopback.
+func newLoopbackClient() *Client {
+	return &Client{HTTPClient: &http.Client{Timeout: time.Second}}
+}
+
+func TestClient_Send(t *testing.T) {
+	const secret = "secret"
+	event, err := model.NewOutboxEvent(model.EventPaymentConfirmed, &model.PaymentEventPayload{UUID: "foo", UserID: 1, Amount: -100}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	event.Sequence = 42
+
+	tests := []struct {
+		name       string
+		status     int
+		wantStatus int
+		wantErr    bool
+	}{
+		{"2xxなら成功", http.StatusOK, http.StatusOK, false},
+		{"4xxは失敗", http.StatusGone, http.StatusGone, true},
+		{"5xxは失敗", http.StatusInternalServerError, http.StatusInternalServerError, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var verified bool
+			var got struct {
+				header http.Header
+				body   []byte
+			}
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got.header = r.Header
+				got.body, _ = io.ReadAll(r.Body)
+				verified = Verify(secret, r.Header, got.body, time.Now(), DefaultTolerance)
+				w.WriteHeader(tt.status)
+			}))
+			defer receiver.Close()
+
+			d := &model.WebhookDelivery{
+				ID:           7,
+				Subscription: &model.WebhookSubscription{URL: receiver.URL, Secret: secret},
+				Event:        event,
+			}
+			status, err := newLoopbackClient().Send(context.Background(), d, time.Now())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if status != tt.wantStatus {
+				t.Errorf("Client.Send() status = %v, want %v", status, tt.wantStatus)
+			}
+			if !verified {
+				t.Errorf("signature %q does not match", got.header.Get(SignatureHeader))
+			}
+			if got.header.Get(DeliveryIDHeader) != "7" || got.header.Get(EventSequenceHeader) != "42" || got.header.Get(EventTypeHeader) != string(model.EventPaymentConfirmed) {
+				t.Errorf("header = %v", got.header)
+			}
+			var envelope struct {
+				Sequence uint64                    `json:"sequence"`
+				Payload  model.PaymentEventPayload `json:"payload"`
+			}
+			if err := json.Unmarshal(got.body, &envelope); err != nil {
+				t.Fatal(err)
+			}
+			if envelope.Sequence != 42 || envelope.Payload.UUID != "foo" {
+				t.Errorf("body = %s", got.body)
+			}
+		})
+	}
+}
+
+func TestClient_SendNoResponse(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := receiver.URL
+	receiver.Close()
+
+	event, err := model.NewOutboxEvent(model.EventPaymentConfirmed, &model.PaymentEventPayload{UUID: "foo"}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &model.WebhookDelivery{Subscription: &model.WebhookSubscription{URL: url, Secret: "secret"}, Event: event}
+	// 応答がなければステータスは0
+	status, err := newLoopbackClient().Send(context.Background(), d, time.Now())
+	if err == nil || status != 0 {
+		t.Errorf("Client.Send() = %v, %v, want 0 and an error", status, err)
+	}
+}
+
+func TestClient_SendForbiddenAddress(t *testing.T) {
+	var received bool
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = true
+	}))
+	defer receiver.Close()
+
+	event, err := model.NewOutboxEvent(model.EventPaymentConfirmed, &model.PaymentEventPayload{UUID: "foo"}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &model.WebhookDelivery{Subscription: &model.WebhookSubscription{URL: receiver.URL, Secret: "secret"}, Event: event}
+	// 登録の後で名前がループバックを指すようになっても接続しない
+	status, err := NewClient(time.Second).Send(context.Background(), d, time.Now())
+	if !errors.Is(err, ErrForbiddenAddress) || status != 0 {
+		t.Errorf("Client.Send() = %v, %v, want 0 and ErrForbiddenAddress", status, err)
+	}
+	if received {
+		t.Errorf("Client.Send() reached the loopback receiver")
+	}
+}
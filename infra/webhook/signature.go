@@ -0,0 +1,49 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// 配信のリクエストのヘッダ
+const (
+	SignatureHeader     = "X-Webhook-Signature"
+	TimestampHeader     = "X-Webhook-Timestamp"
+	DeliveryIDHeader    = "X-Webhook-Delivery-Id"
+	EventSequenceHeader = "X-Event-Sequence"
+	EventTypeHeader     = "X-Event-Type"
+)
+
+const signaturePrefix = "sha256="
+
+// DefaultTolerance is the recommended tolerance of Verify for the difference between the timestamp and the clock
+// of the receiver. The dispatcher signs each attempt at the time of sending it, so a retry is not rejected.
+const DefaultTolerance = 5 * time.Minute
+
+// Sign returns the signature of the body sent at the unix timestamp: the hex HMAC-SHA256 of "<timestamp>.<body>"
+// keyed with the secret, prefixed with "sha256=". Signing the timestamp lets the receivers reject replayed requests.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether the signature header of the delivery matches the body, for the receivers.
+// It also rejects the requests whose timestamp differs from now by more than tolerance, so that a captured request
+// cannot be replayed later.
+func Verify(secret string, header http.Header, body []byte, now time.Time, tolerance time.Duration) bool {
+	timestamp, err := strconv.ParseInt(header.Get(TimestampHeader), 10, 64)
+	if err != nil {
+		return false
+	}
+	if d := now.Sub(time.Unix(timestamp, 0)); d > tolerance || d < -tolerance {
+		return false
+	}
+	return hmac.Equal([]byte(header.Get(SignatureHeader)), []byte(Sign(secret, timestamp, body)))
+}
@@ -0,0 +1,43 @@
+package webhook
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"sequence":1}`)
+	const timestamp = 1700000000
+	signed := func(secret string, ts int64, b []byte) http.Header {
+		header := http.Header{}
+		header.Set(SignatureHeader, Sign(secret, ts, b))
+		header.Set(TimestampHeader, strconv.FormatInt(ts, 10))
+		return header
+	}
+	tampered := signed("secret", timestamp, body)
+	tampered.Set(TimestampHeader, strconv.FormatInt(timestamp+1, 10))
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   bool
+	}{
+		{"正しい署名", signed("secret", timestamp, body), true},
+		{"別の鍵の署名", signed("other", timestamp, body), false},
+		{"別の本文の署名", signed("secret", timestamp, []byte(`{"sequence":2}`)), false},
+		{"タイムスタンプの改ざん", tampered, false},
+		{"署名なし", http.Header{}, false},
+		{"許容範囲内の古いタイムスタンプ", signed("secret", timestamp-300, body), true},
+		{"許容範囲を超えて古いタイムスタンプ", signed("secret", timestamp-301, body), false},
+		{"許容範囲を超えて未来のタイムスタンプ", signed("secret", timestamp+301, body), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Verify("secret", tt.header, body, time.Unix(timestamp, 0), DefaultTolerance); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
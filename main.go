@@ -2,27 +2,63 @@ package main
 
 import (
 	"database/sql"
+	"flag"
+	"fmt"
 	"log"
+	"net"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/kawabatas/m-bank/domain/model"
 	"github.com/kawabatas/m-bank/infra/database"
+	"github.com/kawabatas/m-bank/infra/postgres"
+	"github.com/kawabatas/m-bank/infra/sqlite"
 )
 
 func main() {
-	db, err := setupDB(
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_NAME"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-	)
+	dbFlag := flag.String("db", "", "DB to use: mysql, postgres, memory or sqlite:<path> (overrides DB_DRIVER)")
+	flag.Parse()
+
+	cfg, err := loadConfig(*dbFlag)
+	if err != nil {
+		log.Fatalf("load config error: %v", err)
+	}
+
+	var db *sql.DB
+	switch cfg.DBDriver {
+	case driverMemory:
+		// メモリ上に保存する場合はDBに接続しない
+	case driverSQLite:
+		// SQLiteはファイルがなければ作り、マイグレーションを適用する
+		db, err = sqlite.Open(cfg.SQLitePath)
+	default:
+		db, err = setupDB(
+			cfg.DBDriver,
+			os.Getenv("DB_HOST"),
+			os.Getenv("DB_NAME"),
+			os.Getenv("DB_USER"),
+			os.Getenv("DB_PASSWORD"),
+		)
+	}
 	if err != nil {
 		log.Fatalf("setup DB error: %v", err)
 	}
-	defer db.Close()
+	if db != nil {
+		defer db.Close()
+	}
+
+	// RESTとgRPCのAPIで同じアプリケーションサービスを使う
+	app, err := newApp(db, cfg)
+	if err != nil {
+		log.Fatalf("new app error: %v", err)
+	}
 
 	// create new service API
-	server, err := newServer(db)
+	server, err := newServer(app, cfg)
 	if err != nil {
 		log.Fatalf("new Server error: %v", err)
 	}
@@ -30,6 +66,19 @@ func main() {
 		_ = server.Shutdown()
 	}()
 
+	// serve gRPC API on the second port
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
+	if err != nil {
+		log.Fatalf("listen gRPC error: %v", err)
+	}
+	grpcServer := newGRPCServer(app)
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Printf("serve gRPC error: %v", err)
+		}
+	}()
+	defer grpcServer.GracefulStop()
+
 	// serve API
 	server.Port = 3000
 	if err := server.Serve(); err != nil {
@@ -37,7 +86,45 @@ func main() {
 	}
 }
 
-func setupDB(dbHost, dbName, dbUser, dbPassword string) (*sql.DB, error) {
+// DB_DRIVERで選べるDB。memoryはDBを使わずメモリ上に保存し、sqliteは sqlite:<path> の形でファイルを指定する
+const (
+	driverMySQL    = "mysql"
+	driverPostgres = "postgres"
+	driverMemory   = "memory"
+	driverSQLite   = "sqlite"
+)
+
+// SQLiteのファイルの指定がない場合のパス
+const defaultSQLitePath = "bank.db"
+
+// TCPのポート番号の上限
+const maxPort = 65535
+
+// parseDBDriver parses the DB to use in the form of driver or sqlite:<path>, and returns the driver and the path of the SQLite file.
+func parseDBDriver(v string) (string, string, error) {
+	driver, path := v, ""
+	if i := strings.Index(v, ":"); i >= 0 {
+		driver, path = v[:i], v[i+1:]
+	}
+	switch driver {
+	case driverMySQL, driverPostgres, driverMemory:
+		if path != "" {
+			return "", "", fmt.Errorf("%s does not take a path: %s", driver, v)
+		}
+	case driverSQLite:
+		if path == "" {
+			path = defaultSQLitePath
+		}
+	default:
+		return "", "", fmt.Errorf("unknown DB_DRIVER: %s", v)
+	}
+	return driver, path, nil
+}
+
+func setupDB(driver, dbHost, dbName, dbUser, dbPassword string) (*sql.DB, error) {
+	if driver == driverPostgres {
+		return sql.Open("pgx", postgres.DSN(dbHost, dbUser, dbPassword, dbName))
+	}
 	dsn := database.DSN(dbHost, dbUser, dbPassword, dbName)
 	db, err := sql.Open("mysql", dsn)
 	if err != nil {
@@ -45,3 +132,175 @@ func setupDB(dbHost, dbName, dbUser, dbPassword string) (*sql.DB, error) {
 	}
 	return db, nil
 }
+
+// config is the server configuration read from environment variables.
+type config struct {
+	DBDriver            string        // 使用するDB(mysql, postgres, memory, sqlite)
+	SQLitePath          string        // SQLiteのファイルのパス
+	TryTTL              time.Duration // Tryの有効期限のデフォルト値
+	SweepInterval       time.Duration // 期限切れのTryを処理する間隔
+	SweepBatchSize      int           // 期限切れのTryを1トランザクションで処理する件数
+	StrictMode          bool          // Confirm/Cancelのuser_id,amountがTry時と異なる場合にエラーにするかどうか
+	BulkCreditInterval  time.Duration // 未完了の一斉加算のジョブを処理する間隔
+	BulkCreditChunkSize int           // 一斉加算で1トランザクションで加算するユーザ数
+	GRPCPort            int           // gRPCのAPIのポート
+	OutboxPublisher     string        // 残高の変更のイベントの配信先(stdout, file:<path>, WebhookのURL)。空なら配信しない
+	OutboxInterval      time.Duration // 配信待ちのイベントを配信する間隔
+	OutboxBatchSize     int           // 1回の取得で配信するイベントの数
+	OutboxMaxAttempts   int           // 配信をあきらめるまでの試行回数
+	WebhookInterval     time.Duration // 登録されたWebhookへの配信を送信する間隔
+	WebhookBatchSize    int           // 1回の取得で送信する配信の数
+	WebhookMaxAttempts  int           // 配信をデッドレターにするまでの試行回数
+}
+
+// loadConfig reads the configuration. The db flag, when given, takes precedence over DB_DRIVER.
+func loadConfig(dbFlag string) (*config, error) {
+	cfg := &config{
+		DBDriver:            driverMySQL,
+		TryTTL:              model.DefaultTryTTL,
+		SweepInterval:       time.Minute,
+		SweepBatchSize:      100,
+		StrictMode:          true,
+		BulkCreditInterval:  10 * time.Second,
+		BulkCreditChunkSize: 1000,
+		GRPCPort:            3001,
+		OutboxInterval:      time.Second,
+		OutboxBatchSize:     100,
+		OutboxMaxAttempts:   10,
+		WebhookInterval:     time.Second,
+		WebhookBatchSize:    100,
+		WebhookMaxAttempts:  10,
+	}
+	dbDriver := os.Getenv("DB_DRIVER")
+	if dbFlag != "" {
+		dbDriver = dbFlag
+	}
+	if dbDriver != "" {
+		driver, path, err := parseDBDriver(dbDriver)
+		if err != nil {
+			return nil, err
+		}
+		cfg.DBDriver = driver
+		cfg.SQLitePath = path
+	}
+	if v := os.Getenv("PAYMENT_TRY_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, err
+		}
+		cfg.TryTTL = d
+	}
+	if v := os.Getenv("PAYMENT_SWEEP_INTERVAL"); v != "" {
+		d, err := parsePositiveDuration("PAYMENT_SWEEP_INTERVAL", v)
+		if err != nil {
+			return nil, err
+		}
+		cfg.SweepInterval = d
+	}
+	if v := os.Getenv("PAYMENT_SWEEP_BATCH_SIZE"); v != "" {
+		n, err := parsePositiveInt("PAYMENT_SWEEP_BATCH_SIZE", v)
+		if err != nil {
+			return nil, err
+		}
+		cfg.SweepBatchSize = n
+	}
+	if v := os.Getenv("PAYMENT_STRICT_MODE"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, err
+		}
+		cfg.StrictMode = b
+	}
+	if v := os.Getenv("BULK_CREDIT_INTERVAL"); v != "" {
+		d, err := parsePositiveDuration("BULK_CREDIT_INTERVAL", v)
+		if err != nil {
+			return nil, err
+		}
+		cfg.BulkCreditInterval = d
+	}
+	if v := os.Getenv("BULK_CREDIT_CHUNK_SIZE"); v != "" {
+		n, err := parsePositiveInt("BULK_CREDIT_CHUNK_SIZE", v)
+		if err != nil {
+			return nil, err
+		}
+		cfg.BulkCreditChunkSize = n
+	}
+	if v := os.Getenv("GRPC_PORT"); v != "" {
+		n, err := parsePositiveInt("GRPC_PORT", v)
+		if err != nil {
+			return nil, err
+		}
+		if n > maxPort {
+			return nil, fmt.Errorf("GRPC_PORT must be at most %d: %s", maxPort, v)
+		}
+		cfg.GRPCPort = n
+	}
+	cfg.OutboxPublisher = os.Getenv("OUTBOX_PUBLISHER")
+	if v := os.Getenv("OUTBOX_INTERVAL"); v != "" {
+		d, err := parsePositiveDuration("OUTBOX_INTERVAL", v)
+		if err != nil {
+			return nil, err
+		}
+		cfg.OutboxInterval = d
+	}
+	if v := os.Getenv("OUTBOX_BATCH_SIZE"); v != "" {
+		n, err := parsePositiveInt("OUTBOX_BATCH_SIZE", v)
+		if err != nil {
+			return nil, err
+		}
+		cfg.OutboxBatchSize = n
+	}
+	if v := os.Getenv("OUTBOX_MAX_ATTEMPTS"); v != "" {
+		n, err := parsePositiveInt("OUTBOX_MAX_ATTEMPTS", v)
+		if err != nil {
+			return nil, err
+		}
+		cfg.OutboxMaxAttempts = n
+	}
+	if v := os.Getenv("WEBHOOK_INTERVAL"); v != "" {
+		d, err := parsePositiveDuration("WEBHOOK_INTERVAL", v)
+		if err != nil {
+			return nil, err
+		}
+		cfg.WebhookInterval = d
+	}
+	if v := os.Getenv("WEBHOOK_BATCH_SIZE"); v != "" {
+		n, err := parsePositiveInt("WEBHOOK_BATCH_SIZE", v)
+		if err != nil {
+			return nil, err
+		}
+		cfg.WebhookBatchSize = n
+	}
+	if v := os.Getenv("WEBHOOK_MAX_ATTEMPTS"); v != "" {
+		n, err := parsePositiveInt("WEBHOOK_MAX_ATTEMPTS", v)
+		if err != nil {
+			return nil, err
+		}
+		cfg.WebhookMaxAttempts = n
+	}
+	return cfg, nil
+}
+
+// parsePositiveDuration parses the interval of a background worker. A non-positive interval cannot tick.
+func parsePositiveDuration(name, v string) (time.Duration, error) {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", name, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%s must be positive: %s", name, v)
+	}
+	return d, nil
+}
+
+// parsePositiveInt parses a batch size or a number of attempts, which must be at least 1.
+func parsePositiveInt(name, v string) (int, error) {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", name, err)
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("%s must be positive: %s", name, v)
+	}
+	return n, nil
+}
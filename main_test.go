@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func Test_loadConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		check   func(*config) bool
+		wantErr bool
+	}{
+		{"デフォルト値", nil, func(cfg *config) bool { return cfg.SweepInterval == time.Minute && cfg.SweepBatchSize == 100 }, false},
+		{"スイープの間隔とバッチサイズ", map[string]string{"PAYMENT_SWEEP_INTERVAL": "30s", "PAYMENT_SWEEP_BATCH_SIZE": "10"}, func(cfg *config) bool { return cfg.SweepInterval == 30*time.Second && cfg.SweepBatchSize == 10 }, false},
+		{"スイープの間隔が0", map[string]string{"PAYMENT_SWEEP_INTERVAL": "0s"}, nil, true},
+		{"スイープの間隔が負", map[string]string{"PAYMENT_SWEEP_INTERVAL": "-1m"}, nil, true},
+		{"スイープのバッチサイズが0", map[string]string{"PAYMENT_SWEEP_BATCH_SIZE": "0"}, nil, true},
+		{"スイープのバッチサイズが負", map[string]string{"PAYMENT_SWEEP_BATCH_SIZE": "-1"}, nil, true},
+		{"一斉加算の間隔とチャンクサイズ", map[string]string{"BULK_CREDIT_INTERVAL": "1s", "BULK_CREDIT_CHUNK_SIZE": "500"}, func(cfg *config) bool { return cfg.BulkCreditInterval == time.Second && cfg.BulkCreditChunkSize == 500 }, false},
+		{"一斉加算の間隔が0", map[string]string{"BULK_CREDIT_INTERVAL": "0s"}, nil, true},
+		{"一斉加算のチャンクサイズが0", map[string]string{"BULK_CREDIT_CHUNK_SIZE": "0"}, nil, true},
+		{"イベントの配信の設定", map[string]string{"OUTBOX_INTERVAL": "2s", "OUTBOX_BATCH_SIZE": "50", "OUTBOX_MAX_ATTEMPTS": "3"}, func(cfg *config) bool {
+			return cfg.OutboxInterval == 2*time.Second && cfg.OutboxBatchSize == 50 && cfg.OutboxMaxAttempts == 3
+		}, false},
+		{"イベントの配信の間隔が負", map[string]string{"OUTBOX_INTERVAL": "-1s"}, nil, true},
+		{"イベントの配信のバッチサイズが0", map[string]string{"OUTBOX_BATCH_SIZE": "0"}, nil, true},
+		{"イベントの配信の試行回数が0", map[string]string{"OUTBOX_MAX_ATTEMPTS": "0"}, nil, true},
+		{"Webhookの配信の設定", map[string]string{"WEBHOOK_INTERVAL": "2s", "WEBHOOK_BATCH_SIZE": "50", "WEBHOOK_MAX_ATTEMPTS": "3"}, func(cfg *config) bool {
+			return cfg.WebhookInterval == 2*time.Second && cfg.WebhookBatchSize == 50 && cfg.WebhookMaxAttempts == 3
+		}, false},
+		{"Webhookの配信の間隔が0", map[string]string{"WEBHOOK_INTERVAL": "0s"}, nil, true},
+		{"Webhookの配信のバッチサイズが負", map[string]string{"WEBHOOK_BATCH_SIZE": "-1"}, nil, true},
+		{"Webhookの配信の試行回数が0", map[string]string{"WEBHOOK_MAX_ATTEMPTS": "0"}, nil, true},
+		{"gRPCのポート", map[string]string{"GRPC_PORT": "4001"}, func(cfg *config) bool { return cfg.GRPCPort == 4001 }, false},
+		{"gRPCのポートが0", map[string]string{"GRPC_PORT": "0"}, nil, true},
+		{"gRPCのポートが負", map[string]string{"GRPC_PORT": "-1"}, nil, true},
+		{"gRPCのポートが上限を超える", map[string]string{"GRPC_PORT": "65536"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				os.Setenv(k, v)
+			}
+			defer func() {
+				for k := range tt.env {
+					os.Unsetenv(k)
+				}
+			}()
+			cfg, err := loadConfig("")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !tt.check(cfg) {
+				t.Errorf("loadConfig() = %+v", cfg)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain/model"
+	"github.com/kawabatas/m-bank/domain/repository"
+	"github.com/kawabatas/m-bank/infra/publisher"
+)
+
+// EventPublisher delivers an outbox event to the downstream services. The same event may be published more than once,
+// so the consumers drop the sequences which they have already received.
+type EventPublisher interface {
+	Publish(ctx context.Context, event *model.OutboxEvent) error
+}
+
+// 配信中のイベントを他のリレーが取得しない期間。Webhookのタイムアウトより長くする
+const (
+	outboxLease    = time.Minute
+	webhookTimeout = 10 * time.Second
+)
+
+// newEventPublisher creates the publisher of OUTBOX_PUBLISHER: stdout, file:<path> or the URL of a webhook.
+func newEventPublisher(v string) (EventPublisher, error) {
+	switch {
+	case v == "stdout":
+		return publisher.NewWriterPublisher(os.Stdout), nil
+	case strings.HasPrefix(v, "file:"):
+		return publisher.NewFilePublisher(strings.TrimPrefix(v, "file:"))
+	case strings.HasPrefix(v, "http://"), strings.HasPrefix(v, "https://"):
+		return publisher.NewHTTPPublisher(v, webhookTimeout), nil
+	}
+	return nil, fmt.Errorf("unknown OUTBOX_PUBLISHER: %s", v)
+}
+
+// outboxRelay periodically delivers the pending outbox events through the publisher, retrying the failed ones.
+type outboxRelay struct {
+	OutboxRepo  repository.OutboxRepository
+	Publisher   EventPublisher
+	Interval    time.Duration
+	BatchSize   int
+	MaxAttempts int
+	Lease       time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newOutboxRelay(outboxRepo repository.OutboxRepository, publisher EventPublisher, interval time.Duration, batchSize, maxAttempts int) *outboxRelay {
+	return &outboxRelay{
+		OutboxRepo:  outboxRepo,
+		Publisher:   publisher,
+		Interval:    interval,
+		BatchSize:   batchSize,
+		MaxAttempts: maxAttempts,
+		Lease:       outboxLease,
+	}
+}
+
+// Start runs the relay in a background goroutine until Stop is called.
+func (r *outboxRelay) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.relay(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops the relay, waits for the running delivery to finish and closes the publisher.
+func (r *outboxRelay) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+	if c, ok := r.Publisher.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			log.Printf("close event publisher error: %v", err)
+		}
+	}
+}
+
+func (r *outboxRelay) relay(ctx context.Context) {
+	// 配信できるイベントがなくなるまでバッチ単位で配信する
+	for ctx.Err() == nil {
+		now := time.Now()
+		events, err := r.OutboxRepo.ClaimPending(ctx, now, r.Lease, r.BatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("claim outbox events error: %v", err)
+			}
+			return
+		}
+		// 取得の期限を過ぎると他のリレーが同じイベントを取得するので、期限までに配信を打ち切る。
+		// 配信しなかったイベントは期限の後にもう一度取得される
+		leaseCtx, cancel := context.WithDeadline(ctx, now.Add(r.Lease))
+		for _, event := range events {
+			if leaseCtx.Err() != nil {
+				break
+			}
+			r.publish(ctx, leaseCtx, event)
+		}
+		cancel()
+		if len(events) < r.BatchSize {
+			return
+		}
+	}
+}
+
+// publish publishes the event within the lease of publishCtx and saves the result with ctx.
+func (r *outboxRelay) publish(ctx, publishCtx context.Context, event *model.OutboxEvent) {
+	if err := r.Publisher.Publish(publishCtx, event); err != nil {
+		event.Failed(err, r.MaxAttempts, time.Now())
+		if event.Status == model.OutboxEventStatusDead {
+			log.Printf("give up publishing event %d after %d attempts: %v", event.Sequence, event.Attempts, err)
+		} else {
+			log.Printf("publish event %d error: %v", event.Sequence, err)
+		}
+	} else {
+		event.Published(time.Now())
+	}
+	// 結果を保存できなかったイベントは、取得の期限を過ぎてからもう一度配信される
+	if err := r.OutboxRepo.Update(ctx, event); err != nil && ctx.Err() == nil {
+		log.Printf("update outbox event %d error: %v", event.Sequence, err)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kawabatas/m-bank/domain/mock"
+	"github.com/kawabatas/m-bank/domain/model"
+	"github.com/kawabatas/m-bank/infra/publisher"
+)
+
+func Test_outboxRelay_relay(t *testing.T) {
+	errUnavailable := errors.New("unavailable")
+
+	tests := []struct {
+		name         string
+		attempts     int     // 配信前の試行回数
+		errs         []error // 配信の失敗
+		wantStatus   model.OutboxEventStatus
+		wantAttempts int
+		wantRetry    bool // 次の試行が後にずらされるかどうか
+		wantLastErr  string
+	}{
+		{"配信に成功", 0, nil, model.OutboxEventStatusPublished, 1, false, ""},
+		{"失敗したら後でリトライする", 0, []error{errUnavailable}, model.OutboxEventStatusPending, 1, true, "unavailable"},
+		{"リトライで成功", 1, nil, model.OutboxEventStatusPublished, 2, false, ""},
+		{"最大試行回数まで失敗したらあきらめる", 2, []error{errUnavailable}, model.OutboxEventStatusDead, 3, false, "unavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			now := time.Now()
+			event, err := model.NewOutboxEvent(model.EventPaymentConfirmed, &model.PaymentEventPayload{UUID: "foo"}, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			event.Sequence = 1
+			event.Attempts = tt.attempts
+
+			var updated *model.OutboxEvent
+			outboxRepo := mock.NewMockOutboxRepository(ctrl)
+			outboxRepo.
+				EXPECT().
+				ClaimPending(gomock.Any(), gomock.Any(), outboxLease, 10).
+				Return([]*model.OutboxEvent{event}, nil)
+			outboxRepo.
+				EXPECT().
+				Update(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, event *model.OutboxEvent) error {
+					updated = event
+					return nil
+				})
+			fake := publisher.NewFakePublisher()
+			fake.FailNext(tt.errs...)
+
+			r := newOutboxRelay(outboxRepo, fake, time.Second, 10, 3)
+			r.relay(context.Background())
+
+			if updated.Status != tt.wantStatus || updated.Attempts != tt.wantAttempts || updated.LastError != tt.wantLastErr {
+				t.Errorf("updated = %+v, want status %v, attempts %v, last error %q", updated, tt.wantStatus, tt.wantAttempts, tt.wantLastErr)
+			}
+			if retry := updated.NextAttemptTime.After(now); retry != tt.wantRetry {
+				t.Errorf("next attempt time = %v, want retry %v", updated.NextAttemptTime, tt.wantRetry)
+			}
+			published := len(fake.Events()) > 0
+			if published != (tt.wantStatus == model.OutboxEventStatusPublished) {
+				t.Errorf("published = %v", fake.Events())
+			}
+		})
+	}
+}
+
+func Test_outboxRelay_relayBatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var batches [][]*model.OutboxEvent
+	var sequence uint64
+	for _, n := range []int{2, 2, 1} {
+		var batch []*model.OutboxEvent
+		for i := 0; i < n; i++ {
+			sequence++
+			event, err := model.NewOutboxEvent(model.EventPaymentConfirmed, &model.PaymentEventPayload{}, time.Now())
+			if err != nil {
+				t.Fatal(err)
+			}
+			event.Sequence = sequence
+			batch = append(batch, event)
+		}
+		batches = append(batches, batch)
+	}
+	outboxRepo := mock.NewMockOutboxRepository(ctrl)
+	// 取得したイベントがバッチサイズより少なくなるまで続けて取得する
+	gomock.InOrder(
+		outboxRepo.EXPECT().ClaimPending(gomock.Any(), gomock.Any(), gomock.Any(), 2).Return(batches[0], nil),
+		outboxRepo.EXPECT().ClaimPending(gomock.Any(), gomock.Any(), gomock.Any(), 2).Return(batches[1], nil),
+		outboxRepo.EXPECT().ClaimPending(gomock.Any(), gomock.Any(), gomock.Any(), 2).Return(batches[2], nil),
+	)
+	outboxRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).Times(5)
+	fake := publisher.NewFakePublisher()
+
+	r := newOutboxRelay(outboxRepo, fake, time.Second, 2, 3)
+	r.relay(context.Background())
+
+	events := fake.Events()
+	if len(events) != 5 {
+		t.Fatalf("published %d events, want 5", len(events))
+	}
+	for i, event := range events {
+		if event.Sequence != uint64(i+1) {
+			t.Errorf("events[%d].Sequence = %v, want %v", i, event.Sequence, i+1)
+		}
+	}
+}
+
+// slowPublisher is a publisher which blocks until the context is done.
+type slowPublisher struct {
+	published int
+}
+
+func (p *slowPublisher) Publish(ctx context.Context, event *model.OutboxEvent) error {
+	p.published++
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func Test_outboxRelay_relaySlowPublisher(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	var events []*model.OutboxEvent
+	for i := 1; i <= 2; i++ {
+		event, err := model.NewOutboxEvent(model.EventPaymentConfirmed, &model.PaymentEventPayload{UUID: "foo"}, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		event.Sequence = uint64(i)
+		events = append(events, event)
+	}
+
+	// 期限を過ぎた配信は打ち切って失敗にし、残りのイベントは配信しない
+	var updated []*model.OutboxEvent
+	outboxRepo := mock.NewMockOutboxRepository(ctrl)
+	outboxRepo.
+		EXPECT().
+		ClaimPending(gomock.Any(), gomock.Any(), 100*time.Millisecond, 10).
+		Return(events, nil)
+	outboxRepo.
+		EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, event *model.OutboxEvent) error {
+			updated = append(updated, event)
+			return nil
+		})
+	slow := &slowPublisher{}
+
+	r := newOutboxRelay(outboxRepo, slow, time.Second, 10, 3)
+	r.Lease = 100 * time.Millisecond
+	r.relay(context.Background())
+
+	if slow.published != 1 {
+		t.Errorf("published %d events, want 1", slow.published)
+	}
+	if len(updated) != 1 || updated[0].Sequence != 1 || updated[0].Status != model.OutboxEventStatusPending || updated[0].Attempts != 1 {
+		t.Errorf("updated = %+v, want the first event to be retried", updated)
+	}
+	if elapsed := time.Since(now); elapsed >= time.Second {
+		t.Errorf("relay took %v, want it to stop at the lease", elapsed)
+	}
+}
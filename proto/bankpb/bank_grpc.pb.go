@@ -0,0 +1,259 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: bank.proto
+
+package bankpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// BankClient is the client API for Bank service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BankClient interface {
+	// GetBalance returns all the wallets of the user.
+	GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*BalanceList, error)
+	// Try tries a payment, i.e. a credit or a debit of the user's wallet. A debit reserves the balance.
+	Try(ctx context.Context, in *PaymentRequest, opts ...grpc.CallOption) (*PayResponse, error)
+	// Confirm confirms a tried payment.
+	Confirm(ctx context.Context, in *PaymentRequest, opts ...grpc.CallOption) (*PayResponse, error)
+	// Cancel cancels a tried payment.
+	Cancel(ctx context.Context, in *PaymentRequest, opts ...grpc.CallOption) (*PayResponse, error)
+	// AddToUsers credits the users in the range of limit and offset. Deprecated in favour of bulk credits.
+	AddToUsers(ctx context.Context, in *AddToUsersRequest, opts ...grpc.CallOption) (*AddToUsersResponse, error)
+}
+
+type bankClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBankClient(cc grpc.ClientConnInterface) BankClient {
+	return &bankClient{cc}
+}
+
+func (c *bankClient) GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*BalanceList, error) {
+	out := new(BalanceList)
+	err := c.cc.Invoke(ctx, "/mbank.v1.Bank/GetBalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankClient) Try(ctx context.Context, in *PaymentRequest, opts ...grpc.CallOption) (*PayResponse, error) {
+	out := new(PayResponse)
+	err := c.cc.Invoke(ctx, "/mbank.v1.Bank/Try", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankClient) Confirm(ctx context.Context, in *PaymentRequest, opts ...grpc.CallOption) (*PayResponse, error) {
+	out := new(PayResponse)
+	err := c.cc.Invoke(ctx, "/mbank.v1.Bank/Confirm", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankClient) Cancel(ctx context.Context, in *PaymentRequest, opts ...grpc.CallOption) (*PayResponse, error) {
+	out := new(PayResponse)
+	err := c.cc.Invoke(ctx, "/mbank.v1.Bank/Cancel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankClient) AddToUsers(ctx context.Context, in *AddToUsersRequest, opts ...grpc.CallOption) (*AddToUsersResponse, error) {
+	out := new(AddToUsersResponse)
+	err := c.cc.Invoke(ctx, "/mbank.v1.Bank/AddToUsers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BankServer is the server API for Bank service.
+// All implementations must embed UnimplementedBankServer
+// for forward compatibility
+type BankServer interface {
+	// GetBalance returns all the wallets of the user.
+	GetBalance(context.Context, *GetBalanceRequest) (*BalanceList, error)
+	// Try tries a payment, i.e. a credit or a debit of the user's wallet. A debit reserves the balance.
+	Try(context.Context, *PaymentRequest) (*PayResponse, error)
+	// Confirm confirms a tried payment.
+	Confirm(context.Context, *PaymentRequest) (*PayResponse, error)
+	// Cancel cancels a tried payment.
+	Cancel(context.Context, *PaymentRequest) (*PayResponse, error)
+	// AddToUsers credits the users in the range of limit and offset. Deprecated in favour of bulk credits.
+	AddToUsers(context.Context, *AddToUsersRequest) (*AddToUsersResponse, error)
+	mustEmbedUnimplementedBankServer()
+}
+
+// UnimplementedBankServer must be embedded to have forward compatible implementations.
+type UnimplementedBankServer struct {
+}
+
+func (UnimplementedBankServer) GetBalance(context.Context, *GetBalanceRequest) (*BalanceList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalance not implemented")
+}
+func (UnimplementedBankServer) Try(context.Context, *PaymentRequest) (*PayResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Try not implemented")
+}
+func (UnimplementedBankServer) Confirm(context.Context, *PaymentRequest) (*PayResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Confirm not implemented")
+}
+func (UnimplementedBankServer) Cancel(context.Context, *PaymentRequest) (*PayResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Cancel not implemented")
+}
+func (UnimplementedBankServer) AddToUsers(context.Context, *AddToUsersRequest) (*AddToUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddToUsers not implemented")
+}
+func (UnimplementedBankServer) mustEmbedUnimplementedBankServer() {}
+
+// UnsafeBankServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BankServer will
+// result in compilation errors.
+type UnsafeBankServer interface {
+	mustEmbedUnimplementedBankServer()
+}
+
+func RegisterBankServer(s grpc.ServiceRegistrar, srv BankServer) {
+	s.RegisterService(&Bank_ServiceDesc, srv)
+}
+
+func _Bank_GetBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankServer).GetBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mbank.v1.Bank/GetBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankServer).GetBalance(ctx, req.(*GetBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Bank_Try_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankServer).Try(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mbank.v1.Bank/Try",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankServer).Try(ctx, req.(*PaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Bank_Confirm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankServer).Confirm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mbank.v1.Bank/Confirm",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankServer).Confirm(ctx, req.(*PaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Bank_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankServer).Cancel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mbank.v1.Bank/Cancel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankServer).Cancel(ctx, req.(*PaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Bank_AddToUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddToUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankServer).AddToUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mbank.v1.Bank/AddToUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankServer).AddToUsers(ctx, req.(*AddToUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Bank_ServiceDesc is the grpc.ServiceDesc for Bank service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Bank_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mbank.v1.Bank",
+	HandlerType: (*BankServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetBalance",
+			Handler:    _Bank_GetBalance_Handler,
+		},
+		{
+			MethodName: "Try",
+			Handler:    _Bank_Try_Handler,
+		},
+		{
+			MethodName: "Confirm",
+			Handler:    _Bank_Confirm_Handler,
+		},
+		{
+			MethodName: "Cancel",
+			Handler:    _Bank_Cancel_Handler,
+		},
+		{
+			MethodName: "AddToUsers",
+			Handler:    _Bank_AddToUsers_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "bank.proto",
+}
@@ -2,24 +2,31 @@ package main
 
 import (
 	"context"
-	"database/sql"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-openapi/loads"
+	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/kawabatas/m-bank/domain"
 	"github.com/kawabatas/m-bank/domain/model"
+	"github.com/kawabatas/m-bank/domain/repository"
 	"github.com/kawabatas/m-bank/gen/models"
 	"github.com/kawabatas/m-bank/gen/restapi"
 	"github.com/kawabatas/m-bank/gen/restapi/operations"
 	"github.com/kawabatas/m-bank/gen/restapi/operations/bank"
+	"github.com/kawabatas/m-bank/infra/webhook"
 )
 
-func newServer(db *sql.DB) (*restapi.Server, error) {
+func newServer(app *application, cfg *config) (*restapi.Server, error) {
 	swaggerSpec, err := loads.Analyzed(restapi.SwaggerJSON, "")
 	if err != nil {
 		return nil, err
@@ -29,7 +36,6 @@ func newServer(db *sql.DB) (*restapi.Server, error) {
 	server := restapi.NewServer(api)
 	api.Logger = log.Printf
 
-	app := newApp(db)
 	setHandler(api, app)
 	server.SetAPI(api)
 
@@ -38,22 +44,112 @@ func newServer(db *sql.DB) (*restapi.Server, error) {
 	}
 	server.ConfigureAPI()
 
+	// 記録された残高の変更のイベントをOUTBOX_PUBLISHERの配信先に送る。
+	// 登録されたWebhookへの配信はイベントと同じトランザクションで作られ、ディスパッチャーが別に送る
+	if app.OutboxRepo == nil && cfg.OutboxPublisher != "" {
+		return nil, fmt.Errorf("OUTBOX_PUBLISHER is set, but the %s backend does not record outbox events", app.Driver)
+	}
+	var outboxRelay *outboxRelay
+	if cfg.OutboxPublisher != "" {
+		publisher, err := newEventPublisher(cfg.OutboxPublisher)
+		if err != nil {
+			return nil, err
+		}
+		outboxRelay = newOutboxRelay(app.OutboxRepo, publisher, cfg.OutboxInterval, cfg.OutboxBatchSize, cfg.OutboxMaxAttempts)
+	}
+	var webhookDispatcher *webhookDispatcher
+	if app.WebhookService != nil {
+		webhookDispatcher = newWebhookDispatcher(app.WebhookService.WebhookRepo, webhook.NewClient(webhookTimeout), cfg.WebhookInterval, cfg.WebhookBatchSize, cfg.WebhookMaxAttempts)
+	}
+
+	// 期限切れのTryとポイントを定期的に処理し、サーバーのシャットダウン時に停止する
+	var transferRepo repository.TransferRepository
+	if app.TransferService != nil {
+		transferRepo = app.TransferService.TransferRepo
+	}
+	sweeper := newExpirationSweeper(app.PaymentService.PaymentRepo, transferRepo, app.BalanceService.PointLotRepo, cfg.SweepInterval, cfg.SweepBatchSize)
+	sweeper.Start()
+	// 未完了の一斉加算のジョブをチャンク単位で処理する
+	var bulkCreditWorker *bulkCreditWorker
+	if app.BulkCreditService != nil {
+		bulkCreditWorker = newBulkCreditWorker(app.BulkCreditService.JobRepo, cfg.BulkCreditInterval, cfg.BulkCreditChunkSize)
+		bulkCreditWorker.Start()
+	}
+	if outboxRelay != nil {
+		outboxRelay.Start()
+	}
+	if webhookDispatcher != nil {
+		webhookDispatcher.Start()
+	}
+	api.PreServerShutdown = func() {
+		sweeper.Stop()
+		if bulkCreditWorker != nil {
+			bulkCreditWorker.Stop()
+		}
+		if outboxRelay != nil {
+			outboxRelay.Stop()
+		}
+		if webhookDispatcher != nil {
+			webhookDispatcher.Stop()
+		}
+	}
+
 	return server, nil
 }
 
+// setHandler sets the handlers of the services in the app. The APIs of the nil services answer 501 with the feature
+// which the backend does not support.
 func setHandler(api *operations.BankAPI, app *application) {
 	ctx := context.Background()
 	api.BankGetBalanceHandler = bank.GetBalanceHandlerFunc(func(params bank.GetBalanceParams) middleware.Responder {
-		balance, err := app.BalanceService.Get(ctx, uint(params.UserID))
+		balances, err := app.BalanceService.List(ctx, uint(params.UserID))
 		if err != nil {
 			ec, em := errToCodeAndMessage(err)
 			return bank.NewGetBalanceDefault(ec).WithPayload(toErrorResponse(ec, em))
 		}
-		return bank.NewGetBalanceOK().WithPayload(&models.Balance{UserID: int32(balance.UserID), Amount: int32(balance.Amount)})
+		return bank.NewGetBalanceOK().WithPayload(toBalanceList(uint(params.UserID), balances))
+	})
+	api.BankListBalanceLogsHandler = bank.ListBalanceLogsHandlerFunc(func(params bank.ListBalanceLogsParams) middleware.Responder {
+		var from, to time.Time
+		if params.From != nil {
+			from = time.Time(*params.From)
+		}
+		if params.To != nil {
+			to = time.Time(*params.To)
+		}
+		limit := int(swag.Int32Value(params.Limit))
+		logs, nextCursor, err := app.BalanceService.ListLogs(ctx, uint(params.UserID), swag.StringValue(params.Currency), from, to, swag.StringValue(params.Cursor), limit)
+		if err != nil {
+			ec, em := errToCodeAndMessage(err)
+			return bank.NewListBalanceLogsDefault(ec).WithPayload(toErrorResponse(ec, em))
+		}
+		return bank.NewListBalanceLogsOK().WithPayload(toBalanceLogList(logs, nextCursor))
 	})
 
+	api.BankListPaymentsHandler = bank.ListPaymentsHandlerFunc(func(params bank.ListPaymentsParams) middleware.Responder {
+		var from, to time.Time
+		if params.From != nil {
+			from = time.Time(*params.From)
+		}
+		if params.To != nil {
+			to = time.Time(*params.To)
+		}
+		limit := int(swag.Int32Value(params.Limit))
+		pts, nextCursor, err := app.PaymentService.List(ctx, uint(params.UserID), swag.StringValue(params.Status), swag.StringValue(params.Sign), from, to, swag.StringValue(params.Cursor), limit)
+		if err != nil {
+			ec, em := errToCodeAndMessage(err)
+			return bank.NewListPaymentsDefault(ec).WithPayload(toErrorResponse(ec, em))
+		}
+		return bank.NewListPaymentsOK().WithPayload(toPaymentList(pts, nextCursor))
+	})
 	api.BankPaymentTryHandler = bank.PaymentTryHandlerFunc(func(params bank.PaymentTryParams) middleware.Responder {
-		pt, balance, err := app.PaymentService.Try(ctx, *params.Body.IdempotencyKey, uint(*params.Body.UserID), int(params.Body.Amount))
+		amount, err := parseAmount(params.Body.Amount)
+		if err != nil {
+			ec, em := errToCodeAndMessage(err)
+			return bank.NewPaymentTryDefault(ec).WithPayload(toErrorResponse(ec, em))
+		}
+		expiresIn := time.Duration(params.Body.ExpiresIn) * time.Second
+		pt, balance, err := app.PaymentService.Try(ctx, *params.Body.IdempotencyKey, uint(*params.Body.UserID), params.Body.Currency, amount, expiresIn)
 		if err != nil {
 			ec, em := errToCodeAndMessage(err)
 			return bank.NewPaymentTryDefault(ec).WithPayload(toErrorResponse(ec, em))
@@ -61,7 +157,12 @@ func setHandler(api *operations.BankAPI, app *application) {
 		return bank.NewPaymentTryOK().WithPayload(toPayResponse(pt, balance))
 	})
 	api.BankPaymentConfirmHandler = bank.PaymentConfirmHandlerFunc(func(params bank.PaymentConfirmParams) middleware.Responder {
-		pt, balance, err := app.PaymentService.Confirm(ctx, *params.Body.IdempotencyKey, uint(*params.Body.UserID), int(params.Body.Amount))
+		amount, err := parseAmount(params.Body.Amount)
+		if err != nil {
+			ec, em := errToCodeAndMessage(err)
+			return bank.NewPaymentConfirmDefault(ec).WithPayload(toErrorResponse(ec, em))
+		}
+		pt, balance, err := app.PaymentService.Confirm(ctx, *params.Body.IdempotencyKey, uint(*params.Body.UserID), params.Body.Currency, amount)
 		if err != nil {
 			ec, em := errToCodeAndMessage(err)
 			return bank.NewPaymentConfirmDefault(ec).WithPayload(toErrorResponse(ec, em))
@@ -69,7 +170,12 @@ func setHandler(api *operations.BankAPI, app *application) {
 		return bank.NewPaymentConfirmOK().WithPayload(toPayResponse(pt, balance))
 	})
 	api.BankPaymentCancelHandler = bank.PaymentCancelHandlerFunc(func(params bank.PaymentCancelParams) middleware.Responder {
-		pt, balance, err := app.PaymentService.Cancel(ctx, *params.Body.IdempotencyKey, uint(*params.Body.UserID), int(params.Body.Amount))
+		amount, err := parseAmount(params.Body.Amount)
+		if err != nil {
+			ec, em := errToCodeAndMessage(err)
+			return bank.NewPaymentCancelDefault(ec).WithPayload(toErrorResponse(ec, em))
+		}
+		pt, balance, err := app.PaymentService.Cancel(ctx, *params.Body.IdempotencyKey, uint(*params.Body.UserID), params.Body.Currency, amount)
 		if err != nil {
 			ec, em := errToCodeAndMessage(err)
 			return bank.NewPaymentCancelDefault(ec).WithPayload(toErrorResponse(ec, em))
@@ -77,8 +183,264 @@ func setHandler(api *operations.BankAPI, app *application) {
 		return bank.NewPaymentCancelOK().WithPayload(toPayResponse(pt, balance))
 	})
 
+	if app.BulkCreditService != nil {
+		api.BankCreateBulkCreditHandler = bank.CreateBulkCreditHandlerFunc(func(params bank.CreateBulkCreditParams) middleware.Responder {
+			amount, err := parseAmount(*params.Body.Amount)
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewCreateBulkCreditDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			target, err := fromBulkCreditTarget(params.Body.Target)
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewCreateBulkCreditDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			job, failures, err := app.BulkCreditService.Create(ctx, *params.Body.IdempotencyKey, params.Body.Currency, amount, target, params.Body.DryRun)
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewCreateBulkCreditDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			res := toBulkCreditJob(job, failures)
+			res.DryRun = params.Body.DryRun
+			return bank.NewCreateBulkCreditOK().WithPayload(res)
+		})
+		api.BankGetBulkCreditHandler = bank.GetBulkCreditHandlerFunc(func(params bank.GetBulkCreditParams) middleware.Responder {
+			job, failures, err := app.BulkCreditService.Get(ctx, uint64(params.ID))
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewGetBulkCreditDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			return bank.NewGetBulkCreditOK().WithPayload(toBulkCreditJob(job, failures))
+		})
+		api.BankReverseBulkCreditHandler = bank.ReverseBulkCreditHandlerFunc(func(params bank.ReverseBulkCreditParams) middleware.Responder {
+			job, items, err := app.BulkCreditService.Reverse(ctx, uint64(params.ID), *params.Body.Mode)
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewReverseBulkCreditDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			return bank.NewReverseBulkCreditOK().WithPayload(toBulkCreditJob(job, items))
+		})
+	} else {
+		unsupported := unsupportedResponder("bulk credit jobs", app.Driver)
+		api.BankCreateBulkCreditHandler = bank.CreateBulkCreditHandlerFunc(func(bank.CreateBulkCreditParams) middleware.Responder { return unsupported })
+		api.BankGetBulkCreditHandler = bank.GetBulkCreditHandlerFunc(func(bank.GetBulkCreditParams) middleware.Responder { return unsupported })
+		api.BankReverseBulkCreditHandler = bank.ReverseBulkCreditHandlerFunc(func(bank.ReverseBulkCreditParams) middleware.Responder { return unsupported })
+	}
+
+	if app.TransferService != nil {
+		api.BankTransferTryHandler = bank.TransferTryHandlerFunc(func(params bank.TransferTryParams) middleware.Responder {
+			amount, err := parseAmount(*params.Body.Amount)
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewTransferTryDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			expiresIn := time.Duration(params.Body.ExpiresIn) * time.Second
+			t, from, to, err := app.TransferService.Try(ctx, *params.Body.IdempotencyKey, uint(*params.Body.FromUserID), uint(*params.Body.ToUserID), params.Body.Currency, amount, expiresIn)
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewTransferTryDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			return bank.NewTransferTryOK().WithPayload(toTransferResponse(t, from, to))
+		})
+		api.BankTransferConfirmHandler = bank.TransferConfirmHandlerFunc(func(params bank.TransferConfirmParams) middleware.Responder {
+			amount, err := parseAmount(*params.Body.Amount)
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewTransferConfirmDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			t, from, to, err := app.TransferService.Confirm(ctx, *params.Body.IdempotencyKey, uint(*params.Body.FromUserID), uint(*params.Body.ToUserID), params.Body.Currency, amount)
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewTransferConfirmDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			return bank.NewTransferConfirmOK().WithPayload(toTransferResponse(t, from, to))
+		})
+		api.BankTransferCancelHandler = bank.TransferCancelHandlerFunc(func(params bank.TransferCancelParams) middleware.Responder {
+			amount, err := parseAmount(*params.Body.Amount)
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewTransferCancelDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			t, from, to, err := app.TransferService.Cancel(ctx, *params.Body.IdempotencyKey, uint(*params.Body.FromUserID), uint(*params.Body.ToUserID), params.Body.Currency, amount)
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewTransferCancelDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			return bank.NewTransferCancelOK().WithPayload(toTransferResponse(t, from, to))
+		})
+	} else {
+		unsupported := unsupportedResponder("transfers", app.Driver)
+		api.BankTransferTryHandler = bank.TransferTryHandlerFunc(func(bank.TransferTryParams) middleware.Responder { return unsupported })
+		api.BankTransferConfirmHandler = bank.TransferConfirmHandlerFunc(func(bank.TransferConfirmParams) middleware.Responder { return unsupported })
+		api.BankTransferCancelHandler = bank.TransferCancelHandlerFunc(func(bank.TransferCancelParams) middleware.Responder { return unsupported })
+	}
+
+	if app.ExchangeService != nil {
+		api.BankExchangeHandler = bank.ExchangeHandlerFunc(func(params bank.ExchangeParams) middleware.Responder {
+			amount, err := parseAmount(*params.Body.Amount)
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewExchangeDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			e, from, to, err := app.ExchangeService.Exchange(ctx, *params.Body.IdempotencyKey, uint(*params.Body.UserID), uint64(*params.Body.RateID), *params.Body.FromCurrency, *params.Body.ToCurrency, amount)
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewExchangeDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			return bank.NewExchangeOK().WithPayload(toExchangeResponse(e, from, to))
+		})
+		api.BankListExchangeRatesHandler = bank.ListExchangeRatesHandlerFunc(func(params bank.ListExchangeRatesParams) middleware.Responder {
+			rates, err := app.ExchangeService.ListRates(ctx, swag.StringValue(params.FromCurrency), swag.StringValue(params.ToCurrency))
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewListExchangeRatesDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			return bank.NewListExchangeRatesOK().WithPayload(toExchangeRateList(rates))
+		})
+		api.BankUploadExchangeRatesHandler = bank.UploadExchangeRatesHandlerFunc(func(params bank.UploadExchangeRatesParams) middleware.Responder {
+			rates := make([]*model.ExchangeRate, 0, len(params.Body.Rates))
+			for _, r := range params.Body.Rates {
+				rates = append(rates, fromExchangeRate(r))
+			}
+			rates, err := app.ExchangeService.UploadRates(ctx, rates)
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewUploadExchangeRatesDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			return bank.NewUploadExchangeRatesOK().WithPayload(toExchangeRateList(rates))
+		})
+	} else {
+		unsupported := unsupportedResponder("exchanges", app.Driver)
+		api.BankExchangeHandler = bank.ExchangeHandlerFunc(func(bank.ExchangeParams) middleware.Responder { return unsupported })
+		api.BankListExchangeRatesHandler = bank.ListExchangeRatesHandlerFunc(func(bank.ListExchangeRatesParams) middleware.Responder { return unsupported })
+		api.BankUploadExchangeRatesHandler = bank.UploadExchangeRatesHandlerFunc(func(bank.UploadExchangeRatesParams) middleware.Responder { return unsupported })
+	}
+
+	api.BankSetOverdraftLimitHandler = bank.SetOverdraftLimitHandlerFunc(func(params bank.SetOverdraftLimitParams) middleware.Responder {
+		limit, err := parseAmount(*params.Body.OverdraftLimit)
+		if err != nil {
+			ec, em := errToCodeAndMessage(err)
+			return bank.NewSetOverdraftLimitDefault(ec).WithPayload(toErrorResponse(ec, em))
+		}
+		balance, err := app.BalanceService.SetOverdraftLimit(ctx, uint(params.UserID), *params.Body.Currency, limit)
+		if err != nil {
+			ec, em := errToCodeAndMessage(err)
+			return bank.NewSetOverdraftLimitDefault(ec).WithPayload(toErrorResponse(ec, em))
+		}
+		return bank.NewSetOverdraftLimitOK().WithPayload(toBalance(balance))
+	})
+
+	if app.LimitService != nil {
+		api.BankListSpendingLimitsHandler = bank.ListSpendingLimitsHandlerFunc(func(params bank.ListSpendingLimitsParams) middleware.Responder {
+			limits, err := app.LimitService.ListLimits(ctx, swag.StringValue(params.Scope), swag.StringValue(params.ScopeID))
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewListSpendingLimitsDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			return bank.NewListSpendingLimitsOK().WithPayload(toSpendingLimitList(limits))
+		})
+		api.BankSetSpendingLimitHandler = bank.SetSpendingLimitHandlerFunc(func(params bank.SetSpendingLimitParams) middleware.Responder {
+			limit, err := fromSpendingLimit(params.Body)
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewSetSpendingLimitDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			limit, err = app.LimitService.SetLimit(ctx, limit)
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewSetSpendingLimitDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			return bank.NewSetSpendingLimitOK().WithPayload(toSpendingLimit(limit))
+		})
+		api.BankSetUserTierHandler = bank.SetUserTierHandlerFunc(func(params bank.SetUserTierParams) middleware.Responder {
+			user, err := app.LimitService.SetUserTier(ctx, uint(params.UserID), *params.Body.Tier)
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewSetUserTierDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			return bank.NewSetUserTierOK().WithPayload(&models.User{ID: int32(user.ID), Name: user.Name, Tier: user.Tier})
+		})
+	} else {
+		unsupported := unsupportedResponder("spending limits", app.Driver)
+		api.BankListSpendingLimitsHandler = bank.ListSpendingLimitsHandlerFunc(func(bank.ListSpendingLimitsParams) middleware.Responder { return unsupported })
+		api.BankSetSpendingLimitHandler = bank.SetSpendingLimitHandlerFunc(func(bank.SetSpendingLimitParams) middleware.Responder { return unsupported })
+		api.BankSetUserTierHandler = bank.SetUserTierHandlerFunc(func(bank.SetUserTierParams) middleware.Responder { return unsupported })
+	}
+
+	if app.PaymentService.RefundRepo != nil {
+		api.BankPaymentRefundHandler = bank.PaymentRefundHandlerFunc(func(params bank.PaymentRefundParams) middleware.Responder {
+			amount, err := parseAmount(*params.Body.Amount)
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewPaymentRefundDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			refund, pt, balance, err := app.PaymentService.Refund(ctx, *params.Body.IdempotencyKey, params.IdempotencyKey, amount)
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewPaymentRefundDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			return bank.NewPaymentRefundOK().WithPayload(&models.RefundResponse{
+				Refund:  toRefund(refund),
+				Payment: toPayment(pt),
+				Balance: toBalance(balance),
+			})
+		})
+		api.BankListRefundsHandler = bank.ListRefundsHandlerFunc(func(params bank.ListRefundsParams) middleware.Responder {
+			refunds, err := app.PaymentService.ListRefunds(ctx, params.IdempotencyKey)
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewListRefundsDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			return bank.NewListRefundsOK().WithPayload(toRefundList(refunds))
+		})
+	} else {
+		unsupported := unsupportedResponder("refunds", app.Driver)
+		api.BankPaymentRefundHandler = bank.PaymentRefundHandlerFunc(func(bank.PaymentRefundParams) middleware.Responder { return unsupported })
+		api.BankListRefundsHandler = bank.ListRefundsHandlerFunc(func(bank.ListRefundsParams) middleware.Responder { return unsupported })
+	}
+
+	if app.WebhookService != nil {
+		api.BankCreateWebhookHandler = bank.CreateWebhookHandlerFunc(func(params bank.CreateWebhookParams) middleware.Responder {
+			userIDs := make([]uint, 0, len(params.Body.UserIds))
+			for _, userID := range params.Body.UserIds {
+				if userID <= 0 {
+					ec, em := errToCodeAndMessage(domain.ErrInvalidParam)
+					return bank.NewCreateWebhookDefault(ec).WithPayload(toErrorResponse(ec, em))
+				}
+				userIDs = append(userIDs, uint(userID))
+			}
+			subscription, err := app.WebhookService.Subscribe(ctx, *params.Body.URL, params.Body.EventTypes, userIDs)
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewCreateWebhookDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			// 署名の鍵は登録時にだけ返す
+			res := toWebhook(subscription)
+			res.Secret = subscription.Secret
+			return bank.NewCreateWebhookOK().WithPayload(res)
+		})
+		api.BankListWebhookDeliveriesHandler = bank.ListWebhookDeliveriesHandlerFunc(func(params bank.ListWebhookDeliveriesParams) middleware.Responder {
+			limit := int(swag.Int32Value(params.Limit))
+			deliveries, nextCursor, err := app.WebhookService.ListDeliveries(ctx, uint64(params.ID), swag.StringValue(params.Cursor), limit)
+			if err != nil {
+				ec, em := errToCodeAndMessage(err)
+				return bank.NewListWebhookDeliveriesDefault(ec).WithPayload(toErrorResponse(ec, em))
+			}
+			return bank.NewListWebhookDeliveriesOK().WithPayload(toWebhookDeliveryList(deliveries, nextCursor))
+		})
+	} else {
+		unsupported := unsupportedResponder("webhooks", app.Driver)
+		api.BankCreateWebhookHandler = bank.CreateWebhookHandlerFunc(func(bank.CreateWebhookParams) middleware.Responder { return unsupported })
+		api.BankListWebhookDeliveriesHandler = bank.ListWebhookDeliveriesHandlerFunc(func(bank.ListWebhookDeliveriesParams) middleware.Responder { return unsupported })
+	}
+
 	api.BankPaymentAddToUsersHandler = bank.PaymentAddToUsersHandlerFunc(func(params bank.PaymentAddToUsersParams) middleware.Responder {
-		if err := app.PaymentService.AddToUsers(ctx, int(*params.Body.Amount), int(params.Body.Limit), int(params.Body.Offset)); err != nil {
+		amount, err := parseAmount(*params.Body.Amount)
+		if err != nil {
+			ec, em := errToCodeAndMessage(err)
+			return bank.NewPaymentAddToUsersDefault(ec).WithPayload(toErrorResponse(ec, em))
+		}
+		if err := app.PaymentService.AddToUsers(ctx, params.Body.Currency, amount, int(params.Body.ExpiresInDays), int(params.Body.Limit), int(params.Body.Offset)); err != nil {
 			ec, em := errToCodeAndMessage(err)
 			return bank.NewPaymentAddToUsersDefault(ec).WithPayload(toErrorResponse(ec, em))
 		}
@@ -89,16 +451,391 @@ func setHandler(api *operations.BankAPI, app *application) {
 func toPayResponse(pt *model.PaymentTransaction, balance *model.Balance) *models.PayResponse {
 	return &models.PayResponse{
 		IdempotencyKey: pt.UUID,
+		Status:         string(pt.Status),
 		TryTime:        strfmt.DateTime(pt.TryTime),
+		ExpireTime:     strfmt.DateTime(pt.ExpireTime),
 		ConfirmTime:    strfmt.DateTime(pt.ConfirmTime),
 		CancelTime:     strfmt.DateTime(pt.CancelTime),
-		Balance: &models.Balance{
-			UserID: int32(balance.UserID),
-			Amount: int32(balance.Amount),
-		},
+		ExpiredTime:    strfmt.DateTime(pt.ExpiredTime),
+		RefundedAmount: formatAmount(pt.RefundedAmount),
+		Balance:        toBalance(balance),
+	}
+}
+
+func toPaymentList(pts []*model.PaymentTransaction, nextCursor string) *models.PaymentList {
+	list := &models.PaymentList{
+		Payments:   make([]*models.Payment, 0, len(pts)),
+		NextCursor: nextCursor,
+	}
+	for _, pt := range pts {
+		list.Payments = append(list.Payments, toPayment(pt))
+	}
+	return list
+}
+
+func toPayment(pt *model.PaymentTransaction) *models.Payment {
+	return &models.Payment{
+		IdempotencyKey: pt.UUID,
+		UserID:         int32(pt.UserID),
+		Currency:       string(pt.Currency),
+		Amount:         formatAmount(pt.Amount),
+		Status:         string(pt.Status),
+		TryTime:        strfmt.DateTime(pt.TryTime),
+		ExpireTime:     strfmt.DateTime(pt.ExpireTime),
+		ConfirmTime:    strfmt.DateTime(pt.ConfirmTime),
+		CancelTime:     strfmt.DateTime(pt.CancelTime),
+		ExpiredTime:    strfmt.DateTime(pt.ExpiredTime),
+		RefundedAmount: formatAmount(pt.RefundedAmount),
+	}
+}
+
+func toRefund(refund *model.Refund) *models.Refund {
+	return &models.Refund{
+		IdempotencyKey:        refund.UUID,
+		PaymentIdempotencyKey: refund.PaymentUUID,
+		UserID:                int32(refund.UserID),
+		Currency:              string(refund.Currency),
+		Amount:                formatAmount(refund.Amount),
+		CreateTime:            strfmt.DateTime(refund.CreateTime),
+	}
+}
+
+func toRefundList(refunds []*model.Refund) *models.RefundList {
+	list := &models.RefundList{Refunds: make([]*models.Refund, 0, len(refunds))}
+	for _, refund := range refunds {
+		list.Refunds = append(list.Refunds, toRefund(refund))
+	}
+	return list
+}
+
+func toWebhook(subscription *model.WebhookSubscription) *models.Webhook {
+	res := &models.Webhook{
+		ID:         int64(subscription.ID),
+		URL:        subscription.URL,
+		EventTypes: make([]string, 0, len(subscription.EventTypes)),
+		UserIds:    make([]int32, 0, len(subscription.UserIDs)),
+		CreateTime: strfmt.DateTime(subscription.CreateTime),
+	}
+	for _, t := range subscription.EventTypes {
+		res.EventTypes = append(res.EventTypes, string(t))
+	}
+	for _, userID := range subscription.UserIDs {
+		res.UserIds = append(res.UserIds, int32(userID))
+	}
+	return res
+}
+
+func toWebhookDeliveryList(deliveries []*model.WebhookDelivery, nextCursor string) *models.WebhookDeliveryList {
+	list := &models.WebhookDeliveryList{
+		Deliveries: make([]*models.WebhookDelivery, 0, len(deliveries)),
+		NextCursor: nextCursor,
+	}
+	for _, d := range deliveries {
+		delivery := &models.WebhookDelivery{
+			ID:             int64(d.ID),
+			EventSequence:  int64(d.EventSequence),
+			EventType:      string(d.EventType),
+			Status:         string(d.Status),
+			Attempts:       int32(d.Attempts),
+			LastStatusCode: int32(d.LastStatusCode),
+			LastError:      d.LastError,
+			CreateTime:     strfmt.DateTime(d.CreateTime),
+			DeliverTime:    strfmt.DateTime(d.DeliverTime),
+		}
+		// 次の試行はリトライ待ちの配信にだけある
+		if d.Status == model.WebhookDeliveryStatusPending {
+			delivery.NextAttemptTime = strfmt.DateTime(d.NextAttemptTime)
+		}
+		list.Deliveries = append(list.Deliveries, delivery)
+	}
+	return list
+}
+
+func toTransferResponse(t *model.Transfer, from, to *model.Balance) *models.TransferResponse {
+	return &models.TransferResponse{
+		IdempotencyKey: t.UUID,
+		Status:         string(t.Status),
+		Currency:       string(t.Currency),
+		Amount:         formatAmount(t.Amount),
+		TryTime:        strfmt.DateTime(t.TryTime),
+		ExpireTime:     strfmt.DateTime(t.ExpireTime),
+		ConfirmTime:    strfmt.DateTime(t.ConfirmTime),
+		CancelTime:     strfmt.DateTime(t.CancelTime),
+		ExpiredTime:    strfmt.DateTime(t.ExpiredTime),
+		FromBalance:    toBalance(from),
+		ToBalance:      toBalance(to),
+	}
+}
+
+// toBulkCreditJob converts the job with its failed items and the items whose reversal is skipped.
+func toBulkCreditJob(job *model.BulkCreditJob, items []*model.BulkCreditItem) *models.BulkCreditJob {
+	// 合計金額が64bitに収まることはジョブの作成時に検証している
+	totalAmount, _ := job.TotalAmount()
+	res := &models.BulkCreditJob{
+		ID:             int64(job.ID),
+		IdempotencyKey: job.IdempotencyKey,
+		Currency:       string(job.Currency),
+		Amount:         formatAmount(job.Amount),
+		Target:         toBulkCreditTarget(job.Target),
+		Status:         string(job.Status),
+		TotalCount:     int32(job.TotalCount),
+		TotalAmount:    formatAmount(totalAmount),
+		ProcessedCount: int32(job.ProcessedCount()),
+		CreditedCount:  int32(job.CreditedCount),
+		FailedCount:    int32(job.FailedCount),
+		Failures:       []*models.BulkCreditFailure{},
+		LastError:      job.LastError,
+		CreateTime:     strfmt.DateTime(job.CreateTime),
+		UpdateTime:     strfmt.DateTime(job.UpdateTime),
+		FinishTime:     strfmt.DateTime(job.FinishTime),
+	}
+	if job.ReversalStatus != "" {
+		res.Reversal = &models.BulkCreditReversal{
+			Status:         string(job.ReversalStatus),
+			Mode:           string(job.ReversalMode),
+			ReversedCount:  int32(job.ReversedCount),
+			SkippedCount:   int32(job.ReversalSkippedCount),
+			ReversedAmount: formatAmount(job.ReversedAmount),
+			Skipped:        []*models.BulkCreditFailure{},
+			RequestTime:    strfmt.DateTime(job.ReversalRequestTime),
+			FinishTime:     strfmt.DateTime(job.ReversalFinishTime),
+		}
+	}
+	for _, item := range items {
+		if item.Status == model.BulkCreditItemStatusFailed {
+			res.Failures = append(res.Failures, &models.BulkCreditFailure{
+				UserID: int32(item.UserID),
+				Error:  item.Error,
+			})
+		}
+		if item.ReversalStatus == model.BulkCreditItemReversalSkipped && res.Reversal != nil {
+			res.Reversal.Skipped = append(res.Reversal.Skipped, &models.BulkCreditFailure{
+				UserID: int32(item.UserID),
+				Error:  domain.ErrShortBalance.Error(),
+			})
+		}
+	}
+	return res
+}
+
+func toBulkCreditTarget(target model.BulkCreditTarget) *models.BulkCreditTarget {
+	res := &models.BulkCreditTarget{
+		Type:    string(target.Type),
+		UserIds: make([]int32, 0, len(target.UserIDs)),
+	}
+	if target.BalanceBelow != 0 {
+		res.BalanceBelow = formatAmount(target.BalanceBelow)
+	}
+	for _, userID := range target.UserIDs {
+		res.UserIds = append(res.UserIds, int32(userID))
+	}
+	if !target.CreatedFrom.IsZero() {
+		res.CreatedFrom = strfmt.DateTime(target.CreatedFrom)
+	}
+	if !target.CreatedTo.IsZero() {
+		res.CreatedTo = strfmt.DateTime(target.CreatedTo)
+	}
+	return res
+}
+
+// fromBulkCreditTarget converts the requested target. A CSV is read as the list of user ids.
+func fromBulkCreditTarget(t *models.BulkCreditTarget) (model.BulkCreditTarget, error) {
+	if t == nil {
+		return model.BulkCreditTarget{Type: model.BulkCreditTargetAll}, nil
+	}
+	target := model.BulkCreditTarget{
+		Type:        model.BulkCreditTargetType(t.Type),
+		CreatedFrom: time.Time(t.CreatedFrom),
+		CreatedTo:   time.Time(t.CreatedTo),
+	}
+	if t.BalanceBelow != "" {
+		balanceBelow, err := parseAmount(t.BalanceBelow)
+		if err != nil {
+			return model.BulkCreditTarget{}, err
+		}
+		target.BalanceBelow = balanceBelow
+	}
+	for _, userID := range t.UserIds {
+		if userID <= 0 {
+			return model.BulkCreditTarget{}, domain.ErrInvalidParam
+		}
+		target.UserIDs = append(target.UserIDs, uint(userID))
+	}
+	if t.Type == "csv" {
+		if len(target.UserIDs) > 0 {
+			return model.BulkCreditTarget{}, domain.ErrInvalidParam
+		}
+		userIDs, err := model.ParseUserIDsCSV(strings.NewReader(t.Csv))
+		if err != nil {
+			return model.BulkCreditTarget{}, err
+		}
+		target.Type = model.BulkCreditTargetUserIDs
+		target.UserIDs = userIDs
+	} else if t.Csv != "" {
+		return model.BulkCreditTarget{}, domain.ErrInvalidParam
+	}
+	return target, nil
+}
+
+func toExchangeResponse(e *model.Exchange, from, to *model.Balance) *models.ExchangeResponse {
+	return &models.ExchangeResponse{
+		IdempotencyKey: e.UUID,
+		UserID:         int32(e.UserID),
+		RateID:         int64(e.RateID),
+		FromCurrency:   string(e.FromCurrency),
+		FromAmount:     formatAmount(e.FromAmount),
+		ToCurrency:     string(e.ToCurrency),
+		ToAmount:       formatAmount(e.ToAmount),
+		Rate:           e.Rate,
+		Rounding:       string(e.Rounding),
+		CreateTime:     strfmt.DateTime(e.CreateTime),
+		FromBalance:    toBalance(from),
+		ToBalance:      toBalance(to),
 	}
 }
 
+func toExchangeRateList(rates []*model.ExchangeRate) *models.ExchangeRateList {
+	list := &models.ExchangeRateList{
+		Rates: make([]*models.ExchangeRate, 0, len(rates)),
+	}
+	for _, r := range rates {
+		list.Rates = append(list.Rates, &models.ExchangeRate{
+			ID:           int64(r.ID),
+			FromCurrency: string(r.FromCurrency),
+			ToCurrency:   string(r.ToCurrency),
+			Rate:         r.Rate,
+			Rounding:     string(r.Rounding),
+			ValidFrom:    strfmt.DateTime(r.ValidFrom),
+			ValidTo:      strfmt.DateTime(r.ValidTo),
+			CreateTime:   strfmt.DateTime(r.CreateTime),
+		})
+	}
+	return list
+}
+
+func fromExchangeRate(r *models.ExchangeRate) *model.ExchangeRate {
+	return &model.ExchangeRate{
+		FromCurrency: domain.Currency(r.FromCurrency),
+		ToCurrency:   domain.Currency(r.ToCurrency),
+		Rate:         r.Rate,
+		Rounding:     domain.RoundingMode(r.Rounding),
+		ValidFrom:    time.Time(r.ValidFrom),
+		ValidTo:      time.Time(r.ValidTo),
+	}
+}
+
+func toSpendingLimit(limit *model.SpendingLimit) *models.SpendingLimit {
+	return &models.SpendingLimit{
+		Scope:             swag.String(string(limit.Scope)),
+		ScopeID:           swag.String(limit.ScopeID),
+		Currency:          swag.String(string(limit.Currency)),
+		MaxSingleDebit:    formatAmount(limit.MaxSingleDebit),
+		DailyDebitLimit:   formatAmount(limit.DailyDebitLimit),
+		MonthlyDebitLimit: formatAmount(limit.MonthlyDebitLimit),
+		HourlyDebitCount:  int32(limit.HourlyDebitCount),
+		UpdateTime:        strfmt.DateTime(limit.UpdateTime),
+	}
+}
+
+func toSpendingLimitList(limits []*model.SpendingLimit) *models.SpendingLimitList {
+	res := &models.SpendingLimitList{Limits: make([]*models.SpendingLimit, 0, len(limits))}
+	for _, limit := range limits {
+		res.Limits = append(res.Limits, toSpendingLimit(limit))
+	}
+	return res
+}
+
+// 省略された上限は0（上限なし）として扱う
+func fromSpendingLimit(l *models.SpendingLimit) (*model.SpendingLimit, error) {
+	limit := &model.SpendingLimit{
+		Scope:            model.SpendingLimitScope(*l.Scope),
+		ScopeID:          *l.ScopeID,
+		Currency:         domain.Currency(*l.Currency),
+		HourlyDebitCount: int(l.HourlyDebitCount),
+	}
+	for _, f := range []struct {
+		s string
+		v *int64
+	}{
+		{l.MaxSingleDebit, &limit.MaxSingleDebit},
+		{l.DailyDebitLimit, &limit.DailyDebitLimit},
+		{l.MonthlyDebitLimit, &limit.MonthlyDebitLimit},
+	} {
+		if f.s == "" {
+			continue
+		}
+		amount, err := parseAmount(f.s)
+		if err != nil {
+			return nil, err
+		}
+		*f.v = amount
+	}
+	return limit, nil
+}
+
+func toBalance(balance *model.Balance) *models.Balance {
+	res := &models.Balance{
+		UserID:         int32(balance.UserID),
+		Amount:         formatAmount(balance.Amount),
+		Available:      formatAmount(balance.AvailableAmount()),
+		OverdraftLimit: formatAmount(balance.OverdraftLimit),
+		Currency:       string(balance.Currency),
+	}
+	for _, e := range balance.Expirations {
+		res.Expirations = append(res.Expirations, &models.PointExpiration{
+			Amount:     formatAmount(e.Amount),
+			ExpireTime: strfmt.DateTime(e.ExpireTime),
+		})
+	}
+	return res
+}
+
+func toBalanceList(userID uint, balances []*model.Balance) *models.BalanceList {
+	list := &models.BalanceList{
+		UserID:   int32(userID),
+		Balances: make([]*models.Balance, 0, len(balances)),
+	}
+	for _, b := range balances {
+		list.Balances = append(list.Balances, toBalance(b))
+	}
+	return list
+}
+
+func toBalanceLogList(logs []*model.BalanceLog, nextCursor string) *models.BalanceLogList {
+	list := &models.BalanceLogList{
+		Logs:       make([]*models.BalanceLog, 0, len(logs)),
+		NextCursor: nextCursor,
+	}
+	for _, l := range logs {
+		list.Logs = append(list.Logs, &models.BalanceLog{
+			ID:           int64(l.ID),
+			UserID:       int32(l.UserID),
+			Currency:     string(l.Currency),
+			BeforeAmount: formatAmount(l.BeforeAmount),
+			AfterAmount:  formatAmount(l.AfterAmount),
+			Delta:        formatAmount(l.Delta),
+			SourceType:   l.SourceType,
+			SourceID:     l.SourceID,
+			Reason:       l.Reason,
+			CreateTime:   strfmt.DateTime(l.CreateTime),
+		})
+	}
+	return list
+}
+
+// parseAmount parses an amount in the minor unit, which the API represents as a decimal string to keep 64-bit precision.
+func parseAmount(s string) (int64, error) {
+	amount, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: amount must be a 64-bit integer string: %q", domain.ErrInvalidParam, s)
+	}
+	return amount, nil
+}
+
+func formatAmount(amount int64) string {
+	return strconv.FormatInt(amount, 10)
+}
+
 func toErrorResponse(c int, m string) *models.ErrorResponse {
 	code := int32(c)
 	return &models.ErrorResponse{
@@ -107,11 +844,29 @@ func toErrorResponse(c int, m string) *models.ErrorResponse {
 	}
 }
 
+// unsupportedResponder answers 501 to the APIs of the feature which the backend does not support.
+func unsupportedResponder(feature, driver string) middleware.Responder {
+	payload := toErrorResponse(http.StatusNotImplemented, fmt.Sprintf("%s are not supported by the %s backend", feature, driver))
+	return middleware.ResponderFunc(func(rw http.ResponseWriter, p runtime.Producer) {
+		rw.WriteHeader(http.StatusNotImplemented)
+		if err := p.Produce(rw, payload); err != nil {
+			panic(err)
+		}
+	})
+}
+
 func errToCodeAndMessage(err error) (code int, message string) {
 	message = err.Error()
-	if errors.Is(err, domain.ErrDuplicateUUID) || errors.Is(err, domain.ErrInvalidUUID) || errors.Is(err, domain.ErrShortBalance) || errors.Is(err, domain.ErrInvalidParam) {
+	switch {
+	case errors.Is(err, domain.ErrNoSuchEntity):
+		code = 404
+	case errors.Is(err, domain.ErrIdempotencyKeyMismatch):
+		code = 409
+	case errors.Is(err, domain.ErrLimitExceeded):
+		code = 422
+	case errors.Is(err, domain.ErrDuplicateUUID) || errors.Is(err, domain.ErrInvalidUUID) || errors.Is(err, domain.ErrShortBalance) || errors.Is(err, domain.ErrInvalidParam) || errors.Is(err, domain.ErrExpiredTransaction) || errors.Is(err, domain.ErrTransactionMismatch) || errors.Is(err, domain.ErrIllegalTransition) || errors.Is(err, domain.ErrAmountOverflow) || errors.Is(err, domain.ErrCurrencyMismatch) || errors.Is(err, domain.ErrExpiredRate):
 		code = 400
-	} else {
+	default:
 		code = 500
 	}
 	return
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/loads"
+	"github.com/kawabatas/m-bank/gen/models"
+	"github.com/kawabatas/m-bank/gen/restapi"
+	"github.com/kawabatas/m-bank/gen/restapi/operations"
+	"github.com/kawabatas/m-bank/infra/sqlite"
+)
+
+func Test_setHandler_unsupported(t *testing.T) {
+	cfg := &config{TryTTL: time.Minute}
+	memoryApp, err := newMemoryApp(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "bank.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	swaggerSpec, err := loads.Analyzed(restapi.SwaggerJSON, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		body        string
+		wantCode    int
+		wantFeature string // 対応していない機能
+	}{
+		{"残高は取得できる", http.MethodGet, "/balances/1", "", http.StatusOK, ""},
+		{"送金は対応していない", http.MethodPost, "/transfers/try", `{"idempotency_key": "foo", "from_user_id": 1, "to_user_id": 2, "amount": "10"}`, http.StatusNotImplemented, "transfers"},
+		{"返金は対応していない", http.MethodGet, "/payments/foo/refunds", "", http.StatusNotImplemented, "refunds"},
+		{"Webhookは対応していない", http.MethodPost, "/webhooks", `{"url": "http://example.com", "event_types": ["payment.confirmed"], "user_ids": [1]}`, http.StatusNotImplemented, "webhooks"},
+	}
+	for _, app := range []*application{memoryApp, newSQLiteApp(db, cfg)} {
+		api := operations.NewBankAPI(swaggerSpec)
+		setHandler(api, app)
+		handler := api.Serve(nil)
+		for _, tt := range tests {
+			t.Run(app.Driver+"/"+tt.name, func(t *testing.T) {
+				req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+				req.Header.Set("Content-Type", "application/json")
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				if rec.Code != tt.wantCode {
+					t.Fatalf("%s %s code = %v, want %v: %s", tt.method, tt.path, rec.Code, tt.wantCode, rec.Body)
+				}
+				if tt.wantFeature == "" {
+					return
+				}
+				var res models.ErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+					t.Fatal(err)
+				}
+				if want := fmt.Sprintf("%s are not supported by the %s backend", tt.wantFeature, app.Driver); res.Message != want {
+					t.Errorf("%s %s message = %q, want %q", tt.method, tt.path, res.Message, want)
+				}
+			})
+		}
+	}
+}
+
+func Test_newServer_outboxPublisherUnsupported(t *testing.T) {
+	cfg := &config{DBDriver: driverMemory, TryTTL: time.Minute, OutboxPublisher: "stdout"}
+	app, err := newMemoryApp(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// イベントを記録しないDBでは、配信先を指定しても起動しない
+	if _, err := newServer(app, cfg); err == nil || !strings.Contains(err.Error(), "memory backend") {
+		t.Errorf("newServer() error = %v, want the unsupported OUTBOX_PUBLISHER", err)
+	}
+}
@@ -2,75 +2,305 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/kawabatas/m-bank/domain"
 	"github.com/kawabatas/m-bank/domain/model"
 	"github.com/kawabatas/m-bank/domain/repository"
 	"github.com/kawabatas/m-bank/infra/database"
+	"github.com/kawabatas/m-bank/infra/memory"
+	"github.com/kawabatas/m-bank/infra/postgres"
+	"github.com/kawabatas/m-bank/infra/sqlite"
+	"github.com/kawabatas/m-bank/infra/webhook"
 )
 
 type application struct {
-	BalanceService *balanceService
-	PaymentService *paymentService
+	Driver            string // DB_DRIVER。対応していない機能のエラーに使う
+	BalanceService    *balanceService
+	PaymentService    *paymentService
+	TransferService   *transferService
+	BulkCreditService *bulkCreditService
+	ExchangeService   *exchangeService
+	LimitService      *spendingLimitService
+	WebhookService    *webhookService
+	// 残高の変更と同じトランザクションでイベントを記録するDBでのみ設定される
+	OutboxRepo repository.OutboxRepository
 }
 
 // balanceService is a service to handle balances.
 type balanceService struct {
-	BalanceRepo repository.BalanceRepository
+	BalanceRepo    repository.BalanceRepository
+	BalanceLogRepo repository.BalanceLogRepository
+	PointLotRepo   repository.PointLotRepository
 }
 
 // paymentService is a service to handle payments.
 type paymentService struct {
 	BalanceRepo repository.BalanceRepository
 	PaymentRepo repository.PaymentTransactionRepository
+	RefundRepo  repository.RefundRepository
+	TryTTL      time.Duration // Tryの有効期限のデフォルト値
+	StrictMode  bool          // Confirm/Cancelのリクエスト内容を保存済みの取引と照合する
+}
+
+// transferService is a service to handle transfers between users.
+type transferService struct {
+	BalanceRepo  repository.BalanceRepository
+	TransferRepo repository.TransferRepository
+	TryTTL       time.Duration // Tryの有効期限のデフォルト値
+	StrictMode   bool          // Confirm/Cancelのリクエスト内容を保存済みの送金と照合する
+}
+
+// bulkCreditService is a service to credit users through bulk credit jobs.
+type bulkCreditService struct {
+	BalanceRepo repository.BalanceRepository
+	JobRepo     repository.BulkCreditJobRepository
+}
+
+// exchangeService is a service to exchange currencies between the wallets of a user.
+type exchangeService struct {
+	BalanceRepo  repository.BalanceRepository
+	RateRepo     repository.ExchangeRateRepository
+	ExchangeRepo repository.ExchangeRepository
+}
+
+// spendingLimitService is a service to configure the spending limits of users and tiers.
+type spendingLimitService struct {
+	LimitRepo repository.SpendingLimitRepository
+}
+
+// webhookService is a service to register webhooks and to look into their deliveries.
+type webhookService struct {
+	WebhookRepo repository.WebhookRepository
+	Resolver    webhook.Resolver
 }
 
 // newApp creates application services.
-func newApp(db *sql.DB) *application {
+func newApp(db *sql.DB, cfg *config) (*application, error) {
+	switch cfg.DBDriver {
+	case driverPostgres:
+		return newPostgresApp(db, cfg), nil
+	case driverMemory:
+		return newMemoryApp(cfg)
+	case driverSQLite:
+		return newSQLiteApp(db, cfg), nil
+	}
 	balanceRepository := database.NewBalanceRepository(db)
 	paymentRepository := database.NewPaymentTransactionRepository(db)
+	transferRepository := database.NewTransferRepository(db)
 
 	return &application{
+		Driver: driverMySQL,
 		BalanceService: &balanceService{
-			BalanceRepo: balanceRepository,
+			BalanceRepo:    balanceRepository,
+			BalanceLogRepo: database.NewBalanceLogRepository(db),
+			PointLotRepo:   database.NewPointLotRepository(db),
 		},
 		PaymentService: &paymentService{
 			BalanceRepo: balanceRepository,
 			PaymentRepo: paymentRepository,
+			RefundRepo:  database.NewRefundRepository(db),
+			TryTTL:      cfg.TryTTL,
+			StrictMode:  cfg.StrictMode,
+		},
+		TransferService: &transferService{
+			BalanceRepo:  balanceRepository,
+			TransferRepo: transferRepository,
+			TryTTL:       cfg.TryTTL,
+			StrictMode:   cfg.StrictMode,
+		},
+		BulkCreditService: &bulkCreditService{
+			BalanceRepo: balanceRepository,
+			JobRepo:     database.NewBulkCreditJobRepository(db),
+		},
+		ExchangeService: &exchangeService{
+			BalanceRepo:  balanceRepository,
+			RateRepo:     database.NewExchangeRateRepository(db),
+			ExchangeRepo: database.NewExchangeRepository(db),
+		},
+		LimitService: &spendingLimitService{
+			LimitRepo: database.NewSpendingLimitRepository(db),
+		},
+		WebhookService: &webhookService{
+			WebhookRepo: database.NewWebhookRepository(db),
+			Resolver:    net.DefaultResolver,
+		},
+		OutboxRepo: database.NewOutboxRepository(db),
+	}, nil
+}
+
+// newPostgresApp creates the application services on PostgreSQL. Only balances and payments without refunds are available,
+// so the other services are nil and their APIs answer that they are not supported. No outbox events are recorded.
+func newPostgresApp(db *sql.DB, cfg *config) *application {
+	balanceRepository := postgres.NewBalanceRepository(db)
+	return &application{
+		Driver: driverPostgres,
+		BalanceService: &balanceService{
+			BalanceRepo:    balanceRepository,
+			BalanceLogRepo: postgres.NewBalanceLogRepository(db),
+			PointLotRepo:   postgres.NewPointLotRepository(db),
+		},
+		PaymentService: &paymentService{
+			BalanceRepo: balanceRepository,
+			PaymentRepo: postgres.NewPaymentTransactionRepository(db),
+			TryTTL:      cfg.TryTTL,
+			StrictMode:  cfg.StrictMode,
 		},
 	}
 }
 
-func (s *balanceService) Get(ctx context.Context, userID uint) (*model.Balance, error) {
-	return s.BalanceRepo.Get(ctx, userID)
+// newSQLiteApp creates the application services on SQLite. The same services as on PostgreSQL are available,
+// in the same transactions as on MySQL; the others answer that they are not supported.
+func newSQLiteApp(db *sql.DB, cfg *config) *application {
+	balanceRepository := sqlite.NewBalanceRepository(db)
+	return &application{
+		Driver: driverSQLite,
+		BalanceService: &balanceService{
+			BalanceRepo:    balanceRepository,
+			BalanceLogRepo: sqlite.NewBalanceLogRepository(db),
+			PointLotRepo:   sqlite.NewPointLotRepository(db),
+		},
+		PaymentService: &paymentService{
+			BalanceRepo: balanceRepository,
+			PaymentRepo: sqlite.NewPaymentTransactionRepository(db),
+			TryTTL:      cfg.TryTTL,
+			StrictMode:  cfg.StrictMode,
+		},
+	}
 }
 
-func (s *paymentService) Try(ctx context.Context, uuid string, userID uint, amount int) (*model.PaymentTransaction, *model.Balance, error) {
-	// 残高が足りるかチェック
-	ok, err := s.isEnoughBalance(ctx, userID, amount)
+// newMemoryApp creates the application services on the memory store, which is lost on shutdown.
+// The store has the same sample users as the migration. The same services as on PostgreSQL are available,
+// except that spending limits are not checked.
+func newMemoryApp(cfg *config) (*application, error) {
+	store := memory.NewStore()
+	for _, u := range []struct {
+		id     uint
+		name   string
+		amount int64
+	}{{1, "user1", 100}, {2, "user2", 200}} {
+		if err := store.AddUser(&model.User{ID: u.id, Name: u.name}, domain.NewMoney(u.amount, domain.JPY)); err != nil {
+			return nil, err
+		}
+	}
+	balanceRepository := memory.NewBalanceRepository(store)
+	return &application{
+		Driver: driverMemory,
+		BalanceService: &balanceService{
+			BalanceRepo:    balanceRepository,
+			BalanceLogRepo: memory.NewBalanceLogRepository(store),
+			PointLotRepo:   memory.NewPointLotRepository(store),
+		},
+		PaymentService: &paymentService{
+			BalanceRepo: balanceRepository,
+			PaymentRepo: memory.NewPaymentTransactionRepository(store),
+			TryTTL:      cfg.TryTTL,
+			StrictMode:  cfg.StrictMode,
+		},
+	}, nil
+}
+
+// ポイントのウォレットに含める、失効予定の最大件数
+const maxPointExpirations = 10
+
+// List returns all the wallets of the user. The point wallet has its upcoming expirations.
+func (s *balanceService) List(ctx context.Context, userID uint) ([]*model.Balance, error) {
+	balances, err := s.BalanceRepo.List(ctx, userID)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	if !ok {
-		return nil, nil, domain.ErrShortBalance
+	for _, b := range balances {
+		if b.Currency != domain.Point {
+			continue
+		}
+		if b.Expirations, err = s.PointLotRepo.ListExpirations(ctx, userID, time.Now(), maxPointExpirations); err != nil {
+			return nil, err
+		}
 	}
+	return balances, nil
+}
 
-	pt, err := s.PaymentRepo.Try(ctx, uuid, userID, amount)
+// SetOverdraftLimit approves the credit line of the user's wallet in the currency. Points have no credit line.
+func (s *balanceService) SetOverdraftLimit(ctx context.Context, userID uint, currencyCode string, limit int64) (*model.Balance, error) {
+	currency, err := domain.LookupCurrency(currencyCode)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+	if currency == domain.Point {
+		return nil, fmt.Errorf("%w: points cannot be overdrawn", domain.ErrInvalidParam)
 	}
+	if limit < 0 {
+		return nil, fmt.Errorf("%w: overdraft limit must not be negative", domain.ErrInvalidParam)
+	}
+	return s.BalanceRepo.SetOverdraftLimit(ctx, userID, currency, limit)
+}
 
-	balance, err := s.BalanceRepo.Get(ctx, userID)
+// ListLogs returns the balance logs of the user from the newest one and the cursor of the next page.
+// The logs of all the wallets are returned when currency is empty.
+func (s *balanceService) ListLogs(ctx context.Context, userID uint, currency string, from, to time.Time, cursor string, limit int) ([]*model.BalanceLog, string, error) {
+	limit, err := pageSize(limit)
 	if err != nil {
-		return nil, nil, err
+		return nil, "", err
 	}
-	return pt, balance, nil
+	filter := model.BalanceLogFilter{From: from, To: to, Limit: limit + 1}
+	if currency != "" {
+		if filter.Currency, err = domain.LookupCurrency(currency); err != nil {
+			return nil, "", err
+		}
+	}
+	if cursor != "" {
+		key, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		if filter.BeforeID, err = strconv.ParseUint(key, 10, 64); err != nil {
+			return nil, "", domain.ErrInvalidParam
+		}
+	}
+
+	logs, err := s.BalanceLogRepo.List(ctx, userID, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	// 1件多く取得して、次のページがあるかどうかを判定する
+	if len(logs) <= limit {
+		return logs, "", nil
+	}
+	logs = logs[:limit]
+	return logs, encodeCursor(strconv.FormatUint(logs[limit-1].ID, 10)), nil
 }
 
-func (s *paymentService) Confirm(ctx context.Context, uuid string, userID uint, amount int) (*model.PaymentTransaction, *model.Balance, error) {
-	// 残高が足りるかチェック
-	ok, err := s.isEnoughBalance(ctx, userID, amount)
+func (s *paymentService) Try(ctx context.Context, uuid string, userID uint, currencyCode string, amount int64, expiresIn time.Duration) (*model.PaymentTransaction, *model.Balance, error) {
+	if err := validatePaymentKey(uuid, userID); err != nil {
+		return nil, nil, err
+	}
+	if expiresIn < 0 {
+		return nil, nil, fmt.Errorf("%w: expires_in must not be negative", domain.ErrInvalidParam)
+	}
+	currency, err := parseCurrency(currencyCode)
+	if err != nil {
+		return nil, nil, err
+	}
+	// 同じ冪等キーでの再試行であれば、保存済みの結果を返す
+	pt, err := s.PaymentRepo.Get(ctx, uuid)
+	if err == nil {
+		return s.replay(ctx, pt, userID, currency, amount)
+	}
+	if !errors.Is(err, domain.ErrInvalidUUID) {
+		return nil, nil, err
+	}
+
+	// 残高が足りるかチェック(仮押さえ時にもトランザクション内でチェックされる)
+	ok, err := s.isEnoughBalance(ctx, userID, currency, amount)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -78,66 +308,723 @@ func (s *paymentService) Confirm(ctx context.Context, uuid string, userID uint,
 		return nil, nil, domain.ErrShortBalance
 	}
 
+	if expiresIn <= 0 {
+		expiresIn = s.TryTTL
+	}
+	pt, err = s.PaymentRepo.Try(ctx, uuid, userID, currency, amount, expiresIn)
+	if errors.Is(err, domain.ErrDuplicateUUID) {
+		// 同時に届いた再試行に先を越された場合
+		if pt, err = s.PaymentRepo.Get(ctx, uuid); err != nil {
+			return nil, nil, err
+		}
+		return s.replay(ctx, pt, userID, currency, amount)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.withResultBalance(ctx, pt)
+}
+
+func (s *paymentService) Confirm(ctx context.Context, uuid string, userID uint, currencyCode string, amount int64) (*model.PaymentTransaction, *model.Balance, error) {
+	if err := validatePaymentKey(uuid, userID); err != nil {
+		return nil, nil, err
+	}
+	currency, err := parseCurrency(currencyCode)
+	if err != nil {
+		return nil, nil, err
+	}
 	pt, err := s.PaymentRepo.Get(ctx, uuid)
 	if err != nil {
 		return nil, nil, err
 	}
-	if !pt.IsTryStatus() {
-		return nil, nil, domain.ErrInvalidUUID
+	if pt.IsConfirmStatus() {
+		return s.replay(ctx, pt, userID, currency, amount)
 	}
+	if pt.IsExpired(time.Now()) {
+		return nil, nil, domain.ErrExpiredTransaction
+	}
+	if !pt.Status.CanTransitionTo(model.PaymentStatusConfirmed) {
+		return nil, nil, domain.ErrIllegalTransition
+	}
+	if err := s.validateRequest(pt, userID, currency, amount); err != nil {
+		return nil, nil, err
+	}
+	// 確定する金額はリクエストではなく保存済みのpt.Amount。
+	// 減算分はTryで仮押さえ済みのため、残高のチェックは不要
 	pt, err = s.PaymentRepo.Confirm(ctx, uuid)
 	if err != nil {
 		return nil, nil, err
 	}
+	return s.withResultBalance(ctx, pt)
+}
 
-	balance, err := s.BalanceRepo.Get(ctx, userID)
+func (s *paymentService) Cancel(ctx context.Context, uuid string, userID uint, currencyCode string, amount int64) (*model.PaymentTransaction, *model.Balance, error) {
+	if err := validatePaymentKey(uuid, userID); err != nil {
+		return nil, nil, err
+	}
+	currency, err := parseCurrency(currencyCode)
 	if err != nil {
 		return nil, nil, err
 	}
-	return pt, balance, nil
-}
-
-func (s *paymentService) Cancel(ctx context.Context, uuid string, userID uint, amount int) (*model.PaymentTransaction, *model.Balance, error) {
 	pt, err := s.PaymentRepo.Get(ctx, uuid)
 	if err != nil {
 		return nil, nil, err
 	}
-	if !pt.IsTryStatus() {
-		return nil, nil, domain.ErrInvalidUUID
+	if pt.IsCancelStatus() {
+		return s.replay(ctx, pt, userID, currency, amount)
+	}
+	if !pt.Status.CanTransitionTo(model.PaymentStatusCancelled) {
+		return nil, nil, domain.ErrIllegalTransition
+	}
+	if err := s.validateRequest(pt, userID, currency, amount); err != nil {
+		return nil, nil, err
 	}
 	pt, err = s.PaymentRepo.Cancel(ctx, uuid)
 	if err != nil {
 		return nil, nil, err
 	}
+	return s.withResultBalance(ctx, pt)
+}
 
-	balance, err := s.BalanceRepo.Get(ctx, userID)
+// Refund credits the amount of a confirmed debit back to the user. A payment can be refunded in several parts
+// as long as the total does not exceed the debit. Each refund is idempotent on its own key.
+func (s *paymentService) Refund(ctx context.Context, uuid, paymentUUID string, amount int64) (*model.Refund, *model.PaymentTransaction, *model.Balance, error) {
+	// 同じ冪等キーでの再試行であれば、保存済みの結果を返す
+	refund, err := s.RefundRepo.Get(ctx, uuid)
+	if err == nil {
+		return s.replayRefund(ctx, refund, paymentUUID, amount)
+	}
+	if !errors.Is(err, domain.ErrInvalidUUID) {
+		return nil, nil, nil, err
+	}
+	if amount <= 0 {
+		return nil, nil, nil, fmt.Errorf("%w: refund amount must be positive", domain.ErrInvalidParam)
+	}
+
+	refund, pt, err := s.RefundRepo.Refund(ctx, uuid, paymentUUID, amount, time.Now())
+	// 同時に同じ冪等キーで返金された場合も、保存済みの結果を返す
+	if errors.Is(err, domain.ErrDuplicateUUID) {
+		if refund, err = s.RefundRepo.Get(ctx, uuid); err != nil {
+			return nil, nil, nil, err
+		}
+		return s.replayRefund(ctx, refund, paymentUUID, amount)
+	}
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
-	return pt, balance, nil
+	balance, err := s.BalanceRepo.Get(ctx, pt.UserID, pt.Currency)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return refund, pt, balance, nil
+}
+
+// ListRefunds returns the refunds of the payment from the oldest one.
+func (s *paymentService) ListRefunds(ctx context.Context, paymentUUID string) ([]*model.Refund, error) {
+	if _, err := s.PaymentRepo.Get(ctx, paymentUUID); err != nil {
+		if errors.Is(err, domain.ErrInvalidUUID) {
+			return nil, domain.ErrNoSuchEntity
+		}
+		return nil, err
+	}
+	return s.RefundRepo.ListByPayment(ctx, paymentUUID)
+}
+
+// 処理済みの返金に対する再試行の結果を、現在の支払いと残高とともに返す
+func (s *paymentService) replayRefund(ctx context.Context, refund *model.Refund, paymentUUID string, amount int64) (*model.Refund, *model.PaymentTransaction, *model.Balance, error) {
+	if !refund.MatchesRequest(paymentUUID, amount) {
+		return nil, nil, nil, domain.ErrIdempotencyKeyMismatch
+	}
+	pt, err := s.PaymentRepo.Get(ctx, refund.PaymentUUID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	balance, err := s.BalanceRepo.Get(ctx, refund.UserID, refund.Currency)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return refund, pt, balance, nil
 }
 
-func (s *paymentService) AddToUsers(ctx context.Context, amount, limit, offset int) error {
+// AddToUsers credits the users in the range of limit and offset.
+// The points credited expire after expiresInDays days unless it is zero.
+func (s *paymentService) AddToUsers(ctx context.Context, currencyCode string, amount int64, expiresInDays, limit, offset int) error {
+	currency, err := parseCurrency(currencyCode)
+	if err != nil {
+		return err
+	}
 	if amount <= 0 {
 		return domain.ErrInvalidParam
 	}
-	return s.BalanceRepo.AddToUsers(ctx, amount, limit, offset)
+	if limit < 0 || offset < 0 {
+		return fmt.Errorf("%w: limit and offset must not be negative", domain.ErrInvalidParam)
+	}
+	var pointExpireTime time.Time
+	if expiresInDays != 0 {
+		// 有効期限があるのはポイントだけ
+		if currency != domain.Point || expiresInDays < 0 {
+			return fmt.Errorf("%w: only points can expire after positive days", domain.ErrInvalidParam)
+		}
+		pointExpireTime = time.Now().AddDate(0, 0, expiresInDays)
+	}
+	return s.BalanceRepo.AddToUsers(ctx, currency, amount, pointExpireTime, limit, offset)
+}
+
+// List returns the payments of the user from the newest try and the cursor of the next page.
+func (s *paymentService) List(ctx context.Context, userID uint, status, sign string, from, to time.Time, cursor string, limit int) ([]*model.PaymentTransaction, string, error) {
+	limit, err := pageSize(limit)
+	if err != nil {
+		return nil, "", err
+	}
+	filter := model.PaymentTransactionFilter{
+		Status: model.PaymentStatus(status),
+		Sign:   model.AmountSign(sign),
+		From:   from,
+		To:     to,
+		Limit:  limit + 1,
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, "", domain.ErrInvalidParam
+	}
+	if filter.Sign != "" && !filter.Sign.IsValid() {
+		return nil, "", domain.ErrInvalidParam
+	}
+	if cursor != "" {
+		key, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		if filter.BeforeTryTime, filter.BeforeUUID, err = parsePaymentCursorKey(key); err != nil {
+			return nil, "", err
+		}
+	}
+
+	pts, err := s.PaymentRepo.List(ctx, userID, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	// 1件多く取得して、次のページがあるかどうかを判定する
+	if len(pts) <= limit {
+		return pts, "", nil
+	}
+	pts = pts[:limit]
+	return pts, encodeCursor(paymentCursorKey(pts[limit-1])), nil
+}
+
+// 支払いのカーソルのキーは「Tryの時刻(UnixNano),UUID」
+func paymentCursorKey(pt *model.PaymentTransaction) string {
+	return strconv.FormatInt(pt.TryTime.UnixNano(), 10) + "," + pt.UUID
+}
+
+func parsePaymentCursorKey(key string) (time.Time, string, error) {
+	parts := strings.SplitN(key, ",", 2)
+	if len(parts) != 2 {
+		return time.Time{}, "", domain.ErrInvalidParam
+	}
+	nsec, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, "", domain.ErrInvalidParam
+	}
+	return time.Unix(0, nsec), parts[1], nil
+}
+
+// 処理済みの取引に対する再試行の結果を、元の応答と同じ残高とともに返す。
+// 冪等キーが同じでも、リクエストの内容が異なる場合はエラーにする
+func (s *paymentService) replay(ctx context.Context, pt *model.PaymentTransaction, userID uint, currency domain.Currency, amount int64) (*model.PaymentTransaction, *model.Balance, error) {
+	if !pt.MatchesRequest(userID, currency, amount) {
+		return nil, nil, domain.ErrIdempotencyKeyMismatch
+	}
+	return s.withResultBalance(ctx, pt)
+}
+
+// 取引と同じトランザクションで保存した直後の残高を返す。保存されていない古い取引では現在の残高を返す
+func (s *paymentService) withResultBalance(ctx context.Context, pt *model.PaymentTransaction) (*model.PaymentTransaction, *model.Balance, error) {
+	if pt.ResultBalance != nil {
+		return pt, pt.ResultBalance, nil
+	}
+	balance, err := s.BalanceRepo.Get(ctx, pt.UserID, pt.Currency)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pt, balance, nil
+}
+
+// validatePaymentKey checks the required fields, which the gRPC API passes as zero values when they are omitted.
+func validatePaymentKey(uuid string, userID uint) error {
+	if uuid == "" {
+		return fmt.Errorf("%w: idempotency_key is required", domain.ErrInvalidParam)
+	}
+	if userID == 0 {
+		return fmt.Errorf("%w: user_id is required", domain.ErrInvalidParam)
+	}
+	return nil
+}
+
+// StrictModeの場合、Confirm/Cancelのリクエスト内容がTry時と同じかどうかを検証する
+func (s *paymentService) validateRequest(pt *model.PaymentTransaction, userID uint, currency domain.Currency, amount int64) error {
+	if !s.StrictMode {
+		return nil
+	}
+	if pt.UserID != userID || pt.Currency != currency || pt.Amount != amount {
+		return domain.ErrTransactionMismatch
+	}
+	return nil
 }
 
 // 残高が十分かどうか
-func (s *paymentService) isEnoughBalance(ctx context.Context, userID uint, amount int) (bool, error) {
+func (s *paymentService) isEnoughBalance(ctx context.Context, userID uint, currency domain.Currency, amount int64) (bool, error) {
 	// 加算の時は考慮しない
 	if amount >= 0 {
 		return true, nil
 	}
 
-	balance, err := s.BalanceRepo.Get(ctx, userID)
+	balance, err := s.BalanceRepo.Get(ctx, userID, currency)
 	if err != nil {
 		return false, err
 	}
-	// 減算後の値が正かどうか
-	if int(balance.Amount)+amount >= 0 {
+	// 与信枠を含めて仮押さえ分を除いた残高から減算した値が正かどうか
+	if balance.AvailableAmount()+amount >= 0 {
 		return true, nil
 	}
 	return false, nil
 }
+
+func (s *transferService) Try(ctx context.Context, uuid string, fromUserID, toUserID uint, currencyCode string, amount int64, expiresIn time.Duration) (*model.Transfer, *model.Balance, *model.Balance, error) {
+	currency, err := parseCurrency(currencyCode)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// 同じ冪等キーでの再試行であれば、保存済みの結果を返す
+	t, err := s.TransferRepo.Get(ctx, uuid)
+	if err == nil {
+		return s.replay(ctx, t, fromUserID, toUserID, currency, amount)
+	}
+	if !errors.Is(err, domain.ErrInvalidUUID) {
+		return nil, nil, nil, err
+	}
+
+	if fromUserID == toUserID || amount <= 0 {
+		return nil, nil, nil, domain.ErrInvalidParam
+	}
+	// 残高が足りるかチェック(仮押さえ時にもトランザクション内でチェックされる)
+	from, err := s.BalanceRepo.Get(ctx, fromUserID, currency)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if from.AvailableAmount() < amount {
+		return nil, nil, nil, domain.ErrShortBalance
+	}
+
+	if expiresIn <= 0 {
+		expiresIn = s.TryTTL
+	}
+	t, err = s.TransferRepo.Try(ctx, uuid, fromUserID, toUserID, currency, amount, expiresIn)
+	if errors.Is(err, domain.ErrDuplicateUUID) {
+		// 同時に届いた再試行に先を越された場合
+		if t, err = s.TransferRepo.Get(ctx, uuid); err != nil {
+			return nil, nil, nil, err
+		}
+		return s.replay(ctx, t, fromUserID, toUserID, currency, amount)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return s.withBalances(ctx, t)
+}
+
+func (s *transferService) Confirm(ctx context.Context, uuid string, fromUserID, toUserID uint, currencyCode string, amount int64) (*model.Transfer, *model.Balance, *model.Balance, error) {
+	currency, err := parseCurrency(currencyCode)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	t, err := s.TransferRepo.Get(ctx, uuid)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if t.IsConfirmStatus() {
+		return s.replay(ctx, t, fromUserID, toUserID, currency, amount)
+	}
+	if t.IsExpired(time.Now()) {
+		return nil, nil, nil, domain.ErrExpiredTransaction
+	}
+	if !t.Status.CanTransitionTo(model.PaymentStatusConfirmed) {
+		return nil, nil, nil, domain.ErrIllegalTransition
+	}
+	if err := s.validateRequest(t, fromUserID, toUserID, currency, amount); err != nil {
+		return nil, nil, nil, err
+	}
+	// 送金額はTryで仮押さえ済みのため、残高のチェックは不要
+	t, err = s.TransferRepo.Confirm(ctx, uuid)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return s.withBalances(ctx, t)
+}
+
+func (s *transferService) Cancel(ctx context.Context, uuid string, fromUserID, toUserID uint, currencyCode string, amount int64) (*model.Transfer, *model.Balance, *model.Balance, error) {
+	currency, err := parseCurrency(currencyCode)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	t, err := s.TransferRepo.Get(ctx, uuid)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if t.IsCancelStatus() {
+		return s.replay(ctx, t, fromUserID, toUserID, currency, amount)
+	}
+	if !t.Status.CanTransitionTo(model.PaymentStatusCancelled) {
+		return nil, nil, nil, domain.ErrIllegalTransition
+	}
+	if err := s.validateRequest(t, fromUserID, toUserID, currency, amount); err != nil {
+		return nil, nil, nil, err
+	}
+	t, err = s.TransferRepo.Cancel(ctx, uuid)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return s.withBalances(ctx, t)
+}
+
+// 処理済みの送金に対する再試行の結果を返す
+func (s *transferService) replay(ctx context.Context, t *model.Transfer, fromUserID, toUserID uint, currency domain.Currency, amount int64) (*model.Transfer, *model.Balance, *model.Balance, error) {
+	if !t.MatchesRequest(fromUserID, toUserID, currency, amount) {
+		return nil, nil, nil, domain.ErrIdempotencyKeyMismatch
+	}
+	return s.withBalances(ctx, t)
+}
+
+// StrictModeの場合、Confirm/Cancelのリクエスト内容がTry時と同じかどうかを検証する
+func (s *transferService) validateRequest(t *model.Transfer, fromUserID, toUserID uint, currency domain.Currency, amount int64) error {
+	if !s.StrictMode {
+		return nil
+	}
+	if t.FromUserID != fromUserID || t.ToUserID != toUserID || t.Currency != currency || t.Amount != amount {
+		return domain.ErrTransactionMismatch
+	}
+	return nil
+}
+
+// 送金と同じトランザクションで保存した直後の送金元と送金先のウォレットを返す。保存されていない古い送金では現在のウォレットを返す
+func (s *transferService) withBalances(ctx context.Context, t *model.Transfer) (*model.Transfer, *model.Balance, *model.Balance, error) {
+	if t.FromResultBalance != nil && t.ToResultBalance != nil {
+		return t, t.FromResultBalance, t.ToResultBalance, nil
+	}
+	from, err := s.BalanceRepo.Get(ctx, t.FromUserID, t.Currency)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	to, err := s.BalanceRepo.Get(ctx, t.ToUserID, t.Currency)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return t, from, to, nil
+}
+
+// Create creates a bulk credit job for the target users. A retry with the same idempotency key returns the job created first.
+// When dryRun is true, it only returns the job which would be created, with the number of the target users.
+func (s *bulkCreditService) Create(ctx context.Context, idempotencyKey, currencyCode string, amount int64, target model.BulkCreditTarget, dryRun bool) (*model.BulkCreditJob, []*model.BulkCreditItem, error) {
+	currency, err := parseCurrency(currencyCode)
+	if err != nil {
+		return nil, nil, err
+	}
+	if amount <= 0 {
+		return nil, nil, domain.ErrInvalidParam
+	}
+	if err := target.Normalize(); err != nil {
+		return nil, nil, err
+	}
+	if dryRun {
+		count, err := s.BalanceRepo.CountTargets(ctx, currency, target)
+		if err != nil {
+			return nil, nil, err
+		}
+		job := model.NewBulkCreditJob(idempotencyKey, currency, amount, target, count)
+		if _, err := job.TotalAmount(); err != nil {
+			return nil, nil, err
+		}
+		// 作成していないジョブにはステータスがない
+		job.Status = ""
+		return job, nil, nil
+	}
+
+	job, err := s.JobRepo.Create(ctx, idempotencyKey, currency, amount, target)
+	if errors.Is(err, domain.ErrDuplicateUUID) {
+		if job, err = s.JobRepo.GetByIdempotencyKey(ctx, idempotencyKey); err != nil {
+			return nil, nil, err
+		}
+		if !job.MatchesRequest(currency, amount, target) {
+			return nil, nil, domain.ErrIdempotencyKeyMismatch
+		}
+		return s.withItems(ctx, job)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return job, nil, nil
+}
+
+// Get returns the job and the users who have failed to be credited or whose reversal is skipped.
+func (s *bulkCreditService) Get(ctx context.Context, id uint64) (*model.BulkCreditJob, []*model.BulkCreditItem, error) {
+	job, err := s.JobRepo.Get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.withItems(ctx, job)
+}
+
+// Reverse requests to debit what the completed job credited to each user. The reversal is processed by the worker.
+// A retry with the same mode returns the job as it is.
+func (s *bulkCreditService) Reverse(ctx context.Context, id uint64, mode string) (*model.BulkCreditJob, []*model.BulkCreditItem, error) {
+	reversalMode := model.BulkCreditReversalMode(mode)
+	if !reversalMode.IsValid() {
+		return nil, nil, fmt.Errorf("%w: unknown reversal mode %q", domain.ErrInvalidParam, mode)
+	}
+	job, err := s.JobRepo.RequestReversal(ctx, id, reversalMode)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.withItems(ctx, job)
+}
+
+// レスポンスに含める、加算に失敗したユーザと取り消しをスキップしたユーザのそれぞれの最大件数
+const maxBulkCreditFailures = 100
+
+// withItems returns the job with the failed items and the items whose reversal is skipped.
+func (s *bulkCreditService) withItems(ctx context.Context, job *model.BulkCreditJob) (*model.BulkCreditJob, []*model.BulkCreditItem, error) {
+	var items []*model.BulkCreditItem
+	if job.FailedCount > 0 {
+		failures, err := s.JobRepo.ListFailedItems(ctx, job.ID, maxBulkCreditFailures)
+		if err != nil {
+			return nil, nil, err
+		}
+		items = append(items, failures...)
+	}
+	if job.ReversalSkippedCount > 0 {
+		skips, err := s.JobRepo.ListSkippedReversalItems(ctx, job.ID, maxBulkCreditFailures)
+		if err != nil {
+			return nil, nil, err
+		}
+		items = append(items, skips...)
+	}
+	return job, items, nil
+}
+
+// Exchange debits the amount from the user's wallet of fromCurrency and credits the amount converted at the quoted rate
+// to the wallet of toCurrency. A retry with the same uuid returns the exchange made first, even after the rate has expired.
+func (s *exchangeService) Exchange(ctx context.Context, uuid string, userID uint, rateID uint64, fromCurrencyCode, toCurrencyCode string, amount int64) (*model.Exchange, *model.Balance, *model.Balance, error) {
+	fromCurrency, err := domain.LookupCurrency(fromCurrencyCode)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	toCurrency, err := domain.LookupCurrency(toCurrencyCode)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// 同じ冪等キーでの再試行であれば、保存済みの結果を返す
+	e, err := s.ExchangeRepo.Get(ctx, uuid)
+	if err == nil {
+		return s.replay(ctx, e, userID, rateID, fromCurrency, toCurrency, amount)
+	}
+	if !errors.Is(err, domain.ErrInvalidUUID) {
+		return nil, nil, nil, err
+	}
+
+	if amount <= 0 {
+		return nil, nil, nil, domain.ErrInvalidParam
+	}
+	// 見積もった時とは異なる通貨のレートで両替しない
+	rate, err := s.RateRepo.Get(ctx, rateID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if rate.FromCurrency != fromCurrency || rate.ToCurrency != toCurrency {
+		return nil, nil, nil, fmt.Errorf("%w: rate %d is from %s to %s", domain.ErrCurrencyMismatch, rate.ID, rate.FromCurrency, rate.ToCurrency)
+	}
+
+	e, err = s.ExchangeRepo.Exchange(ctx, uuid, userID, rateID, amount, time.Now())
+	if errors.Is(err, domain.ErrDuplicateUUID) {
+		// 同時に届いた再試行に先を越された場合
+		if e, err = s.ExchangeRepo.Get(ctx, uuid); err != nil {
+			return nil, nil, nil, err
+		}
+		return s.replay(ctx, e, userID, rateID, fromCurrency, toCurrency, amount)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return s.withBalances(ctx, e)
+}
+
+// UploadRates registers the rates at once. The rates without a rounding mode are rounded down.
+func (s *exchangeService) UploadRates(ctx context.Context, rates []*model.ExchangeRate) ([]*model.ExchangeRate, error) {
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("%w: no rates", domain.ErrInvalidParam)
+	}
+	for i, rate := range rates {
+		if rate.Rounding == "" {
+			rate.Rounding = domain.RoundDown
+		}
+		if err := rate.Validate(); err != nil {
+			return nil, fmt.Errorf("rates[%d]: %w", i, err)
+		}
+	}
+	return s.RateRepo.Create(ctx, rates)
+}
+
+// ListRates returns the rates from one currency to another which are valid now, from the newest one.
+func (s *exchangeService) ListRates(ctx context.Context, fromCurrencyCode, toCurrencyCode string) ([]*model.ExchangeRate, error) {
+	fromCurrency, err := domain.LookupCurrency(fromCurrencyCode)
+	if err != nil {
+		return nil, err
+	}
+	toCurrency, err := domain.LookupCurrency(toCurrencyCode)
+	if err != nil {
+		return nil, err
+	}
+	return s.RateRepo.ListValid(ctx, fromCurrency, toCurrency, time.Now())
+}
+
+// 処理済みの両替に対する再試行の結果を返す。
+// 冪等キーが同じでも、リクエストの内容が異なる場合はエラーにする
+func (s *exchangeService) replay(ctx context.Context, e *model.Exchange, userID uint, rateID uint64, fromCurrency, toCurrency domain.Currency, amount int64) (*model.Exchange, *model.Balance, *model.Balance, error) {
+	if !e.MatchesRequest(userID, rateID, fromCurrency, toCurrency, amount) {
+		return nil, nil, nil, domain.ErrIdempotencyKeyMismatch
+	}
+	return s.withBalances(ctx, e)
+}
+
+// withBalances returns the exchange with the current wallets of both currencies.
+func (s *exchangeService) withBalances(ctx context.Context, e *model.Exchange) (*model.Exchange, *model.Balance, *model.Balance, error) {
+	from, err := s.BalanceRepo.Get(ctx, e.UserID, e.FromCurrency)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	to, err := s.BalanceRepo.Get(ctx, e.UserID, e.ToCurrency)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return e, from, to, nil
+}
+
+// 通貨の指定がないリクエストは、複数通貨に対応する前と同じくデフォルトの通貨として扱う
+func parseCurrency(code string) (domain.Currency, error) {
+	if code == "" {
+		return domain.DefaultCurrency, nil
+	}
+	return domain.LookupCurrency(code)
+}
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// 一覧の取得件数。0の場合はデフォルト値
+func pageSize(limit int) (int, error) {
+	if limit == 0 {
+		return defaultPageSize, nil
+	}
+	if limit < 0 || limit > maxPageSize {
+		return 0, domain.ErrInvalidParam
+	}
+	return limit, nil
+}
+
+// ページングのカーソルは、最後の要素のキーを呼び出し元に意識させない文字列にする
+func encodeCursor(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key))
+}
+
+func decodeCursor(cursor string) (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", domain.ErrInvalidParam
+	}
+	return string(b), nil
+}
+
+// SetLimit creates or replaces the limit of the scope in the currency.
+func (s *spendingLimitService) SetLimit(ctx context.Context, limit *model.SpendingLimit) (*model.SpendingLimit, error) {
+	if err := limit.Validate(); err != nil {
+		return nil, err
+	}
+	return s.LimitRepo.Set(ctx, limit)
+}
+
+// ListLimits returns the limits of the scope in all currencies.
+func (s *spendingLimitService) ListLimits(ctx context.Context, scope, scopeID string) ([]*model.SpendingLimit, error) {
+	if scopeID == "" {
+		return nil, fmt.Errorf("%w: scope_id is required", domain.ErrInvalidParam)
+	}
+	switch model.SpendingLimitScope(scope) {
+	case model.SpendingLimitScopeUser, model.SpendingLimitScopeTier:
+	default:
+		return nil, fmt.Errorf("%w: unknown scope %q", domain.ErrInvalidParam, scope)
+	}
+	return s.LimitRepo.List(ctx, model.SpendingLimitScope(scope), scopeID)
+}
+
+// SetUserTier moves the user to the tier, whose limits apply in the currencies without the user's own limit.
+func (s *spendingLimitService) SetUserTier(ctx context.Context, userID uint, tier string) (*model.User, error) {
+	if tier == "" {
+		return nil, fmt.Errorf("%w: tier is required", domain.ErrInvalidParam)
+	}
+	return s.LimitRepo.SetUserTier(ctx, userID, tier)
+}
+
+// Webhookの署名の鍵のバイト数
+const webhookSecretBytes = 32
+
+// Subscribe registers the webhook to the event types about the users with a new secret, which is returned only here.
+func (s *webhookService) Subscribe(ctx context.Context, url string, eventTypes []string, userIDs []uint) (*model.WebhookSubscription, error) {
+	types := make([]model.OutboxEventType, 0, len(eventTypes))
+	for _, t := range eventTypes {
+		types = append(types, model.OutboxEventType(t))
+	}
+	b := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	subscription, err := model.NewWebhookSubscription(url, types, userIDs, hex.EncodeToString(b), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	// 内部のネットワークに送信させないよう、公開されたアドレスのホストだけを登録する
+	if err := webhook.CheckURL(ctx, s.Resolver, url); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidParam, err)
+	}
+	return s.WebhookRepo.Create(ctx, subscription)
+}
+
+// ListDeliveries returns the deliveries to the webhook from the newest one and the cursor of the next page.
+func (s *webhookService) ListDeliveries(ctx context.Context, id uint64, cursor string, limit int) ([]*model.WebhookDelivery, string, error) {
+	limit, err := pageSize(limit)
+	if err != nil {
+		return nil, "", err
+	}
+	var beforeID uint64
+	if cursor != "" {
+		key, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		if beforeID, err = strconv.ParseUint(key, 10, 64); err != nil {
+			return nil, "", domain.ErrInvalidParam
+		}
+	}
+	if _, err := s.WebhookRepo.Get(ctx, id); err != nil {
+		return nil, "", err
+	}
+
+	deliveries, err := s.WebhookRepo.ListDeliveries(ctx, id, beforeID, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+	// 1件多く取得して、次のページがあるかどうかを判定する
+	if len(deliveries) <= limit {
+		return deliveries, "", nil
+	}
+	deliveries = deliveries[:limit]
+	return deliveries, encodeCursor(strconv.FormatUint(deliveries[limit-1].ID, 10)), nil
+}
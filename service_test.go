@@ -2,10 +2,16 @@ package main
 
 import (
 	"context"
+	"errors"
+	"math"
+	"net"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/kawabatas/m-bank/domain"
 	"github.com/kawabatas/m-bank/domain/mock"
 	"github.com/kawabatas/m-bank/domain/model"
@@ -17,26 +23,70 @@ func Test_paymentService_Try(t *testing.T) {
 	defer ctrl.Finish()
 
 	sampleBalance := &model.Balance{
-		UserID: 1,
-		Amount: 100,
+		UserID:         1,
+		Amount:         100,
+		ReservedAmount: 10,
 	}
 	samplePayment := &model.PaymentTransaction{
-		UUID:   "foo",
-		UserID: 1,
-		Amount: 100,
+		UUID:          "foo",
+		UserID:        1,
+		Currency:      domain.JPY,
+		Amount:        100,
+		ResultBalance: &model.Balance{UserID: 1, Currency: domain.JPY, Amount: 100, ReservedAmount: 10},
 	}
+	overdraftBalance := &model.Balance{
+		UserID:         2,
+		Amount:         100,
+		ReservedAmount: 10,
+		OverdraftLimit: 50,
+	}
+	overdraftPayment := &model.PaymentTransaction{
+		UUID:          "bar",
+		UserID:        2,
+		Currency:      domain.JPY,
+		Amount:        -140,
+		ResultBalance: &model.Balance{UserID: 2, Currency: domain.JPY, Amount: 100, ReservedAmount: 150, OverdraftLimit: 50},
+	}
+	// Tryの後に残高が変わっていても、再試行にはTryの直後の残高を返す
+	triedPayment := model.NewPaymentTransaction("tried", 1, domain.JPY, -10, 0)
+	triedPayment.ResultBalance = &model.Balance{UserID: 1, Currency: domain.JPY, Amount: 300, ReservedAmount: 10}
+	// 直後の残高が保存されていない古い取引
+	legacyPayment := model.NewPaymentTransaction("legacy", 1, domain.JPY, -10, 0)
 	balanceRepo := mock.NewMockBalanceRepository(ctrl)
 	balanceRepo.
 		EXPECT().
-		Get(gomock.Any(), gomock.Any()).
-		Return(sampleBalance, nil).
+		Get(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userID uint, currency domain.Currency) (*model.Balance, error) {
+			if userID == overdraftBalance.UserID {
+				return overdraftBalance, nil
+			}
+			return sampleBalance, nil
+		}).
 		AnyTimes()
 	paymentRepo := mock.NewMockPaymentTransactionRepository(ctrl)
 	paymentRepo.
 		EXPECT().
-		Try(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
-		Return(samplePayment, nil).
-		Times(2)
+		Get(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, uuid string) (*model.PaymentTransaction, error) {
+			switch uuid {
+			case triedPayment.UUID:
+				return triedPayment, nil
+			case legacyPayment.UUID:
+				return legacyPayment, nil
+			}
+			return nil, domain.ErrInvalidUUID
+		}).
+		AnyTimes()
+	paymentRepo.
+		EXPECT().
+		Try(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, uuid string, userID uint, currency domain.Currency, amount int64, ttl time.Duration) (*model.PaymentTransaction, error) {
+			if userID == overdraftPayment.UserID {
+				return overdraftPayment, nil
+			}
+			return samplePayment, nil
+		}).
+		Times(3)
 
 	ctx := context.Background()
 
@@ -45,10 +95,11 @@ func Test_paymentService_Try(t *testing.T) {
 		PaymentRepo repository.PaymentTransactionRepository
 	}
 	type args struct {
-		ctx    context.Context
-		uuid   string
-		userID uint
-		amount int
+		ctx      context.Context
+		uuid     string
+		userID   uint
+		currency string
+		amount   int64
 	}
 	tests := []struct {
 		name    string
@@ -61,23 +112,103 @@ func Test_paymentService_Try(t *testing.T) {
 		{
 			"加算できる",
 			fields{balanceRepo, paymentRepo},
-			args{ctx, samplePayment.UUID, samplePayment.UserID, 1},
+			args{ctx, samplePayment.UUID, samplePayment.UserID, "", 1},
 			samplePayment,
-			sampleBalance,
+			samplePayment.ResultBalance,
 			false,
 		},
 		{
 			"減算できる",
 			fields{balanceRepo, paymentRepo},
-			args{ctx, samplePayment.UUID, samplePayment.UserID, -samplePayment.Amount},
+			args{ctx, samplePayment.UUID, samplePayment.UserID, "", -sampleBalance.AvailableAmount()},
 			samplePayment,
-			sampleBalance,
+			samplePayment.ResultBalance,
 			false,
 		},
 		{
 			"減算で残高が足りない",
 			fields{balanceRepo, paymentRepo},
-			args{ctx, samplePayment.UUID, samplePayment.UserID, -int(sampleBalance.Amount) - 1},
+			args{ctx, samplePayment.UUID, samplePayment.UserID, "", -sampleBalance.Amount - 1},
+			nil,
+			nil,
+			true,
+		},
+		{
+			"減算で仮押さえ分を除いた残高が足りない",
+			fields{balanceRepo, paymentRepo},
+			args{ctx, samplePayment.UUID, samplePayment.UserID, "", -sampleBalance.AvailableAmount() - 1},
+			nil,
+			nil,
+			true,
+		},
+		{
+			"与信枠の分まで減算できる",
+			fields{balanceRepo, paymentRepo},
+			args{ctx, overdraftPayment.UUID, overdraftBalance.UserID, "", -140},
+			overdraftPayment,
+			overdraftPayment.ResultBalance,
+			false,
+		},
+		{
+			"減算で与信枠を含めた残高が足りない",
+			fields{balanceRepo, paymentRepo},
+			args{ctx, samplePayment.UUID, overdraftBalance.UserID, "", -141},
+			nil,
+			nil,
+			true,
+		},
+		{
+			"同じ内容での再試行は保存済みの結果を返す",
+			fields{balanceRepo, paymentRepo},
+			args{ctx, triedPayment.UUID, triedPayment.UserID, "", triedPayment.Amount},
+			triedPayment,
+			triedPayment.ResultBalance,
+			false,
+		},
+		{
+			"直後の残高がない取引の再試行は現在の残高を返す",
+			fields{balanceRepo, paymentRepo},
+			args{ctx, legacyPayment.UUID, legacyPayment.UserID, "", legacyPayment.Amount},
+			legacyPayment,
+			sampleBalance,
+			false,
+		},
+		{
+			"異なる内容での再試行",
+			fields{balanceRepo, paymentRepo},
+			args{ctx, triedPayment.UUID, triedPayment.UserID, "", triedPayment.Amount - 1},
+			nil,
+			nil,
+			true,
+		},
+		{
+			"異なる通貨での再試行",
+			fields{balanceRepo, paymentRepo},
+			args{ctx, triedPayment.UUID, triedPayment.UserID, "USD", triedPayment.Amount},
+			nil,
+			nil,
+			true,
+		},
+		{
+			"対応していない通貨",
+			fields{balanceRepo, paymentRepo},
+			args{ctx, samplePayment.UUID, samplePayment.UserID, "XXX", 1},
+			nil,
+			nil,
+			true,
+		},
+		{
+			"冪等キーが空",
+			fields{balanceRepo, paymentRepo},
+			args{ctx, "", samplePayment.UserID, "", 1},
+			nil,
+			nil,
+			true,
+		},
+		{
+			"ユーザIDが空",
+			fields{balanceRepo, paymentRepo},
+			args{ctx, samplePayment.UUID, 0, "", 1},
 			nil,
 			nil,
 			true,
@@ -89,7 +220,7 @@ func Test_paymentService_Try(t *testing.T) {
 				BalanceRepo: tt.fields.BalanceRepo,
 				PaymentRepo: tt.fields.PaymentRepo,
 			}
-			got, got1, err := s.Try(tt.args.ctx, tt.args.uuid, tt.args.userID, tt.args.amount)
+			got, got1, err := s.Try(tt.args.ctx, tt.args.uuid, tt.args.userID, tt.args.currency, tt.args.amount, 0)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("paymentService.Try() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -114,16 +245,34 @@ func Test_paymentService_Confirm(t *testing.T) {
 	}
 	invalidUuid := "invalid"
 	notTryUuid := "not try"
+	expiredUuid := "expired"
 	samplePayment := &model.PaymentTransaction{
-		UUID:   "foo",
-		UserID: 1,
-		Amount: 100,
+		UUID:     "foo",
+		UserID:   1,
+		Currency: domain.JPY,
+		Amount:   100,
+		Status:   model.PaymentStatusTried,
 	}
+	expiredPayment := &model.PaymentTransaction{
+		UUID:       expiredUuid,
+		UserID:     1,
+		Amount:     100,
+		Status:     model.PaymentStatusTried,
+		TryTime:    time.Now().Add(-2 * time.Minute),
+		ExpireTime: time.Now().Add(-time.Minute),
+	}
+	confirmedPayment := model.NewPaymentTransaction("confirmed", 1, domain.JPY, 100, 0)
+	_ = confirmedPayment.Confirm(time.Now())
 	balanceRepo := mock.NewMockBalanceRepository(ctrl)
 	balanceRepo.
 		EXPECT().
-		Get(gomock.Any(), gomock.Any()).
-		Return(sampleBalance, nil).
+		Get(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userID uint, currency domain.Currency) (*model.Balance, error) {
+			if userID != sampleBalance.UserID {
+				return &model.Balance{UserID: userID}, nil
+			}
+			return sampleBalance, nil
+		}).
 		AnyTimes()
 	paymentRepo := mock.NewMockPaymentTransactionRepository(ctrl)
 	paymentRepo.
@@ -133,6 +282,12 @@ func Test_paymentService_Confirm(t *testing.T) {
 			if uuid == invalidUuid || uuid == notTryUuid {
 				return nil, domain.ErrInvalidUUID
 			}
+			if uuid == expiredUuid {
+				return expiredPayment, nil
+			}
+			if uuid == confirmedPayment.UUID {
+				return confirmedPayment, nil
+			}
 			return samplePayment, nil
 		}).
 		AnyTimes()
@@ -140,19 +295,31 @@ func Test_paymentService_Confirm(t *testing.T) {
 		EXPECT().
 		Confirm(gomock.Any(), gomock.Any()).
 		Return(samplePayment, nil).
-		Times(2)
+		Times(4)
+	// strictモードで内容が一致しない場合はConfirmしない
+	mismatchPaymentRepo := mock.NewMockPaymentTransactionRepository(ctrl)
+	mismatchPaymentRepo.
+		EXPECT().
+		Get(gomock.Any(), gomock.Any()).
+		Return(samplePayment, nil).
+		AnyTimes()
+	mismatchPaymentRepo.
+		EXPECT().
+		Confirm(gomock.Any(), gomock.Any()).
+		Times(0)
 
 	ctx := context.Background()
 
 	type fields struct {
 		BalanceRepo repository.BalanceRepository
 		PaymentRepo repository.PaymentTransactionRepository
+		StrictMode  bool
 	}
 	type args struct {
 		ctx    context.Context
 		uuid   string
 		userID uint
-		amount int
+		amount int64
 	}
 	tests := []struct {
 		name    string
@@ -160,47 +327,103 @@ func Test_paymentService_Confirm(t *testing.T) {
 		args    args
 		want    *model.PaymentTransaction
 		want1   *model.Balance
-		wantErr bool
+		wantErr error
 	}{
 		{
 			"加算できる",
-			fields{balanceRepo, paymentRepo},
+			fields{balanceRepo, paymentRepo, false},
 			args{ctx, samplePayment.UUID, samplePayment.UserID, 1},
 			samplePayment,
 			sampleBalance,
-			false,
+			nil,
 		},
 		{
 			"減算できる",
-			fields{balanceRepo, paymentRepo},
+			fields{balanceRepo, paymentRepo, false},
 			args{ctx, samplePayment.UUID, samplePayment.UserID, -samplePayment.Amount},
 			samplePayment,
 			sampleBalance,
-			false,
-		},
-		{
-			"減算で残高が足りない",
-			fields{balanceRepo, paymentRepo},
-			args{ctx, samplePayment.UUID, samplePayment.UserID, -int(sampleBalance.Amount) - 1},
 			nil,
-			nil,
-			true,
 		},
 		{
 			"ステータスがtryでないUUID",
-			fields{balanceRepo, paymentRepo},
+			fields{balanceRepo, paymentRepo, false},
 			args{ctx, notTryUuid, samplePayment.UserID, 1},
 			nil,
 			nil,
-			true,
+			domain.ErrInvalidUUID,
 		},
 		{
 			"存在しないUUID",
-			fields{balanceRepo, paymentRepo},
+			fields{balanceRepo, paymentRepo, false},
 			args{ctx, invalidUuid, samplePayment.UserID, 1},
 			nil,
 			nil,
-			true,
+			domain.ErrInvalidUUID,
+		},
+		{
+			"有効期限切れのUUID",
+			fields{balanceRepo, paymentRepo, false},
+			args{ctx, expiredUuid, expiredPayment.UserID, 1},
+			nil,
+			nil,
+			domain.ErrExpiredTransaction,
+		},
+		{
+			"同じ内容での再試行は保存済みの結果を返す",
+			fields{balanceRepo, paymentRepo, false},
+			args{ctx, confirmedPayment.UUID, confirmedPayment.UserID, confirmedPayment.Amount},
+			confirmedPayment,
+			sampleBalance,
+			nil,
+		},
+		{
+			"異なる内容での再試行",
+			fields{balanceRepo, paymentRepo, false},
+			args{ctx, confirmedPayment.UUID, confirmedPayment.UserID + 1, confirmedPayment.Amount},
+			nil,
+			nil,
+			domain.ErrIdempotencyKeyMismatch,
+		},
+		{
+			"strictモードで内容が一致する",
+			fields{balanceRepo, paymentRepo, true},
+			args{ctx, samplePayment.UUID, samplePayment.UserID, samplePayment.Amount},
+			samplePayment,
+			sampleBalance,
+			nil,
+		},
+		{
+			"strictモードでuser_idが異なる",
+			fields{balanceRepo, mismatchPaymentRepo, true},
+			args{ctx, samplePayment.UUID, samplePayment.UserID + 1, samplePayment.Amount},
+			nil,
+			nil,
+			domain.ErrTransactionMismatch,
+		},
+		{
+			"strictモードでamountが異なる",
+			fields{balanceRepo, mismatchPaymentRepo, true},
+			args{ctx, samplePayment.UUID, samplePayment.UserID, samplePayment.Amount + 1},
+			nil,
+			nil,
+			domain.ErrTransactionMismatch,
+		},
+		{
+			"strictモードでamountの符号が異なる",
+			fields{balanceRepo, mismatchPaymentRepo, true},
+			args{ctx, samplePayment.UUID, samplePayment.UserID, -samplePayment.Amount},
+			nil,
+			nil,
+			domain.ErrTransactionMismatch,
+		},
+		{
+			"user_idが異なっても取引のユーザの残高を返す",
+			fields{balanceRepo, paymentRepo, false},
+			args{ctx, samplePayment.UUID, samplePayment.UserID + 1, samplePayment.Amount},
+			samplePayment,
+			sampleBalance,
+			nil,
 		},
 	}
 	for _, tt := range tests {
@@ -208,9 +431,10 @@ func Test_paymentService_Confirm(t *testing.T) {
 			s := &paymentService{
 				BalanceRepo: tt.fields.BalanceRepo,
 				PaymentRepo: tt.fields.PaymentRepo,
+				StrictMode:  tt.fields.StrictMode,
 			}
-			got, got1, err := s.Confirm(tt.args.ctx, tt.args.uuid, tt.args.userID, tt.args.amount)
-			if (err != nil) != tt.wantErr {
+			got, got1, err := s.Confirm(tt.args.ctx, tt.args.uuid, tt.args.userID, "", tt.args.amount)
+			if !errors.Is(err, tt.wantErr) {
 				t.Errorf("paymentService.Confirm() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
@@ -235,15 +459,24 @@ func Test_paymentService_Cancel(t *testing.T) {
 	invalidUuid := "invalid"
 	notTryUuid := "not try"
 	samplePayment := &model.PaymentTransaction{
-		UUID:   "foo",
-		UserID: 1,
-		Amount: 100,
+		UUID:     "foo",
+		UserID:   1,
+		Currency: domain.JPY,
+		Amount:   100,
+		Status:   model.PaymentStatusTried,
 	}
+	cancelledPayment := model.NewPaymentTransaction("cancelled", 1, domain.JPY, 100, 0)
+	_ = cancelledPayment.Cancel(time.Now())
 	balanceRepo := mock.NewMockBalanceRepository(ctrl)
 	balanceRepo.
 		EXPECT().
-		Get(gomock.Any(), gomock.Any()).
-		Return(sampleBalance, nil).
+		Get(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userID uint, currency domain.Currency) (*model.Balance, error) {
+			if userID != sampleBalance.UserID {
+				return &model.Balance{UserID: userID}, nil
+			}
+			return sampleBalance, nil
+		}).
 		AnyTimes()
 	paymentRepo := mock.NewMockPaymentTransactionRepository(ctrl)
 	paymentRepo.
@@ -253,6 +486,9 @@ func Test_paymentService_Cancel(t *testing.T) {
 			if uuid == invalidUuid || uuid == notTryUuid {
 				return nil, domain.ErrInvalidUUID
 			}
+			if uuid == cancelledPayment.UUID {
+				return cancelledPayment, nil
+			}
 			return samplePayment, nil
 		}).
 		AnyTimes()
@@ -260,19 +496,31 @@ func Test_paymentService_Cancel(t *testing.T) {
 		EXPECT().
 		Cancel(gomock.Any(), gomock.Any()).
 		Return(samplePayment, nil).
-		Times(1)
+		Times(3)
+	// strictモードで内容が一致しない場合はCancelしない
+	mismatchPaymentRepo := mock.NewMockPaymentTransactionRepository(ctrl)
+	mismatchPaymentRepo.
+		EXPECT().
+		Get(gomock.Any(), gomock.Any()).
+		Return(samplePayment, nil).
+		AnyTimes()
+	mismatchPaymentRepo.
+		EXPECT().
+		Cancel(gomock.Any(), gomock.Any()).
+		Times(0)
 
 	ctx := context.Background()
 
 	type fields struct {
 		BalanceRepo repository.BalanceRepository
 		PaymentRepo repository.PaymentTransactionRepository
+		StrictMode  bool
 	}
 	type args struct {
 		ctx    context.Context
 		uuid   string
 		userID uint
-		amount int
+		amount int64
 	}
 	tests := []struct {
 		name    string
@@ -280,31 +528,87 @@ func Test_paymentService_Cancel(t *testing.T) {
 		args    args
 		want    *model.PaymentTransaction
 		want1   *model.Balance
-		wantErr bool
+		wantErr error
 	}{
 		{
 			"キャンセルできる",
-			fields{balanceRepo, paymentRepo},
+			fields{balanceRepo, paymentRepo, false},
 			args{ctx, samplePayment.UUID, samplePayment.UserID, 1},
 			samplePayment,
 			sampleBalance,
-			false,
+			nil,
 		},
 		{
 			"ステータスがtryでないUUID",
-			fields{balanceRepo, paymentRepo},
+			fields{balanceRepo, paymentRepo, false},
 			args{ctx, notTryUuid, samplePayment.UserID, 1},
 			nil,
 			nil,
-			true,
+			domain.ErrInvalidUUID,
 		},
 		{
 			"存在しないUUID",
-			fields{balanceRepo, paymentRepo},
+			fields{balanceRepo, paymentRepo, false},
 			args{ctx, invalidUuid, samplePayment.UserID, 1},
 			nil,
 			nil,
-			true,
+			domain.ErrInvalidUUID,
+		},
+		{
+			"同じ内容での再試行は保存済みの結果を返す",
+			fields{balanceRepo, paymentRepo, false},
+			args{ctx, cancelledPayment.UUID, cancelledPayment.UserID, cancelledPayment.Amount},
+			cancelledPayment,
+			sampleBalance,
+			nil,
+		},
+		{
+			"異なる内容での再試行",
+			fields{balanceRepo, paymentRepo, false},
+			args{ctx, cancelledPayment.UUID, cancelledPayment.UserID, cancelledPayment.Amount + 1},
+			nil,
+			nil,
+			domain.ErrIdempotencyKeyMismatch,
+		},
+		{
+			"strictモードで内容が一致する",
+			fields{balanceRepo, paymentRepo, true},
+			args{ctx, samplePayment.UUID, samplePayment.UserID, samplePayment.Amount},
+			samplePayment,
+			sampleBalance,
+			nil,
+		},
+		{
+			"strictモードでuser_idが異なる",
+			fields{balanceRepo, mismatchPaymentRepo, true},
+			args{ctx, samplePayment.UUID, samplePayment.UserID + 1, samplePayment.Amount},
+			nil,
+			nil,
+			domain.ErrTransactionMismatch,
+		},
+		{
+			"strictモードでamountが異なる",
+			fields{balanceRepo, mismatchPaymentRepo, true},
+			args{ctx, samplePayment.UUID, samplePayment.UserID, samplePayment.Amount + 1},
+			nil,
+			nil,
+			domain.ErrTransactionMismatch,
+		},
+		{
+			"strictモードでamountの符号が異なる",
+			fields{balanceRepo, mismatchPaymentRepo, true},
+			args{ctx, samplePayment.UUID, samplePayment.UserID, -samplePayment.Amount},
+			nil,
+			nil,
+			domain.ErrTransactionMismatch,
+		},
+		{
+			"user_idが異なっても取引のユーザの残高を返す",
+			fields{balanceRepo, paymentRepo, false},
+			args{ctx, samplePayment.UUID, samplePayment.UserID + 1, samplePayment.Amount},
+			samplePayment,
+			sampleBalance,
+			nil,
 		},
 	}
 	for _, tt := range tests {
@@ -312,9 +616,10 @@ func Test_paymentService_Cancel(t *testing.T) {
 			s := &paymentService{
 				BalanceRepo: tt.fields.BalanceRepo,
 				PaymentRepo: tt.fields.PaymentRepo,
+				StrictMode:  tt.fields.StrictMode,
 			}
-			got, got1, err := s.Cancel(tt.args.ctx, tt.args.uuid, tt.args.userID, tt.args.amount)
-			if (err != nil) != tt.wantErr {
+			got, got1, err := s.Cancel(tt.args.ctx, tt.args.uuid, tt.args.userID, "", tt.args.amount)
+			if !errors.Is(err, tt.wantErr) {
 				t.Errorf("paymentService.Cancel() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
@@ -328,6 +633,109 @@ func Test_paymentService_Cancel(t *testing.T) {
 	}
 }
 
+func Test_paymentService_Refund(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	payment := &model.PaymentTransaction{
+		UUID:           "payment",
+		UserID:         1,
+		Currency:       domain.JPY,
+		Amount:         -1000,
+		Status:         model.PaymentStatusPartiallyRefunded,
+		RefundedAmount: 300,
+	}
+	sampleBalance := &model.Balance{UserID: 1, Currency: domain.JPY, Amount: 300}
+	sampleRefund := model.NewRefund("foo", payment, 300, now)
+	refunded := model.NewRefund("refunded", payment, 300, now)
+	racedRefund := model.NewRefund("raced", payment, 300, now)
+	balanceRepo := mock.NewMockBalanceRepository(ctrl)
+	balanceRepo.
+		EXPECT().
+		Get(gomock.Any(), payment.UserID, payment.Currency).
+		Return(sampleBalance, nil).
+		AnyTimes()
+	paymentRepo := mock.NewMockPaymentTransactionRepository(ctrl)
+	paymentRepo.
+		EXPECT().
+		Get(gomock.Any(), payment.UUID).
+		Return(payment, nil).
+		AnyTimes()
+	refundRepo := mock.NewMockRefundRepository(ctrl)
+	// racedは、先にGetしたときにはまだなく、同時に返金されて重複エラーになる
+	racedGot := false
+	refundRepo.
+		EXPECT().
+		Get(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, uuid string) (*model.Refund, error) {
+			switch {
+			case uuid == refunded.UUID:
+				return refunded, nil
+			case uuid == racedRefund.UUID && racedGot:
+				return racedRefund, nil
+			case uuid == racedRefund.UUID:
+				racedGot = true
+			}
+			return nil, domain.ErrInvalidUUID
+		}).
+		AnyTimes()
+	refundRepo.
+		EXPECT().
+		Refund(gomock.Any(), sampleRefund.UUID, payment.UUID, sampleRefund.Amount, gomock.Any()).
+		Return(sampleRefund, payment, nil).
+		Times(1)
+	refundRepo.
+		EXPECT().
+		Refund(gomock.Any(), racedRefund.UUID, payment.UUID, racedRefund.Amount, gomock.Any()).
+		Return(nil, nil, domain.ErrDuplicateUUID).
+		Times(1)
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		uuid        string
+		paymentUUID string
+		amount      int64
+		want        *model.Refund
+		wantErr     error
+	}{
+		{"返金できる", sampleRefund.UUID, payment.UUID, 300, sampleRefund, nil},
+		{"同じ内容での再試行は保存済みの結果を返す", refunded.UUID, payment.UUID, 300, refunded, nil},
+		{"異なる金額での再試行", refunded.UUID, payment.UUID, 200, nil, domain.ErrIdempotencyKeyMismatch},
+		{"異なる支払いでの再試行", refunded.UUID, "other", 300, nil, domain.ErrIdempotencyKeyMismatch},
+		{"同時に同じ冪等キーで返金された場合は保存済みの結果を返す", racedRefund.UUID, payment.UUID, 300, racedRefund, nil},
+		{"0円は返金できない", "zero", payment.UUID, 0, nil, domain.ErrInvalidParam},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &paymentService{
+				BalanceRepo: balanceRepo,
+				PaymentRepo: paymentRepo,
+				RefundRepo:  refundRepo,
+			}
+			got, got1, got2, err := s.Refund(ctx, tt.uuid, tt.paymentUUID, tt.amount)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("paymentService.Refund() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("paymentService.Refund() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got1, payment) {
+				t.Errorf("paymentService.Refund() got1 = %v, want %v", got1, payment)
+			}
+			if !reflect.DeepEqual(got2, sampleBalance) {
+				t.Errorf("paymentService.Refund() got2 = %v, want %v", got2, sampleBalance)
+			}
+		})
+	}
+}
+
 func Test_paymentService_AddToUsers(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -335,8 +743,14 @@ func Test_paymentService_AddToUsers(t *testing.T) {
 	balanceRepo := mock.NewMockBalanceRepository(ctrl)
 	balanceRepo.
 		EXPECT().
-		AddToUsers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
-		Return(nil).
+		AddToUsers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, currency domain.Currency, amount int64, pointExpireTime time.Time, limit, offset int) error {
+			// ポイントの有効期限は日数から決まる
+			if currency == domain.Point && pointExpireTime.Before(time.Now().AddDate(0, 0, 29)) {
+				return errors.New("unexpected expire time")
+			}
+			return nil
+		}).
 		AnyTimes()
 	paymentRepo := mock.NewMockPaymentTransactionRepository(ctrl)
 
@@ -347,10 +761,12 @@ func Test_paymentService_AddToUsers(t *testing.T) {
 		PaymentRepo repository.PaymentTransactionRepository
 	}
 	type args struct {
-		ctx    context.Context
-		amount int
-		limit  int
-		offset int
+		ctx           context.Context
+		currency      string
+		amount        int64
+		expiresInDays int
+		limit         int
+		offset        int
 	}
 	tests := []struct {
 		name    string
@@ -361,25 +777,1516 @@ func Test_paymentService_AddToUsers(t *testing.T) {
 		{
 			"加算できる",
 			fields{balanceRepo, paymentRepo},
-			args{ctx, 1, 10, 0},
+			args{ctx, "", 1, 0, 10, 0},
 			false,
 		},
 		{
 			"減算できない",
 			fields{balanceRepo, paymentRepo},
-			args{ctx, 0, 10, 0},
+			args{ctx, "", 0, 0, 10, 0},
 			true,
 		},
-	}
-	for _, tt := range tests {
+		{
+			"有効期限のあるポイントを加算できる",
+			fields{balanceRepo, paymentRepo},
+			args{ctx, "PTS", 100, 30, 10, 0},
+			false,
+		},
+		{
+			"ポイント以外には有効期限を指定できない",
+			fields{balanceRepo, paymentRepo},
+			args{ctx, "JPY", 100, 30, 10, 0},
+			true,
+		},
+		{
+			"有効期限の日数が負",
+			fields{balanceRepo, paymentRepo},
+			args{ctx, "PTS", 100, -1, 10, 0},
+			true,
+		},
+		{
+			"オフセットが負",
+			fields{balanceRepo, paymentRepo},
+			args{ctx, "", 1, 0, 10, -1},
+			true,
+		},
+	}
+	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s := &paymentService{
 				BalanceRepo: tt.fields.BalanceRepo,
 				PaymentRepo: tt.fields.PaymentRepo,
 			}
-			if err := s.AddToUsers(tt.args.ctx, tt.args.amount, tt.args.limit, tt.args.offset); (err != nil) != tt.wantErr {
+			if err := s.AddToUsers(tt.args.ctx, tt.args.currency, tt.args.amount, tt.args.expiresInDays, tt.args.limit, tt.args.offset); (err != nil) != tt.wantErr {
 				t.Errorf("paymentService.AddToUsers() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func Test_paymentService_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	samplePayments := []*model.PaymentTransaction{
+		{UUID: "c", UserID: 1, Amount: 30, TryTime: now},
+		{UUID: "b", UserID: 1, Amount: -20, TryTime: now},
+		{UUID: "a", UserID: 1, Amount: 10, TryTime: now.Add(-time.Minute)},
+	}
+	paymentRepo := mock.NewMockPaymentTransactionRepository(ctrl)
+	paymentRepo.
+		EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userID uint, filter model.PaymentTransactionFilter) ([]*model.PaymentTransaction, error) {
+			var pts []*model.PaymentTransaction
+			for _, pt := range samplePayments {
+				if !filter.BeforeTryTime.IsZero() && (pt.TryTime.After(filter.BeforeTryTime) || pt.TryTime.Equal(filter.BeforeTryTime) && pt.UUID >= filter.BeforeUUID) {
+					continue
+				}
+				if len(pts) == filter.Limit {
+					break
+				}
+				pts = append(pts, pt)
+			}
+			return pts, nil
+		}).
+		AnyTimes()
+
+	ctx := context.Background()
+
+	type fields struct {
+		PaymentRepo repository.PaymentTransactionRepository
+	}
+	type args struct {
+		ctx    context.Context
+		userID uint
+		status string
+		sign   string
+		cursor string
+		limit  int
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*model.PaymentTransaction
+		want1   string
+		wantErr bool
+	}{
+		{
+			"件数を指定しなければすべて取得できる",
+			fields{paymentRepo},
+			args{ctx, 1, "", "", "", 0},
+			samplePayments,
+			"",
+			false,
+		},
+		{
+			"続きがあれば次のカーソルを返す",
+			fields{paymentRepo},
+			args{ctx, 1, "confirmed", "positive", "", 1},
+			samplePayments[:1],
+			encodeCursor(paymentCursorKey(samplePayments[0])),
+			false,
+		},
+		{
+			"Tryの時刻が同じ取引もカーソルの続きから取得できる",
+			fields{paymentRepo},
+			args{ctx, 1, "", "", encodeCursor(paymentCursorKey(samplePayments[0])), 10},
+			samplePayments[1:],
+			"",
+			false,
+		},
+		{
+			"不正なステータスはエラー",
+			fields{paymentRepo},
+			args{ctx, 1, "unknown", "", "", 0},
+			nil,
+			"",
+			true,
+		},
+		{
+			"不正な符号はエラー",
+			fields{paymentRepo},
+			args{ctx, 1, "", "zero", "", 0},
+			nil,
+			"",
+			true,
+		},
+		{
+			"不正なカーソルはエラー",
+			fields{paymentRepo},
+			args{ctx, 1, "", "", encodeCursor("foo"), 0},
+			nil,
+			"",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &paymentService{
+				PaymentRepo: tt.fields.PaymentRepo,
+			}
+			got, got1, err := s.List(tt.args.ctx, tt.args.userID, tt.args.status, tt.args.sign, time.Time{}, time.Time{}, tt.args.cursor, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("paymentService.List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("paymentService.List() got = %v, want %v", got, tt.want)
+			}
+			if got1 != tt.want1 {
+				t.Errorf("paymentService.List() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func Test_balanceService_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	jpy := &model.Balance{UserID: 1, Currency: domain.JPY, Amount: 100}
+	pts := &model.Balance{UserID: 1, Currency: domain.Point, Amount: 300}
+	expirations := []*model.PointExpiration{{Amount: 200, ExpireTime: time.Now().Add(time.Hour)}}
+	balanceRepo := mock.NewMockBalanceRepository(ctrl)
+	balanceRepo.
+		EXPECT().
+		List(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userID uint) ([]*model.Balance, error) {
+			if userID != 1 {
+				return nil, domain.ErrNoSuchEntity
+			}
+			return []*model.Balance{jpy, pts}, nil
+		}).
+		AnyTimes()
+	pointLotRepo := mock.NewMockPointLotRepository(ctrl)
+	pointLotRepo.
+		EXPECT().
+		ListExpirations(gomock.Any(), uint(1), gomock.Any(), maxPointExpirations).
+		Return(expirations, nil).
+		Times(1)
+
+	tests := []struct {
+		name    string
+		userID  uint
+		want    []*model.Balance
+		wantErr error
+	}{
+		{
+			"ポイントのウォレットだけに失効予定を含める",
+			1,
+			[]*model.Balance{
+				{UserID: 1, Currency: domain.JPY, Amount: 100},
+				{UserID: 1, Currency: domain.Point, Amount: 300, Expirations: expirations},
+			},
+			nil,
+		},
+		{
+			"存在しないユーザ",
+			2,
+			nil,
+			domain.ErrNoSuchEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &balanceService{
+				BalanceRepo:  balanceRepo,
+				PointLotRepo: pointLotRepo,
+			}
+			got, err := s.List(context.Background(), tt.userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("balanceService.List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("balanceService.List() mismatch (-want +got): \n %s", diff)
+			}
+		})
+	}
+}
+
+func Test_balanceService_SetOverdraftLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	balanceRepo := mock.NewMockBalanceRepository(ctrl)
+	balanceRepo.
+		EXPECT().
+		SetOverdraftLimit(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userID uint, currency domain.Currency, limit int64) (*model.Balance, error) {
+			return &model.Balance{UserID: userID, Currency: currency, OverdraftLimit: limit}, nil
+		}).
+		Times(2)
+
+	tests := []struct {
+		name     string
+		currency string
+		limit    int64
+		wantErr  error
+	}{
+		{"与信枠を設定できる", "JPY", 10000, nil},
+		{"与信枠をなくせる", "USD", 0, nil},
+		{"ポイントには設定できない", "PTS", 10000, domain.ErrInvalidParam},
+		{"負の与信枠", "JPY", -1, domain.ErrInvalidParam},
+		{"対応していない通貨", "XXX", 10000, domain.ErrInvalidParam},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &balanceService{
+				BalanceRepo: balanceRepo,
+			}
+			got, err := s.SetOverdraftLimit(context.Background(), 1, tt.currency, tt.limit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("balanceService.SetOverdraftLimit() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.OverdraftLimit != tt.limit {
+				t.Errorf("balanceService.SetOverdraftLimit() overdraft limit = %v, want %v", got.OverdraftLimit, tt.limit)
+			}
+		})
+	}
+}
+
+func Test_balanceService_ListLogs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sampleLogs := []*model.BalanceLog{
+		{ID: 3, UserID: 1, Delta: 10},
+		{ID: 2, UserID: 1, Delta: -5},
+		{ID: 1, UserID: 1, Delta: 100},
+	}
+	balanceLogRepo := mock.NewMockBalanceLogRepository(ctrl)
+	balanceLogRepo.
+		EXPECT().
+		List(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userID uint, filter model.BalanceLogFilter) ([]*model.BalanceLog, error) {
+			var logs []*model.BalanceLog
+			for _, l := range sampleLogs {
+				if filter.BeforeID > 0 && l.ID >= filter.BeforeID {
+					continue
+				}
+				if len(logs) == filter.Limit {
+					break
+				}
+				logs = append(logs, l)
+			}
+			return logs, nil
+		}).
+		AnyTimes()
+
+	ctx := context.Background()
+
+	type fields struct {
+		BalanceLogRepo repository.BalanceLogRepository
+	}
+	type args struct {
+		ctx    context.Context
+		userID uint
+		cursor string
+		limit  int
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*model.BalanceLog
+		want1   string
+		wantErr bool
+	}{
+		{
+			"件数を指定しなければすべて取得できる",
+			fields{balanceLogRepo},
+			args{ctx, 1, "", 0},
+			sampleLogs,
+			"",
+			false,
+		},
+		{
+			"続きがあれば次のカーソルを返す",
+			fields{balanceLogRepo},
+			args{ctx, 1, "", 2},
+			sampleLogs[:2],
+			encodeCursor("2"),
+			false,
+		},
+		{
+			"カーソルの続きから取得できる",
+			fields{balanceLogRepo},
+			args{ctx, 1, encodeCursor("2"), 2},
+			sampleLogs[2:],
+			"",
+			false,
+		},
+		{
+			"不正なカーソルはエラー",
+			fields{balanceLogRepo},
+			args{ctx, 1, "!!", 2},
+			nil,
+			"",
+			true,
+		},
+		{
+			"件数が上限を超えるとエラー",
+			fields{balanceLogRepo},
+			args{ctx, 1, "", maxPageSize + 1},
+			nil,
+			"",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &balanceService{
+				BalanceLogRepo: tt.fields.BalanceLogRepo,
+			}
+			got, got1, err := s.ListLogs(tt.args.ctx, tt.args.userID, "", time.Time{}, time.Time{}, tt.args.cursor, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("balanceService.ListLogs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("balanceService.ListLogs() got = %v, want %v", got, tt.want)
+			}
+			if got1 != tt.want1 {
+				t.Errorf("balanceService.ListLogs() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func Test_transferService_Try(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fromBalance := &model.Balance{
+		UserID:         1,
+		Amount:         100,
+		ReservedAmount: 10,
+	}
+	toBalance := &model.Balance{
+		UserID: 2,
+		Amount: 100,
+	}
+	sampleTransfer := &model.Transfer{
+		UUID:       "foo",
+		FromUserID: 1,
+		ToUserID:   2,
+		Amount:     10,
+	}
+	// Tryの後に残高が変わっていても、再試行にはTryの直後の残高を返す
+	triedTransfer := model.NewTransfer("tried", 1, 2, domain.JPY, 10, 0)
+	triedTransfer.FromResultBalance = &model.Balance{UserID: 1, Currency: domain.JPY, Amount: 300, ReservedAmount: 10}
+	triedTransfer.ToResultBalance = &model.Balance{UserID: 2, Currency: domain.JPY, Amount: 50}
+	// 直後の残高が保存されていない古い送金
+	legacyTransfer := model.NewTransfer("legacy", 1, 2, domain.JPY, 10, 0)
+	balanceRepo := mock.NewMockBalanceRepository(ctrl)
+	balanceRepo.
+		EXPECT().
+		Get(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userID uint, currency domain.Currency) (*model.Balance, error) {
+			if userID == fromBalance.UserID {
+				return fromBalance, nil
+			}
+			return toBalance, nil
+		}).
+		AnyTimes()
+	transferRepo := mock.NewMockTransferRepository(ctrl)
+	transferRepo.
+		EXPECT().
+		Get(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, uuid string) (*model.Transfer, error) {
+			switch uuid {
+			case triedTransfer.UUID:
+				return triedTransfer, nil
+			case legacyTransfer.UUID:
+				return legacyTransfer, nil
+			}
+			return nil, domain.ErrInvalidUUID
+		}).
+		AnyTimes()
+	transferRepo.
+		EXPECT().
+		Try(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(sampleTransfer, nil).
+		Times(1)
+
+	ctx := context.Background()
+
+	type fields struct {
+		BalanceRepo  repository.BalanceRepository
+		TransferRepo repository.TransferRepository
+	}
+	type args struct {
+		ctx        context.Context
+		uuid       string
+		fromUserID uint
+		toUserID   uint
+		amount     int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *model.Transfer
+		want1   *model.Balance
+		want2   *model.Balance
+		wantErr bool
+	}{
+		{
+			"送金できる",
+			fields{balanceRepo, transferRepo},
+			args{ctx, sampleTransfer.UUID, fromBalance.UserID, toBalance.UserID, fromBalance.AvailableAmount()},
+			sampleTransfer,
+			fromBalance,
+			toBalance,
+			false,
+		},
+		{
+			"仮押さえ分を除いた残高が足りない",
+			fields{balanceRepo, transferRepo},
+			args{ctx, sampleTransfer.UUID, fromBalance.UserID, toBalance.UserID, fromBalance.AvailableAmount() + 1},
+			nil,
+			nil,
+			nil,
+			true,
+		},
+		{
+			"自分自身には送金できない",
+			fields{balanceRepo, transferRepo},
+			args{ctx, sampleTransfer.UUID, fromBalance.UserID, fromBalance.UserID, 1},
+			nil,
+			nil,
+			nil,
+			true,
+		},
+		{
+			"0円は送金できない",
+			fields{balanceRepo, transferRepo},
+			args{ctx, sampleTransfer.UUID, fromBalance.UserID, toBalance.UserID, 0},
+			nil,
+			nil,
+			nil,
+			true,
+		},
+		{
+			"同じ内容での再試行は保存済みの結果を返す",
+			fields{balanceRepo, transferRepo},
+			args{ctx, triedTransfer.UUID, triedTransfer.FromUserID, triedTransfer.ToUserID, triedTransfer.Amount},
+			triedTransfer,
+			triedTransfer.FromResultBalance,
+			triedTransfer.ToResultBalance,
+			false,
+		},
+		{
+			"直後の残高がない送金の再試行は現在の残高を返す",
+			fields{balanceRepo, transferRepo},
+			args{ctx, legacyTransfer.UUID, legacyTransfer.FromUserID, legacyTransfer.ToUserID, legacyTransfer.Amount},
+			legacyTransfer,
+			fromBalance,
+			toBalance,
+			false,
+		},
+		{
+			"異なる内容での再試行",
+			fields{balanceRepo, transferRepo},
+			args{ctx, triedTransfer.UUID, triedTransfer.ToUserID, triedTransfer.FromUserID, triedTransfer.Amount},
+			nil,
+			nil,
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &transferService{
+				BalanceRepo:  tt.fields.BalanceRepo,
+				TransferRepo: tt.fields.TransferRepo,
+			}
+			got, got1, got2, err := s.Try(tt.args.ctx, tt.args.uuid, tt.args.fromUserID, tt.args.toUserID, "", tt.args.amount, 0)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("transferService.Try() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("transferService.Try() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("transferService.Try() got1 = %v, want %v", got1, tt.want1)
+			}
+			if !reflect.DeepEqual(got2, tt.want2) {
+				t.Errorf("transferService.Try() got2 = %v, want %v", got2, tt.want2)
+			}
+		})
+	}
+}
+
+func Test_transferService_Confirm(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fromBalance := &model.Balance{
+		UserID: 1,
+		Amount: 100,
+	}
+	toBalance := &model.Balance{
+		UserID: 2,
+		Amount: 100,
+	}
+	invalidUuid := "invalid"
+	sampleTransfer := model.NewTransfer("foo", 1, 2, domain.JPY, 10, 0)
+	expiredTransfer := model.NewTransfer("expired", 1, 2, domain.JPY, 10, 0)
+	expiredTransfer.ExpireTime = time.Now().Add(-time.Minute)
+	cancelledTransfer := model.NewTransfer("cancelled", 1, 2, domain.JPY, 10, 0)
+	_ = cancelledTransfer.Cancel(time.Now())
+	confirmedTransfer := model.NewTransfer("confirmed", 1, 2, domain.JPY, 10, 0)
+	_ = confirmedTransfer.Confirm(time.Now())
+	balanceRepo := mock.NewMockBalanceRepository(ctrl)
+	balanceRepo.
+		EXPECT().
+		Get(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userID uint, currency domain.Currency) (*model.Balance, error) {
+			if userID == fromBalance.UserID {
+				return fromBalance, nil
+			}
+			return toBalance, nil
+		}).
+		AnyTimes()
+	transferRepo := mock.NewMockTransferRepository(ctrl)
+	transferRepo.
+		EXPECT().
+		Get(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, uuid string) (*model.Transfer, error) {
+			switch uuid {
+			case expiredTransfer.UUID:
+				return expiredTransfer, nil
+			case cancelledTransfer.UUID:
+				return cancelledTransfer, nil
+			case confirmedTransfer.UUID:
+				return confirmedTransfer, nil
+			case invalidUuid:
+				return nil, domain.ErrInvalidUUID
+			}
+			return sampleTransfer, nil
+		}).
+		AnyTimes()
+	transferRepo.
+		EXPECT().
+		Confirm(gomock.Any(), gomock.Any()).
+		Return(sampleTransfer, nil).
+		Times(1)
+
+	ctx := context.Background()
+
+	type fields struct {
+		BalanceRepo  repository.BalanceRepository
+		TransferRepo repository.TransferRepository
+		StrictMode   bool
+	}
+	type args struct {
+		ctx        context.Context
+		uuid       string
+		fromUserID uint
+		toUserID   uint
+		amount     int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *model.Transfer
+		want1   *model.Balance
+		want2   *model.Balance
+		wantErr bool
+	}{
+		{
+			"確定できる",
+			fields{balanceRepo, transferRepo, true},
+			args{ctx, sampleTransfer.UUID, sampleTransfer.FromUserID, sampleTransfer.ToUserID, sampleTransfer.Amount},
+			sampleTransfer,
+			fromBalance,
+			toBalance,
+			false,
+		},
+		{
+			"strictモードで金額が異なる",
+			fields{balanceRepo, transferRepo, true},
+			args{ctx, sampleTransfer.UUID, sampleTransfer.FromUserID, sampleTransfer.ToUserID, sampleTransfer.Amount + 1},
+			nil,
+			nil,
+			nil,
+			true,
+		},
+		{
+			"存在しないUUID",
+			fields{balanceRepo, transferRepo, true},
+			args{ctx, invalidUuid, sampleTransfer.FromUserID, sampleTransfer.ToUserID, sampleTransfer.Amount},
+			nil,
+			nil,
+			nil,
+			true,
+		},
+		{
+			"有効期限切れのUUID",
+			fields{balanceRepo, transferRepo, true},
+			args{ctx, expiredTransfer.UUID, expiredTransfer.FromUserID, expiredTransfer.ToUserID, expiredTransfer.Amount},
+			nil,
+			nil,
+			nil,
+			true,
+		},
+		{
+			"キャンセル済みのUUID",
+			fields{balanceRepo, transferRepo, true},
+			args{ctx, cancelledTransfer.UUID, cancelledTransfer.FromUserID, cancelledTransfer.ToUserID, cancelledTransfer.Amount},
+			nil,
+			nil,
+			nil,
+			true,
+		},
+		{
+			"同じ内容での再試行は保存済みの結果を返す",
+			fields{balanceRepo, transferRepo, true},
+			args{ctx, confirmedTransfer.UUID, confirmedTransfer.FromUserID, confirmedTransfer.ToUserID, confirmedTransfer.Amount},
+			confirmedTransfer,
+			fromBalance,
+			toBalance,
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &transferService{
+				BalanceRepo:  tt.fields.BalanceRepo,
+				TransferRepo: tt.fields.TransferRepo,
+				StrictMode:   tt.fields.StrictMode,
+			}
+			got, got1, got2, err := s.Confirm(tt.args.ctx, tt.args.uuid, tt.args.fromUserID, tt.args.toUserID, "", tt.args.amount)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("transferService.Confirm() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("transferService.Confirm() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("transferService.Confirm() got1 = %v, want %v", got1, tt.want1)
+			}
+			if !reflect.DeepEqual(got2, tt.want2) {
+				t.Errorf("transferService.Confirm() got2 = %v, want %v", got2, tt.want2)
+			}
+		})
+	}
+}
+
+func Test_transferService_Cancel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fromBalance := &model.Balance{
+		UserID: 1,
+		Amount: 100,
+	}
+	toBalance := &model.Balance{
+		UserID: 2,
+		Amount: 100,
+	}
+	sampleTransfer := model.NewTransfer("foo", 1, 2, domain.JPY, 10, 0)
+	confirmedTransfer := model.NewTransfer("confirmed", 1, 2, domain.JPY, 10, 0)
+	_ = confirmedTransfer.Confirm(time.Now())
+	balanceRepo := mock.NewMockBalanceRepository(ctrl)
+	balanceRepo.
+		EXPECT().
+		Get(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userID uint, currency domain.Currency) (*model.Balance, error) {
+			if userID == fromBalance.UserID {
+				return fromBalance, nil
+			}
+			return toBalance, nil
+		}).
+		AnyTimes()
+	transferRepo := mock.NewMockTransferRepository(ctrl)
+	transferRepo.
+		EXPECT().
+		Get(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, uuid string) (*model.Transfer, error) {
+			if uuid == confirmedTransfer.UUID {
+				return confirmedTransfer, nil
+			}
+			return sampleTransfer, nil
+		}).
+		AnyTimes()
+	transferRepo.
+		EXPECT().
+		Cancel(gomock.Any(), gomock.Any()).
+		Return(sampleTransfer, nil).
+		Times(1)
+
+	ctx := context.Background()
+
+	type fields struct {
+		BalanceRepo  repository.BalanceRepository
+		TransferRepo repository.TransferRepository
+	}
+	type args struct {
+		ctx        context.Context
+		uuid       string
+		fromUserID uint
+		toUserID   uint
+		amount     int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *model.Transfer
+		want1   *model.Balance
+		want2   *model.Balance
+		wantErr bool
+	}{
+		{
+			"キャンセルできる",
+			fields{balanceRepo, transferRepo},
+			args{ctx, sampleTransfer.UUID, sampleTransfer.FromUserID, sampleTransfer.ToUserID, sampleTransfer.Amount},
+			sampleTransfer,
+			fromBalance,
+			toBalance,
+			false,
+		},
+		{
+			"確定済みのUUID",
+			fields{balanceRepo, transferRepo},
+			args{ctx, confirmedTransfer.UUID, confirmedTransfer.FromUserID, confirmedTransfer.ToUserID, confirmedTransfer.Amount},
+			nil,
+			nil,
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &transferService{
+				BalanceRepo:  tt.fields.BalanceRepo,
+				TransferRepo: tt.fields.TransferRepo,
+			}
+			got, got1, got2, err := s.Cancel(tt.args.ctx, tt.args.uuid, tt.args.fromUserID, tt.args.toUserID, "", tt.args.amount)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("transferService.Cancel() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("transferService.Cancel() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("transferService.Cancel() got1 = %v, want %v", got1, tt.want1)
+			}
+			if !reflect.DeepEqual(got2, tt.want2) {
+				t.Errorf("transferService.Cancel() got2 = %v, want %v", got2, tt.want2)
+			}
+		})
+	}
+}
+
+func Test_bulkCreditService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	all := model.BulkCreditTarget{Type: model.BulkCreditTargetAll}
+	userIDs := model.BulkCreditTarget{Type: model.BulkCreditTargetUserIDs, UserIDs: []uint{3, 1}}
+	createdJob := model.NewBulkCreditJob("created", domain.JPY, 100, all, 2)
+	createdJob.ID = 1
+	failedJob := model.NewBulkCreditJob("failed", domain.JPY, 100, all, 2)
+	failedJob.ID = 2
+	failedJob.Status, failedJob.CreditedCount, failedJob.FailedCount = model.BulkCreditStatusCompleted, 1, 1
+	failures := []*model.BulkCreditItem{{JobID: 2, UserID: 2, Status: model.BulkCreditItemStatusFailed, Error: "foo"}}
+	jobs := map[string]*model.BulkCreditJob{
+		createdJob.IdempotencyKey: createdJob,
+		failedJob.IdempotencyKey:  failedJob,
+	}
+	newJob := &model.BulkCreditJob{ID: 3, IdempotencyKey: "new", Amount: 100, Status: model.BulkCreditStatusPending}
+	balanceRepo := mock.NewMockBalanceRepository(ctrl)
+	balanceRepo.
+		EXPECT().
+		CountTargets(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(2, nil).
+		AnyTimes()
+	jobRepo := mock.NewMockBulkCreditJobRepository(ctrl)
+	jobRepo.
+		EXPECT().
+		Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, idempotencyKey string, currency domain.Currency, amount int64, target model.BulkCreditTarget) (*model.BulkCreditJob, error) {
+			if _, ok := jobs[idempotencyKey]; ok {
+				return nil, domain.ErrDuplicateUUID
+			}
+			return newJob, nil
+		}).
+		AnyTimes()
+	jobRepo.
+		EXPECT().
+		GetByIdempotencyKey(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, idempotencyKey string) (*model.BulkCreditJob, error) {
+			return jobs[idempotencyKey], nil
+		}).
+		AnyTimes()
+	jobRepo.
+		EXPECT().
+		ListFailedItems(gomock.Any(), failedJob.ID, gomock.Any()).
+		Return(failures, nil).
+		AnyTimes()
+
+	ctx := context.Background()
+
+	type fields struct {
+		BalanceRepo repository.BalanceRepository
+		JobRepo     repository.BulkCreditJobRepository
+	}
+	type args struct {
+		ctx            context.Context
+		idempotencyKey string
+		amount         int64
+		target         model.BulkCreditTarget
+		dryRun         bool
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *model.BulkCreditJob
+		want1   []*model.BulkCreditItem
+		wantErr error
+	}{
+		{
+			"作成できる",
+			fields{balanceRepo, jobRepo},
+			args{ctx, newJob.IdempotencyKey, 100, all, false},
+			newJob,
+			nil,
+			nil,
+		},
+		{
+			"同じ冪等性キーでの再試行は作成済みのジョブを返す",
+			fields{balanceRepo, jobRepo},
+			args{ctx, createdJob.IdempotencyKey, 100, all, false},
+			createdJob,
+			nil,
+			nil,
+		},
+		{
+			"作成済みのジョブの失敗したユーザを返す",
+			fields{balanceRepo, jobRepo},
+			args{ctx, failedJob.IdempotencyKey, 100, all, false},
+			failedJob,
+			failures,
+			nil,
+		},
+		{
+			"同じ冪等性キーで金額が異なるとエラー",
+			fields{balanceRepo, jobRepo},
+			args{ctx, createdJob.IdempotencyKey, 200, all, false},
+			nil,
+			nil,
+			domain.ErrIdempotencyKeyMismatch,
+		},
+		{
+			"同じ冪等性キーで対象が異なるとエラー",
+			fields{balanceRepo, jobRepo},
+			args{ctx, createdJob.IdempotencyKey, 100, userIDs, false},
+			nil,
+			nil,
+			domain.ErrIdempotencyKeyMismatch,
+		},
+		{
+			"dry_runではジョブを作成せず対象数を返す",
+			fields{balanceRepo, jobRepo},
+			args{ctx, "dry_run", 100, userIDs, true},
+			&model.BulkCreditJob{
+				IdempotencyKey:     "dry_run",
+				RequestFingerprint: model.BulkCreditRequestFingerprint(100, model.BulkCreditTarget{Type: model.BulkCreditTargetUserIDs, UserIDs: []uint{1, 3}}),
+				Currency:           domain.JPY,
+				Amount:             100,
+				Target:             model.BulkCreditTarget{Type: model.BulkCreditTargetUserIDs, UserIDs: []uint{1, 3}},
+				TotalCount:         2,
+			},
+			nil,
+			nil,
+		},
+		{
+			"合計金額が64bitに収まらないとエラー",
+			fields{balanceRepo, jobRepo},
+			args{ctx, "dry_run", math.MaxInt64, all, true},
+			nil,
+			nil,
+			domain.ErrAmountOverflow,
+		},
+		{
+			"不正な対象はエラー",
+			fields{balanceRepo, jobRepo},
+			args{ctx, newJob.IdempotencyKey, 100, model.BulkCreditTarget{Type: model.BulkCreditTargetBalanceBelow}, false},
+			nil,
+			nil,
+			domain.ErrInvalidParam,
+		},
+		{
+			"減算はできない",
+			fields{balanceRepo, jobRepo},
+			args{ctx, newJob.IdempotencyKey, 0, all, false},
+			nil,
+			nil,
+			domain.ErrInvalidParam,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &bulkCreditService{
+				BalanceRepo: tt.fields.BalanceRepo,
+				JobRepo:     tt.fields.JobRepo,
+			}
+			got, got1, err := s.Create(tt.args.ctx, tt.args.idempotencyKey, "", tt.args.amount, tt.args.target, tt.args.dryRun)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("bulkCreditService.Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			opt := cmpopts.IgnoreFields(model.BulkCreditJob{}, "CreateTime", "UpdateTime")
+			if diff := cmp.Diff(tt.want, got, opt); diff != "" {
+				t.Errorf("bulkCreditService.Create() mismatch (-want +got): \n %s", diff)
+			}
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("bulkCreditService.Create() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func Test_bulkCreditService_Reverse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	all := model.BulkCreditTarget{Type: model.BulkCreditTargetAll}
+	reversedJob := model.NewBulkCreditJob("reversed", domain.JPY, 100, all, 2)
+	reversedJob.ID = 1
+	reversedJob.Status, reversedJob.CreditedCount = model.BulkCreditStatusCompleted, 2
+	reversedJob.ReversalStatus, reversedJob.ReversalMode = model.BulkCreditStatusCompleted, model.BulkCreditReversalSkip
+	reversedJob.ReversedCount, reversedJob.ReversalSkippedCount, reversedJob.ReversedAmount = 1, 1, 100
+	skips := []*model.BulkCreditItem{{JobID: 1, UserID: 2, Status: model.BulkCreditItemStatusCredited, ReversalStatus: model.BulkCreditItemReversalSkipped}}
+	jobRepo := mock.NewMockBulkCreditJobRepository(ctrl)
+	jobRepo.
+		EXPECT().
+		RequestReversal(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, id uint64, mode model.BulkCreditReversalMode) (*model.BulkCreditJob, error) {
+			if id != reversedJob.ID {
+				return nil, domain.ErrNoSuchEntity
+			}
+			if mode != reversedJob.ReversalMode {
+				return nil, domain.ErrIllegalTransition
+			}
+			return reversedJob, nil
+		}).
+		AnyTimes()
+	jobRepo.
+		EXPECT().
+		ListSkippedReversalItems(gomock.Any(), reversedJob.ID, gomock.Any()).
+		Return(skips, nil).
+		AnyTimes()
+
+	ctx := context.Background()
+
+	type fields struct {
+		JobRepo repository.BulkCreditJobRepository
+	}
+	type args struct {
+		ctx  context.Context
+		id   uint64
+		mode string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *model.BulkCreditJob
+		want1   []*model.BulkCreditItem
+		wantErr error
+	}{
+		{
+			"取り消しのスキップしたユーザを返す",
+			fields{jobRepo},
+			args{ctx, reversedJob.ID, "skip"},
+			reversedJob,
+			skips,
+			nil,
+		},
+		{
+			"異なるmodeでは取り消せない",
+			fields{jobRepo},
+			args{ctx, reversedJob.ID, "clamp"},
+			nil,
+			nil,
+			domain.ErrIllegalTransition,
+		},
+		{
+			"存在しないジョブはエラー",
+			fields{jobRepo},
+			args{ctx, 100, "skip"},
+			nil,
+			nil,
+			domain.ErrNoSuchEntity,
+		},
+		{
+			"不正なmodeはエラー",
+			fields{jobRepo},
+			args{ctx, reversedJob.ID, "foo"},
+			nil,
+			nil,
+			domain.ErrInvalidParam,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &bulkCreditService{
+				JobRepo: tt.fields.JobRepo,
+			}
+			got, got1, err := s.Reverse(tt.args.ctx, tt.args.id, tt.args.mode)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("bulkCreditService.Reverse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("bulkCreditService.Reverse() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("bulkCreditService.Reverse() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func Test_exchangeService_Exchange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	rate := &model.ExchangeRate{
+		ID:           1,
+		FromCurrency: domain.JPY,
+		ToCurrency:   domain.USD,
+		Rate:         "0.0067",
+		Rounding:     domain.RoundDown,
+		ValidFrom:    now.Add(-time.Hour),
+		ValidTo:      now.Add(time.Hour),
+	}
+	jpyBalance := &model.Balance{UserID: 1, Currency: domain.JPY, Amount: 9000}
+	usdBalance := &model.Balance{UserID: 1, Currency: domain.USD, Amount: 670}
+	sampleExchange, err := model.NewExchange("foo", 1, rate, 1000, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exchanged, err := model.NewExchange("exchanged", 1, rate, 1000, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	balanceRepo := mock.NewMockBalanceRepository(ctrl)
+	balanceRepo.
+		EXPECT().
+		Get(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userID uint, currency domain.Currency) (*model.Balance, error) {
+			if currency == domain.JPY {
+				return jpyBalance, nil
+			}
+			return usdBalance, nil
+		}).
+		AnyTimes()
+	rateRepo := mock.NewMockExchangeRateRepository(ctrl)
+	rateRepo.
+		EXPECT().
+		Get(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, id uint64) (*model.ExchangeRate, error) {
+			if id == rate.ID {
+				return rate, nil
+			}
+			return nil, domain.ErrNoSuchEntity
+		}).
+		AnyTimes()
+	exchangeRepo := mock.NewMockExchangeRepository(ctrl)
+	exchangeRepo.
+		EXPECT().
+		Get(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, uuid string) (*model.Exchange, error) {
+			if uuid == exchanged.UUID {
+				return exchanged, nil
+			}
+			return nil, domain.ErrInvalidUUID
+		}).
+		AnyTimes()
+	exchangeRepo.
+		EXPECT().
+		Exchange(gomock.Any(), sampleExchange.UUID, sampleExchange.UserID, rate.ID, sampleExchange.FromAmount, gomock.Any()).
+		Return(sampleExchange, nil).
+		Times(1)
+
+	ctx := context.Background()
+
+	type fields struct {
+		BalanceRepo  repository.BalanceRepository
+		RateRepo     repository.ExchangeRateRepository
+		ExchangeRepo repository.ExchangeRepository
+	}
+	type args struct {
+		ctx          context.Context
+		uuid         string
+		userID       uint
+		rateID       uint64
+		fromCurrency string
+		toCurrency   string
+		amount       int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *model.Exchange
+		want1   *model.Balance
+		want2   *model.Balance
+		wantErr error
+	}{
+		{
+			"両替できる",
+			fields{balanceRepo, rateRepo, exchangeRepo},
+			args{ctx, sampleExchange.UUID, 1, rate.ID, "JPY", "USD", 1000},
+			sampleExchange,
+			jpyBalance,
+			usdBalance,
+			nil,
+		},
+		{
+			"レートと通貨が異なる",
+			fields{balanceRepo, rateRepo, exchangeRepo},
+			args{ctx, sampleExchange.UUID, 1, rate.ID, "USD", "JPY", 1000},
+			nil,
+			nil,
+			nil,
+			domain.ErrCurrencyMismatch,
+		},
+		{
+			"存在しないレート",
+			fields{balanceRepo, rateRepo, exchangeRepo},
+			args{ctx, sampleExchange.UUID, 1, 100, "JPY", "USD", 1000},
+			nil,
+			nil,
+			nil,
+			domain.ErrNoSuchEntity,
+		},
+		{
+			"対応していない通貨",
+			fields{balanceRepo, rateRepo, exchangeRepo},
+			args{ctx, sampleExchange.UUID, 1, rate.ID, "JPY", "GBP", 1000},
+			nil,
+			nil,
+			nil,
+			domain.ErrInvalidParam,
+		},
+		{
+			"0円は両替できない",
+			fields{balanceRepo, rateRepo, exchangeRepo},
+			args{ctx, sampleExchange.UUID, 1, rate.ID, "JPY", "USD", 0},
+			nil,
+			nil,
+			nil,
+			domain.ErrInvalidParam,
+		},
+		{
+			"同じ内容での再試行は保存済みの結果を返す",
+			fields{balanceRepo, rateRepo, exchangeRepo},
+			args{ctx, exchanged.UUID, 1, rate.ID, "JPY", "USD", 1000},
+			exchanged,
+			jpyBalance,
+			usdBalance,
+			nil,
+		},
+		{
+			"異なる内容での再試行",
+			fields{balanceRepo, rateRepo, exchangeRepo},
+			args{ctx, exchanged.UUID, 1, rate.ID, "JPY", "USD", 2000},
+			nil,
+			nil,
+			nil,
+			domain.ErrIdempotencyKeyMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &exchangeService{
+				BalanceRepo:  tt.fields.BalanceRepo,
+				RateRepo:     tt.fields.RateRepo,
+				ExchangeRepo: tt.fields.ExchangeRepo,
+			}
+			got, got1, got2, err := s.Exchange(tt.args.ctx, tt.args.uuid, tt.args.userID, tt.args.rateID, tt.args.fromCurrency, tt.args.toCurrency, tt.args.amount)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("exchangeService.Exchange() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("exchangeService.Exchange() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got1, tt.want1) {
+				t.Errorf("exchangeService.Exchange() got1 = %v, want %v", got1, tt.want1)
+			}
+			if !reflect.DeepEqual(got2, tt.want2) {
+				t.Errorf("exchangeService.Exchange() got2 = %v, want %v", got2, tt.want2)
+			}
+		})
+	}
+}
+
+func Test_exchangeService_UploadRates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rateRepo := mock.NewMockExchangeRateRepository(ctrl)
+	rateRepo.
+		EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, rates []*model.ExchangeRate) ([]*model.ExchangeRate, error) {
+			return rates, nil
+		}).
+		Times(1)
+
+	now := time.Now()
+	newRate := func(rate, rounding string, validTo time.Time) *model.ExchangeRate {
+		return &model.ExchangeRate{
+			FromCurrency: domain.USD,
+			ToCurrency:   domain.JPY,
+			Rate:         rate,
+			Rounding:     domain.RoundingMode(rounding),
+			ValidFrom:    now,
+			ValidTo:      validTo,
+		}
+	}
+	tests := []struct {
+		name         string
+		rates        []*model.ExchangeRate
+		wantRounding domain.RoundingMode
+		wantErr      error
+	}{
+		{"丸め方の指定がなければ切り捨てる", []*model.ExchangeRate{newRate("149.5", "", now.Add(time.Hour))}, domain.RoundDown, nil},
+		{"レートがない", nil, "", domain.ErrInvalidParam},
+		{"不正なレート", []*model.ExchangeRate{newRate("-1", "", now.Add(time.Hour))}, "", domain.ErrInvalidParam},
+		{"不正な丸め方", []*model.ExchangeRate{newRate("149.5", "ceil", now.Add(time.Hour))}, "", domain.ErrInvalidParam},
+		{"有効期間が空", []*model.ExchangeRate{newRate("149.5", "", now)}, "", domain.ErrInvalidParam},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &exchangeService{
+				RateRepo: rateRepo,
+			}
+			got, err := s.UploadRates(context.Background(), tt.rates)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("exchangeService.UploadRates() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got[0].Rounding != tt.wantRounding {
+				t.Errorf("exchangeService.UploadRates() rounding = %v, want %v", got[0].Rounding, tt.wantRounding)
+			}
+		})
+	}
+}
+
+func Test_spendingLimitService_SetLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	limitRepo := mock.NewMockSpendingLimitRepository(ctrl)
+	limitRepo.
+		EXPECT().
+		Set(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, limit *model.SpendingLimit) (*model.SpendingLimit, error) {
+			return limit, nil
+		}).
+		Times(2)
+
+	tests := []struct {
+		name    string
+		limit   *model.SpendingLimit
+		wantErr error
+	}{
+		{"ユーザの上限を設定できる", &model.SpendingLimit{Scope: model.SpendingLimitScopeUser, ScopeID: "1", Currency: domain.JPY, DailyDebitLimit: 10000}, nil},
+		{"ユーザ区分の上限を設定できる", &model.SpendingLimit{Scope: model.SpendingLimitScopeTier, ScopeID: model.DefaultUserTier, Currency: domain.USD, HourlyDebitCount: 10}, nil},
+		{"不正な対象", &model.SpendingLimit{Scope: "group", ScopeID: "1", Currency: domain.JPY}, domain.ErrInvalidParam},
+		{"ユーザIDが数値でない", &model.SpendingLimit{Scope: model.SpendingLimitScopeUser, ScopeID: "foo", Currency: domain.JPY}, domain.ErrInvalidParam},
+		{"ユーザ区分がない", &model.SpendingLimit{Scope: model.SpendingLimitScopeTier, Currency: domain.JPY}, domain.ErrInvalidParam},
+		{"不正な通貨", &model.SpendingLimit{Scope: model.SpendingLimitScopeUser, ScopeID: "1", Currency: "XXX"}, domain.ErrInvalidParam},
+		{"負の上限", &model.SpendingLimit{Scope: model.SpendingLimitScopeUser, ScopeID: "1", Currency: domain.JPY, MaxSingleDebit: -1}, domain.ErrInvalidParam},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &spendingLimitService{
+				LimitRepo: limitRepo,
+			}
+			_, err := s.SetLimit(context.Background(), tt.limit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("spendingLimitService.SetLimit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_webhookService_Subscribe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	webhookRepo := mock.NewMockWebhookRepository(ctrl)
+	webhookRepo.
+		EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, subscription *model.WebhookSubscription) (*model.WebhookSubscription, error) {
+			return subscription, nil
+		}).
+		Times(3)
+
+	tests := []struct {
+		name           string
+		url            string
+		eventTypes     []string
+		userIDs        []uint
+		wantEventTypes []model.OutboxEventType
+		wantUserIDs    []uint
+		wantErr        error
+	}{
+		{"Webhookを登録できる", "https://example.com/hooks", []string{"payment.confirmed"}, []uint{1}, []model.OutboxEventType{model.EventPaymentConfirmed}, []uint{1}, nil},
+		{"イベントの種類とユーザは昇順で重複なし", "http://example.com:8080/hooks", []string{"payment.confirmed", "payment.cancelled", "payment.confirmed"}, []uint{3, 1, 3}, []model.OutboxEventType{model.EventPaymentCancelled, model.EventPaymentConfirmed}, []uint{1, 3}, nil},
+		{"不正なURL", "ftp://example.com/hooks", []string{"payment.confirmed"}, []uint{1}, nil, nil, domain.ErrInvalidParam},
+		{"相対URL", "/hooks", []string{"payment.confirmed"}, []uint{1}, nil, nil, domain.ErrInvalidParam},
+		{"イベントの種類がない", "https://example.com/hooks", nil, []uint{1}, nil, nil, domain.ErrInvalidParam},
+		{"不明なイベントの種類", "https://example.com/hooks", []string{"payment.tried"}, []uint{1}, nil, nil, domain.ErrInvalidParam},
+		{"送金や返金のイベントも購読できる", "https://example.com/hooks", []string{"transfer.confirmed", "refund.created", "points.expired"}, []uint{1}, []model.OutboxEventType{model.EventPointsExpired, model.EventRefundCreated, model.EventTransferConfirmed}, []uint{1}, nil},
+		{"複数のユーザのイベントは購読できない", "https://example.com/hooks", []string{"balances.added_to_users"}, []uint{1}, nil, nil, domain.ErrInvalidParam},
+		{"一斉加算の取り消しのイベントは購読できない", "https://example.com/hooks", []string{"bulk_credit.reversed"}, []uint{1}, nil, nil, domain.ErrInvalidParam},
+		{"ユーザがいない", "https://example.com/hooks", []string{"payment.confirmed"}, nil, nil, nil, domain.ErrInvalidParam},
+		{"ユーザが多すぎる", "https://example.com/hooks", []string{"payment.confirmed"}, make([]uint, model.MaxWebhookUsers+1), nil, nil, domain.ErrInvalidParam},
+		{"ループバックのホスト", "http://localhost:8080/hooks", []string{"payment.confirmed"}, []uint{1}, nil, nil, domain.ErrInvalidParam},
+		{"メタデータサーバのアドレス", "http://169.254.169.254/latest/meta-data", []string{"payment.confirmed"}, []uint{1}, nil, nil, domain.ErrInvalidParam},
+		{"プライベートなアドレスに解決されるホスト", "https://internal.example.com/hooks", []string{"payment.confirmed"}, []uint{1}, nil, nil, domain.ErrInvalidParam},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &webhookService{
+				WebhookRepo: webhookRepo,
+				Resolver: fakeResolver{
+					"example.com":          {"93.184.216.34"},
+					"internal.example.com": {"10.0.0.1"},
+					"localhost":            {"127.0.0.1"},
+					"169.254.169.254":      {"169.254.169.254"},
+				},
+			}
+			got, err := s.Subscribe(context.Background(), tt.url, tt.eventTypes, tt.userIDs)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("webhookService.Subscribe() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got.EventTypes, tt.wantEventTypes) {
+				t.Errorf("webhookService.Subscribe() event types = %v, want %v", got.EventTypes, tt.wantEventTypes)
+			}
+			if !reflect.DeepEqual(got.UserIDs, tt.wantUserIDs) {
+				t.Errorf("webhookService.Subscribe() user ids = %v, want %v", got.UserIDs, tt.wantUserIDs)
+			}
+			if len(got.Secret) != webhookSecretBytes*2 {
+				t.Errorf("webhookService.Subscribe() secret = %q", got.Secret)
+			}
+		})
+	}
+}
+
+// fakeResolver resolves the hosts to the fixed addresses.
+type fakeResolver map[string][]string
+
+func (r fakeResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	ips, ok := r[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	var addrs []net.IPAddr
+	for _, ip := range ips {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+	return addrs, nil
+}
+
+func Test_webhookService_ListDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	webhookRepo := mock.NewMockWebhookRepository(ctrl)
+	webhookRepo.
+		EXPECT().
+		Get(gomock.Any(), uint64(1)).
+		Return(&model.WebhookSubscription{ID: 1}, nil).
+		AnyTimes()
+	webhookRepo.
+		EXPECT().
+		Get(gomock.Any(), uint64(2)).
+		Return(nil, domain.ErrNoSuchEntity).
+		AnyTimes()
+	webhookRepo.
+		EXPECT().
+		ListDeliveries(gomock.Any(), uint64(1), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, subscriptionID, beforeID uint64, limit int) ([]*model.WebhookDelivery, error) {
+			// IDが1から5の配信を新しい順に返す
+			var deliveries []*model.WebhookDelivery
+			for id := uint64(5); id > 0 && len(deliveries) < limit; id-- {
+				if beforeID == 0 || id < beforeID {
+					deliveries = append(deliveries, &model.WebhookDelivery{ID: id, SubscriptionID: subscriptionID})
+				}
+			}
+			return deliveries, nil
+		}).
+		AnyTimes()
+
+	s := &webhookService{
+		WebhookRepo: webhookRepo,
+	}
+	ctx := context.Background()
+
+	// ページを辿ってすべての配信を取得する
+	var ids []uint64
+	cursor := ""
+	for i := 0; i < 3; i++ {
+		deliveries, next, err := s.ListDeliveries(ctx, 1, cursor, 2)
+		if err != nil {
+			t.Fatalf("webhookService.ListDeliveries() error = %v", err)
+		}
+		for _, d := range deliveries {
+			ids = append(ids, d.ID)
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	if want := []uint64{5, 4, 3, 2, 1}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("webhookService.ListDeliveries() ids = %v, want %v", ids, want)
+	}
+
+	tests := []struct {
+		name    string
+		id      uint64
+		cursor  string
+		limit   int
+		wantErr error
+	}{
+		{"存在しないWebhook", 2, "", 0, domain.ErrNoSuchEntity},
+		{"不正なカーソル", 1, "foo", 0, domain.ErrInvalidParam},
+		{"件数が上限を超える", 1, "", 101, domain.ErrInvalidParam},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := s.ListDeliveries(ctx, tt.id, tt.cursor, tt.limit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("webhookService.ListDeliveries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
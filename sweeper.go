@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain/repository"
+)
+
+// expirationSweeper periodically expires tried payments and transfers which are past their expire time,
+// and point lots which are past their expire time.
+type expirationSweeper struct {
+	PaymentRepo  repository.PaymentTransactionRepository
+	TransferRepo repository.TransferRepository
+	PointLotRepo repository.PointLotRepository
+	Interval     time.Duration
+	BatchSize    int
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newExpirationSweeper(paymentRepo repository.PaymentTransactionRepository, transferRepo repository.TransferRepository, pointLotRepo repository.PointLotRepository, interval time.Duration, batchSize int) *expirationSweeper {
+	return &expirationSweeper{
+		PaymentRepo:  paymentRepo,
+		TransferRepo: transferRepo,
+		PointLotRepo: pointLotRepo,
+		Interval:     interval,
+		BatchSize:    batchSize,
+	}
+}
+
+// Start runs the sweeper in a background goroutine until Stop is called.
+func (s *expirationSweeper) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.sweep(ctx, "tried payments", s.PaymentRepo.ExpireTries)
+				// 送金に対応していないDBでは送金のリポジトリがない
+				if s.TransferRepo != nil {
+					s.sweep(ctx, "tried transfers", s.TransferRepo.ExpireTries)
+				}
+				s.sweep(ctx, "point lots", s.PointLotRepo.ExpireLots)
+			}
+		}
+	}()
+}
+
+// Stop stops the sweeper and waits for the running sweep to finish.
+func (s *expirationSweeper) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+func (s *expirationSweeper) sweep(ctx context.Context, name string, expire func(ctx context.Context, now time.Time, limit int) (int, error)) {
+	// 期限切れのものがなくなるまでバッチ単位で処理する
+	for {
+		n, err := expire(ctx, time.Now(), s.BatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("expire %s error: %v", name, err)
+			}
+			return
+		}
+		if n > 0 {
+			log.Printf("expired %d %s", n, name)
+		}
+		if n < s.BatchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/kawabatas/m-bank/domain/model"
+	"github.com/kawabatas/m-bank/domain/repository"
+	"github.com/kawabatas/m-bank/infra/webhook"
+)
+
+// 送信中の配信を他のディスパッチャーが取得しない期間。Webhookのタイムアウトより長くする
+const webhookLease = time.Minute
+
+// webhookDispatcher periodically sends the pending deliveries to the webhooks, retrying the failed ones
+// with an exponential backoff until they succeed or become dead.
+type webhookDispatcher struct {
+	WebhookRepo repository.WebhookRepository
+	Client      *webhook.Client
+	Interval    time.Duration
+	BatchSize   int
+	MaxAttempts int
+	Lease       time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newWebhookDispatcher(webhookRepo repository.WebhookRepository, client *webhook.Client, interval time.Duration, batchSize, maxAttempts int) *webhookDispatcher {
+	return &webhookDispatcher{
+		WebhookRepo: webhookRepo,
+		Client:      client,
+		Interval:    interval,
+		BatchSize:   batchSize,
+		MaxAttempts: maxAttempts,
+		Lease:       webhookLease,
+	}
+}
+
+// Start runs the dispatcher in a background goroutine until Stop is called.
+func (d *webhookDispatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(d.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.dispatch(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops the dispatcher and waits for the running deliveries to finish.
+func (d *webhookDispatcher) Stop() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+	d.wg.Wait()
+}
+
+func (d *webhookDispatcher) dispatch(ctx context.Context) {
+	// 送信できる配信がなくなるまでバッチ単位で送信する
+	for ctx.Err() == nil {
+		now := time.Now()
+		deliveries, err := d.WebhookRepo.ClaimPendingDeliveries(ctx, now, d.Lease, d.BatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("claim webhook deliveries error: %v", err)
+			}
+			return
+		}
+		// 取得の期限を過ぎると他のディスパッチャーが同じ配信を取得するので、期限までに送信を打ち切る。
+		// 送信しなかった配信は期限の後にもう一度取得される
+		leaseCtx, cancel := context.WithDeadline(ctx, now.Add(d.Lease))
+		for _, delivery := range deliveries {
+			if leaseCtx.Err() != nil {
+				break
+			}
+			d.send(ctx, leaseCtx, delivery)
+		}
+		cancel()
+		if len(deliveries) < d.BatchSize {
+			return
+		}
+	}
+}
+
+// send sends the delivery within the lease of sendCtx and saves the result with ctx.
+func (d *webhookDispatcher) send(ctx, sendCtx context.Context, delivery *model.WebhookDelivery) {
+	status, err := d.Client.Send(sendCtx, delivery, time.Now())
+	if err != nil {
+		delivery.Failed(status, err, d.MaxAttempts, time.Now())
+		if delivery.Status == model.WebhookDeliveryStatusDead {
+			log.Printf("give up webhook delivery %d after %d attempts: %v", delivery.ID, delivery.Attempts, err)
+		}
+	} else {
+		delivery.Succeeded(status, time.Now())
+	}
+	// 結果を保存できなかった配信は、取得の期限を過ぎてからもう一度送信される
+	if err := d.WebhookRepo.UpdateDelivery(ctx, delivery); err != nil && ctx.Err() == nil {
+		log.Printf("update webhook delivery %d error: %v", delivery.ID, err)
+	}
+}
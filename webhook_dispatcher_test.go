@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kawabatas/m-bank/domain/mock"
+	"github.com/kawabatas/m-bank/domain/model"
+	"github.com/kawabatas/m-bank/infra/webhook"
+)
+
+// newLoopbackWebhookClient creates a client without the address check, since the test receivers listen on the loopback.
+func newLoopbackWebhookClient() *webhook.Client {
+	return &webhook.Client{HTTPClient: &http.Client{Timeout: time.Second}}
+}
+
+func Test_webhookDispatcher_dispatch(t *testing.T) {
+	const secret = "secret"
+
+	tests := []struct {
+		name           string
+		attempts       int // 送信前の試行回数
+		status         int // 受け取り側の応答
+		wantStatus     model.WebhookDeliveryStatus
+		wantAttempts   int
+		wantStatusCode int
+		wantRetry      bool // 次の試行が後にずらされるかどうか
+	}{
+		{"送信に成功", 0, http.StatusOK, model.WebhookDeliveryStatusSucceeded, 1, http.StatusOK, false},
+		{"失敗したらバックオフしてリトライする", 0, http.StatusServiceUnavailable, model.WebhookDeliveryStatusPending, 1, http.StatusServiceUnavailable, true},
+		{"リトライで成功", 1, http.StatusNoContent, model.WebhookDeliveryStatusSucceeded, 2, http.StatusNoContent, false},
+		{"最大試行回数まで失敗したらデッドレターにする", 2, http.StatusInternalServerError, model.WebhookDeliveryStatusDead, 3, http.StatusInternalServerError, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var received int
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if !webhook.Verify(secret, r.Header, body, time.Now(), webhook.DefaultTolerance) {
+					t.Errorf("invalid signature %q", r.Header.Get(webhook.SignatureHeader))
+				}
+				received++
+				w.WriteHeader(tt.status)
+			}))
+			defer receiver.Close()
+
+			now := time.Now()
+			event, err := model.NewOutboxEvent(model.EventPaymentConfirmed, &model.PaymentEventPayload{UUID: "foo"}, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			delivery := &model.WebhookDelivery{
+				ID:              1,
+				Status:          model.WebhookDeliveryStatusPending,
+				Attempts:        tt.attempts,
+				NextAttemptTime: now,
+				Subscription:    &model.WebhookSubscription{URL: receiver.URL, Secret: secret},
+				Event:           event,
+			}
+
+			var updated *model.WebhookDelivery
+			webhookRepo := mock.NewMockWebhookRepository(ctrl)
+			webhookRepo.
+				EXPECT().
+				ClaimPendingDeliveries(gomock.Any(), gomock.Any(), webhookLease, 10).
+				Return([]*model.WebhookDelivery{delivery}, nil)
+			webhookRepo.
+				EXPECT().
+				UpdateDelivery(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, delivery *model.WebhookDelivery) error {
+					updated = delivery
+					return nil
+				})
+
+			d := newWebhookDispatcher(webhookRepo, newLoopbackWebhookClient(), time.Second, 10, 3)
+			d.dispatch(context.Background())
+
+			if received != 1 {
+				t.Errorf("received %d requests, want 1", received)
+			}
+			if updated.Status != tt.wantStatus || updated.Attempts != tt.wantAttempts || updated.LastStatusCode != tt.wantStatusCode {
+				t.Errorf("updated = %+v, want status %v, attempts %v, status code %v", updated, tt.wantStatus, tt.wantAttempts, tt.wantStatusCode)
+			}
+			if retry := updated.NextAttemptTime.After(now); retry != tt.wantRetry {
+				t.Errorf("next attempt time = %v, want retry %v", updated.NextAttemptTime, tt.wantRetry)
+			}
+			if (updated.Status == model.WebhookDeliveryStatusSucceeded) == updated.DeliverTime.IsZero() {
+				t.Errorf("deliver time = %v", updated.DeliverTime)
+			}
+		})
+	}
+}
+
+func Test_webhookDispatcher_dispatchSlowReceiver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// 取得の期限より遅い受け取り側
+	var received int
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+		select {
+		case <-r.Context().Done():
+		case <-time.After(500 * time.Millisecond):
+		}
+	}))
+	defer receiver.Close()
+
+	now := time.Now()
+	var deliveries []*model.WebhookDelivery
+	for i := 1; i <= 2; i++ {
+		event, err := model.NewOutboxEvent(model.EventPaymentConfirmed, &model.PaymentEventPayload{UUID: "foo"}, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		deliveries = append(deliveries, &model.WebhookDelivery{
+			ID:              uint64(i),
+			Status:          model.WebhookDeliveryStatusPending,
+			NextAttemptTime: now,
+			Subscription:    &model.WebhookSubscription{URL: receiver.URL, Secret: "secret"},
+			Event:           event,
+		})
+	}
+
+	// 期限を過ぎた送信は打ち切って失敗にし、残りの配信は送信しない
+	var updated []*model.WebhookDelivery
+	webhookRepo := mock.NewMockWebhookRepository(ctrl)
+	webhookRepo.
+		EXPECT().
+		ClaimPendingDeliveries(gomock.Any(), gomock.Any(), 100*time.Millisecond, 10).
+		Return(deliveries, nil)
+	webhookRepo.
+		EXPECT().
+		UpdateDelivery(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, delivery *model.WebhookDelivery) error {
+			updated = append(updated, delivery)
+			return nil
+		})
+
+	d := newWebhookDispatcher(webhookRepo, newLoopbackWebhookClient(), time.Second, 10, 3)
+	d.Lease = 100 * time.Millisecond
+	d.dispatch(context.Background())
+
+	if received != 1 {
+		t.Errorf("received %d requests, want 1", received)
+	}
+	if len(updated) != 1 || updated[0].ID != 1 || updated[0].Status != model.WebhookDeliveryStatusPending || updated[0].Attempts != 1 {
+		t.Errorf("updated = %+v, want the first delivery to be retried", updated)
+	}
+	if elapsed := time.Since(now); elapsed >= 500*time.Millisecond {
+		t.Errorf("dispatch took %v, want it to stop at the lease", elapsed)
+	}
+}